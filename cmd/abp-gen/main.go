@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/generator"
+	"github.com/mohamedhabibwork/abp-gen/internal/merger"
+	"github.com/mohamedhabibwork/abp-gen/internal/presenter"
 	"github.com/mohamedhabibwork/abp-gen/internal/prompts"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
 	"github.com/mohamedhabibwork/abp-gen/internal/templates"
@@ -24,7 +34,12 @@ var (
 
 var (
 	// Global flags
-	verbose bool
+	verbose    bool
+	colorMode  string
+	configFile string
+
+	// ui prints decorated status lines; reconfigured from --color before each command runs
+	ui = presenter.New(os.Stdout, presenter.ModeAuto)
 
 	// Generate command flags
 	inputFile       string
@@ -33,12 +48,40 @@ var (
 	templatesPath   string
 	targetFramework string
 	autoScaffold    bool
+	noInteractive   bool
 	dryRun          bool
 	force           bool
+	backup          bool
 	mergeMode       bool
 	noMerge         bool
 	mergeAll        bool
+	mergeBatch      bool
 	mergeStrategy   string
+	headerText      string
+	noHeader        bool
+	strict          bool
+	listGenerators  bool
+	outputDir       string
+	onlyGenerators  string
+	skipGenerators  string
+	sinceRef        string
+	outputFormat    string
+	watch           bool
+	parallel        bool
+
+	// Format command flags
+	formatCanonical bool
+	formatOutput    string
+
+	// Schema command flags
+	jsonSchemaOutput string
+
+	// Add command flags
+	addInputFile string
+
+	// Templates command flags
+	listTemplatesPath   string
+	listTemplatesTarget string
 
 	// Schema override flags (can override values from schema file)
 	schemaSolutionName        string
@@ -48,15 +91,40 @@ var (
 	schemaDBProvider          string
 	schemaGenerateControllers bool
 	schemaGenerationMode      string
+	schemaSeedCount           int
 )
 
 func main() {
+	// Errors are printed once, by printError, rather than also by cobra
+	rootCmd.SilenceErrors = true
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// printError prints err; a generator failure is followed by the generator, entity, template
+// and output file it concerned
+func printError(out io.Writer, err error) {
+	var genErr *generator.GenerationError
+	if !errors.As(err, &genErr) {
+		fmt.Fprintf(out, "Error: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(out, "Error: %v\n", genErr.Err)
+	fmt.Fprintf(out, "  Generator: %s\n", genErr.Generator)
+	if genErr.Entity != "" {
+		fmt.Fprintf(out, "  Entity:    %s\n", genErr.Entity)
+	}
+	if genErr.Template != "" {
+		fmt.Fprintf(out, "  Template:  %s\n", genErr.Template)
+	}
+	if genErr.Path != "" {
+		fmt.Fprintf(out, "  Output:    %s\n", genErr.Path)
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "abp-gen",
 	Short: "ABP Framework code generator",
@@ -71,6 +139,19 @@ It supports:
   - Interactive schema building mode
   - Smart file merging with conflict resolution`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", Version, GitCommit, BuildDate),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadConfigDefaults(cmd); err != nil {
+			return err
+		}
+
+		mode, err := presenter.ParseMode(colorMode)
+		if err != nil {
+			return err
+		}
+		ui = presenter.New(os.Stdout, mode)
+		prompts.SetOutput(os.Stdout, mode)
+		return nil
+	},
 }
 
 var versionCmd = &cobra.Command{
@@ -94,6 +175,68 @@ This allows you to customize the templates for your specific needs.`,
 	},
 }
 
+var formatCmd = &cobra.Command{
+	Use:   "format <schema.json>",
+	Short: "Rewrite a schema file in minimal or canonical form",
+	Long: `Rewrites a schema file without default-valued fields (false, 0, empty strings,
+empty relations/enums, ...), keeping the author's key order.
+
+With --canonical, keys are sorted alphabetically to produce a normalized form for diffing.
+
+Examples:
+  # Remove default-valued fields in place
+  abp-gen format schema.json
+
+  # Write a canonical copy for diffing
+  abp-gen format schema.json --canonical --output schema.canonical.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFormat(args[0])
+	},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Work with the schema file format",
+}
+
+var exportJSONSchemaCmd = &cobra.Command{
+	Use:   "export-jsonschema",
+	Short: "Print a JSON Schema describing schema files",
+	Long: `Prints a JSON Schema (draft-07) document describing the schema file format: every field,
+the accepted values of enumerated fields, and required fields.
+
+Reference it from a schema file's "$schema" key to get completion and validation in editors.
+
+Examples:
+  # Write the JSON Schema next to your schema files
+  abp-gen schema export-jsonschema --output abp-gen.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExportJSONSchema()
+	},
+}
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add items to an existing schema file",
+}
+
+var addEntityCmd = &cobra.Command{
+	Use:   "entity",
+	Short: "Interactively add an entity to an existing schema file",
+	Long: `Loads an existing schema file, prompts for a single entity, appends it, and saves the
+schema back in place. The entity name must not already be used in the schema.
+
+Examples:
+  # Add one more entity to schema.json
+  abp-gen add entity --input schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAddEntity()
+	},
+}
+
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate ABP code from schema",
@@ -120,15 +263,70 @@ Examples:
   abp-gen generate --input schema.json --dry-run
 
   # Force overwrite existing files
-  abp-gen generate --input schema.json --force`,
+  abp-gen generate --input schema.json --force
+
+  # Generate into a scratch directory for review
+  abp-gen generate --input schema.json --output-dir ./generated
+
+  # Fail instead of prompting (for CI)
+  abp-gen generate --input schema.json --no-interactive
+
+  # Regenerate on every schema save
+  abp-gen generate --input schema.json --watch`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGenerate(cmd)
+	},
+}
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Inspect the templates used for generation",
+}
+
+var listTemplatesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List each template and the source it resolves from",
+	Long: `Lists every template with the file it is loaded from for a target framework. Sources are
+tried in order: the --templates directory, ./abp-gen-templates (written by init), then the
+templates embedded in the binary; within each, the target's folder comes before "common".
+
+A customized template that fails to parse is skipped in favor of the next source; such
+templates are listed below the one used, with the parse error.
+
+Examples:
+  # See which customized templates a microservice generation would pick up
+  abp-gen templates list --target abp9-microservice --templates ./my-templates`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runGenerate()
+		loader := templates.NewLoaderWithTarget(listTemplatesPath, listTemplatesTarget)
+		return writeTemplateList(os.Stdout, loader, listTemplatesTarget)
+	},
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the files written by the last generate run",
+	Long: `Reverts the last generate run using the operation log it saved to ` + "`.abp-gen/last-run.json`" + `:
+files the run created are deleted, and files it updated are restored from the {file}.bak
+copies written by --backup. Updated files without a backup are reported and left as they are.
+
+The log is removed once the run has been undone, so undo only reverts a run once.
+
+Examples:
+  # Generate with backups so updated files can be restored, then revert
+  abp-gen generate --input schema.json --merge --backup
+  abp-gen undo`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUndo()
 	},
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", string(presenter.ModeAuto), "colored, emoji-decorated output: auto (only on a terminal), always, or never")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file with flag defaults (defaults to "+defaultConfigFile+" in the working directory, if present)")
 
 	// Generate command flags
 	generateCmd.Flags().StringVarP(&inputFile, "input", "i", "", "input schema JSON file (optional, triggers interactive mode if not provided)")
@@ -138,12 +336,26 @@ func init() {
 	generateCmd.Flags().StringVarP(&templatesPath, "templates", "t", "", "custom templates directory")
 	generateCmd.Flags().StringVar(&targetFramework, "target", "auto", "target framework: aspnetcore9, aspnetcore10, abp8-monolith, abp8-microservice, abp9-*, abp10-*, or auto")
 	generateCmd.Flags().BoolVar(&autoScaffold, "auto-scaffold", false, "automatically create missing solutions/projects without prompting")
+	generateCmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "fail instead of prompting when information cannot be detected")
 	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview changes without writing files")
+	generateCmd.Flags().StringVar(&outputFormat, "format", "text", "output format: text, or json to print the file manifest as JSON on stdout (e.g. with --dry-run)")
 	generateCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
+	generateCmd.Flags().BoolVar(&backup, "backup", false, "copy each existing file to {file}.bak before overwriting or merging it")
 	generateCmd.Flags().BoolVar(&mergeMode, "merge", false, "enable smart merge mode for existing files")
 	generateCmd.Flags().BoolVar(&noMerge, "no-merge", false, "disable merge mode (skip existing files)")
 	generateCmd.Flags().BoolVar(&mergeAll, "merge-all", false, "automatically merge all files without prompting")
+	generateCmd.Flags().BoolVar(&mergeBatch, "merge-batch", false, "compute every merge first, then show one summary of clean merges and conflicts and prompt once")
 	generateCmd.Flags().StringVar(&mergeStrategy, "merge-strategy", "", "merge strategy: pattern, ast, or json (auto-detected if not specified)")
+	generateCmd.Flags().StringVar(&headerText, "header", defaultHeaderText, "header comment for generated C# files ({version} and {schema} are replaced)")
+	generateCmd.Flags().BoolVar(&noHeader, "no-header", false, "do not prepend a header comment to generated C# files")
+	generateCmd.Flags().BoolVar(&listGenerators, "list-generators", false, "list the available generators, the layers and files they write, and exit")
+	generateCmd.Flags().StringVar(&outputDir, "output-dir", "", "write generated files under this directory instead of the detected solution, keeping the layer structure")
+	generateCmd.Flags().StringVar(&onlyGenerators, "only", "", "comma-separated generators to run, e.g. entity,dto,service (see --list-generators)")
+	generateCmd.Flags().StringVar(&skipGenerators, "skip", "", "comma-separated generators to leave out, e.g. integration-tests,seeder")
+	generateCmd.Flags().StringVar(&sinceRef, "since", "", "only regenerate the entities added or changed in the input schema since this git ref, e.g. HEAD, and the entities related to them")
+	generateCmd.Flags().BoolVar(&watch, "watch", false, "regenerate in merge mode whenever the input schema or custom templates change, until Ctrl+C")
+	generateCmd.Flags().BoolVar(&parallel, "parallel", false, "generate entities concurrently on up to GOMAXPROCS workers; files shared by all entities are still updated one entity at a time")
+	generateCmd.Flags().BoolVar(&strict, "strict", false, "fail on schema warnings such as property/member name collisions")
 
 	// Schema override flags - can override values from schema file
 	generateCmd.Flags().StringVar(&schemaSolutionName, "solutionName", "", "solution name (overrides schema)")
@@ -153,9 +365,33 @@ func init() {
 	generateCmd.Flags().StringVar(&schemaDBProvider, "dbProvider", "", "database provider: efcore, mongodb, or both (overrides schema)")
 	generateCmd.Flags().BoolVar(&schemaGenerateControllers, "generateControllers", false, "generate controllers (overrides schema)")
 	generateCmd.Flags().StringVar(&schemaGenerationMode, "generationMode", "", "generation mode: existing or new (overrides schema)")
+	generateCmd.Flags().IntVar(&schemaSeedCount, "seed-count", 0, "rows each data seeder generates with deterministic pseudo-random values (overrides schema seedCount)")
+
+	// Format command flags
+	formatCmd.Flags().BoolVar(&formatCanonical, "canonical", false, "sort keys alphabetically for a normalized, diff-friendly form")
+	formatCmd.Flags().StringVarP(&formatOutput, "output", "o", "", "output file (defaults to rewriting the input file)")
+
+	// Schema command flags
+	exportJSONSchemaCmd.Flags().StringVarP(&jsonSchemaOutput, "output", "o", "", "output file (defaults to stdout)")
+	schemaCmd.AddCommand(exportJSONSchemaCmd)
+
+	// Add command flags
+	addEntityCmd.Flags().StringVarP(&addInputFile, "input", "i", "", "schema JSON file to add the entity to")
+	_ = addEntityCmd.MarkFlagRequired("input")
+	addCmd.AddCommand(addEntityCmd)
+
+	// Templates command flags
+	listTemplatesCmd.Flags().StringVarP(&listTemplatesPath, "templates", "t", "", "custom templates directory")
+	listTemplatesCmd.Flags().StringVar(&listTemplatesTarget, "target", "abp8-monolith", "target framework: aspnetcore9, aspnetcore10, abp8-monolith, abp8-microservice, abp9-*, or abp10-*")
+	templatesCmd.AddCommand(listTemplatesCmd)
 
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(formatCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(undoCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -166,22 +402,361 @@ func runInit() error {
 		return fmt.Errorf("failed to extract templates: %w", err)
 	}
 
-	fmt.Println("\n✓ Templates extracted successfully!")
+	fmt.Println()
+	ui.Success("Templates extracted successfully!")
 	fmt.Println("\nYou can now customize the templates in ./abp-gen-templates/")
 	fmt.Println("Use --templates ./abp-gen-templates when generating code to use customized templates.")
 
 	return nil
 }
 
+// defaultConfigFile is the config file discovered in the working directory when --config is not given
+const defaultConfigFile = ".abp-gen.json"
+
+// loadConfigDefaults seeds the command's flags from the config file. Flags given on the command
+// line win; config values in turn override the schema file, since overrides are applied after loading it.
+func loadConfigDefaults(cmd *cobra.Command) error {
+	path := configFile
+	if path == "" {
+		path = defaultConfigFile
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := applyConfigDefaults(cmd, config); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if verbose {
+		fmt.Printf("Using config file %s\n", path)
+	}
+	return nil
+}
+
+// applyConfigDefaults sets every flag named in config that was not given on the command line.
+// Keys must be flag names of some command; keys for other commands are ignored.
+func applyConfigDefaults(cmd *cobra.Command, config map[string]interface{}) error {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			if !isKnownFlag(cmd.Root(), name) {
+				return fmt.Errorf("unknown option %q", name)
+			}
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+
+		value, err := configFlagValue(config[name])
+		if err != nil {
+			return fmt.Errorf("option %q: %w", name, err)
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("option %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// configFlagValue converts a JSON config value to its flag string form; lists become comma-separated
+func configFlagValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, float64:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("list items must be strings")
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("value must be a string, number, boolean, or list of strings")
+	}
+}
+
+// isKnownFlag checks if any command in the tree declares the flag
+func isKnownFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, sub := range cmd.Commands() {
+		if isKnownFlag(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGeneratorList prints every registered generator with its scope, layers, and outputs
+func writeGeneratorList(out io.Writer) {
+	fmt.Fprintln(out, "Available generators (in run order):")
+	for _, registration := range generator.Registry {
+		fmt.Fprintf(out, "\n%s (%s)\n", registration.Name, registration.Scope)
+		fmt.Fprintf(out, "  %s\n", registration.Description)
+		fmt.Fprintf(out, "  Layers:  %s\n", strings.Join(registration.Layers, ", "))
+		fmt.Fprintf(out, "  Outputs: %s\n", strings.Join(registration.Outputs, "\n           "))
+	}
+}
+
+// writeTemplateList prints each template with the source it resolves from for target
+func writeTemplateList(out io.Writer, loader *templates.Loader, target string) error {
+	names, err := loader.ListAvailableTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	fmt.Fprintf(out, "Templates for target %s:\n\n", target)
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		source, skipped, err := loader.Resolve(name)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t%s\t%v\n", name, "error", err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, source.Layer, source.Path)
+		for _, skip := range skipped {
+			fmt.Fprintf(tw, "  skipped\t%s\t%s: %v\n", skip.Source.Layer, skip.Source.Path, skip.Err)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeManifest prints the file operations as an indented JSON array
+func writeManifest(out io.Writer, manifest []writer.FileOperation) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// splitGeneratorNames parses a comma-separated --only/--skip value, ignoring blanks
+func splitGeneratorNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func runExportJSONSchema() error {
+	data, err := schema.MarshalJSONSchema()
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON Schema: %w", err)
+	}
+
+	if jsonSchemaOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(jsonSchemaOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON Schema: %w", err)
+	}
+
+	ui.Success("JSON Schema saved to %s", jsonSchemaOutput)
+	return nil
+}
+
+func runAddEntity() error {
+	sch, err := schema.LoadFromFile(addInputFile)
+	if err != nil {
+		return fmt.Errorf("failed to load schema: %w", err)
+	}
+	if len(sch.IncludedFiles()) > 0 {
+		return fmt.Errorf("%s includes entities from other files, which saving it would inline: add the entity to a new file instead", addInputFile)
+	}
+
+	// Validate a copy so the defaults filled in by Validate are not written back to the file
+	current, err := sch.Clone()
+	if err != nil {
+		return err
+	}
+	if err := current.Validate(); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+
+	var entityNames []string
+	for _, existing := range current.Entities {
+		entityNames = append(entityNames, existing.Name)
+	}
+	entity, err := prompts.PromptEntity(current.Solution.PrimaryKeyType, entityNames)
+	if err != nil {
+		return fmt.Errorf("failed to get entity: %w", err)
+	}
+
+	if err := sch.AddEntity(*entity); err != nil {
+		return err
+	}
+
+	updated, err := sch.Clone()
+	if err != nil {
+		return err
+	}
+	if err := updated.Validate(); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+
+	if err := sch.SaveToFile(addInputFile); err != nil {
+		return fmt.Errorf("failed to save schema: %w", err)
+	}
+
+	ui.Success("Entity %s added to %s", entity.Name, addInputFile)
+	return nil
+}
+
+func runUndo() error {
+	log, err := writer.LoadRunLog(writer.RunLogPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no generation run to undo: %s not found", writer.RunLogPath)
+		}
+		return fmt.Errorf("failed to load run log: %w", err)
+	}
+
+	fmt.Printf("Undoing generation run from %s...\n", log.Time.Local().Format("2006-01-02 15:04:05"))
+	results, err := log.Undo()
+	notRestored := 0
+	for _, result := range results {
+		switch result.Action {
+		case writer.UndoDeleted:
+			ui.Success("Deleted %s", result.Path)
+		case writer.UndoRestored:
+			ui.Success("Restored %s", result.Path)
+		case writer.UndoNotRestored:
+			notRestored++
+			ui.Warning("Cannot restore %s: no backup (generate with --backup)", result.Path)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(writer.RunLogPath); err != nil {
+		return fmt.Errorf("failed to remove run log: %w", err)
+	}
+
+	if notRestored > 0 {
+		ui.Warning("%d updated file(s) were left as generated", notRestored)
+	}
+	ui.Success("Undo completed (%d file(s) reverted)", len(results)-notRestored)
+	return nil
+}
+
+func runFormat(path string) error {
+	sch, err := schema.LoadFromFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	output := formatOutput
+	if output == "" {
+		output = path
+	}
+	if output == path && len(sch.IncludedFiles()) > 0 {
+		return fmt.Errorf("%s includes entities from other files, which formatting it in place would inline: pass --output to write the composed schema elsewhere", path)
+	}
+
+	if formatCanonical {
+		err = sch.SaveCanonicalToFile(output)
+	} else {
+		err = sch.SaveToFile(output)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save schema: %w", err)
+	}
+
+	ui.Success("Schema saved to %s", output)
+	return nil
+}
+
+// defaultHeaderText is the default text of the header comment in generated C# files
+const defaultHeaderText = "by abp-gen {version} from {schema}"
+
+// generateEntitiesParallel runs the per-entity generators for the selected entities on a worker pool
+// bounded by GOMAXPROCS
+func generateEntitiesParallel(out io.Writer, sch *schema.Schema, selected []schema.Entity, generators *generator.Generators, relationHandler *generator.RelationshipHandler, paths *detector.LayerPaths) error {
+	// Relationships are resolved up front; processing them may update the shared relation definitions
+	entities := make([]*schema.Entity, len(selected))
+	for i := range selected {
+		entity := selected[i]
+		if err := relationHandler.ProcessRelationships(sch, &entity); err != nil {
+			return fmt.Errorf("failed to process relationships for %s: %w", entity.Name, err)
+		}
+		entities[i] = &entity
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(entities) {
+		workers = len(entities)
+	}
+	fmt.Fprintf(out, "Using %d worker(s)...\n", workers)
+
+	err := generators.RunForEntitiesParallel(sch, entities, paths, workers, func(entity *schema.Entity) {
+		ui.Success("Generated %s", entity.Name)
+	})
+	fmt.Fprintln(out)
+	return err
+}
+
+// generatedHeader builds the header comment line for generated C# files.
+// The text always follows the auto-generated marker so the merger can recognize it.
+func generatedHeader(text, version, schemaFile string) string {
+	if version != "dev" && !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	schemaName := "interactive session"
+	if schemaFile != "" {
+		schemaName = filepath.Base(schemaFile)
+	}
+
+	text = strings.ReplaceAll(text, "{version}", version)
+	text = strings.ReplaceAll(text, "{schema}", schemaName)
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), merger.GeneratedHeaderMarker))
+
+	if text == "" {
+		return merger.GeneratedHeaderMarker
+	}
+	return merger.GeneratedHeaderMarker + " " + text
+}
+
 // detectAndPromptMissingFields detects missing required fields from solution structure
-// and prompts user if detection fails
-func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.SolutionInfo, solutionDetectErr error) error {
+// and prompts user if detection fails. With --no-interactive, required fields that
+// cannot be detected produce an error and optional fields fall back to their defaults.
+func detectAndPromptMissingFields(out io.Writer, sch *schema.Schema, solutionInfo *detector.SolutionInfo, solutionDetectErr error) error {
 	// Detect solution name
 	if sch.Solution.Name == "" {
 		if solutionDetectErr == nil && solutionInfo != nil && solutionInfo.Name != "" {
 			sch.Solution.Name = solutionInfo.Name
 			if verbose {
-				fmt.Printf("✓ Auto-detected solution name from solution file: %s\n", solutionInfo.Name)
+				ui.Success("Auto-detected solution name from solution file: %s", solutionInfo.Name)
 			}
 		} else {
 			// Try to get from current directory name
@@ -191,14 +766,17 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 				if dirName != "" && dirName != "." && dirName != "/" {
 					sch.Solution.Name = dirName
 					if verbose {
-						fmt.Printf("✓ Auto-detected solution name from current directory: %s\n", dirName)
+						ui.Success("Auto-detected solution name from current directory: %s", dirName)
 					}
 				}
 			}
 
 			// If still empty, prompt user
 			if sch.Solution.Name == "" {
-				fmt.Print("Solution name not found. Please enter solution name: ")
+				if noInteractive {
+					return fmt.Errorf("solution name is required: it could not be detected from a solution file or the working directory (set solution.name or pass --solutionName)")
+				}
+				fmt.Fprint(out, "Solution name not found. Please enter solution name: ")
 				var solutionName string
 				fmt.Scanln(&solutionName)
 				if solutionName == "" {
@@ -243,11 +821,13 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 		if detectedModuleName != "" {
 			sch.Solution.ModuleName = detectedModuleName
 			if verbose {
-				fmt.Printf("✓ Auto-detected module name from project structure: %s\n", detectedModuleName)
+				ui.Success("Auto-detected module name from project structure: %s", detectedModuleName)
 			}
+		} else if noInteractive {
+			return fmt.Errorf("module name is required: it could not be detected from the solution's project names (set solution.moduleName or pass --moduleName)")
 		} else {
 			// Prompt user for module name
-			fmt.Print("Module name not found. Please enter module name: ")
+			fmt.Fprint(out, "Module name not found. Please enter module name: ")
 			var moduleNameInput string
 			fmt.Scanln(&moduleNameInput)
 			if moduleNameInput == "" {
@@ -258,8 +838,10 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 	}
 
 	// Prompt for module suffix (optional, defaults to "Module")
-	if sch.Solution.ModuleSuffix == "" {
-		fmt.Print("Enter module suffix (e.g., 'Module', 'Service', or leave empty for none) [default: Module]: ")
+	if sch.Solution.ModuleSuffix == "" && noInteractive {
+		sch.Solution.ModuleSuffix = "Module"
+	} else if sch.Solution.ModuleSuffix == "" {
+		fmt.Fprint(out, "Enter module suffix (e.g., 'Module', 'Service', or leave empty for none) [default: Module]: ")
 		var suffixInput string
 		fmt.Scanln(&suffixInput)
 		if suffixInput == "" {
@@ -270,8 +852,8 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 	}
 
 	// Prompt for folder prefix (optional)
-	if sch.Solution.FolderPrefix == "" {
-		fmt.Print("Enter folder prefix (optional, leave empty for none): ")
+	if sch.Solution.FolderPrefix == "" && !noInteractive {
+		fmt.Fprint(out, "Enter folder prefix (optional, leave empty for none): ")
 		var prefixInput string
 		fmt.Scanln(&prefixInput)
 		sch.Solution.FolderPrefix = prefixInput
@@ -357,7 +939,7 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 		if detectedNamespaceRoot != "" {
 			sch.Solution.NamespaceRoot = detectedNamespaceRoot
 			if verbose {
-				fmt.Printf("✓ Auto-detected namespace root: %s\n", detectedNamespaceRoot)
+				ui.Success("Auto-detected namespace root: %s", detectedNamespaceRoot)
 			}
 		}
 		// If still empty, will default to Solution.Name in validator
@@ -370,16 +952,25 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 			if abpVer != "" {
 				sch.Solution.ABPVersion = abpVer + ".0" // Convert "8" to "8.0"
 				if verbose {
-					fmt.Printf("✓ Auto-detected ABP version: %s\n", sch.Solution.ABPVersion)
+					ui.Success("Auto-detected ABP version: %s", sch.Solution.ABPVersion)
 				}
 			}
 		}
 		// If still empty, will default to "9.0" in validator
 	}
 
-	// Detect primary key type (hard to detect, will default to "Guid" in validator)
-	// Can check project files for entity base classes, but that's complex
-	// For now, we'll let it default
+	// Detect primary key type from the base classes of existing entities
+	if sch.Solution.PrimaryKeyType == "" {
+		if solutionInfo != nil {
+			if keyType := solutionInfo.DetectPrimaryKeyType(); keyType != "" {
+				sch.Solution.PrimaryKeyType = keyType
+				if verbose {
+					ui.Success("Auto-detected primary key type from existing entities: %s", keyType)
+				}
+			}
+		}
+		// If still empty, will default to "Guid" in validator
+	}
 
 	// Detect DB provider (can check for MongoDB projects)
 	if sch.Solution.DBProvider == "" {
@@ -397,17 +988,17 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 			if hasMongoDB && hasEFCore {
 				sch.Solution.DBProvider = "both"
 				if verbose {
-					fmt.Printf("✓ Auto-detected database provider: both (EF Core and MongoDB)\n")
+					ui.Success("Auto-detected database provider: both (EF Core and MongoDB)")
 				}
 			} else if hasMongoDB {
 				sch.Solution.DBProvider = "mongodb"
 				if verbose {
-					fmt.Printf("✓ Auto-detected database provider: mongodb\n")
+					ui.Success("Auto-detected database provider: mongodb")
 				}
 			} else if hasEFCore {
 				sch.Solution.DBProvider = "efcore"
 				if verbose {
-					fmt.Printf("✓ Auto-detected database provider: efcore\n")
+					ui.Success("Auto-detected database provider: efcore")
 				}
 			}
 		}
@@ -419,12 +1010,12 @@ func detectAndPromptMissingFields(sch *schema.Schema, solutionInfo *detector.Sol
 
 // applySchemaOverrides applies CLI flag values to schema, overriding schema file values.
 // CLI flags take precedence over schema file values when provided.
-func applySchemaOverrides(sch *schema.Schema) {
+func applySchemaOverrides(cmd *cobra.Command, sch *schema.Schema) {
 	// Override solution name
 	if schemaSolutionName != "" {
 		sch.Solution.Name = schemaSolutionName
 		if verbose {
-			fmt.Printf("✓ Overriding solution name from CLI: %s\n", schemaSolutionName)
+			ui.Success("Overriding solution name from CLI: %s", schemaSolutionName)
 		}
 	}
 
@@ -432,7 +1023,7 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if schemaNamespaceRoot != "" {
 		sch.Solution.NamespaceRoot = schemaNamespaceRoot
 		if verbose {
-			fmt.Printf("✓ Overriding namespace root from CLI: %s\n", schemaNamespaceRoot)
+			ui.Success("Overriding namespace root from CLI: %s", schemaNamespaceRoot)
 		}
 	}
 
@@ -440,7 +1031,7 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if moduleName != "" {
 		sch.Solution.ModuleName = moduleName
 		if verbose {
-			fmt.Printf("✓ Overriding module name from CLI: %s\n", moduleName)
+			ui.Success("Overriding module name from CLI: %s", moduleName)
 		}
 	}
 
@@ -448,7 +1039,7 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if schemaABPVersion != "" {
 		sch.Solution.ABPVersion = schemaABPVersion
 		if verbose {
-			fmt.Printf("✓ Overriding ABP version from CLI: %s\n", schemaABPVersion)
+			ui.Success("Overriding ABP version from CLI: %s", schemaABPVersion)
 		}
 	}
 
@@ -456,7 +1047,7 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if schemaPrimaryKeyType != "" {
 		sch.Solution.PrimaryKeyType = schemaPrimaryKeyType
 		if verbose {
-			fmt.Printf("✓ Overriding primary key type from CLI: %s\n", schemaPrimaryKeyType)
+			ui.Success("Overriding primary key type from CLI: %s", schemaPrimaryKeyType)
 		}
 	}
 
@@ -464,18 +1055,15 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if schemaDBProvider != "" {
 		sch.Solution.DBProvider = schemaDBProvider
 		if verbose {
-			fmt.Printf("✓ Overriding database provider from CLI: %s\n", schemaDBProvider)
+			ui.Success("Overriding database provider from CLI: %s", schemaDBProvider)
 		}
 	}
 
-	// Override generate controllers
-	// Note: Bool flags default to false, so we check if it was explicitly set
-	// by checking the command's flag set. For now, we'll override if true.
-	// Users can set it to false in schema file if they don't want controllers.
-	if schemaGenerateControllers {
-		sch.Solution.GenerateControllers = true
+	// Override generate controllers; a bool flag can't tell unset from false, so check if it was passed
+	if cmd.Flags().Changed("generateControllers") {
+		sch.Solution.GenerateControllers = schemaGenerateControllers
 		if verbose {
-			fmt.Println("✓ Overriding generate controllers from CLI: true")
+			ui.Success("Overriding generate controllers from CLI: %t", schemaGenerateControllers)
 		}
 	}
 
@@ -483,23 +1071,103 @@ func applySchemaOverrides(sch *schema.Schema) {
 	if schemaGenerationMode != "" {
 		sch.Solution.GenerationMode = schema.GenerationMode(schemaGenerationMode)
 		if verbose {
-			fmt.Printf("✓ Overriding generation mode from CLI: %s\n", schemaGenerationMode)
+			ui.Success("Overriding generation mode from CLI: %s", schemaGenerationMode)
+		}
+	}
+
+	// Override seed count; 0 is a meaningful value, so check if the flag was passed
+	if cmd.Flags().Changed("seed-count") {
+		sch.Solution.SeedCount = schemaSeedCount
+		if verbose {
+			ui.Success("Overriding seed count from CLI: %d", schemaSeedCount)
 		}
 	}
 }
 
-func runGenerate() error {
+func runGenerate(cmd *cobra.Command) error {
+	if listGenerators {
+		writeGeneratorList(os.Stdout)
+		return nil
+	}
+
+	if !watch {
+		return generate(cmd)
+	}
+
+	if inputFile == "" {
+		return fmt.Errorf("--watch requires an input schema (pass --input)")
+	}
+	if outputFormat == "json" {
+		return fmt.Errorf("--watch cannot be combined with --format=json")
+	}
+
+	// Later runs regenerate over files written by earlier ones, so merge instead of skipping;
+	// --force and --no-merge still take precedence
+	mergeMode = true
+
+	watched := []string{inputFile}
+	if sch, err := schema.LoadFromFile(inputFile); err == nil {
+		watched = append(watched, sch.IncludedFiles()...)
+	}
+	if templatesPath != "" {
+		watched = append(watched, templatesPath)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	regenerate := func() {
+		// A broken schema mid-edit is reported without ending the watch
+		if err := generate(cmd); err != nil {
+			ui.Warning("Generation failed: %v", err)
+		}
+		fmt.Printf("\nWatching %s for changes (Ctrl+C to stop)...\n", strings.Join(watched, ", "))
+	}
+
+	regenerate()
+	return watchForChanges(ctx, watched, watchDebounce, func() {
+		fmt.Println("\nChange detected, regenerating...")
+		detector.ClearScanCache()
+		regenerate()
+	})
+}
+
+// generate runs a single generation pass for the generate command
+func generate(cmd *cobra.Command) error {
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid --format %q: must be text or json", outputFormat)
+	}
+	if sinceRef != "" && inputFile == "" {
+		return fmt.Errorf("--since requires an input schema (pass --input)")
+	}
+
+	// With --format=json stdout carries only the manifest; progress output goes to stderr
+	out := io.Writer(os.Stdout)
+	if outputFormat == "json" {
+		out = os.Stderr
+
+		mode, err := presenter.ParseMode(colorMode)
+		if err != nil {
+			return err
+		}
+		ui = presenter.New(os.Stderr, mode)
+		prompts.SetOutput(os.Stderr, mode)
+		defer prompts.SetOutput(os.Stdout, mode)
+	}
+
 	// Load or build schema
 	var sch *schema.Schema
 	var err error
 
 	if inputFile != "" {
 		// Load from file
-		fmt.Printf("Loading schema from %s...\n", inputFile)
+		fmt.Fprintf(out, "Loading schema from %s...\n", inputFile)
 		sch, err = schema.LoadFromFile(inputFile)
 		if err != nil {
 			return fmt.Errorf("failed to load schema: %w", err)
 		}
+	} else if noInteractive {
+		return fmt.Errorf("an input schema is required: interactive schema building is disabled by --no-interactive (pass --input)")
 	} else {
 		// Interactive mode
 		sch, err = prompts.BuildSchemaInteractively()
@@ -508,12 +1176,40 @@ func runGenerate() error {
 		}
 	}
 
+	// Diff the schema as written, before the CLI flag overrides are applied
+	var changes *schemaChanges
+	if sinceRef != "" {
+		changes, err = schemaChangesSince(sch, inputFile, sinceRef)
+		if err != nil {
+			return err
+		}
+		if changes.All {
+			ui.Success("Regenerating every entity: %s", changes.Reason)
+		} else if len(changes.Entities) == 0 {
+			ui.Success("No entities changed since %s", sinceRef)
+		} else {
+			ui.Success("Entities changed since %s: %s", sinceRef, strings.Join(changes.sortedNames(), ", "))
+		}
+	}
+
 	// Apply CLI flag overrides to schema (CLI flags take precedence)
-	applySchemaOverrides(sch)
+	applySchemaOverrides(cmd, sch)
+
+	// The early validation defaults the primary key type, so remember whether it is still to be detected
+	detectPrimaryKeyType := sch.Solution.PrimaryKeyType == ""
 
 	// Validate schema early to ensure generationMode is set
-	if err := sch.Validate(); err != nil {
-		return fmt.Errorf("schema validation failed: %w", err)
+	if strict {
+		if err := sch.ValidateStrict(); err != nil {
+			return fmt.Errorf("schema validation failed: %w", err)
+		}
+	} else {
+		if err := sch.Validate(); err != nil {
+			return fmt.Errorf("schema validation failed: %w", err)
+		}
+		for _, collision := range sch.MemberCollisions() {
+			ui.Warning("%s", collision)
+		}
 	}
 
 	var solutionInfo *detector.SolutionInfo
@@ -522,8 +1218,9 @@ func runGenerate() error {
 	// Handle generation mode
 	if sch.Solution.GenerationMode == schema.GenerationModeNew {
 		// For "new" mode, automatically create a solution
-		fmt.Println("\nGeneration mode: new - creating new solution...")
+		fmt.Fprintln(out, "\nGeneration mode: new - creating new solution...")
 		scaffolder := prompts.NewScaffolder()
+		scaffolder.SetNonInteractive(noInteractive)
 		created, newSolutionPath, scaffoldErr := scaffolder.PromptCreateSolution(".", true) // Force auto-scaffold for new mode
 
 		if !created {
@@ -534,13 +1231,14 @@ func runGenerate() error {
 		}
 
 		// Try to detect the newly created solution
+		detector.ClearScanCache()
 		solutionInfo, solutionDetectErr = detector.FindSolution(newSolutionPath)
 		if solutionDetectErr != nil {
 			return fmt.Errorf("failed to detect newly created solution: %w", solutionDetectErr)
 		}
 	} else {
 		// For "existing" mode, try to detect solution
-		fmt.Println("\nGeneration mode: existing - detecting solution structure...")
+		fmt.Fprintln(out, "\nGeneration mode: existing - detecting solution structure...")
 		if solutionPath != "" {
 			solutionInfo, solutionDetectErr = detector.ParseSolution(solutionPath)
 		} else {
@@ -548,7 +1246,10 @@ func runGenerate() error {
 		}
 
 		// Detect and prompt for all missing required fields
-		if err := detectAndPromptMissingFields(sch, solutionInfo, solutionDetectErr); err != nil {
+		if detectPrimaryKeyType {
+			sch.Solution.PrimaryKeyType = ""
+		}
+		if err := detectAndPromptMissingFields(out, sch, solutionInfo, solutionDetectErr); err != nil {
 			return err
 		}
 
@@ -572,6 +1273,7 @@ func runGenerate() error {
 		// If no solution found, offer to create one (only in existing mode)
 		if err != nil {
 			scaffolder := prompts.NewScaffolder()
+			scaffolder.SetNonInteractive(noInteractive)
 			created, newSolutionPath, scaffoldErr := scaffolder.PromptCreateSolution(".", autoScaffold)
 
 			if !created {
@@ -582,6 +1284,7 @@ func runGenerate() error {
 			}
 
 			// Try to detect the newly created solution
+			detector.ClearScanCache()
 			solutionInfo, err = detector.FindSolution(newSolutionPath)
 			if err != nil {
 				return fmt.Errorf("failed to detect newly created solution: %w", err)
@@ -589,30 +1292,30 @@ func runGenerate() error {
 		}
 	}
 
-	fmt.Printf("✓ Found solution: %s\n", solutionInfo.Name)
+	ui.Success("Found solution: %s", solutionInfo.Name)
 
 	// Determine target framework
 	effectiveTarget := targetFramework
 	if effectiveTarget == "auto" || effectiveTarget == "" {
 		effectiveTarget = solutionInfo.TargetFramework
-		fmt.Printf("✓ Auto-detected target framework: %s", effectiveTarget)
+		detected := effectiveTarget
 
 		// Show detected versions for transparency
 		if verbose {
 			abpVer, dotnetVer := detector.ScanProjectsForVersions(solutionInfo)
 			if abpVer != "" {
-				fmt.Printf(" (ABP %s", abpVer)
+				detected += fmt.Sprintf(" (ABP %s", abpVer)
 				if dotnetVer != "" {
-					fmt.Printf(", .NET %s", dotnetVer)
+					detected += fmt.Sprintf(", .NET %s", dotnetVer)
 				}
-				fmt.Printf(")")
+				detected += ")"
 			} else if dotnetVer != "" {
-				fmt.Printf(" (.NET %s)", dotnetVer)
+				detected += fmt.Sprintf(" (.NET %s)", dotnetVer)
 			}
 		}
-		fmt.Println()
+		ui.Success("Auto-detected target framework: %s", detected)
 	} else {
-		fmt.Printf("✓ Using specified target framework: %s\n", effectiveTarget)
+		ui.Success("Using specified target framework: %s", effectiveTarget)
 	}
 
 	// Update schema with target framework if not already set
@@ -633,24 +1336,24 @@ func runGenerate() error {
 			TenantIdProperty:    "TenantId",
 		}
 		if verbose {
-			fmt.Printf("✓ Auto-detected multi-tenancy: %s strategy\n", tenancyStrategy)
+			ui.Success("Auto-detected multi-tenancy: %s strategy", tenancyStrategy)
 		}
 	}
 
 	// Show detected projects in verbose mode
 	if verbose {
-		fmt.Printf("\nDetected projects:\n")
+		fmt.Fprintf(out, "\nDetected projects:\n")
 		for _, project := range solutionInfo.Projects {
 			projectType := string(project.Type)
 			if projectType == "Unknown" {
 				projectType = "Unknown (not recognized as ABP layer)"
 			}
-			fmt.Printf("  - %s (%s)\n", project.Name, projectType)
+			fmt.Fprintf(out, "  - %s (%s)\n", project.Name, projectType)
 		}
 
 		// Show configuration summary
-		fmt.Println("\nConfiguration Summary:")
-		fmt.Print(configScanner.SummarizeConfiguration(solutionInfo))
+		fmt.Fprintln(out, "\nConfiguration Summary:")
+		fmt.Fprint(out, configScanner.SummarizeConfiguration(solutionInfo))
 	}
 
 	// Detect layer paths
@@ -666,6 +1369,18 @@ func runGenerate() error {
 		return fmt.Errorf("failed to detect layer paths: %w", err)
 	}
 
+	// Write into a scratch directory instead of the detected solution
+	if outputDir != "" {
+		if err := paths.Reroot(solutionInfo.RootDirectory, outputDir); err != nil {
+			return fmt.Errorf("failed to apply --output-dir: %w", err)
+		}
+		// A relative localization merge target would otherwise still point into the working tree
+		if lm := sch.Options.LocalizationMerge; lm != nil && lm.TargetPath != "" && !filepath.IsAbs(lm.TargetPath) {
+			lm.TargetPath = filepath.Join(outputDir, lm.TargetPath)
+		}
+		ui.Success("Writing generated files under %s", outputDir)
+	}
+
 	// Get module folder name (with prefix/suffix if configured)
 	moduleFolder := sch.Solution.GetModuleFolderName()
 
@@ -683,9 +1398,21 @@ func runGenerate() error {
 	// Handle merge flags
 	enableMerge := mergeMode && !noMerge && !force
 
+	// Merge prompts cannot be shown while other entities are being generated
+	if parallel && enableMerge && !mergeAll && !mergeBatch && !noInteractive {
+		return fmt.Errorf("--parallel cannot prompt for merge decisions: combine it with --merge-all, --merge-batch or --no-interactive")
+	}
+
 	// Initialize generators with target framework
 	tmplLoader := templates.NewLoaderWithTarget(templatesPath, effectiveTarget)
 	w := writer.NewWriterWithMerge(dryRun, force, verbose, enableMerge)
+	w.SetOutput(out)
+	w.SetNonInteractive(noInteractive)
+	w.SetBackup(backup)
+	w.SetBatchMerge(mergeBatch)
+	if !noHeader {
+		w.SetHeader(generatedHeader(headerText, Version, inputFile))
+	}
 
 	// Configure merge engine with flags if merge is enabled
 	if enableMerge && mergeAll {
@@ -695,196 +1422,97 @@ func runGenerate() error {
 		w.SetMergeAll(true)
 	}
 
-	entityGen := generator.NewEntityGenerator(tmplLoader, w)
-	managerGen := generator.NewManagerGenerator(tmplLoader, w)
-	dtoGen := generator.NewDTOGenerator(tmplLoader, w)
-	validatorGen := generator.NewValidatorGenerator(tmplLoader, w)
-	eventHandlerGen := generator.NewEventHandlerGenerator(tmplLoader, w)
-	serviceGen := generator.NewServiceGenerator(tmplLoader, w)
-	permissionsGen := generator.NewPermissionsGenerator(tmplLoader, w)
-	relationHandler := generator.NewRelationshipHandler()
-	customRepoGen := generator.NewCustomRepositoryGenerator(tmplLoader, w)
-	domainEventsGen := generator.NewDomainEventsGenerator(tmplLoader, w)
-	enumGen := generator.NewEnumGenerator(tmplLoader, w)
-	valueObjectGen := generator.NewValueObjectGenerator(tmplLoader, w)
-	localizationGen := generator.NewLocalizationGenerator(w)
-	integrationTestGen := generator.NewIntegrationTestGenerator(tmplLoader, w)
-
-	var efcoreGen *generator.EFCoreGenerator
-	var mongoGen *generator.MongoDBGenerator
-
-	if sch.Solution.DBProvider == "efcore" || sch.Solution.DBProvider == "both" {
-		efcoreGen = generator.NewEFCoreGenerator(tmplLoader, w)
-	}
-
-	if sch.Solution.DBProvider == "mongodb" || sch.Solution.DBProvider == "both" {
-		mongoGen = generator.NewMongoDBGenerator(tmplLoader, w)
+	generators := generator.NewGenerators(tmplLoader, w, sch)
+	if err := generators.Select(splitGeneratorNames(onlyGenerators), splitGeneratorNames(skipGenerators)); err != nil {
+		return err
 	}
+	relationHandler := generator.NewRelationshipHandler()
 
 	// Print merge mode status
 	if enableMerge {
-		fmt.Println("\n✓ Smart merge mode enabled - existing files will be merged intelligently")
+		fmt.Fprintln(out)
+		ui.Success("Smart merge mode enabled - existing files will be merged intelligently")
 	} else if force {
-		fmt.Println("\n⚠️  Force mode enabled - existing files will be overwritten")
+		fmt.Fprintln(out)
+		ui.Warning("Force mode enabled - existing files will be overwritten")
 	} else {
-		fmt.Println("\n✓ Safe mode - existing files will be skipped")
+		fmt.Fprintln(out)
+		ui.Success("Safe mode - existing files will be skipped")
 	}
 
 	// Generate test project if integration tests are enabled
-	if sch.Options.GenerateIntegrationTests {
-		fmt.Println("\n✓ Integration tests enabled - generating test infrastructure")
-		if err := integrationTestGen.GenerateTestProject(sch, paths); err != nil {
-			fmt.Printf("⚠️  Failed to generate test project: %v\n", err)
+	if sch.Options.GenerateIntegrationTests && generators.Enabled("integration-tests") {
+		fmt.Fprintln(out)
+		ui.Success("Integration tests enabled - generating test infrastructure")
+		if err := generators.IntegrationTest.GenerateTestProject(sch, paths); err != nil {
+			ui.Warning("Failed to generate test project: %v", err)
 		}
 	}
 
-	// Generate code for each entity
-	fmt.Printf("\nGenerating code for %d entity(s)...\n\n", len(sch.Entities))
-
-	for i, entity := range sch.Entities {
-		fmt.Printf("[%d/%d] Generating %s...\n", i+1, len(sch.Entities), entity.Name)
-
-		// Process relationships
-		if err := relationHandler.ProcessRelationships(sch, &entity); err != nil {
-			return fmt.Errorf("failed to process relationships for %s: %w", entity.Name, err)
-		}
+	// Generate code for each entity; module-scoped generators below still see the whole schema
+	entities := changes.selectEntities(sch.Entities)
+	fmt.Fprintf(out, "\nGenerating code for %d entity(s)...\n\n", len(entities))
 
-		// Generate enums if defined
-		if err := enumGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate enums for %s: %w", entity.Name, err)
+	if parallel {
+		if err := generateEntitiesParallel(out, sch, entities, generators, relationHandler, paths); err != nil {
+			return err
 		}
+	} else {
+		for i, entity := range entities {
+			fmt.Fprintf(out, "[%d/%d] Generating %s...\n", i+1, len(entities), entity.Name)
 
-		// Generate value object or entity
-		if entity.EntityType == "ValueObject" {
-			if err := valueObjectGen.Generate(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate value object %s: %w", entity.Name, err)
-			}
-			if err := valueObjectGen.GenerateFactory(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate value object factory for %s: %w", entity.Name, err)
+			// Process relationships
+			if err := relationHandler.ProcessRelationships(sch, &entity); err != nil {
+				return fmt.Errorf("failed to process relationships for %s: %w", entity.Name, err)
 			}
-		} else {
-			// Generate entity and related files
-			if err := entityGen.Generate(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate entity %s: %w", entity.Name, err)
-			}
-		}
-
-		if err := entityGen.GenerateRepository(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate repository for %s: %w", entity.Name, err)
-		}
 
-		// Generate custom repository if defined
-		if err := customRepoGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate custom repository for %s: %w", entity.Name, err)
-		}
-
-		// Generate domain events if defined
-		if err := domainEventsGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate domain events for %s: %w", entity.Name, err)
-		}
-
-		if err := managerGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate manager for %s: %w", entity.Name, err)
-		}
-
-		if err := entityGen.GenerateConstants(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate constants for %s: %w", entity.Name, err)
-		}
-
-		if err := entityGen.GenerateEvents(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate events for %s: %w", entity.Name, err)
-		}
-
-		if err := entityGen.GenerateDataSeeder(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate data seeder for %s: %w", entity.Name, err)
-		}
-
-		// Generate DTOs
-		if err := dtoGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate DTOs for %s: %w", entity.Name, err)
-		}
-
-		if err := dtoGen.GenerateAppServiceInterface(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate app service interface for %s: %w", entity.Name, err)
-		}
-
-		// Generate validators
-		if err := validatorGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate validators for %s: %w", entity.Name, err)
-		}
-
-		// Generate service
-		if err := serviceGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate service for %s: %w", entity.Name, err)
-		}
-
-		// Generate mapper based on mapping library setting
-		if sch.Options.MappingLibrary == "mapperly" {
-			if err := serviceGen.GenerateMapperlyProfile(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate Mapperly profile for %s: %w", entity.Name, err)
+			if err := generators.RunForEntity(sch, &entity, paths); err != nil {
+				return err
 			}
-		} else {
-			if err := serviceGen.GenerateAutoMapperProfile(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate AutoMapper profile for %s: %w", entity.Name, err)
-			}
-		}
-
-		if err := serviceGen.GenerateController(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate controller for %s: %w", entity.Name, err)
-		}
 
-		// Generate permissions
-		if err := permissionsGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate permissions for %s: %w", entity.Name, err)
-		}
-
-		if err := permissionsGen.GenerateLocalization(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate localization for %s: %w", entity.Name, err)
-		}
-
-		// Generate and merge localization files
-		if err := localizationGen.GenerateEntityLocalization(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate entity localization for %s: %w", entity.Name, err)
-		}
-
-		// Generate event handlers
-		if err := eventHandlerGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate event handlers for %s: %w", entity.Name, err)
+			ui.Success("Generated %s", entity.Name)
+			fmt.Fprintln(out)
 		}
+	}
 
-		// Generate EF Core files
-		if efcoreGen != nil {
-			if err := efcoreGen.Generate(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate EF Core files for %s: %w", entity.Name, err)
-			}
-		}
+	// Generate module-scoped artifacts such as background workers
+	if len(sch.Solution.Workers) > 0 {
+		fmt.Fprintf(out, "Generating %d background worker(s)...\n", len(sch.Solution.Workers))
+	}
+	if err := generators.RunForModule(sch, paths); err != nil {
+		return err
+	}
 
-		// Generate MongoDB files
-		if mongoGen != nil {
-			if err := mongoGen.Generate(sch, &entity, paths); err != nil {
-				return fmt.Errorf("failed to generate MongoDB files for %s: %w", entity.Name, err)
-			}
-		}
+	// With --merge-batch, merges of existing files were only queued so far
+	if err := w.FlushMerges(); err != nil {
+		return err
+	}
 
-		// Generate integration tests
-		if err := integrationTestGen.Generate(sch, &entity, paths); err != nil {
-			return fmt.Errorf("failed to generate integration tests for %s: %w", entity.Name, err)
+	// Keep the operation log so the run can be reverted with undo
+	if !dryRun {
+		if err := w.SaveRunLog(writer.RunLogPath); err != nil {
+			ui.Warning("Failed to save run log, undo will not be available: %v", err)
 		}
-
-		fmt.Printf("✓ Generated %s\n\n", entity.Name)
 	}
 
 	// Print summary
 	w.PrintSummary()
 
+	if outputFormat == "json" {
+		if err := writeManifest(os.Stdout, w.Manifest()); err != nil {
+			return err
+		}
+	}
+
 	if dryRun {
-		fmt.Println("\nTo apply these changes, run the command without --dry-run")
+		fmt.Fprintln(out, "\nTo apply these changes, run the command without --dry-run")
 	} else {
-		fmt.Println("\n✓ Code generation completed successfully!")
-		fmt.Println("\nNext steps:")
-		fmt.Println("  1. Add database migration: dotnet ef migrations add Add<EntityName>")
-		fmt.Println("  2. Update database: dotnet ef database update")
-		fmt.Println("  3. Build solution: dotnet build")
+		fmt.Fprintln(out)
+		ui.Success("Code generation completed successfully!")
+		fmt.Fprintln(out, "\nNext steps:")
+		fmt.Fprintln(out, "  1. Add database migration: dotnet ef migrations add Add<EntityName>")
+		fmt.Fprintln(out, "  2. Update database: dotnet ef database update")
+		fmt.Fprintln(out, "  3. Build solution: dotnet build")
 	}
 
 	return nil
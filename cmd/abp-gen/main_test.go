@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/generator"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+func TestDetectAndPromptMissingFields_NoInteractiveMissingModule(t *testing.T) {
+	noInteractive = true
+	defer func() { noInteractive = false }()
+
+	sch := &schema.Schema{Solution: schema.Solution{Name: "Shop"}}
+	solutionInfo := &detector.SolutionInfo{
+		Name: "Shop",
+		Projects: []detector.ProjectInfo{
+			{Name: "Shop.Domain", Type: detector.ProjectTypeDomain},
+			{Name: "Shop.Application", Type: detector.ProjectTypeApplication},
+		},
+	}
+
+	err := detectAndPromptMissingFields(io.Discard, sch, solutionInfo, nil)
+	if err == nil {
+		t.Fatal("detectAndPromptMissingFields() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "module name is required") {
+		t.Errorf("detectAndPromptMissingFields() error = %q; want it to mention %q", err, "module name is required")
+	}
+}
+
+func TestDetectAndPromptMissingFields_NoInteractiveDefaults(t *testing.T) {
+	noInteractive = true
+	defer func() { noInteractive = false }()
+
+	sch := &schema.Schema{Solution: schema.Solution{Name: "Shop", ModuleName: "Catalog"}}
+
+	if err := detectAndPromptMissingFields(io.Discard, sch, &detector.SolutionInfo{Name: "Shop"}, nil); err != nil {
+		t.Fatalf("detectAndPromptMissingFields() error = %v", err)
+	}
+	if sch.Solution.ModuleSuffix != "Module" {
+		t.Errorf("ModuleSuffix = %q; want %q", sch.Solution.ModuleSuffix, "Module")
+	}
+	if sch.Solution.FolderPrefix != "" {
+		t.Errorf("FolderPrefix = %q; want empty", sch.Solution.FolderPrefix)
+	}
+}
+
+func TestWriteGeneratorList(t *testing.T) {
+	var out strings.Builder
+	writeGeneratorList(&out)
+	list := out.String()
+
+	expected := []string{
+		"dto (per-entity)",
+		"Layers:  Application.Contracts\n",
+		"efcore (per-entity)",
+		"Layers:  EntityFrameworkCore, Domain.Shared\n",
+		"worker (per-module)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(list, want) {
+			t.Errorf("generator list missing %q:\n%s", want, list)
+		}
+	}
+}
+
+func TestWriteTemplateList(t *testing.T) {
+	custom := t.TempDir()
+	for name, content := range map[string]string{
+		"abp9-microservice/entity.tmpl": "namespace {{.NamespaceRoot}};",
+		"common/manager.tmpl":           "{{.Broken",
+	} {
+		path := filepath.Join(custom, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	loader := templates.NewLoaderWithTarget(custom, "abp9-microservice")
+	if err := writeTemplateList(&out, loader, "abp9-microservice"); err != nil {
+		t.Fatalf("writeTemplateList() error = %v", err)
+	}
+	list := out.String()
+
+	lines := make(map[string]string)
+	for _, line := range strings.Split(list, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 3 {
+			lines[fields[0]] = strings.Join(fields[1:], " ")
+		}
+	}
+	if got, want := lines["entity.tmpl"], "custom "+filepath.Join(custom, "abp9-microservice", "entity.tmpl"); got != want {
+		t.Errorf("entity.tmpl resolves from %q, want %q", got, want)
+	}
+	if got, want := lines["manager.tmpl"], "embedded manager.tmpl"; got != want {
+		t.Errorf("manager.tmpl resolves from %q, want %q", got, want)
+	}
+	if got := lines["skipped"]; !strings.HasPrefix(got, "custom "+filepath.Join(custom, "common", "manager.tmpl")+": template:") {
+		t.Errorf("template list does not report the broken custom manager.tmpl:\n%s", list)
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	var out strings.Builder
+	printError(&out, errors.New("failed to load schema"))
+	if got := out.String(); got != "Error: failed to load schema\n" {
+		t.Errorf("printError() = %q", got)
+	}
+
+	out.Reset()
+	printError(&out, fmt.Errorf("wrapped: %w", &generator.GenerationError{
+		Generator: "entity",
+		Entity:    "Product",
+		Template:  "entity.tmpl",
+		Err:       errors.New("failed to execute entity template"),
+	}))
+	want := "Error: failed to execute entity template\n" +
+		"  Generator: entity\n" +
+		"  Entity:    Product\n" +
+		"  Template:  entity.tmpl\n"
+	if got := out.String(); got != want {
+		t.Errorf("printError() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	w := writer.NewWriter(true, false, false)
+	path := filepath.Join(t.TempDir(), "Product.cs")
+	if err := w.WriteFile(path, "public class Product {}"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var out strings.Builder
+	if err := writeManifest(&out, w.Manifest()); err != nil {
+		t.Fatalf("writeManifest() error = %v", err)
+	}
+
+	var manifest []struct {
+		Path      string `json:"path"`
+		Operation string `json:"operation"`
+		Bytes     int    `json:"bytes"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &manifest); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v\n%s", err, out.String())
+	}
+	if len(manifest) != 1 || manifest[0].Path != path || manifest[0].Operation != "CREATE" || manifest[0].Bytes != len("public class Product {}") {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if strings.Contains(out.String(), "public class Product") {
+		t.Errorf("manifest includes file content:\n%s", out.String())
+	}
+}
+
+func TestApplyConfigDefaults(t *testing.T) {
+	var namespaceRoot, primaryKeyType, only string
+	var controllers bool
+
+	root := &cobra.Command{Use: "abp-gen"}
+	generate := &cobra.Command{Use: "generate"}
+	generate.Flags().StringVar(&namespaceRoot, "namespaceRoot", "", "")
+	generate.Flags().StringVar(&primaryKeyType, "primaryKeyType", "", "")
+	generate.Flags().StringVar(&only, "only", "", "")
+	generate.Flags().BoolVar(&controllers, "generateControllers", false, "")
+	format := &cobra.Command{Use: "format"}
+	format.Flags().Bool("canonical", false, "")
+	root.AddCommand(generate, format)
+
+	if err := generate.ParseFlags([]string{"--primaryKeyType", "long"}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := map[string]interface{}{
+		"namespaceRoot":       "Acme.Shop",
+		"primaryKeyType":      "Guid",
+		"only":                []interface{}{"entity", "dto"},
+		"generateControllers": true,
+		"canonical":           true, // Belongs to another command
+	}
+	if err := applyConfigDefaults(generate, config); err != nil {
+		t.Fatalf("applyConfigDefaults() error = %v", err)
+	}
+
+	if namespaceRoot != "Acme.Shop" {
+		t.Errorf("namespaceRoot = %q, want value from config", namespaceRoot)
+	}
+	if primaryKeyType != "long" {
+		t.Errorf("primaryKeyType = %q, want command-line value to win", primaryKeyType)
+	}
+	if only != "entity,dto" {
+		t.Errorf("only = %q, want comma-separated list", only)
+	}
+	if !controllers {
+		t.Error("generateControllers = false, want true from config")
+	}
+
+	err := applyConfigDefaults(generate, map[string]interface{}{"namespaceRot": "Acme"})
+	if err == nil || !strings.Contains(err.Error(), `unknown option "namespaceRot"`) {
+		t.Errorf("applyConfigDefaults() with unknown key error = %v", err)
+	}
+}
+
+func TestApplySchemaOverrides_GenerateControllers(t *testing.T) {
+	defer func() { schemaGenerateControllers = false }()
+
+	tests := []struct {
+		args   []string
+		schema bool
+		want   bool
+	}{
+		{nil, true, true},
+		{nil, false, false},
+		{[]string{"--generateControllers=false"}, true, false},
+		{[]string{"--generateControllers"}, false, true},
+	}
+
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "generate"}
+		cmd.Flags().BoolVar(&schemaGenerateControllers, "generateControllers", false, "")
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+
+		sch := &schema.Schema{Solution: schema.Solution{GenerateControllers: tt.schema}}
+		applySchemaOverrides(cmd, sch)
+
+		if sch.Solution.GenerateControllers != tt.want {
+			t.Errorf("args %v with schema value %t: GenerateControllers = %t, want %t", tt.args, tt.schema, sch.Solution.GenerateControllers, tt.want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+// schemaChanges describes what changed in a schema since a git ref
+type schemaChanges struct {
+	All      bool            // Every entity is regenerated, for the reason given by Reason
+	Reason   string          // Why every entity is regenerated
+	Entities map[string]bool // Entities to regenerate when not All
+}
+
+// sortedNames returns the entities to regenerate in alphabetical order
+func (c *schemaChanges) sortedNames() []string {
+	names := make([]string, 0, len(c.Entities))
+	for name := range c.Entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectEntities returns the entities to regenerate, in schema order. A nil receiver, when
+// --since is not given, selects every entity.
+func (c *schemaChanges) selectEntities(entities []schema.Entity) []schema.Entity {
+	if c == nil || c.All {
+		return entities
+	}
+	var selected []schema.Entity
+	for _, entity := range entities {
+		if c.Entities[entity.Name] {
+			selected = append(selected, entity)
+		}
+	}
+	return selected
+}
+
+// schemaChangesSince compares sch, loaded from path, with the version of the schema committed at
+// ref. Added and modified entities are regenerated together with the entities related to them,
+// since relations generate code on both sides. Changes outside the entities, such as to the
+// solution or options, affect every entity.
+func schemaChangesSince(sch *schema.Schema, path, ref string) (*schemaChanges, error) {
+	previous, err := loadSchemaAtRef(sch, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return &schemaChanges{All: true, Reason: fmt.Sprintf("%s is not committed at %s", path, ref)}, nil
+	}
+	return diffSchemas(previous, sch, ref)
+}
+
+// diffSchemas lists the entities of current to regenerate after the changes since previous
+func diffSchemas(previous, current *schema.Schema, ref string) (*schemaChanges, error) {
+	previousSettings, err := schemaSettings(previous)
+	if err != nil {
+		return nil, err
+	}
+	currentSettings, err := schemaSettings(current)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(previousSettings, currentSettings) {
+		return &schemaChanges{All: true, Reason: fmt.Sprintf("the solution or options changed since %s", ref)}, nil
+	}
+
+	previousEntities := make(map[string][]byte)
+	for _, entity := range previous.Entities {
+		data, err := json.Marshal(entity)
+		if err != nil {
+			return nil, err
+		}
+		previousEntities[entity.Name] = data
+	}
+
+	changed := make(map[string]bool)
+	for _, entity := range current.Entities {
+		data, err := json.Marshal(entity)
+		if err != nil {
+			return nil, err
+		}
+		if before, ok := previousEntities[entity.Name]; !ok || !bytes.Equal(before, data) {
+			changed[entity.Name] = true
+		}
+		delete(previousEntities, entity.Name)
+	}
+
+	// Entities related to a changed or removed one regenerate their side of the relation
+	changes := &schemaChanges{Entities: make(map[string]bool)}
+	for name := range changed {
+		changes.Entities[name] = true
+	}
+	related := relatedEntities(current)
+	for name := range changed {
+		for other := range related[name] {
+			changes.Entities[other] = true
+		}
+	}
+	previousRelated := relatedEntities(previous)
+	for name := range previousEntities {
+		for other := range previousRelated[name] {
+			if current.FindEntity(other) != nil {
+				changes.Entities[other] = true
+			}
+		}
+	}
+	return changes, nil
+}
+
+// schemaSettings encodes everything in a schema but its entities
+func schemaSettings(sch *schema.Schema) ([]byte, error) {
+	settings := *sch
+	settings.SchemaURI = ""
+	settings.Entities = nil
+	return json.Marshal(settings)
+}
+
+// relatedEntities maps each entity to the entities it is linked to, in either direction, through
+// foreign keys, relations or inheritance
+func relatedEntities(sch *schema.Schema) map[string]map[string]bool {
+	related := make(map[string]map[string]bool)
+	link := func(a, b string) {
+		if a == "" || b == "" || a == b {
+			return
+		}
+		for _, pair := range [][2]string{{a, b}, {b, a}} {
+			if related[pair[0]] == nil {
+				related[pair[0]] = make(map[string]bool)
+			}
+			related[pair[0]][pair[1]] = true
+		}
+	}
+
+	for _, entity := range sch.Entities {
+		link(entity.Name, entity.BaseEntity)
+		for _, prop := range entity.Properties {
+			if prop.IsForeignKey {
+				link(entity.Name, prop.TargetEntity)
+			}
+		}
+		if entity.Relations == nil {
+			continue
+		}
+		for _, rel := range entity.Relations.OneToOne {
+			link(entity.Name, rel.TargetEntity)
+		}
+		for _, rel := range entity.Relations.OneToMany {
+			link(entity.Name, rel.TargetEntity)
+		}
+		for _, rel := range entity.Relations.ManyToOne {
+			link(entity.Name, rel.TargetEntity)
+		}
+		for _, rel := range entity.Relations.ManyToMany {
+			link(entity.Name, rel.TargetEntity)
+			link(entity.Name, rel.JoinEntity)
+		}
+	}
+
+	// Derived entities build on everything their base generates, however deep the hierarchy
+	for i := range sch.Entities {
+		entity := &sch.Entities[i]
+		for _, derived := range sch.GetDerivedEntities(entity) {
+			link(entity.Name, derived.Name)
+		}
+	}
+	return related
+}
+
+// loadSchemaAtRef loads the version of the schema at path committed at ref. The schema file and
+// the files it currently includes are read from git into a temporary copy of the repository
+// layout; included files that did not exist at ref are left out, so their entities count as
+// added. It returns nil when the schema file itself is not committed at ref.
+func loadSchemaAtRef(sch *schema.Schema, path, ref string) (*schema.Schema, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(absPath)
+
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("--since: %q is not a git commit: %w", ref, err)
+	}
+	topLevel, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("--since: %s is not in a git repository: %w", path, err)
+	}
+	topLevel = strings.TrimSpace(topLevel)
+
+	scratch, err := os.MkdirTemp("", "abp-gen-since-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(scratch)
+
+	var schemaCopy string
+	for i, file := range append([]string{absPath}, sch.IncludedFiles()...) {
+		rel, err := repoRelativePath(topLevel, file)
+		if err != nil {
+			return nil, err
+		}
+		content, err := git(topLevel, "show", ref+":"+rel)
+		if err != nil {
+			// Not committed at ref
+			if i == 0 {
+				return nil, nil
+			}
+			continue
+		}
+
+		copyPath := filepath.Join(scratch, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(copyPath), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(copyPath, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			schemaCopy = copyPath
+		}
+	}
+
+	previous, err := schema.LoadFromFile(schemaCopy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema at %s: %w", ref, err)
+	}
+	return previous, nil
+}
+
+// repoRelativePath returns file relative to the repository root topLevel, in git's slash form.
+// Symbolic links, such as a temporary directory under /var on macOS, are resolved on both sides.
+func repoRelativePath(topLevel, file string) (string, error) {
+	if resolved, err := filepath.EvalSymlinks(file); err == nil {
+		file = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(topLevel); err == nil {
+		topLevel = resolved
+	}
+	rel, err := filepath.Rel(topLevel, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("--since: %s is outside the git repository %s", file, topLevel)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// git runs a git command in dir and returns its standard output
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	previous := &schema.Schema{
+		Solution: schema.Solution{Name: "Shop", ModuleName: "Catalog"},
+		Entities: []schema.Entity{
+			{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string"}}},
+			{Name: "Product", Properties: []schema.Property{
+				{Name: "Name", Type: "string"},
+				{Name: "Category", Type: "Guid", IsForeignKey: true, TargetEntity: "Category"},
+			}},
+			{Name: "Tag", Properties: []schema.Property{{Name: "Label", Type: "string"}}},
+			{Name: "Coupon", Properties: []schema.Property{{Name: "Code", Type: "string"}}},
+			{Name: "Promotion", Relations: &schema.Relations{
+				ManyToOne: []schema.ManyToOneRelation{{TargetEntity: "Coupon"}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		change  func(sch *schema.Schema)
+		want    string
+		wantAll bool
+	}{
+		{
+			name:   "unchanged",
+			change: func(sch *schema.Schema) {},
+			want:   "",
+		},
+		{
+			name: "modified entity and the entities related to it",
+			change: func(sch *schema.Schema) {
+				sch.Entities[0].Properties = append(sch.Entities[0].Properties, schema.Property{Name: "Slug", Type: "string"})
+			},
+			want: "Category,Product",
+		},
+		{
+			name: "added entity",
+			change: func(sch *schema.Schema) {
+				sch.Entities = append(sch.Entities, schema.Entity{Name: "Review", BaseEntity: "Tag"})
+			},
+			want: "Review,Tag",
+		},
+		{
+			name: "removed entities without related ones left",
+			change: func(sch *schema.Schema) {
+				sch.Entities = sch.Entities[:3]
+			},
+			want: "",
+		},
+		{
+			name: "removed relation target",
+			change: func(sch *schema.Schema) {
+				sch.Entities = append(sch.Entities[:3], sch.Entities[4])
+			},
+			want: "Promotion",
+		},
+		{
+			name: "solution change",
+			change: func(sch *schema.Schema) {
+				sch.Solution.ModuleName = "Sales"
+			},
+			wantAll: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := *previous
+			current.Entities = append([]schema.Entity(nil), previous.Entities...)
+			tt.change(&current)
+
+			changes, err := diffSchemas(previous, &current, "HEAD")
+			if err != nil {
+				t.Fatalf("diffSchemas() error = %v", err)
+			}
+			if changes.All != tt.wantAll {
+				t.Fatalf("diffSchemas() All = %v, want %v (%s)", changes.All, tt.wantAll, changes.Reason)
+			}
+			if tt.wantAll {
+				return
+			}
+			if got := strings.Join(changes.sortedNames(), ","); got != tt.want {
+				t.Errorf("diffSchemas() entities = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaChangesSince_ReadsSchemaFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := git(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "--quiet")
+	writeFile("schema.json", `{"solution": {"name": "Shop", "moduleName": "Catalog"}, "$include": ["entities/*.json"], "entities": [
+  {"name": "Product", "properties": [{"name": "Name", "type": "string"}]},
+  {"name": "Tag", "properties": [{"name": "Label", "type": "string"}]}
+]}`)
+	writeFile("entities/category.json", `{"name": "Category", "properties": [{"name": "Title", "type": "string"}]}`)
+	runGit("add", "-A")
+	runGit("commit", "--quiet", "-m", "Add schema")
+
+	writeFile("schema.json", `{"solution": {"name": "Shop", "moduleName": "Catalog"}, "$include": ["entities/*.json"], "entities": [
+  {"name": "Product", "properties": [{"name": "Name", "type": "string"}, {"name": "Price", "type": "decimal"}]},
+  {"name": "Tag", "properties": [{"name": "Label", "type": "string"}]}
+]}`)
+	writeFile("entities/brand.json", `{"name": "Brand", "properties": [{"name": "Title", "type": "string"}]}`)
+
+	path := filepath.Join(dir, "schema.json")
+	sch, err := schema.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := schemaChangesSince(sch, path, "HEAD")
+	if err != nil {
+		t.Fatalf("schemaChangesSince() error = %v", err)
+	}
+	if got, want := strings.Join(changes.sortedNames(), ","), "Brand,Product"; changes.All || got != want {
+		t.Errorf("schemaChangesSince() = %q (all %v), want %q", got, changes.All, want)
+	}
+
+	if _, err := schemaChangesSince(sch, path, "no-such-ref"); err == nil || !strings.Contains(err.Error(), "is not a git commit") {
+		t.Errorf("schemaChangesSince() with an unknown ref error = %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long watched files must stay unchanged before regenerating,
+// so editors that save in several steps trigger a single run
+const watchDebounce = 500 * time.Millisecond
+
+// watchSet decides which file system events concern the watched paths
+type watchSet struct {
+	files map[string]bool
+	dirs  []string
+}
+
+// newWatchSet splits paths into watched files and watched directory trees
+func newWatchSet(paths []string) *watchSet {
+	set := &watchSet{files: make(map[string]bool)}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			set.dirs = append(set.dirs, path)
+		} else {
+			set.files[path] = true
+		}
+	}
+	return set
+}
+
+// matches reports whether path is a watched file or lies in a watched directory
+func (s *watchSet) matches(path string) bool {
+	path = filepath.Clean(path)
+	if s.files[path] {
+		return true
+	}
+	for _, dir := range s.dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// addWatches registers the parent directory of every watched file, so files replaced by
+// editors on save are still seen, and every directory of the watched directory trees
+func (s *watchSet) addWatches(watcher *fsnotify.Watcher) error {
+	for file := range s.files {
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", file, err)
+		}
+	}
+	for _, dir := range s.dirs {
+		if err := addDirectoryTree(watcher, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDirectoryTree registers root and its subdirectories, as fsnotify does not watch recursively
+func addDirectoryTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// watchForChanges watches paths for file system events and calls onChange once they have
+// stayed unchanged for debounce after a modification. It returns when ctx is done.
+func watchForChanges(ctx context.Context, paths []string, debounce time.Duration, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	set := newWatchSet(paths)
+	if err := set.addWatches(watcher); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod || !set.matches(event.Name) {
+				continue
+			}
+			// Directories created inside a watched tree are watched too
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = addDirectoryTree(watcher, event.Name)
+				}
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			ui.Warning("File watcher error: %v", err)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
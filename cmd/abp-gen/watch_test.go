@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchForChanges_DebouncesSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		if err := watchForChanges(ctx, []string{path}, 100*time.Millisecond, func() {
+			changes <- struct{}{}
+		}); err != nil {
+			t.Errorf("watchForChanges() error = %v", err)
+		}
+		close(done)
+	}()
+
+	// Let the watcher register, then save twice in quick succession
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := os.WriteFile(path, []byte(`{"entities":[]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watchForChanges() did not report the change")
+	}
+	select {
+	case <-changes:
+		t.Error("watchForChanges() reported rapid saves more than once")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watchForChanges() did not return after the context was cancelled")
+	}
+}
+
+func TestWatchForChanges_ReplacedFilesAndTemplateDirectories(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "schema.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templatesDir := filepath.Join(root, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 10)
+	go func() {
+		_ = watchForChanges(ctx, []string{path, templatesDir}, 20*time.Millisecond, func() {
+			changes <- struct{}{}
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	expectChange := func(what string) {
+		t.Helper()
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("watchForChanges() did not report %s", what)
+		}
+	}
+
+	// Editors often save by writing a temporary file and renaming it over the original
+	tmp := filepath.Join(root, ".schema.json.tmp")
+	if err := os.WriteFile(tmp, []byte(`{"entities":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("a replaced schema file")
+
+	// A template written in a subdirectory created after the watch started
+	subDir := filepath.Join(templatesDir, "custom")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("a new template directory")
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(subDir, "entity.tmpl"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("a template in a new subdirectory")
+
+	// Unrelated files next to the schema are ignored
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Error("watchForChanges() reported a change to an unwatched file")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,16 @@
+// Package csharp holds the C# naming and type rules shared by the schema and the templates
+package csharp
+
+import "github.com/gertd/go-pluralize"
+
+var pluralizeClient = pluralize.NewClient()
+
+// Pluralize converts singular to plural
+func Pluralize(word string) string {
+	return pluralizeClient.Plural(word)
+}
+
+// Singularize converts plural to singular
+func Singularize(word string) string {
+	return pluralizeClient.Singular(word)
+}
@@ -0,0 +1,48 @@
+package csharp
+
+import "strings"
+
+// Nullable adds nullable marker for nullable types
+func Nullable(typeName string, isNullable bool) string {
+	if !isNullable {
+		return typeName
+	}
+
+	if valueTypes[typeName] {
+		return typeName + "?"
+	}
+
+	// Reference types (string, custom types) don't need ?
+	return typeName
+}
+
+// valueTypes lists the built-in C# value types that need a nullable marker
+var valueTypes = map[string]bool{
+	"int": true, "long": true, "decimal": true,
+	"DateTime": true, "bool": true, "Guid": true,
+	"byte": true, "short": true, "float": true,
+	"double": true, "DateTimeOffset": true, "TimeSpan": true,
+	"DateOnly": true, "TimeOnly": true, "char": true,
+	"sbyte": true, "ushort": true, "uint": true, "ulong": true,
+}
+
+// TypeInfo describes the declared C# type of a property
+type TypeInfo struct {
+	Name        string // C# type name
+	Nullable    bool   // Whether the property is nullable
+	IsValueType bool   // Value type not known by name, such as an enum
+}
+
+// TypedProperty is implemented by properties that can be rendered with Type
+type TypedProperty interface {
+	CSharpTypeInfo() TypeInfo
+}
+
+// Type renders a property's C# type, adding the nullable marker for nullable value types
+func Type(prop TypedProperty) string {
+	info := prop.CSharpTypeInfo()
+	if info.Nullable && info.IsValueType && !strings.HasSuffix(info.Name, "?") {
+		return info.Name + "?"
+	}
+	return Nullable(info.Name, info.Nullable)
+}
@@ -63,34 +63,11 @@ func (s *ConfigScanner) DetectMultiTenancy(solutionInfo *SolutionInfo) (enabled
 
 // findAppSettingsFiles recursively finds appsettings*.json files
 func (s *ConfigScanner) findAppSettingsFiles(rootDir string) []string {
-	var files []string
-
-	// Common locations for appsettings
-	searchPaths := []string{
-		filepath.Join(rootDir, "appsettings.json"),
-		filepath.Join(rootDir, "src", "**", "appsettings*.json"),
-		filepath.Join(rootDir, "**", "appsettings*.json"),
-	}
-
-	for _, pattern := range searchPaths {
-		matches, err := filepath.Glob(pattern)
-		if err == nil {
-			files = append(files, matches...)
-		}
-	}
-
-	// Also check each project directory
-	filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if !info.IsDir() && strings.HasPrefix(info.Name(), "appsettings") && strings.HasSuffix(info.Name(), ".json") {
-			files = append(files, path)
-		}
+	scan, err := ScanDirectory(rootDir)
+	if err != nil {
 		return nil
-	})
-
-	return files
+	}
+	return scan.AppSettingsFiles
 }
 
 // parseAppSettings parses an appsettings.json file for multi-tenancy config
@@ -36,8 +36,26 @@ type ItemGroup struct {
 
 // PackageReference represents a NuGet package reference
 type PackageReference struct {
-	Include string `xml:"Include,attr"`
-	Version string `xml:"Version,attr"`
+	Include         string `xml:"Include,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionOverride string `xml:"VersionOverride,attr"` // Central Package Management per-project override
+}
+
+// PackagesProps represents a Directory.Packages.props file of Central Package Management
+type PackagesProps struct {
+	XMLName       xml.Name `xml:"Project"`
+	PropertyGroup []struct {
+		Properties []MSBuildProperty `xml:",any"`
+	} `xml:"PropertyGroup"`
+	ItemGroup []struct {
+		PackageVersion []PackageReference `xml:"PackageVersion"`
+	} `xml:"ItemGroup"`
+}
+
+// MSBuildProperty is a property such as <AbpVersion>8.3.0</AbpVersion>
+type MSBuildProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
 }
 
 // ProjectReference represents a project reference
@@ -83,19 +101,89 @@ func DetectABPVersion(csprojPath string) string {
 		return ""
 	}
 
-	// Look for Volo.Abp package references
+	// Look for Volo.Abp package references. With Central Package Management they carry no
+	// version, which then comes from the nearest Directory.Packages.props.
 	abpVersionRegex := regexp.MustCompile(`Volo\.Abp`)
+	var centralVersions map[string]string
 	for _, itemGroup := range project.ItemGroup {
 		for _, pkg := range itemGroup.PackageReference {
-			if abpVersionRegex.MatchString(pkg.Include) && pkg.Version != "" {
+			if !abpVersionRegex.MatchString(pkg.Include) {
+				continue
+			}
+			if pkg.Version != "" {
 				return normalizeABPVersion(pkg.Version)
 			}
+			if pkg.VersionOverride != "" {
+				return normalizeABPVersion(pkg.VersionOverride)
+			}
+			if centralVersions == nil {
+				centralVersions = loadCentralPackageVersions(filepath.Dir(csprojPath))
+			}
+			if version := centralVersions[pkg.Include]; version != "" {
+				return normalizeABPVersion(version)
+			}
 		}
 	}
 
 	return ""
 }
 
+// findPackagesProps returns the Directory.Packages.props applying to a project directory,
+// the nearest one found walking up from it, or "" when there is none
+func findPackagesProps(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, "Directory.Packages.props")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadCentralPackageVersions maps package names to the versions set by <PackageVersion>
+// entries of the Directory.Packages.props applying to a project directory. Versions
+// referring to properties of the same file, e.g. $(AbpVersion), are resolved.
+func loadCentralPackageVersions(dir string) map[string]string {
+	versions := make(map[string]string)
+
+	path := findPackagesProps(dir)
+	if path == "" {
+		return versions
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return versions
+	}
+	var props PackagesProps
+	if err := xml.Unmarshal(data, &props); err != nil {
+		return versions
+	}
+
+	properties := make(map[string]string)
+	for _, group := range props.PropertyGroup {
+		for _, prop := range group.Properties {
+			properties[prop.XMLName.Local] = strings.TrimSpace(prop.Value)
+		}
+	}
+	propertyRef := regexp.MustCompile(`\$\(([^)]+)\)`)
+	for _, group := range props.ItemGroup {
+		for _, pkg := range group.PackageVersion {
+			versions[pkg.Include] = propertyRef.ReplaceAllStringFunc(pkg.Version, func(ref string) string {
+				return properties[propertyRef.FindStringSubmatch(ref)[1]]
+			})
+		}
+	}
+	return versions
+}
+
 // DetectDotNetVersion detects .NET target framework from csproj
 func DetectDotNetVersion(csprojPath string) string {
 	data, err := os.ReadFile(csprojPath)
@@ -201,6 +289,26 @@ func ScanProjectsForVersions(info *SolutionInfo) (abpVersion, dotnetVersion stri
 		}
 
 		// Stop if both found
+		if abpVersion != "" && dotnetVersion != "" {
+			return
+		}
+	}
+
+	// Fall back to project files found while scanning the solution tree
+	if info.RootDirectory == "" {
+		return
+	}
+	scan, err := ScanDirectory(info.RootDirectory)
+	if err != nil {
+		return
+	}
+	for _, csprojPath := range scan.CsprojFiles {
+		if abpVersion == "" {
+			abpVersion = DetectABPVersion(csprojPath)
+		}
+		if dotnetVersion == "" {
+			dotnetVersion = DetectDotNetVersion(csprojPath)
+		}
 		if abpVersion != "" && dotnetVersion != "" {
 			break
 		}
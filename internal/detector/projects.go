@@ -3,8 +3,10 @@ package detector
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -38,6 +40,21 @@ type LayerPaths struct {
 	EFCoreConfigurations     string
 	EFCoreRepositories       string
 	MongoDBRepositories      string
+
+	// DomainModule is the Domain project's AbpModule class file, when one was found
+	DomainModule string
+
+	// ApplicationModule is the Application project's AbpModule class file and ApplicationModuleClass
+	// the class it declares, when one was found
+	ApplicationModule      string
+	ApplicationModuleClass string
+
+	// EFCoreModule is the EntityFrameworkCore project's AbpModule class file, when one was found
+	EFCoreModule string
+
+	// AppSettingsFiles are the appsettings.json files of the solution's applications, such as
+	// HttpApi.Host and DbMigrator; test projects are left out
+	AppSettingsFiles []string
 }
 
 // DetectLayerPaths detects and returns paths to all ABP layers
@@ -51,6 +68,9 @@ func DetectLayerPaths(solutionInfo *SolutionInfo, moduleName string) (*LayerPath
 		paths.DomainRepositories = filepath.Join(domain.Directory, "Repositories")
 		paths.DomainManagers = filepath.Join(domain.Directory, "Managers")
 		paths.DomainData = filepath.Join(domain.Directory, "Data")
+		if modulePath, _, ok := solutionInfo.FindModuleClass(ProjectTypeDomain); ok {
+			paths.DomainModule = modulePath
+		}
 	}
 
 	if domainShared := solutionInfo.GetProject(ProjectTypeDomainShared); domainShared != nil {
@@ -74,6 +94,10 @@ func DetectLayerPaths(solutionInfo *SolutionInfo, moduleName string) (*LayerPath
 		paths.ApplicationAutoMapper = filepath.Join(app.Directory, "AutoMapper")
 		paths.ApplicationValidators = filepath.Join(app.Directory, "Validators")
 		paths.ApplicationEventHandlers = filepath.Join(app.Directory, "EventHandlers")
+		if modulePath, className, ok := solutionInfo.FindModuleClass(ProjectTypeApplication); ok {
+			paths.ApplicationModule = modulePath
+			paths.ApplicationModuleClass = className
+		}
 	}
 
 	if httpApi := solutionInfo.GetProject(ProjectTypeHttpApi); httpApi != nil {
@@ -85,6 +109,9 @@ func DetectLayerPaths(solutionInfo *SolutionInfo, moduleName string) (*LayerPath
 		paths.EntityFrameworkCore = efCore.Directory
 		paths.EFCoreConfigurations = filepath.Join(efCore.Directory, "EntityFrameworkCore", "Configurations")
 		paths.EFCoreRepositories = filepath.Join(efCore.Directory, "EntityFrameworkCore", "Repositories")
+		if modulePath, _, ok := solutionInfo.FindModuleClass(ProjectTypeEntityFrameworkCore); ok {
+			paths.EFCoreModule = modulePath
+		}
 	}
 
 	if mongodb := solutionInfo.GetProject(ProjectTypeMongoDB); mongodb != nil {
@@ -92,6 +119,12 @@ func DetectLayerPaths(solutionInfo *SolutionInfo, moduleName string) (*LayerPath
 		paths.MongoDBRepositories = filepath.Join(mongodb.Directory, "MongoDB", "Repositories")
 	}
 
+	if solutionInfo.RootDirectory != "" {
+		if scan, err := ScanDirectory(solutionInfo.RootDirectory); err == nil {
+			paths.AppSettingsFiles = applicationSettingsFiles(scan.AppSettingsFiles)
+		}
+	}
+
 	// Validate required paths exist
 	if paths.Domain == "" {
 		// Build a helpful error message listing detected projects
@@ -125,6 +158,80 @@ func DetectLayerPaths(solutionInfo *SolutionInfo, moduleName string) (*LayerPath
 	return paths, nil
 }
 
+// applicationSettingsFiles returns the appsettings.json files that do not belong to a test project
+func applicationSettingsFiles(files []string) []string {
+	var settings []string
+	for _, file := range files {
+		if filepath.Base(file) != "appsettings.json" {
+			continue
+		}
+		isTest := false
+		for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(file)), "/") {
+			if strings.EqualFold(dir, "test") || strings.EqualFold(dir, "tests") || strings.HasSuffix(dir, ".Tests") {
+				isTest = true
+				break
+			}
+		}
+		if !isTest {
+			settings = append(settings, file)
+		}
+	}
+	return settings
+}
+
+// Reroot moves every layer path under outputDir, preserving its location relative to baseDir.
+// Project names and the layer structure stay as detected; only the root changes.
+func (p *LayerPaths) Reroot(baseDir, outputDir string) error {
+	fields := []*string{
+		&p.Domain,
+		&p.DomainShared,
+		&p.ApplicationContracts,
+		&p.Application,
+		&p.HttpApi,
+		&p.EntityFrameworkCore,
+		&p.MongoDB,
+		&p.DomainEntities,
+		&p.DomainRepositories,
+		&p.DomainManagers,
+		&p.DomainData,
+		&p.DomainSharedConstants,
+		&p.DomainSharedEvents,
+		&p.DomainSharedEnums,
+		&p.DomainSharedLocalization,
+		&p.ContractsPermissions,
+		&p.ContractsDTOs,
+		&p.ContractsServices,
+		&p.ApplicationServices,
+		&p.ApplicationAutoMapper,
+		&p.ApplicationValidators,
+		&p.ApplicationEventHandlers,
+		&p.HttpApiControllers,
+		&p.EFCoreConfigurations,
+		&p.EFCoreRepositories,
+		&p.MongoDBRepositories,
+		&p.DomainModule,
+		&p.ApplicationModule,
+		&p.EFCoreModule,
+	}
+
+	for i := range p.AppSettingsFiles {
+		fields = append(fields, &p.AppSettingsFiles[i])
+	}
+
+	for _, field := range fields {
+		if *field == "" {
+			continue
+		}
+		rel, err := filepath.Rel(baseDir, *field)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("layer path %s is outside the solution directory %s", *field, baseDir)
+		}
+		*field = filepath.Join(outputDir, rel)
+	}
+
+	return nil
+}
+
 // EnsureDirectories creates all necessary directories
 func (p *LayerPaths) EnsureDirectories() error {
 	directories := []string{
@@ -210,6 +317,75 @@ func (p *LayerPaths) GetDbContextPath(serviceName string) string {
 	return filepath.Join(p.EntityFrameworkCore, "EntityFrameworkCore", serviceName+"DbContext.cs")
 }
 
+// abpDbContextPattern matches a class declaration deriving from AbpDbContext<T>
+var abpDbContextPattern = regexp.MustCompile(`class\s+(\w+)\s*:\s*AbpDbContext\s*<`)
+
+// ResolveDbContext returns the path and class name of the solution's DbContext.
+// The EF Core project is scanned for a *DbContext.cs file declaring a class that derives
+// from AbpDbContext, preferring {serviceName}DbContext when several exist. When none is
+// found the {serviceName}DbContext convention is returned.
+func (p *LayerPaths) ResolveDbContext(serviceName string) (string, string) {
+	conventional := serviceName + "DbContext"
+	if p.EntityFrameworkCore == "" {
+		return "", conventional
+	}
+
+	var foundPath, foundClass string
+	_ = filepath.WalkDir(p.EntityFrameworkCore, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != p.EntityFrameworkCore && (skippedScanDirectories[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), "DbContext.cs") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		match := abpDbContextPattern.FindStringSubmatch(string(content))
+		if match == nil {
+			return nil
+		}
+		if foundPath == "" || match[1] == conventional {
+			foundPath, foundClass = path, match[1]
+		}
+		if match[1] == conventional {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	if foundPath == "" {
+		return p.GetDbContextPath(serviceName), conventional
+	}
+	return foundPath, foundClass
+}
+
+// ResolveIDbContext returns the path and name of the interface belonging to the DbContext
+// found by ResolveDbContext, kept next to the DbContext as I{DbContext}.cs
+func (p *LayerPaths) ResolveIDbContext(serviceName string) (string, string) {
+	dbContextPath, className := p.ResolveDbContext(serviceName)
+	if dbContextPath == "" {
+		return "", "I" + className
+	}
+	return filepath.Join(filepath.Dir(dbContextPath), "I"+className+".cs"), "I" + className
+}
+
+// GetModelCreatingExtensionsPath returns the path to the DbContextModelCreatingExtensions file
+func (p *LayerPaths) GetModelCreatingExtensionsPath(serviceName string) string {
+	if p.EntityFrameworkCore == "" {
+		return ""
+	}
+	return filepath.Join(p.EntityFrameworkCore, "EntityFrameworkCore", serviceName+"DbContextModelCreatingExtensions.cs")
+}
+
 // GetIDbContextPath returns the path to the IDbContext file
 func (p *LayerPaths) GetIDbContextPath(serviceName string) string {
 	if p.EntityFrameworkCore == "" {
@@ -218,6 +394,51 @@ func (p *LayerPaths) GetIDbContextPath(serviceName string) string {
 	return filepath.Join(p.EntityFrameworkCore, "EntityFrameworkCore", "I"+serviceName+"DbContext.cs")
 }
 
+// GetDomainModulePath returns the path to the Domain layer's ABP module class, preferring the
+// detected module over the conventional file name
+func (p *LayerPaths) GetDomainModulePath(serviceName string) string {
+	if p.DomainModule != "" {
+		return p.DomainModule
+	}
+	if p.Domain == "" {
+		return ""
+	}
+	return filepath.Join(p.Domain, serviceName+"DomainModule.cs")
+}
+
+// GetApplicationModulePath returns the path to the Application layer's ABP module class,
+// preferring the detected module over the conventional file name
+func (p *LayerPaths) GetApplicationModulePath(serviceName string) string {
+	if p.ApplicationModule != "" {
+		return p.ApplicationModule
+	}
+	if p.Application == "" {
+		return ""
+	}
+	return filepath.Join(p.Application, serviceName+"ApplicationModule.cs")
+}
+
+// GetApplicationModuleClass returns the name of the Application layer's ABP module class,
+// preferring the detected class over the conventional name
+func (p *LayerPaths) GetApplicationModuleClass(serviceName string) string {
+	if p.ApplicationModuleClass != "" {
+		return p.ApplicationModuleClass
+	}
+	return serviceName + "ApplicationModule"
+}
+
+// GetEFCoreModulePath returns the path to the EntityFrameworkCore layer's ABP module class,
+// preferring the detected module over the conventional file name
+func (p *LayerPaths) GetEFCoreModulePath(serviceName string) string {
+	if p.EFCoreModule != "" {
+		return p.EFCoreModule
+	}
+	if p.EntityFrameworkCore == "" {
+		return ""
+	}
+	return filepath.Join(p.EntityFrameworkCore, serviceName+"EntityFrameworkCoreModule.cs")
+}
+
 // GetPermissionsFilePath returns the path to the permissions file
 // moduleFolder should be the full module folder name (e.g., "ProductModule", "IntegrationServiceModule")
 // moduleName should be the base module name (e.g., "Product", "IntegrationService")
@@ -228,6 +449,15 @@ func (p *LayerPaths) GetPermissionsFilePath(moduleFolder, moduleName string) str
 	return filepath.Join(p.ContractsPermissions, moduleFolder, moduleName+"Permissions.cs")
 }
 
+// GetLocalizationResourcePath returns the path to the localization resource class in Domain.Shared,
+// where ABP's startup templates keep it
+func (p *LayerPaths) GetLocalizationResourcePath(resourceName string) string {
+	if p.DomainShared == "" {
+		return ""
+	}
+	return filepath.Join(p.DomainShared, "Localization", resourceName+".cs")
+}
+
 // GetPermissionProviderPath returns the path to the permission provider file
 // moduleFolder should be the full module folder name (e.g., "ProductModule", "IntegrationServiceModule")
 // moduleName should be the base module name (e.g., "Product", "IntegrationService")
@@ -0,0 +1,134 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLayerPaths_Reroot(t *testing.T) {
+	root := filepath.Join("work", "Acme.Shop")
+	domain := filepath.Join(root, "src", "Acme.Shop.Domain")
+	app := filepath.Join(root, "src", "Acme.Shop.Application")
+	paths := &LayerPaths{
+		Domain:                domain,
+		DomainEntities:        filepath.Join(domain, "Entities"),
+		Application:           app,
+		ApplicationAutoMapper: filepath.Join(app, "AutoMapper"),
+	}
+
+	out := filepath.Join("tmp", "review")
+	if err := paths.Reroot(root, out); err != nil {
+		t.Fatalf("Reroot() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Domain", paths.Domain, filepath.Join(out, "src", "Acme.Shop.Domain")},
+		{"DomainEntities", paths.DomainEntities, filepath.Join(out, "src", "Acme.Shop.Domain", "Entities")},
+		{"ApplicationAutoMapper", paths.ApplicationAutoMapper, filepath.Join(out, "src", "Acme.Shop.Application", "AutoMapper")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q; want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if paths.MongoDB != "" {
+		t.Errorf("MongoDB = %q; undetected layers must stay empty", paths.MongoDB)
+	}
+	if got := paths.GetDomainModulePath("Catalog"); got != filepath.Join(out, "src", "Acme.Shop.Domain", "CatalogDomainModule.cs") {
+		t.Errorf("GetDomainModulePath() = %q", got)
+	}
+}
+
+func TestDetectLayerPaths_ModuleClasses(t *testing.T) {
+	dir := t.TempDir()
+	appDir := t.TempDir()
+	module := "public class ShopDomainModule : AbpModule\n{\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "ShopDomainModule.cs"), []byte(module), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appModule := "public class ShopApplicationModule : AbpModule\n{\n}\n"
+	if err := os.WriteFile(filepath.Join(appDir, "ShopApplicationModule.cs"), []byte(appModule), 0644); err != nil {
+		t.Fatal(err)
+	}
+	solution := &SolutionInfo{Projects: []ProjectInfo{
+		{Name: "Acme.Shop.Domain", Directory: dir, Type: ProjectTypeDomain},
+		{Name: "Acme.Shop.Application", Directory: appDir, Type: ProjectTypeApplication},
+	}}
+
+	paths, err := DetectLayerPaths(solution, "Catalog")
+	if err != nil {
+		t.Fatalf("DetectLayerPaths() error = %v", err)
+	}
+	if got, want := paths.GetDomainModulePath("Catalog"), filepath.Join(dir, "ShopDomainModule.cs"); got != want {
+		t.Errorf("GetDomainModulePath() = %q; want the detected %q", got, want)
+	}
+	if got, want := paths.GetApplicationModulePath("Catalog"), filepath.Join(appDir, "ShopApplicationModule.cs"); got != want {
+		t.Errorf("GetApplicationModulePath() = %q; want the detected %q", got, want)
+	}
+	if got := paths.GetApplicationModuleClass("Catalog"); got != "ShopApplicationModule" {
+		t.Errorf("GetApplicationModuleClass() = %q; want ShopApplicationModule", got)
+	}
+}
+
+func TestApplicationSettingsFiles(t *testing.T) {
+	host := filepath.Join("src", "Acme.Shop.HttpApi.Host", "appsettings.json")
+	migrator := filepath.Join("src", "Acme.Shop.DbMigrator", "appsettings.json")
+	files := []string{
+		host,
+		filepath.Join("src", "Acme.Shop.HttpApi.Host", "appsettings.Development.json"),
+		migrator,
+		filepath.Join("test", "Acme.Shop.TestBase", "appsettings.json"),
+		filepath.Join("src", "Acme.Shop.Application.Tests", "appsettings.json"),
+	}
+
+	got := applicationSettingsFiles(files)
+	if strings.Join(got, ",") != host+","+migrator {
+		t.Errorf("applicationSettingsFiles() = %v; want the host and migrator settings", got)
+	}
+}
+
+func TestLayerPaths_RerootOutsideSolution(t *testing.T) {
+	paths := &LayerPaths{Domain: filepath.Join("shared", "Acme.Shop.Domain")}
+
+	err := paths.Reroot(filepath.Join("work", "Acme.Shop"), "review")
+	if err == nil || !strings.Contains(err.Error(), "outside the solution directory") {
+		t.Errorf("Reroot() error = %v; want an outside-solution error", err)
+	}
+}
+
+func TestLayerPaths_ResolveDbContext(t *testing.T) {
+	efCore := filepath.Join(t.TempDir(), "Acme.Shop.EntityFrameworkCore")
+	dir := filepath.Join(efCore, "EntityFrameworkCore")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	paths := &LayerPaths{EntityFrameworkCore: efCore}
+
+	path, name := paths.ResolveDbContext("Catalog")
+	if path != filepath.Join(dir, "CatalogDbContext.cs") || name != "CatalogDbContext" {
+		t.Errorf("ResolveDbContext() without a DbContext = %q, %q; want the convention", path, name)
+	}
+
+	shopContext := filepath.Join(dir, "ShopDbContext.cs")
+	content := "public class ShopDbContext : AbpDbContext<ShopDbContext>, IShopDbContext\n{\n}\n"
+	if err := os.WriteFile(shopContext, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ShopDbContextFactory.cs"), []byte("public class ShopDbContextFactory {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, name = paths.ResolveDbContext("Catalog")
+	if path != shopContext || name != "ShopDbContext" {
+		t.Errorf("ResolveDbContext() = %q, %q; want %q, ShopDbContext", path, name, shopContext)
+	}
+	if path, name = paths.ResolveIDbContext("Catalog"); path != filepath.Join(dir, "IShopDbContext.cs") || name != "IShopDbContext" {
+		t.Errorf("ResolveIDbContext() = %q, %q", path, name)
+	}
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ScanResult holds the files collected from a single walk of a solution tree
+type ScanResult struct {
+	RootDirectory    string
+	CsprojFiles      []string
+	AppSettingsFiles []string
+}
+
+var (
+	scanCacheMu sync.Mutex
+	scanCache   = make(map[string]*ScanResult)
+)
+
+// skippedScanDirectories are build output and tooling directories that never contain solution sources
+var skippedScanDirectories = map[string]bool{
+	"bin":          true,
+	"obj":          true,
+	"node_modules": true,
+}
+
+// ScanDirectory walks rootDir once and collects .csproj and appsettings*.json files.
+// Results are cached per directory so project discovery, config scanning, and
+// version scanning share one walk.
+func ScanDirectory(rootDir string) (*ScanResult, error) {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scanCacheMu.Lock()
+	defer scanCacheMu.Unlock()
+
+	if cached, ok := scanCache[absRoot]; ok {
+		return cached, nil
+	}
+
+	result := &ScanResult{RootDirectory: absRoot}
+	err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable entries instead of aborting the scan
+			if d != nil && d.IsDir() && path != absRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if path != absRoot && (skippedScanDirectories[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(name, ".csproj"):
+			result.CsprojFiles = append(result.CsprojFiles, path)
+		case strings.HasPrefix(name, "appsettings") && strings.HasSuffix(name, ".json"):
+			result.AppSettingsFiles = append(result.AppSettingsFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scanCache[absRoot] = result
+	return result, nil
+}
+
+// ClearScanCache discards cached scan results, e.g. after new projects were scaffolded
+func ClearScanCache() {
+	scanCacheMu.Lock()
+	defer scanCacheMu.Unlock()
+
+	scanCache = make(map[string]*ScanResult)
+}
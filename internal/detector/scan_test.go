@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestDiscoverFromProjects_NestedProjects(t *testing.T) {
+	root := t.TempDir()
+	csproj := `<Project Sdk="Microsoft.NET.Sdk"><PropertyGroup><TargetFramework>net9.0</TargetFramework></PropertyGroup></Project>`
+	writeTestFile(t, filepath.Join(root, "src", "Acme.Shop.Domain", "Acme.Shop.Domain.csproj"), csproj)
+	writeTestFile(t, filepath.Join(root, "src", "Acme.Shop.Domain", "bin", "Debug", "Copy.csproj"), csproj)
+	writeTestFile(t, filepath.Join(root, "src", "Acme.Shop.HttpApi.Host", "appsettings.json"), `{}`)
+
+	info, err := discoverFromProjects(root)
+	if err != nil {
+		t.Fatalf("discoverFromProjects() error = %v", err)
+	}
+
+	if len(info.Projects) != 1 {
+		t.Fatalf("discoverFromProjects() found %d projects; want 1: %+v", len(info.Projects), info.Projects)
+	}
+	if info.Name != filepath.Base(root) || info.RootDirectory != root {
+		t.Errorf("discoverFromProjects() solution = %s at %s; want %s at %s", info.Name, info.RootDirectory, filepath.Base(root), root)
+	}
+	project := info.Projects[0]
+	if project.Name != "Acme.Shop.Domain" || project.Type != ProjectTypeDomain {
+		t.Errorf("discovered project = %s (%s); want Acme.Shop.Domain (%s)", project.Name, project.Type, ProjectTypeDomain)
+	}
+
+	scan, err := ScanDirectory(root)
+	if err != nil {
+		t.Fatalf("ScanDirectory() error = %v", err)
+	}
+	if len(scan.AppSettingsFiles) != 1 {
+		t.Errorf("ScanDirectory() found %d appsettings files; want 1: %v", len(scan.AppSettingsFiles), scan.AppSettingsFiles)
+	}
+}
+
+func BenchmarkScanDirectory(b *testing.B) {
+	root := b.TempDir()
+	for _, layer := range []string{"Domain", "Domain.Shared", "Application", "Application.Contracts", "HttpApi", "EntityFrameworkCore"} {
+		dir := filepath.Join(root, "src", "Acme.Shop."+layer)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "Acme.Shop."+layer+".csproj"), []byte("<Project />"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ClearScanCache()
+		if _, err := ScanDirectory(root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
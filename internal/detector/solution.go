@@ -2,9 +2,13 @@ package detector
 
 import (
 	"bufio"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -40,7 +44,7 @@ const (
 	ProjectTypeUnknown              ProjectType = "Unknown"
 )
 
-// FindSolution searches for solution files (.sln, .slnx, .abpsln, .abpslnx)
+// FindSolution searches for solution files (.sln, .slnx, .abpsln, .abpslnx, .slnf)
 // starting from the current directory and moving upward through parent directories.
 // If no solution file is found, attempts to discover projects from .csproj files.
 func FindSolution(startPath string) (*SolutionInfo, error) {
@@ -50,7 +54,7 @@ func FindSolution(startPath string) (*SolutionInfo, error) {
 	}
 
 	// Solution file extensions in priority order
-	solutionExtensions := []string{".sln", ".slnx", ".abpsln", ".abpslnx"}
+	solutionExtensions := []string{".sln", ".slnx", ".abpsln", ".abpslnx", ".slnf"}
 
 	for {
 		// Check for solution files in current directory
@@ -80,20 +84,18 @@ func FindSolution(startPath string) (*SolutionInfo, error) {
 // discoverFromProjects attempts to discover solution info from .csproj files
 // when no traditional solution file exists
 func discoverFromProjects(startPath string) (*SolutionInfo, error) {
-	csprojFiles, err := filepath.Glob(filepath.Join(startPath, "**/*.csproj"))
-	if err != nil || len(csprojFiles) == 0 {
-		// Try current directory
-		csprojFiles, err = filepath.Glob(filepath.Join(startPath, "*.csproj"))
-		if err != nil || len(csprojFiles) == 0 {
-			return nil, fmt.Errorf("no solution or project files found")
-		}
+	scan, err := ScanDirectory(startPath)
+	if err != nil || len(scan.CsprojFiles) == 0 {
+		return nil, fmt.Errorf("no solution or project files found")
 	}
+	csprojFiles := scan.CsprojFiles
 
-	// Create a synthetic solution from discovered projects
+	// Create a synthetic solution from discovered projects, rooted at the absolute
+	// scan directory so a relative start path such as "." still yields a usable name
 	info := &SolutionInfo{
-		Path:            startPath,
-		Name:            filepath.Base(startPath),
-		RootDirectory:   startPath,
+		Path:            scan.RootDirectory,
+		Name:            filepath.Base(scan.RootDirectory),
+		RootDirectory:   scan.RootDirectory,
 		Projects:        []ProjectInfo{},
 		TargetFramework: "abp8-monolith", // Default, will be refined
 		IsMicroservice:  false,
@@ -114,16 +116,12 @@ func discoverFromProjects(startPath string) (*SolutionInfo, error) {
 	return info, nil
 }
 
-// ParseSolution parses a solution file and extracts project information
+// ParseSolution parses a solution file and extracts project information.
+// The classic .sln text format, the XML .slnx format and .slnf solution filters are supported.
 func ParseSolution(solutionPath string) (*SolutionInfo, error) {
-	file, err := os.Open(solutionPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
 	solutionDir := filepath.Dir(solutionPath)
-	solutionName := strings.TrimSuffix(filepath.Base(solutionPath), ".sln")
+	ext := strings.ToLower(filepath.Ext(solutionPath))
+	solutionName := strings.TrimSuffix(filepath.Base(solutionPath), filepath.Ext(solutionPath))
 
 	info := &SolutionInfo{
 		Path:            solutionPath,
@@ -134,6 +132,37 @@ func ParseSolution(solutionPath string) (*SolutionInfo, error) {
 		IsMicroservice:  false,
 	}
 
+	var projects []ProjectInfo
+	var err error
+	switch ext {
+	case ".slnx", ".abpslnx":
+		projects, err = parseSlnxProjects(solutionPath, solutionDir)
+	case ".slnf":
+		projects, err = parseSlnfProjects(solutionPath, solutionDir)
+	default:
+		projects, err = parseSlnProjects(solutionPath, solutionDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Projects = append(info.Projects, projects...)
+
+	// Detect target framework based on projects and structure
+	info.TargetFramework = DetectTargetFramework(info)
+	info.IsMicroservice = IsMicroserviceArchitecture(info)
+
+	return info, nil
+}
+
+// parseSlnProjects reads the Project(...) lines of a classic .sln file
+func parseSlnProjects(solutionPath, solutionDir string) ([]ProjectInfo, error) {
+	file, err := os.Open(solutionPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var projects []ProjectInfo
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -142,7 +171,7 @@ func ParseSolution(solutionPath string) (*SolutionInfo, error) {
 		if strings.HasPrefix(line, "Project(") {
 			project := parseProjectLine(line, solutionDir)
 			if project != nil {
-				info.Projects = append(info.Projects, *project)
+				projects = append(projects, *project)
 			}
 		}
 	}
@@ -150,12 +179,99 @@ func ParseSolution(solutionPath string) (*SolutionInfo, error) {
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	return projects, nil
+}
 
-	// Detect target framework based on projects and structure
-	info.TargetFramework = DetectTargetFramework(info)
-	info.IsMicroservice = IsMicroserviceArchitecture(info)
+// parseSlnxProjects reads the <Project Path="..."> elements of an XML .slnx file,
+// including projects nested inside <Folder> elements
+func parseSlnxProjects(solutionPath, solutionDir string) ([]ProjectInfo, error) {
+	file, err := os.Open(solutionPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-	return info, nil
+	var projects []ProjectInfo
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse solution file %s: %w", solutionPath, err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "Project" {
+			continue
+		}
+		for _, attr := range element.Attr {
+			if attr.Name.Local == "Path" {
+				if project := newProjectInfo(attr.Value, solutionDir); project != nil {
+					projects = append(projects, *project)
+				}
+				break
+			}
+		}
+	}
+	return projects, nil
+}
+
+// solutionFilter is the JSON content of a .slnf solution filter
+type solutionFilter struct {
+	Solution struct {
+		Path     string   `json:"path"`
+		Projects []string `json:"projects"`
+	} `json:"solution"`
+}
+
+// parseSlnfProjects reads the project list of a .slnf solution filter. Project paths
+// are relative to the filtered solution, which is itself relative to the filter file.
+func parseSlnfProjects(solutionPath, solutionDir string) ([]ProjectInfo, error) {
+	data, err := os.ReadFile(solutionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter solutionFilter
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return nil, fmt.Errorf("failed to parse solution filter %s: %w", solutionPath, err)
+	}
+
+	projectsDir := solutionDir
+	if filter.Solution.Path != "" {
+		solutionFile := strings.ReplaceAll(filter.Solution.Path, "\\", string(filepath.Separator))
+		projectsDir = filepath.Dir(filepath.Join(solutionDir, solutionFile))
+	}
+
+	var projects []ProjectInfo
+	for _, projectPath := range filter.Solution.Projects {
+		if project := newProjectInfo(projectPath, projectsDir); project != nil {
+			projects = append(projects, *project)
+		}
+	}
+	return projects, nil
+}
+
+// newProjectInfo builds the project info for a project path relative to baseDir,
+// named after its .csproj file. Non C# projects are skipped.
+func newProjectInfo(projectPath, baseDir string) *ProjectInfo {
+	// Handle both Windows (\\) and Unix (/) path separators
+	projectPath = strings.ReplaceAll(projectPath, "\\", string(filepath.Separator))
+	if !strings.HasSuffix(projectPath, ".csproj") {
+		return nil
+	}
+
+	absPath := filepath.Join(baseDir, projectPath)
+	projectName := strings.TrimSuffix(filepath.Base(absPath), ".csproj")
+
+	return &ProjectInfo{
+		Name:      projectName,
+		Path:      absPath,
+		Directory: filepath.Dir(absPath),
+		Type:      DetermineProjectType(projectName),
+	}
 }
 
 // DetectTargetFramework detects the target framework based on solution structure and csproj files
@@ -329,6 +445,74 @@ func (s *SolutionInfo) HasProject(projectType ProjectType) bool {
 	return s.GetProject(projectType) != nil
 }
 
+// abpModulePattern matches a class declaration inheriting directly from AbpModule
+var abpModulePattern = regexp.MustCompile(`class\s+(\w+)\s*:\s*AbpModule\b`)
+
+// FindModuleClass locates the ABP module definition class of a project by looking at the
+// *Module.cs files in the project directory for a class that inherits AbpModule
+func (s *SolutionInfo) FindModuleClass(projectType ProjectType) (path, className string, ok bool) {
+	dir := s.GetProjectDirectory(projectType)
+	if dir == "" {
+		return "", "", false
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*Module.cs"))
+	if err != nil {
+		return "", "", false
+	}
+	for _, match := range matches {
+		content, err := os.ReadFile(match)
+		if err != nil {
+			continue
+		}
+		if m := abpModulePattern.FindStringSubmatch(string(content)); m != nil {
+			return match, m[1], true
+		}
+	}
+	return "", "", false
+}
+
+// entityKeyPattern matches a class declaration inheriting an ABP entity or aggregate root base
+// class, capturing its key type, e.g. "class Book : FullAuditedAggregateRoot<long>"
+var entityKeyPattern = regexp.MustCompile(`class\s+\w+\s*:\s*(?:[\w.]+\.)?\w*(?:Entity|AggregateRoot)<\s*([\w.]+)\s*>`)
+
+// DetectPrimaryKeyType returns the key type most entities under the Domain project's Entities
+// folder use, "Guid" or "long", or "" when no entity declares one of them. Guid wins a tie.
+func (s *SolutionInfo) DetectPrimaryKeyType() string {
+	dir := s.GetProjectDirectory(ProjectTypeDomain)
+	if dir == "" {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	_ = filepath.Walk(filepath.Join(dir, "Entities"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".cs") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, m := range entityKeyPattern.FindAllStringSubmatch(string(content), -1) {
+			switch m[1] {
+			case "Guid", "System.Guid":
+				counts["Guid"]++
+			case "long", "Int64", "System.Int64":
+				counts["long"]++
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case counts["long"] > counts["Guid"]:
+		return "long"
+	case counts["Guid"] > 0:
+		return "Guid"
+	}
+	return ""
+}
+
 // GetProjectDirectory returns the directory path for a specific project type
 func (s *SolutionInfo) GetProjectDirectory(projectType ProjectType) string {
 	project := s.GetProject(projectType)
@@ -1,6 +1,8 @@
 package detector
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -169,3 +171,212 @@ func TestIsMicroserviceArchitecture(t *testing.T) {
 		})
 	}
 }
+
+func TestSolutionInfo_FindModuleClass(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ShopDomainModule.cs":   "[DependsOn(typeof(AbpDddDomainModule))]\npublic class ShopDomainModule : AbpModule\n{\n}\n",
+		"ShopSettingsModule.cs": "public static class ShopSettingsModule\n{\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	solution := &SolutionInfo{Projects: []ProjectInfo{
+		{Name: "Acme.Shop.Domain", Directory: dir, Type: ProjectTypeDomain},
+	}}
+
+	path, className, ok := solution.FindModuleClass(ProjectTypeDomain)
+	if !ok || path != filepath.Join(dir, "ShopDomainModule.cs") || className != "ShopDomainModule" {
+		t.Errorf("FindModuleClass() = %q, %q, %v; want ShopDomainModule.cs, ShopDomainModule, true", path, className, ok)
+	}
+
+	if _, _, ok := solution.FindModuleClass(ProjectTypeApplication); ok {
+		t.Errorf("FindModuleClass() found a module for a project that does not exist")
+	}
+}
+
+func TestSolutionInfo_DetectPrimaryKeyType(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "long keys prevail",
+			files: map[string]string{
+				"Books/Book.cs":     "public class Book : FullAuditedAggregateRoot<long>\n{\n}\n",
+				"Books/Chapter.cs":  "public class Chapter : Entity<long>, IMultiTenant\n{\n}\n",
+				"Authors/Author.cs": "public class Author : Volo.Abp.Domain.Entities.Auditing.AuditedAggregateRoot<Guid>\n{\n}\n",
+			},
+			want: "long",
+		},
+		{
+			name:  "guid keys",
+			files: map[string]string{"Book.cs": "public class Book : AggregateRoot<Guid>\n{\n}\n"},
+			want:  "Guid",
+		},
+		{
+			name:  "no keyed entities",
+			files: map[string]string{"Address.cs": "public class Address : ValueObject\n{\n}\n"},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, "Entities", filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			solution := &SolutionInfo{Projects: []ProjectInfo{
+				{Name: "Acme.Shop.Domain", Directory: dir, Type: ProjectTypeDomain},
+			}}
+			if got := solution.DetectPrimaryKeyType(); got != tt.want {
+				t.Errorf("DetectPrimaryKeyType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSolution_Formats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Acme.Shop.slnx": `<Solution>
+  <Folder Name="/src/">
+    <Project Path="src/Acme.Shop.Domain/Acme.Shop.Domain.csproj" />
+    <Project Path="src\Acme.Shop.Application/Acme.Shop.Application.csproj" />
+  </Folder>
+  <Project Path="docker/docker-compose.dcproj" />
+</Solution>
+`,
+		"Acme.Shop.Domain.slnf": `{
+  "solution": {
+    "path": "Acme.Shop.sln",
+    "projects": [
+      "src\\Acme.Shop.Domain\\Acme.Shop.Domain.csproj"
+    ]
+  }
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		file     string
+		wantName string
+		want     map[string]ProjectType
+	}{
+		{
+			file:     "Acme.Shop.slnx",
+			wantName: "Acme.Shop",
+			want: map[string]ProjectType{
+				"Acme.Shop.Domain":      ProjectTypeDomain,
+				"Acme.Shop.Application": ProjectTypeApplication,
+			},
+		},
+		{
+			file:     "Acme.Shop.Domain.slnf",
+			wantName: "Acme.Shop.Domain",
+			want: map[string]ProjectType{
+				"Acme.Shop.Domain": ProjectTypeDomain,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			info, err := ParseSolution(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatalf("ParseSolution() error = %v", err)
+			}
+			if info.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", info.Name, tt.wantName)
+			}
+			if len(info.Projects) != len(tt.want) {
+				t.Fatalf("got %d projects, want %d: %+v", len(info.Projects), len(tt.want), info.Projects)
+			}
+			for _, project := range info.Projects {
+				if project.Type != tt.want[project.Name] {
+					t.Errorf("project %q type = %q, want %q", project.Name, project.Type, tt.want[project.Name])
+				}
+				wantDir := filepath.Join(dir, "src", project.Name)
+				if project.Directory != wantDir {
+					t.Errorf("project %q directory = %q, want %q", project.Name, project.Directory, wantDir)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectABPVersion_CentralPackageManagement(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Directory.Packages.props": `<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+    <AbpVersion>9.1.0</AbpVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Volo.Abp.Ddd.Domain" Version="$(AbpVersion)" />
+    <PackageVersion Include="Volo.Abp.Ddd.Application" Version="9.0.2" />
+  </ItemGroup>
+</Project>
+`,
+		"src/Acme.Shop.Domain/Acme.Shop.Domain.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Volo.Abp.Ddd.Domain" />
+  </ItemGroup>
+</Project>
+`,
+		"src/Acme.Shop.Application/Acme.Shop.Application.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Volo.Abp.Ddd.Application" VersionOverride="10.0.0" />
+  </ItemGroup>
+</Project>
+`,
+		"src/Acme.Shop.Contracts/Acme.Shop.Contracts.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Volo.Abp.Ddd.Application.Contracts" />
+  </ItemGroup>
+</Project>
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"src/Acme.Shop.Domain/Acme.Shop.Domain.csproj", "9"},
+		{"src/Acme.Shop.Application/Acme.Shop.Application.csproj", "10"},
+		{"src/Acme.Shop.Contracts/Acme.Shop.Contracts.csproj", ""},
+	}
+
+	for _, tt := range tests {
+		if got := DetectABPVersion(filepath.Join(dir, filepath.FromSlash(tt.project))); got != tt.want {
+			t.Errorf("DetectABPVersion(%s) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// connectionStringsKey is the appsettings.json section holding connection strings
+const connectionStringsKey = "ConnectionStrings"
+
+// AppSettingsGenerator adds the module's connection strings to the solution's appsettings.json files
+type AppSettingsGenerator struct {
+	writer *writer.Writer
+}
+
+// NewAppSettingsGenerator creates a new appsettings generator
+func NewAppSettingsGenerator(w *writer.Writer) *AppSettingsGenerator {
+	return &AppSettingsGenerator{
+		writer: w,
+	}
+}
+
+// connectionString is an entry of the ConnectionStrings section
+type connectionString struct {
+	Name  string
+	Value string
+}
+
+// efCoreConnectionStringName returns the connection string name of the module's DbContext
+func efCoreConnectionStringName(sch *schema.Schema) string {
+	return sch.Solution.ModuleName
+}
+
+// mongoConnectionStringName returns the connection string name of the module's MongoDB context
+// when the module uses both providers, so it does not share the EF Core connection string
+func mongoConnectionStringName(sch *schema.Schema) string {
+	return sch.Solution.ModuleName + "Mongo"
+}
+
+// Generate adds the EF Core and MongoDB connection strings of a module using both providers to
+// every application's appsettings.json, keeping entries that already exist. Without an
+// appsettings.json to update, the entries are written to appsettings.{ModuleName}.json next to
+// the layer projects, to be copied into the host's settings.
+func (g *AppSettingsGenerator) Generate(sch *schema.Schema, paths *detector.LayerPaths) error {
+	if sch.Solution.DBProvider != "both" {
+		return nil
+	}
+
+	updated := false
+	for _, file := range paths.AppSettingsFiles {
+		content, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		missing, err := missingConnectionStrings(string(content), moduleConnectionStrings(sch, string(content)))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if len(missing) > 0 {
+			if err := g.writer.UpdateFile(file, func(content string) (string, error) {
+				return insertConnectionStrings(content, missing)
+			}); err != nil {
+				return err
+			}
+		}
+		// Only a file that now holds every entry makes the fallback snippet unnecessary
+		updated = true
+	}
+	if updated {
+		return nil
+	}
+
+	snippet, err := insertConnectionStrings("{}", moduleConnectionStrings(sch, ""))
+	if err != nil {
+		return err
+	}
+	snippetPath := filepath.Join(filepath.Dir(paths.Domain), "appsettings."+sch.Solution.ModuleName+".json")
+	return g.writer.WriteFile(snippetPath, snippet+"\n")
+}
+
+// moduleConnectionStrings returns the module's connection strings. The EF Core one reuses the
+// Default connection string of settings, when it has one.
+func moduleConnectionStrings(sch *schema.Schema, settings string) []connectionString {
+	efCoreValue := fmt.Sprintf("Server=localhost;Database=%s;Trusted_Connection=True;TrustServerCertificate=True", sch.Solution.Name)
+	var parsed struct {
+		ConnectionStrings map[string]interface{} `json:"ConnectionStrings"`
+	}
+	if json.Unmarshal([]byte(stripJSONComments(settings)), &parsed) == nil {
+		if value, ok := parsed.ConnectionStrings["Default"].(string); ok && value != "" {
+			efCoreValue = value
+		}
+	}
+
+	return []connectionString{
+		{Name: efCoreConnectionStringName(sch), Value: efCoreValue},
+		{Name: mongoConnectionStringName(sch), Value: fmt.Sprintf("mongodb://localhost:27017/%s", sch.Solution.Name)},
+	}
+}
+
+// missingConnectionStrings returns the entries whose names the settings do not declare yet
+func missingConnectionStrings(settings string, entries []connectionString) ([]connectionString, error) {
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(stripJSONComments(settings)), &parsed); err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]json.RawMessage)
+	if raw, ok := parsed[connectionStringsKey]; ok {
+		if err := json.Unmarshal(raw, &declared); err != nil {
+			return nil, fmt.Errorf("%s must be an object: %w", connectionStringsKey, err)
+		}
+	}
+
+	var missing []connectionString
+	for _, entry := range entries {
+		if _, ok := declared[entry.Name]; !ok {
+			missing = append(missing, entry)
+		}
+	}
+	return missing, nil
+}
+
+// insertConnectionStrings adds entries to the ConnectionStrings section of a JSON document,
+// creating the section as the first member when it is missing. Only the inserted lines change,
+// so the formatting, comments and key order of the rest of the document are kept.
+func insertConnectionStrings(content string, entries []connectionString) (string, error) {
+	// Offsets into the stripped copy are offsets into content, since stripping keeps the length
+	clean := stripJSONComments(content)
+	dec := json.NewDecoder(strings.NewReader(clean))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return "", fmt.Errorf("settings must be a JSON object")
+	}
+	rootOpen := int(dec.InputOffset())
+	unit := indentUnit(content)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if tok != connectionStringsKey {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return "", fmt.Errorf("%s must be an object", connectionStringsKey)
+		}
+		sectionOpen := int(dec.InputOffset())
+		for dec.More() {
+			var skipped json.RawMessage
+			if _, err := dec.Token(); err != nil {
+				return "", err
+			}
+			if err := dec.Decode(&skipped); err != nil {
+				return "", err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return "", err
+		}
+		sectionClose := int(dec.InputOffset()) - 1
+
+		keyIndent := lineIndent(content, sectionOpen-1)
+		members, err := connectionStringMembers(entries, keyIndent+unit)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(clean[sectionOpen:sectionClose]) == "" {
+			return content[:sectionOpen] + "\n" + members + "\n" + keyIndent + content[sectionClose:], nil
+		}
+		last := len(strings.TrimRight(clean[:sectionClose], " \t\r\n"))
+		return content[:last] + ",\n" + members + content[last:], nil
+	}
+
+	members, err := connectionStringMembers(entries, unit+unit)
+	if err != nil {
+		return "", err
+	}
+	section := "\n" + unit + `"` + connectionStringsKey + `": {` + "\n" + members + "\n" + unit + "}"
+	if strings.TrimSpace(clean[rootOpen:strings.LastIndex(clean, "}")]) == "" {
+		return content[:rootOpen] + section + "\n}", nil
+	}
+	return content[:rootOpen] + section + "," + content[rootOpen:], nil
+}
+
+// stripJSONComments blanks out the comments and trailing commas that appsettings.json files may
+// contain, as .NET's configuration reader accepts them. Every removed byte except newlines becomes
+// a space, so offsets into the result are offsets into content.
+func stripJSONComments(content string) string {
+	out := []byte(content)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	lastComma := -1
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+			lastComma = -1
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(out) - i - 2
+			} else {
+				end += 2
+			}
+			blank(i, i+2+end)
+			i += 2 + end - 1
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case c != ' ' && c != '\t' && c != '\r' && c != '\n':
+			lastComma = -1
+		}
+	}
+	return string(out)
+}
+
+// connectionStringMembers renders entries as JSON object members, one per line
+func connectionStringMembers(entries []connectionString, indent string) (string, error) {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		name, err := jsonString(entry.Name)
+		if err != nil {
+			return "", err
+		}
+		value, err := jsonString(entry.Value)
+		if err != nil {
+			return "", err
+		}
+		lines[i] = indent + name + ": " + value
+	}
+	return strings.Join(lines, ",\n"), nil
+}
+
+// jsonString encodes s as a JSON string without escaping HTML characters
+func jsonString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// indentUnit returns the indentation of the first indented line of content, defaulting to two spaces
+func indentUnit(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// lineIndent returns the leading whitespace of the line containing offset
+func lineIndent(content string, offset int) string {
+	start := strings.LastIndex(content[:offset], "\n") + 1
+	line := content[start:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestInsertConnectionStrings(t *testing.T) {
+	entries := []connectionString{{Name: "Catalog", Value: "Server=db;Database=Shop"}, {Name: "CatalogMongo", Value: "mongodb://localhost:27017/Shop"}}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "existing section",
+			content: "{\n  \"ConnectionStrings\": {\n    \"Default\": \"Server=db\"\n  },\n  \"Redis\": {\n    \"Configuration\": \"127.0.0.1\"\n  }\n}",
+			want:    "{\n  \"ConnectionStrings\": {\n    \"Default\": \"Server=db\",\n    \"Catalog\": \"Server=db;Database=Shop\",\n    \"CatalogMongo\": \"mongodb://localhost:27017/Shop\"\n  },\n  \"Redis\": {\n    \"Configuration\": \"127.0.0.1\"\n  }\n}",
+		},
+		{
+			name:    "empty section",
+			content: "{\n    \"ConnectionStrings\": {}\n}",
+			want:    "{\n    \"ConnectionStrings\": {\n        \"Catalog\": \"Server=db;Database=Shop\",\n        \"CatalogMongo\": \"mongodb://localhost:27017/Shop\"\n    }\n}",
+		},
+		{
+			name:    "missing section",
+			content: "{\n  \"App\": {\n    \"SelfUrl\": \"https://localhost:44300\"\n  }\n}",
+			want:    "{\n  \"ConnectionStrings\": {\n    \"Catalog\": \"Server=db;Database=Shop\",\n    \"CatalogMongo\": \"mongodb://localhost:27017/Shop\"\n  },\n  \"App\": {\n    \"SelfUrl\": \"https://localhost:44300\"\n  }\n}",
+		},
+		{
+			name:    "comments and trailing commas",
+			content: "{\n  // Connection strings\n  \"ConnectionStrings\": {\n    \"Default\": \"Server=db\", /* local */\n  },\n}",
+			want:    "{\n  // Connection strings\n  \"ConnectionStrings\": {\n    \"Default\": \"Server=db\",\n    \"Catalog\": \"Server=db;Database=Shop\",\n    \"CatalogMongo\": \"mongodb://localhost:27017/Shop\", /* local */\n  },\n}",
+		},
+		{
+			name:    "empty document",
+			content: "{}",
+			want:    "{\n  \"ConnectionStrings\": {\n    \"Catalog\": \"Server=db;Database=Shop\",\n    \"CatalogMongo\": \"mongodb://localhost:27017/Shop\"\n  }\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := insertConnectionStrings(tt.content, entries)
+			if err != nil {
+				t.Fatalf("insertConnectionStrings() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("insertConnectionStrings() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingConnectionStrings_AcceptsComments(t *testing.T) {
+	settings := "{\n  /* generated */\n  \"ConnectionStrings\": {\n    // the module's database\n    \"Catalog\": \"Server=db // not a comment\",\n  },\n}"
+	entries := []connectionString{{Name: "Catalog"}, {Name: "CatalogMongo"}}
+
+	missing, err := missingConnectionStrings(settings, entries)
+	if err != nil {
+		t.Fatalf("missingConnectionStrings() error = %v", err)
+	}
+	if len(missing) != 1 || missing[0].Name != "CatalogMongo" {
+		t.Errorf("missingConnectionStrings() = %+v, want only CatalogMongo", missing)
+	}
+}
+
+func TestAppSettingsGenerator_MergesConnectionStrings(t *testing.T) {
+	// The targeted insert applies whether existing files are skipped, merged or overwritten
+	writers := []struct {
+		name      string
+		newWriter func() *writer.Writer
+	}{
+		{"default", func() *writer.Writer { return writer.NewWriter(false, false, false) }},
+		{"merge", func() *writer.Writer { return writer.NewWriterWithMerge(false, false, false, true) }},
+		{"force", func() *writer.Writer { return writer.NewWriter(false, true, false) }},
+	}
+
+	for _, tt := range writers {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.DBProvider = "both"
+			paths := newTestLayerPaths(t)
+
+			settingsPath := filepath.Join(filepath.Dir(paths.Domain), "Acme.Shop.HttpApi.Host", "appsettings.json")
+			if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			original := "{\r\n  \"ConnectionStrings\": {\r\n    \"Default\": \"Server=db;Database=Shop\",\r\n    \"CatalogMongo\": \"mongodb://mongo/Shop\"\r\n  }\r\n}\r\n"
+			if err := os.WriteFile(settingsPath, []byte(original), 0644); err != nil {
+				t.Fatal(err)
+			}
+			paths.AppSettingsFiles = []string{settingsPath}
+
+			w := tt.newWriter()
+			if err := NewAppSettingsGenerator(w).Generate(sch, paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			content, err := os.ReadFile(settingsPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "{\r\n  \"ConnectionStrings\": {\r\n    \"Default\": \"Server=db;Database=Shop\",\r\n    \"CatalogMongo\": \"mongodb://mongo/Shop\",\r\n    \"Catalog\": \"Server=db;Database=Shop\"\r\n  }\r\n}\r\n"
+			if string(content) != want {
+				t.Errorf("appsettings.json =\n%q\nwant\n%q", content, want)
+			}
+
+			// A second run finds every connection string declared and leaves the file alone
+			w = tt.newWriter()
+			if err := NewAppSettingsGenerator(w).Generate(sch, paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(w.Manifest()) != 0 {
+				t.Errorf("second run wrote %v, want no changes", w.Manifest())
+			}
+		})
+	}
+}
+
+func TestAppSettingsGenerator_WritesSnippetWithoutAppSettings(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	paths := newTestLayerPaths(t)
+	_, w := newTestGenerators()
+
+	if err := NewAppSettingsGenerator(w).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(w.Manifest()) != 0 {
+		t.Fatalf("single-provider module wrote %v, want nothing", w.Manifest())
+	}
+
+	sch.Solution.DBProvider = "both"
+	if err := NewAppSettingsGenerator(w).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	snippet := generatedContent(t, w, "appsettings.Catalog.json")
+	for _, want := range []string{
+		`"Catalog": "Server=localhost;Database=Shop;Trusted_Connection=True;TrustServerCertificate=True"`,
+		`"CatalogMongo": "mongodb://localhost:27017/Shop"`,
+	} {
+		if !strings.Contains(snippet, want) {
+			t.Errorf("snippet missing %s:\n%s", want, snippet)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// BackgroundWorkerGenerator generates periodic background workers and registers them in the Domain module
+type BackgroundWorkerGenerator struct {
+	tmplLoader *templates.Loader
+	writer     *writer.Writer
+}
+
+// NewBackgroundWorkerGenerator creates a new background worker generator
+func NewBackgroundWorkerGenerator(tmplLoader *templates.Loader, w *writer.Writer) *BackgroundWorkerGenerator {
+	return &BackgroundWorkerGenerator{
+		tmplLoader: tmplLoader,
+		writer:     w,
+	}
+}
+
+// Generate generates all workers declared on the solution. It is module-scoped and runs once per generation.
+func (g *BackgroundWorkerGenerator) Generate(sch *schema.Schema, paths *detector.LayerPaths) error {
+	for _, worker := range sch.Solution.Workers {
+		if err := g.generateWorker(sch, &worker, paths); err != nil {
+			return fmt.Errorf("failed to generate worker %s: %w", worker.Name, err)
+		}
+
+		if err := g.registerWorker(sch, &worker, paths); err != nil {
+			return fmt.Errorf("failed to register worker %s: %w", worker.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// generateWorker generates the AsyncPeriodicBackgroundWorkerBase implementation
+func (g *BackgroundWorkerGenerator) generateWorker(sch *schema.Schema, worker *schema.BackgroundWorker, paths *detector.LayerPaths) error {
+	tmpl, err := g.tmplLoader.Load("background_worker.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to load background worker template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"WorkerName":           worker.Name,
+		"Description":          worker.Description,
+		"IntervalSeconds":      worker.IntervalSeconds,
+		"IntervalMilliseconds": worker.IntervalSeconds * 1000,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute background worker template: %w", err)
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	outputPath := filepath.Join(paths.Domain, "BackgroundWorkers", moduleFolder, worker.Name+".cs")
+	return g.writer.WriteFile(outputPath, buf.String())
+}
+
+// initializationAsyncPattern matches the opening of an async OnApplicationInitialization override
+var initializationAsyncPattern = regexp.MustCompile(`(OnApplicationInitializationAsync\(\s*ApplicationInitializationContext\s+context\s*\)\s*\{)`)
+
+// initializationSyncPattern matches the opening of a synchronous OnApplicationInitialization override
+var initializationSyncPattern = regexp.MustCompile(`(void\s+OnApplicationInitialization\(\s*ApplicationInitializationContext\s+context\s*\)\s*\{)`)
+
+// registerWorker adds the worker to the Domain module's OnApplicationInitialization idempotently
+func (g *BackgroundWorkerGenerator) registerWorker(sch *schema.Schema, worker *schema.BackgroundWorker, paths *detector.LayerPaths) error {
+	modulePath := paths.GetDomainModulePath(sch.Solution.ModuleName)
+	if modulePath == "" {
+		return nil
+	}
+
+	// Registration is only possible in an existing module class
+	if _, err := os.Stat(modulePath); err != nil {
+		return nil
+	}
+
+	searchPattern := fmt.Sprintf("AddBackgroundWorkerAsync<%s>", worker.Name)
+	workerNamespace := fmt.Sprintf("%s.Domain.BackgroundWorkers.%s", sch.Solution.NamespaceRoot, sch.Solution.GetModuleNameWithSuffix())
+
+	return g.writer.UpdateFileIdempotent(modulePath, searchPattern, func(content string) (string, error) {
+		return addWorkerRegistration(content, worker.Name, workerNamespace)
+	}, nil)
+}
+
+// addWorkerRegistration inserts an AddBackgroundWorkerAsync call into a module class
+func addWorkerRegistration(content, workerName, workerNamespace string) (string, error) {
+	switch {
+	case initializationAsyncPattern.MatchString(content):
+		line := fmt.Sprintf("\n            await context.AddBackgroundWorkerAsync<%s>();", workerName)
+		content = initializationAsyncPattern.ReplaceAllString(content, "$1"+line)
+	case initializationSyncPattern.MatchString(content):
+		line := fmt.Sprintf("\n            AsyncHelper.RunSync(() => context.AddBackgroundWorkerAsync<%s>());", workerName)
+		content = initializationSyncPattern.ReplaceAllString(content, "$1"+line)
+		content = addUsing(content, "Volo.Abp.Threading")
+	default:
+		var err error
+		content, err = appendModuleMethod(content, []string{
+			"public override async Task OnApplicationInitializationAsync(ApplicationInitializationContext context)",
+			"{",
+			fmt.Sprintf("    await context.AddBackgroundWorkerAsync<%s>();", workerName),
+			"}",
+		})
+		if err != nil {
+			return "", err
+		}
+		content = addUsing(content, "System.Threading.Tasks")
+	}
+
+	content = addUsing(content, "Volo.Abp")
+	content = addUsing(content, "Volo.Abp.BackgroundWorkers")
+	content = addUsing(content, workerNamespace)
+	return content, nil
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestBackgroundWorkerGenerator_TimerPeriod(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Solution.Workers = []schema.BackgroundWorker{
+		{Name: "ExpiredCartCleanupWorker", IntervalSeconds: 60, Description: "Removes expired carts"},
+	}
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewBackgroundWorkerGenerator(loader, w).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "BackgroundWorkers/CatalogModule/ExpiredCartCleanupWorker.cs")
+	expected := []string{
+		"namespace Acme.Shop.Domain.BackgroundWorkers.CatalogModule",
+		"public class ExpiredCartCleanupWorker : AsyncPeriodicBackgroundWorkerBase",
+		"Timer.Period = 60000;",
+		"protected override async Task DoWorkAsync(PeriodicBackgroundWorkerContext workerContext)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("worker missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestBackgroundWorkerGenerator_RegistersWorkerOnce(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Solution.Workers = []schema.BackgroundWorker{{Name: "ExpiredCartCleanupWorker", IntervalSeconds: 60}}
+	paths := newTestLayerPaths(t)
+
+	// Standard solutions name the Domain module after the solution, not the schema's module
+	paths.DomainModule = filepath.Join(paths.Domain, "ShopDomainModule.cs")
+	modulePath := paths.DomainModule
+	if err := os.MkdirAll(paths.Domain, 0755); err != nil {
+		t.Fatal(err)
+	}
+	module := `using Volo.Abp.Modularity;
+
+namespace Acme.Shop
+{
+    public class ShopDomainModule : AbpModule
+    {
+    }
+}
+`
+	if err := os.WriteFile(modulePath, []byte(module), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewBackgroundWorkerGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+	for i := 0; i < 2; i++ {
+		if err := gen.Generate(sch, paths); err != nil {
+			t.Fatalf("Generate() run %d error = %v", i+1, err)
+		}
+	}
+
+	updated, err := os.ReadFile(modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(updated)
+
+	if got := strings.Count(content, "await context.AddBackgroundWorkerAsync<ExpiredCartCleanupWorker>();"); got != 1 {
+		t.Errorf("worker registered %d times; want 1:\n%s", got, content)
+	}
+	for _, want := range []string{
+		"public override async Task OnApplicationInitializationAsync(ApplicationInitializationContext context)",
+		"using Volo.Abp.BackgroundWorkers;",
+		"using Acme.Shop.Domain.BackgroundWorkers.CatalogModule;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("module missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
@@ -28,7 +29,7 @@ func NewCustomRepositoryGenerator(tmplLoader *templates.Loader, w *writer.Writer
 
 // Generate generates custom repository interface and implementation
 func (g *CustomRepositoryGenerator) Generate(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if entity.CustomRepository == nil || len(entity.CustomRepository.Methods) == 0 {
+	if !entity.HasCustomRepository() {
 		return nil // No custom repository defined
 	}
 
@@ -107,6 +108,7 @@ func (g *CustomRepositoryGenerator) generateEFCoreImplementation(sch *schema.Sch
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
 		"Methods":              entity.CustomRepository.Methods,
+		"MethodQueries":        methodQueries(entity),
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
@@ -116,7 +118,7 @@ func (g *CustomRepositoryGenerator) generateEFCoreImplementation(sch *schema.Sch
 	}
 
 	moduleFolder := sch.Solution.GetModuleFolderName()
-	repoPath := filepath.Join(paths.EFCoreRepositories, moduleFolder, entity.Name+"CustomRepository.cs")
+	repoPath := filepath.Join(paths.EFCoreRepositories, moduleFolder, "EfCore"+entity.Name+"Repository.Custom.cs")
 	return g.writer.WriteFile(repoPath, buf.String())
 }
 
@@ -140,6 +142,7 @@ func (g *CustomRepositoryGenerator) generateMongoDBImplementation(sch *schema.Sc
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
 		"Methods":              entity.CustomRepository.Methods,
+		"MethodQueries":        methodQueries(entity),
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
@@ -149,6 +152,81 @@ func (g *CustomRepositoryGenerator) generateMongoDBImplementation(sch *schema.Sc
 	}
 
 	moduleFolder := sch.Solution.GetModuleFolderName()
-	repoPath := filepath.Join(paths.MongoDBRepositories, moduleFolder, entity.Name+"CustomRepository.cs")
+	repoPath := filepath.Join(paths.MongoDBRepositories, moduleFolder, "Mongo"+entity.Name+"Repository.Custom.cs")
 	return g.writer.WriteFile(repoPath, buf.String())
 }
+
+// queryHintLambdaPattern matches a query hint written as a LINQ predicate, e.g. "x => x.Status == status"
+var queryHintLambdaPattern = regexp.MustCompile(`^\s*\(?\s*([A-Za-z_]\w*)\s*\)?\s*=>\s*(.+?)\s*$`)
+
+// queryHintLiteralPattern matches C# string and character literals in a query hint
+var queryHintLiteralPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// queryHintIdentifierPattern matches identifiers in a query hint
+var queryHintIdentifierPattern = regexp.MustCompile(`[A-Za-z_]\w*`)
+
+// methodQueries returns the C# statement implementing each custom repository method whose
+// query hint is a simple predicate, keyed by method name
+func methodQueries(entity *schema.Entity) map[string]string {
+	queries := make(map[string]string)
+	for _, method := range entity.CustomRepository.Methods {
+		if query := queryFromHint(method, entity.Name); query != "" {
+			queries[method.Name] = query
+		}
+	}
+	return queries
+}
+
+// queryFromHint builds a return statement over the variable "query" from a predicate query hint.
+// The operator follows the return type: a list filters, the entity finds the first match, int
+// and long count, and bool checks for any match. It returns "" when the hint is not a predicate
+// binding only the lambda and method parameters, or the return type has no matching operator.
+func queryFromHint(method schema.RepositoryMethod, entityName string) string {
+	match := queryHintLambdaPattern.FindStringSubmatch(method.QueryHint)
+	if match == nil || strings.ContainsAny(match[2], ";{}") {
+		return ""
+	}
+	lambda, body := match[1], match[2]
+
+	bound := map[string]bool{lambda: true, "true": true, "false": true, "null": true}
+	for _, param := range method.Parameters {
+		bound[param.Name] = true
+	}
+
+	// Every free lowercase identifier must be bound; member accesses and types such as
+	// enum names are left to the compiler
+	unquoted := queryHintLiteralPattern.ReplaceAllString(body, `""`)
+	for _, loc := range queryHintIdentifierPattern.FindAllStringIndex(unquoted, -1) {
+		if loc[0] > 0 {
+			prev := unquoted[loc[0]-1]
+			if prev == '.' || prev == '_' || (prev >= '0' && prev <= '9') || (prev >= 'A' && prev <= 'Z') || (prev >= 'a' && prev <= 'z') {
+				continue
+			}
+		}
+		identifier := unquoted[loc[0]:loc[1]]
+		if !bound[identifier] && identifier[0] >= 'a' && identifier[0] <= 'z' {
+			return ""
+		}
+	}
+
+	predicate := lambda + " => " + body
+	result := strings.TrimSpace(method.ReturnType)
+	if strings.HasPrefix(result, "Task<") && strings.HasSuffix(result, ">") {
+		result = strings.TrimSpace(result[len("Task<") : len(result)-1])
+	}
+
+	switch {
+	case strings.TrimSuffix(result, "?") == entityName:
+		return fmt.Sprintf("return await query.FirstOrDefaultAsync(%s);", predicate)
+	case result == "List<"+entityName+">" || result == "IEnumerable<"+entityName+">" ||
+		result == "IReadOnlyList<"+entityName+">" || result == "ICollection<"+entityName+">":
+		return fmt.Sprintf("return await query.Where(%s).ToListAsync();", predicate)
+	case result == "int":
+		return fmt.Sprintf("return await query.CountAsync(%s);", predicate)
+	case result == "long":
+		return fmt.Sprintf("return await query.LongCountAsync(%s);", predicate)
+	case result == "bool":
+		return fmt.Sprintf("return await query.AnyAsync(%s);", predicate)
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestCustomRepositoryGenerator_RepositoryInheritsCustomInterface(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		CustomRepository: &schema.CustomRepository{
+			Methods: []schema.RepositoryMethod{{
+				Name:       "GetBySkuAsync",
+				ReturnType: "Task<Product>",
+				Parameters: []schema.MethodParameter{{Name: "sku", Type: "string"}},
+			}},
+		},
+	})
+	entity := &sch.Entities[0]
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateRepository(sch, entity, paths); err != nil {
+		t.Fatalf("GenerateRepository() error = %v", err)
+	}
+	if err := NewCustomRepositoryGenerator(loader, w).Generate(sch, entity, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateRepository(sch, entity, paths); err != nil {
+		t.Fatalf("GenerateRepository() error = %v", err)
+	}
+
+	repository := generatedContent(t, w, "Repositories/CatalogModule/IProductRepository.cs")
+	if !strings.Contains(repository, "public interface IProductRepository : IRepository<Product, Guid>, IProductCustomRepository") {
+		t.Errorf("IProductRepository does not inherit IProductCustomRepository:\n%s", repository)
+	}
+
+	custom := generatedContent(t, w, "Repositories/CatalogModule/IProductCustomRepository.cs")
+	if !strings.Contains(custom, "public interface IProductCustomRepository\n") {
+		t.Errorf("IProductCustomRepository must not inherit the standard repository:\n%s", custom)
+	}
+
+	efRepository := generatedContent(t, w, "Repositories/CatalogModule/EfCoreProductRepository.cs")
+	if !strings.Contains(efRepository, "public partial class EfCoreProductRepository : EfCoreRepository<") {
+		t.Errorf("EF repository is not partial:\n%s", efRepository)
+	}
+
+	efCustom := generatedContent(t, w, "Repositories/CatalogModule/EfCoreProductRepository.Custom.cs")
+	for _, want := range []string{
+		"public partial class EfCoreProductRepository : IProductCustomRepository",
+		"public async Task<Product> GetBySkuAsync(string sku)",
+	} {
+		if !strings.Contains(efCustom, want) {
+			t.Errorf("EF custom repository part missing %q:\n%s", want, efCustom)
+		}
+	}
+}
+
+func TestEntityGenerator_RepositoryWithoutCustomMethods(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateRepository(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateRepository() error = %v", err)
+	}
+
+	repository := generatedContent(t, w, "Repositories/CatalogModule/IProductRepository.cs")
+	if strings.Contains(repository, "CustomRepository") {
+		t.Errorf("IProductRepository references a custom repository that was not requested:\n%s", repository)
+	}
+}
+
+func TestQueryFromHint(t *testing.T) {
+	status := []schema.MethodParameter{{Name: "status", Type: "ProductStatus"}}
+	tests := []struct {
+		name       string
+		hint       string
+		returnType string
+		params     []schema.MethodParameter
+		want       string
+	}{
+		{"list filter", "x => x.Status == status", "Task<List<Product>>", status, "return await query.Where(x => x.Status == status).ToListAsync();"},
+		{"single entity", "p => p.Sku == sku", "Task<Product?>", []schema.MethodParameter{{Name: "sku", Type: "string"}}, "return await query.FirstOrDefaultAsync(p => p.Sku == sku);"},
+		{"count with literals", `x => x.Name.StartsWith("a b") && x.Price > 10m && x.Status != ProductStatus.Draft`, "Task<int>", nil, `return await query.CountAsync(x => x.Name.StartsWith("a b") && x.Price > 10m && x.Status != ProductStatus.Draft);`},
+		{"any", "(x) => x.IsActive", "Task<bool>", nil, "return await query.AnyAsync(x => x.IsActive);"},
+		{"unbound parameter", "x => x.Status == status", "Task<List<Product>>", nil, ""},
+		{"SQL hint", "WITH (NOLOCK)", "Task<List<Product>>", nil, ""},
+		{"statement body", "x => { return x.IsActive; }", "Task<bool>", nil, ""},
+		{"unsupported return type", "x => x.IsActive", "Task<List<ProductDto>>", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := schema.RepositoryMethod{Name: "FindAsync", ReturnType: tt.returnType, Parameters: tt.params, QueryHint: tt.hint}
+			if got := queryFromHint(method, "Product"); got != tt.want {
+				t.Errorf("queryFromHint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomRepositoryGenerator_QueryHintBodies(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		CustomRepository: &schema.CustomRepository{
+			Methods: []schema.RepositoryMethod{
+				{
+					Name:       "GetByNameAsync",
+					ReturnType: "Task<List<Product>>",
+					Parameters: []schema.MethodParameter{{Name: "name", Type: "string"}},
+					QueryHint:  "x => x.Name == name",
+				},
+				{
+					Name:       "GetTopSellersAsync",
+					ReturnType: "Task<List<Product>>",
+					QueryHint:  "ORDER BY Sales DESC",
+				},
+			},
+		},
+	})
+	sch.Solution.DBProvider = "both"
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewCustomRepositoryGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, suffix := range []string{"EfCoreProductRepository.Custom.cs", "MongoProductRepository.Custom.cs"} {
+		content := generatedContent(t, w, suffix)
+		for _, want := range []string{
+			"            // Query hint: x => x.Name == name\n            return await query.Where(x => x.Name == name).ToListAsync();\n        }",
+			"            // Query hint: ORDER BY Sales DESC\n",
+			`throw new NotImplementedException("GetTopSellersAsync is not yet implemented");`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q:\n%s", suffix, want, content)
+			}
+		}
+		if strings.Count(content, "NotImplementedException(") != 1 {
+			t.Errorf("%s should only stub the method without a predicate hint:\n%s", suffix, content)
+		}
+	}
+}
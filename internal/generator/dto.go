@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -55,6 +56,11 @@ func (g *DTOGenerator) Generate(sch *schema.Schema, entity *schema.Entity, paths
 		return err
 	}
 
+	// Generate the filtered list input
+	if err := g.GenerateGetListInput(sch, entity, paths); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -120,8 +126,15 @@ func (g *DTOGenerator) GenerateEntityDto(sch *schema.Schema, entity *schema.Enti
 		"HasRelations":            entity.HasRelations(),
 		"OneToManyRelations":      getOneToManyRelations(entity),
 		"ManyToManyRelations":     getManyToManyRelations(entity),
+		"DetailNavigations":       getDetailNavigations(entity),
+		"DetailDtoEntities":       detailDtoEntities(entity),
 		"HasEnumProperties":       entity.HasEnumProperties(),
 		"EnumNames":               entity.GetEnumNames(),
+		"HasStronglyTypedId":      entity.HasStronglyTypedId(),
+		"BaseEntity":              entity.BaseEntity,
+		"DtoBaseType":             entityDtoBaseType(entity),
+		"DisplayAttributes":       displayAttributes(sch, entity),
+		"HasConcurrencyStamp":     sch.HasConcurrencyStamp(entity),
 	}
 
 	var buf bytes.Buffer
@@ -135,6 +148,72 @@ func (g *DTOGenerator) GenerateEntityDto(sch *schema.Schema, entity *schema.Enti
 	return g.writer.WriteFile(filePath, buf.String())
 }
 
+// GenerateGetListInput generates the paged list input with a filter for each filterable property
+func (g *DTOGenerator) GenerateGetListInput(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	if !hasListInput(entity) {
+		return nil
+	}
+
+	tmpl, err := g.tmplLoader.Load("get_list_input_dto.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to load list input DTO template: %w", err)
+	}
+
+	hasEnumFilters := false
+	for _, prop := range entity.GetFilterableProperties() {
+		if prop.IsEnum {
+			hasEnumFilters = true
+		}
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityName":           entity.Name,
+		"Fields":               getQueryFilterFields(entity),
+		"HasEnumFilters":       hasEnumFilters,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute list input DTO template: %w", err)
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	dtoPath := paths.GetEntityDTOPath(moduleFolder, entity.Name)
+	filePath := filepath.Join(dtoPath, "Get"+entity.Name+"ListInput.cs")
+	return g.writer.WriteFile(filePath, buf.String())
+}
+
+// entityDtoBaseType returns the ABP DTO base class carrying the audit fields of the entity's base class
+func entityDtoBaseType(entity *schema.Entity) string {
+	switch entity.EntityType {
+	case "CreationAuditedAggregateRoot":
+		return "CreationAuditedEntityDto"
+	case "AuditedAggregateRoot":
+		return "AuditedEntityDto"
+	case "FullAuditedAggregateRoot":
+		return "FullAuditedEntityDto"
+	default:
+		return "EntityDto"
+	}
+}
+
+// hasListInput checks if the entity's list endpoints take a filtered Get{Entity}ListInput
+func hasListInput(entity *schema.Entity) bool {
+	return entity.EntityType != "ValueObject" && len(entity.GetFilterableProperties()) > 0
+}
+
+// listInputType returns the TGetListInput type of the entity's CRUD application service
+func listInputType(entity *schema.Entity) string {
+	if hasListInput(entity) {
+		return "Get" + entity.Name + "ListInput"
+	}
+	return "PagedAndSortedResultRequestDto"
+}
+
 // prepareDtoData prepares common data for DTO templates
 func (g *DTOGenerator) prepareDtoData(sch *schema.Schema, entity *schema.Entity) map[string]interface{} {
 	return map[string]interface{}{
@@ -143,13 +222,78 @@ func (g *DTOGenerator) prepareDtoData(sch *schema.Schema, entity *schema.Entity)
 		"ModuleNameWithSuffix":    sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":           sch.Solution.NamespaceRoot,
 		"EntityName":              entity.Name,
+		"BaseEntity":              entity.BaseEntity,
 		"Properties":              entity.Properties,
 		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
+		"InputProperties":         entity.GetInputProperties(),
 		"ForeignKeyProperties":    entity.GetForeignKeyProperties(),
 		"HasEnumProperties":       entity.HasEnumProperties(),
 		"EnumNames":               entity.GetEnumNames(),
 		"NeedsDataAnnotations":    entity.NeedsDataAnnotations(),
+		"TargetFramework":         string(sch.Solution.TargetFramework),
+		"UseRecords":              useModernDtos(sch),
+		"UseRequiredMembers":      useModernDtos(sch),
+		"DisplayAttributes":       displayAttributes(sch, entity),
+		"HasConcurrencyStamp":     sch.HasConcurrencyStamp(entity),
+	}
+}
+
+// displayAttributes returns the [Display] attribute of every property with display metadata, keyed by
+// property name. With localization enabled the name is the property's localization key.
+func displayAttributes(sch *schema.Schema, entity *schema.Entity) map[string]string {
+	attributes := make(map[string]string)
+	for _, prop := range entity.Properties {
+		if !prop.HasDisplayMetadata() {
+			continue
+		}
+
+		var args []string
+		name := prop.DisplayName
+		if sch.Options.UseLocalization {
+			name = entity.Name + "." + prop.Name
+		}
+		if name != "" {
+			args = append(args, fmt.Sprintf("Name = %q", name))
+		}
+		if prop.DisplayOrder != 0 {
+			args = append(args, fmt.Sprintf("Order = %d", prop.DisplayOrder))
+		}
+		attributes[prop.Name] = "[Display(" + strings.Join(args, ", ") + ")]"
+	}
+	return attributes
+}
+
+// useModernDtos checks if input DTOs can be C# records with required members. Only plain
+// ASP.NET Core targets opt in: ABP solutions keep classes for their object mappers.
+func useModernDtos(sch *schema.Schema) bool {
+	target := sch.Solution.TargetFramework
+	return target.IsASPNETCore() && templates.LangVersion(string(target)) >= 11
+}
+
+// detailDtoEntities returns the related entities, other than the entity itself, whose DTOs
+// the read DTO references through eager-loaded navigations
+func detailDtoEntities(entity *schema.Entity) []string {
+	var names []string
+	seen := map[string]bool{entity.Name: true}
+	for _, nav := range getDetailNavigations(entity) {
+		if !seen[nav.TargetEntity] {
+			seen[nav.TargetEntity] = true
+			names = append(names, nav.TargetEntity)
+		}
+	}
+	return names
+}
+
+// undeclaredDetailEntities returns the detail DTO entities the schema does not declare. Their
+// DTO mappings are not generated with their own profiles, so the referencing profile adds them.
+func undeclaredDetailEntities(sch *schema.Schema, entity *schema.Entity) []string {
+	var names []string
+	for _, name := range detailDtoEntities(entity) {
+		if sch.FindEntity(name) == nil {
+			names = append(names, name)
+		}
 	}
+	return names
 }
 
 // GenerateAppServiceInterface generates the application service interface
@@ -171,7 +315,16 @@ func (g *DTOGenerator) GenerateAppServiceInterface(sch *schema.Schema, entity *s
 		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
+		"EntityNamePlural":     templates.Pluralize(entity.Name),
 		"PrimaryKeyType":       primaryKeyType,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"HasQueryFilter":       hasQueryFilter(sch, entity),
+		"ListInputType":        listInputType(entity),
+		"Operations":           getServiceOperations(entity),
+		"IsCrud":               entity.HasAllOperations(),
+		"Description":          entity.Description,
+		"CustomEndpoints":      getCustomEndpoints(entity),
+		"FileProperties":       entity.GetFileProperties(),
 	}
 
 	var buf bytes.Buffer
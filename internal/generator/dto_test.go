@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestDTOGenerator_GetListInputFiltersFilterableProperties(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsFilterable: true},
+			{Name: "Status", Type: "ProductStatus", IsEnum: true, EnumName: "ProductStatus", IsFilterable: true},
+			{Name: "Price", Type: "decimal"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	input := generatedContent(t, w, "Product/GetProductListInput.cs")
+	for _, want := range []string{
+		"public class GetProductListInput : PagedAndSortedResultRequestDto",
+		"public string Name { get; set; }",
+		"public ProductStatus? Status { get; set; }",
+	} {
+		if !strings.Contains(input, want) {
+			t.Errorf("list input missing %q\n%s", want, input)
+		}
+	}
+	if strings.Contains(input, "Price") {
+		t.Errorf("list input filters non-filterable Price:\n%s", input)
+	}
+
+	service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+	for _, want := range []string{
+		"GetListAsync(GetProductListInput input)",
+		".WhereIf(!input.Name.IsNullOrWhiteSpace(), x => x.Name == input.Name)",
+		".WhereIf(input.Status.HasValue, x => x.Status == input.Status)",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("app service missing %q\n%s", want, service)
+		}
+	}
+}
+
+func TestDTOGenerator_GetListInputSkippedWithoutFilters(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewDTOGenerator(loader, w)
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := gen.GenerateAppServiceInterface(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAppServiceInterface() error = %v", err)
+	}
+
+	for _, op := range w.Operations {
+		if strings.HasSuffix(op.Path, "GetProductListInput.cs") {
+			t.Errorf("unexpected list input generated at %s", op.Path)
+		}
+	}
+	iface := generatedContent(t, w, "Services/CatalogModule/IProductAppService.cs")
+	if !strings.Contains(iface, "PagedAndSortedResultRequestDto,") {
+		t.Errorf("interface does not fall back to PagedAndSortedResultRequestDto:\n%s", iface)
+	}
+}
+
+func TestDTOGenerator_ReadOnlyPropertiesExcludedFromInput(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsRequired: true, MaxLength: 100},
+			{Name: "Slug", Type: "string", IsRequired: true, MaxLength: 120, ReadOnly: true},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewValidatorGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, suffix := range []string{
+		"Product/CreateProductDto.cs",
+		"Product/UpdateProductDto.cs",
+		"Validators/CatalogModule/CreateProductDtoValidator.cs",
+		"Validators/CatalogModule/UpdateProductDtoValidator.cs",
+	} {
+		content := generatedContent(t, w, suffix)
+		if !strings.Contains(content, "Name") {
+			t.Errorf("%s missing writable property Name:\n%s", suffix, content)
+		}
+		if strings.Contains(content, "Slug") {
+			t.Errorf("%s includes read-only property Slug:\n%s", suffix, content)
+		}
+	}
+
+	dto := generatedContent(t, w, "Product/ProductDto.cs")
+	if !strings.Contains(dto, "public string Slug { get; set; }") {
+		t.Errorf("read DTO missing read-only property Slug:\n%s", dto)
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	for _, want := range []string{
+		"public string Slug { get; set; }",
+		"public Product(Guid id, string name) : base(id)",
+		"public void Update(string name)",
+	} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity missing %q:\n%s", want, entity)
+		}
+	}
+}
+
+func TestDTOGenerator_PropertyDescriptions(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsRequired: true, Description: "Display name shown to customers"},
+			{Name: "Sku", Type: "string"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, suffix := range []string{
+		"Product/CreateProductDto.cs",
+		"Product/UpdateProductDto.cs",
+		"Product/ProductDto.cs",
+	} {
+		content := generatedContent(t, w, suffix)
+		if !strings.Contains(content, "        /// <summary>\n        /// Display name shown to customers\n        /// </summary>\n") {
+			t.Errorf("%s missing Name description:\n%s", suffix, content)
+		}
+		if strings.Count(content, "/// <summary>") != 1 {
+			t.Errorf("%s documents properties without a description:\n%s", suffix, content)
+		}
+	}
+}
+
+func TestDTOGenerator_DisplayMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		useLocalization bool
+		wantName        string
+		wantSku         string
+	}{
+		{"literal name", false, `[Display(Name = "Product name", Order = 1)]`, `[Display(Order = 2)]`},
+		{"localized name", true, `[Display(Name = "Product.Name", Order = 1)]`, `[Display(Name = "Product.Sku", Order = 2)]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:       "Product",
+				EntityType: "FullAuditedAggregateRoot",
+				Properties: []schema.Property{
+					{Name: "Name", Type: "string", DisplayName: "Product name", DisplayOrder: 1},
+					{Name: "Sku", Type: "string", DisplayOrder: 2},
+					{Name: "Notes", Type: "string"},
+				},
+			})
+			sch.Options.UseLocalization = tt.useLocalization
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			for _, suffix := range []string{
+				"Product/CreateProductDto.cs",
+				"Product/UpdateProductDto.cs",
+				"Product/ProductDto.cs",
+			} {
+				content := generatedContent(t, w, suffix)
+				for _, want := range []string{
+					"using System.ComponentModel.DataAnnotations;",
+					"        " + tt.wantName + "\n        public string Name { get; set; }",
+					"        " + tt.wantSku + "\n        public string Sku { get; set; }",
+				} {
+					if !strings.Contains(content, want) {
+						t.Errorf("%s missing %q:\n%s", suffix, want, content)
+					}
+				}
+				if got := strings.Count(content, "[Display("); got != 2 {
+					t.Errorf("%s has %d display attributes; want 2:\n%s", suffix, got, content)
+				}
+			}
+		})
+	}
+}
+
+func TestDTOGenerator_ModernTargets(t *testing.T) {
+	tests := []struct {
+		target schema.TargetFramework
+		modern bool
+	}{
+		{schema.TargetASPNETCore9, true},
+		{schema.TargetASPNETCore10, true},
+		{schema.TargetABP9Monolith, false},
+		{schema.TargetAuto, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.target), func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:       "Product",
+				EntityType: "FullAuditedAggregateRoot",
+				Properties: []schema.Property{
+					{Name: "Name", Type: "string", IsRequired: true},
+					{Name: "Stock", Type: "int", IsRequired: true, DefaultValue: "0"},
+					{Name: "Notes", Type: "string"},
+				},
+			})
+			sch.Solution.TargetFramework = tt.target
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			create := generatedContent(t, w, "Product/CreateProductDto.cs")
+			want := map[string]bool{
+				"public record CreateProductDto":            tt.modern,
+				"public class CreateProductDto":             !tt.modern,
+				"public required string Name { get; set; }": tt.modern,
+				"public string Name { get; set; }":          !tt.modern,
+				"public int Stock { get; set; } = 0;":       true,
+				"public string Notes { get; set; }":         true,
+			}
+			for snippet, present := range want {
+				if strings.Contains(create, snippet) != present {
+					t.Errorf("create DTO contains %q = %t, want %t\n%s", snippet, !present, present, create)
+				}
+			}
+
+			update := generatedContent(t, w, "Product/UpdateProductDto.cs")
+			if strings.Contains(update, "public record UpdateProductDto") != tt.modern {
+				t.Errorf("update DTO record = %t, want %t\n%s", !tt.modern, tt.modern, update)
+			}
+		})
+	}
+}
+
+func TestDTOGenerator_BaseEntity(t *testing.T) {
+	sch := newHierarchySchema(t)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	derived := &sch.Entities[1]
+	if err := NewDTOGenerator(loader, w).Generate(sch, derived, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewValidatorGenerator(loader, w).Generate(sch, derived, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for suffix, want := range map[string]string{
+		"BusinessCustomer/CreateBusinessCustomerDto.cs": "public class CreateBusinessCustomerDto : CreateCustomerDto",
+		"BusinessCustomer/UpdateBusinessCustomerDto.cs": "public class UpdateBusinessCustomerDto : UpdateCustomerDto",
+		"BusinessCustomer/BusinessCustomerDto.cs":       "public class BusinessCustomerDto : CustomerDto",
+		"CreateBusinessCustomerDtoValidator.cs":         "Include(new CreateCustomerDtoValidator());",
+		"UpdateBusinessCustomerDtoValidator.cs":         "Include(new UpdateCustomerDtoValidator());",
+	} {
+		content := generatedContent(t, w, suffix)
+		if !strings.Contains(content, want) {
+			t.Errorf("%s missing %q:\n%s", suffix, want, content)
+		}
+		if strings.HasSuffix(suffix, "Dto.cs") {
+			if !strings.Contains(content, "using Acme.Shop.Application.Contracts.CustomerModule;") {
+				t.Errorf("%s does not import the base DTO namespace:\n%s", suffix, content)
+			}
+			if strings.Contains(content, " Email ") {
+				t.Errorf("%s redeclares inherited properties:\n%s", suffix, content)
+			}
+		}
+	}
+}
@@ -3,8 +3,10 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -37,6 +39,11 @@ func (g *EFCoreGenerator) Generate(sch *schema.Schema, entity *schema.Entity, pa
 		return err
 	}
 
+	// Generate value converter for strongly-typed IDs
+	if err := g.GenerateValueConverter(sch, entity, paths); err != nil {
+		return err
+	}
+
 	// Generate entity configuration
 	if err := g.GenerateConfiguration(sch, entity, paths); err != nil {
 		return err
@@ -58,7 +65,124 @@ func (g *EFCoreGenerator) Generate(sch *schema.Schema, entity *schema.Entity, pa
 	}
 
 	// Update IDbContext
-	return g.UpdateIDbContext(sch, entity, paths)
+	if err := g.UpdateIDbContext(sch, entity, paths); err != nil {
+		return err
+	}
+
+	// Register the repository in the EF Core module
+	return g.RegisterRepository(sch, entity, paths)
+}
+
+// getTableName returns the entity's table name, without the module table prefix, defaulting to the plural entity name
+func getTableName(entity *schema.Entity) string {
+	if entity.TableName != "" {
+		return entity.TableName
+	}
+	return templates.Pluralize(entity.Name)
+}
+
+// derivedEntityNames returns the names of the entities stored in the entity's table through table-per-hierarchy
+func derivedEntityNames(sch *schema.Schema, entity *schema.Entity) []string {
+	var names []string
+	for _, derived := range sch.GetDerivedEntities(entity) {
+		names = append(names, derived.Name)
+	}
+	return names
+}
+
+// inverseCollections maps the foreign keys of the entity's many-to-one relations to the collection
+// navigation of the matching one-to-many relation declared on the target, so both sides configure
+// a single relationship
+func inverseCollections(sch *schema.Schema, entity *schema.Entity) map[string]string {
+	inverses := make(map[string]string)
+	for _, rel := range getManyToOneRelations(entity) {
+		target := sch.FindEntity(rel.TargetEntity)
+		if target == nil {
+			continue
+		}
+		for _, inverse := range getOneToManyRelations(target) {
+			if inverse.TargetEntity == entity.Name && oneToManyForeignKey(target, inverse) == rel.ForeignKeyName {
+				inverses[rel.ForeignKeyName] = oneToManyNavigation(inverse)
+				break
+			}
+		}
+	}
+	return inverses
+}
+
+// inverseReferences maps the foreign keys of the entity's one-to-many relations to the reference
+// navigation of the matching many-to-one relation declared on the target
+func inverseReferences(sch *schema.Schema, entity *schema.Entity) map[string]string {
+	inverses := make(map[string]string)
+	for _, rel := range getOneToManyRelations(entity) {
+		target := sch.FindEntity(rel.TargetEntity)
+		if target == nil {
+			continue
+		}
+		foreignKey := oneToManyForeignKey(entity, rel)
+		for _, inverse := range getManyToOneRelations(target) {
+			if inverse.TargetEntity == entity.Name && inverse.ForeignKeyName == foreignKey {
+				inverses[foreignKey] = inverse.NavigationProperty
+				break
+			}
+		}
+	}
+	return inverses
+}
+
+// oneToManyForeignKey returns the foreign key of a one-to-many relation declared on parent
+func oneToManyForeignKey(parent *schema.Entity, rel schema.OneToManyRelation) string {
+	if rel.ForeignKeyName != "" {
+		return rel.ForeignKeyName
+	}
+	return parent.Name + "Id"
+}
+
+// oneToManyNavigation returns the collection navigation of a one-to-many relation
+func oneToManyNavigation(rel schema.OneToManyRelation) string {
+	if rel.NavigationProperty != "" {
+		return rel.NavigationProperty
+	}
+	return templates.Pluralize(rel.TargetEntity)
+}
+
+// foreignKeyIndexes returns the foreign keys that need an explicit index. EF Core already indexes
+// the foreign key of every relationship configured here, and re-creates that index when a
+// configuration removes it, so only foreign keys declared without a relation are indexed here.
+// Foreign keys leading a declared index are left to that index.
+func foreignKeyIndexes(entity *schema.Entity) []string {
+	declared := make(map[string]bool)
+	for _, index := range entity.Indexes {
+		if len(index.Properties) > 0 {
+			declared[index.Properties[0]] = true
+		}
+	}
+
+	related := make(map[string]bool)
+	addRelated := func(foreignKey, targetEntity string) {
+		if foreignKey == "" {
+			foreignKey = targetEntity + "Id"
+		}
+		related[foreignKey] = true
+	}
+	if entity.Relations != nil {
+		for _, rel := range entity.Relations.OneToOne {
+			if !rel.IsOwned {
+				addRelated(rel.ForeignKeyName, rel.TargetEntity)
+			}
+		}
+		for _, rel := range entity.Relations.ManyToOne {
+			addRelated(rel.ForeignKeyName, rel.TargetEntity)
+		}
+	}
+
+	var indexed []string
+	for _, prop := range entity.Properties {
+		if prop.IsForeignKey && !related[prop.Name] && !declared[prop.Name] {
+			indexed = append(indexed, prop.Name)
+		}
+	}
+	return indexed
 }
 
 // GenerateDbProperties generates the DbProperties class for the module
@@ -73,6 +197,11 @@ func (g *EFCoreGenerator) GenerateDbProperties(sch *schema.Schema, paths *detect
 		"ModuleName":           sch.Solution.ModuleName,
 		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"TablePrefix":          sch.Solution.TablePrefix,
+		"ConnectionStringName": efCoreConnectionStringName(sch),
+	}
+	if sch.Solution.DBProvider == "both" {
+		data["MongoConnectionStringName"] = mongoConnectionStringName(sch)
 	}
 
 	var buf bytes.Buffer
@@ -97,12 +226,25 @@ func (g *EFCoreGenerator) GenerateConfiguration(sch *schema.Schema, entity *sche
 		"ModuleName":           sch.Solution.ModuleName,
 		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"TargetFramework":      string(sch.Solution.TargetFramework),
 		"EntityName":           entity.Name,
-		"TableName":            entity.TableName,
+		"TableName":            getTableName(entity),
+		"PrimaryKeyType":       entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType),
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"BaseEntity":           entity.BaseEntity,
+		"DerivedEntities":      derivedEntityNames(sch, entity),
 		"Properties":           entity.Properties,
+		"StringEnumProperties": stringEnumProperties(sch, entity),
 		"HasRelations":         entity.HasRelations(),
+		"OneToOneRelations":    getOneToOneRelations(entity),
 		"OneToManyRelations":   getOneToManyRelations(entity),
+		"ManyToOneRelations":   getManyToOneRelations(entity),
 		"ManyToManyRelations":  getManyToManyRelations(entity),
+		"InverseCollections":   inverseCollections(sch, entity),
+		"InverseReferences":    inverseReferences(sch, entity),
+		"Indexes":              entity.Indexes,
+		"ForeignKeyIndexes":    foreignKeyIndexes(entity),
+		"MultiTenancy":         NewMultiTenancyHelper().BuildMultiTenancyConfig(sch, entity),
 	}
 
 	var buf bytes.Buffer
@@ -115,6 +257,55 @@ func (g *EFCoreGenerator) GenerateConfiguration(sch *schema.Schema, entity *sche
 	return g.writer.WriteFile(filePath, buf.String())
 }
 
+// stringEnumProperties maps the entity's enum properties whose enum has a string underlying type,
+// so they are stored by member name, to the length of the longest member name
+func stringEnumProperties(sch *schema.Schema, entity *schema.Entity) map[string]int {
+	properties := make(map[string]int)
+	for _, prop := range entity.Properties {
+		enum := sch.FindPropertyEnum(prop)
+		if enum == nil || !enum.IsStringBacked() {
+			continue
+		}
+		for _, value := range enum.Values {
+			if len(value.Name) > properties[prop.Name] {
+				properties[prop.Name] = len(value.Name)
+			}
+		}
+	}
+	return properties
+}
+
+// GenerateValueConverter generates the EF Core value converter for a strongly-typed ID
+func (g *EFCoreGenerator) GenerateValueConverter(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	if !entity.HasStronglyTypedId() || entity.BaseEntity != "" {
+		return nil
+	}
+
+	tmpl, err := g.tmplLoader.Load("efcore_value_converter.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to load EF Core value converter template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityName":           entity.Name,
+		"IdTypeName":           entity.PrimaryKeyType,
+		"UnderlyingType":       entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute EF Core value converter template: %w", err)
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	filePath := filepath.Join(paths.EntityFrameworkCore, "EntityFrameworkCore", "ValueConverters", moduleFolder, entity.PrimaryKeyType+"ValueConverter.cs")
+	return g.writer.WriteFile(filePath, buf.String())
+}
+
 // GenerateRepository generates EF Core repository implementation
 func (g *EFCoreGenerator) GenerateRepository(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
 	tmpl, err := g.tmplLoader.Load("efcore_repository.tmpl")
@@ -131,6 +322,9 @@ func (g *EFCoreGenerator) GenerateRepository(sch *schema.Schema, entity *schema.
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"HasCustomRepository":  entity.HasCustomRepository(),
+		"DetailNavigations":    getDetailNavigations(entity),
 	}
 
 	var buf bytes.Buffer
@@ -145,7 +339,8 @@ func (g *EFCoreGenerator) GenerateRepository(sch *schema.Schema, entity *schema.
 
 // UpdateDbContext updates the DbContext to add DbSet
 func (g *EFCoreGenerator) UpdateDbContext(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	dbContextPath := paths.GetDbContextPath(sch.Solution.ModuleName)
+	// Reuse the solution's DbContext, which may be named after the solution rather than the module
+	dbContextPath, dbContextName := paths.ResolveDbContext(sch.Solution.ModuleName)
 
 	// Check if DbSet already exists
 	searchPattern := fmt.Sprintf("DbSet<%s>", entity.Name)
@@ -168,13 +363,13 @@ using %s.Domain.Entities.%s;
 namespace %s.EntityFrameworkCore
 {
     [ConnectionStringName("%s")]
-    public class %sDbContext : AbpDbContext<%sDbContext>
+    public class %s : AbpDbContext<%s>
     {
         /* Add DbSet properties here. Example:
          * public DbSet<Question> Questions { get; set; }
          */
 %s
-        public %sDbContext(DbContextOptions<%sDbContext> options)
+        public %s(DbContextOptions<%s> options)
             : base(options)
         {
         }
@@ -183,13 +378,11 @@ namespace %s.EntityFrameworkCore
         {
             base.OnModelCreating(builder);
 
-            /* Configure your own tables/entities inside here */
-
-            builder.ApplyConfiguration(new %sConfiguration());
+            builder.Configure%s();
         }
     }
 }
-`, namespaceRoot, moduleNamespace, namespaceRoot, moduleNamespace, namespaceRoot, moduleName, moduleName, moduleName, dbSetProperty, moduleName, moduleName, entity.Name)
+`, namespaceRoot, moduleNamespace, namespaceRoot, moduleNamespace, namespaceRoot, moduleName, dbContextName, dbContextName, dbSetProperty, dbContextName, dbContextName, moduleName)
 		return content, nil
 	}
 
@@ -205,9 +398,69 @@ namespace %s.EntityFrameworkCore
 	}, createInitialContent)
 }
 
-// UpdateIDbContext updates the IDbContext interface to add DbSet
+// addAbpDbContextPattern matches the opening of the options lambda passed to AddAbpDbContext
+var addAbpDbContextPattern = regexp.MustCompile(`(?m)^([ \t]*).*AddAbpDbContext<\w+>\(\s*(\w+)\s*=>\s*\{`)
+
+// RegisterRepository registers the entity's EF Core repository in the EntityFrameworkCore module idempotently.
+// The registration joins an existing AddAbpDbContext call, or adds one to ConfigureServices.
+func (g *EFCoreGenerator) RegisterRepository(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	modulePath := paths.GetEFCoreModulePath(sch.Solution.ModuleName)
+	if modulePath == "" {
+		return nil
+	}
+
+	// Registration is only possible in an existing module class
+	if _, err := os.Stat(modulePath); err != nil {
+		return nil
+	}
+
+	_, dbContextName := paths.ResolveDbContext(sch.Solution.ModuleName)
+	registration := fmt.Sprintf("AddRepository<%s, EfCore%sRepository>()", entity.Name, entity.Name)
+	moduleNamespace := sch.Solution.GetModuleNameWithSuffix()
+
+	return g.writer.UpdateFileIdempotent(modulePath, registration, func(content string) (string, error) {
+		var updated string
+		if match := addAbpDbContextPattern.FindStringSubmatchIndex(content); match != nil {
+			closeBrace := matchingBrace(content, match[1]-1)
+			if closeBrace < 0 {
+				return "", fmt.Errorf("AddAbpDbContext options are not closed")
+			}
+			// Append after the last statement of the options lambda
+			insertAt := strings.LastIndex(content[:closeBrace], "\n")
+			indent := content[match[2]:match[3]] + "    "
+			options := content[match[4]:match[5]]
+			updated = content[:insertAt] + "\n" + indent + options + "." + registration + ";" + content[insertAt:]
+		} else {
+			var err error
+			updated, err = addConfigureServicesStatements(content, []string{
+				fmt.Sprintf("context.Services.AddAbpDbContext<%s>(options =>", dbContextName),
+				"{",
+				"    options.AddDefaultRepositories(includeAllEntities: true);",
+				"    options." + registration + ";",
+				"});",
+			})
+			if err != nil {
+				return "", err
+			}
+			updated = addUsing(updated, "Volo.Abp.Modularity")
+		}
+
+		updated = addUsing(updated, sch.Solution.NamespaceRoot+".Domain.Entities."+moduleNamespace)
+		updated = addUsing(updated, sch.Solution.NamespaceRoot+".EntityFrameworkCore.Repositories."+moduleNamespace)
+		return updated, nil
+	}, nil)
+}
+
+// UpdateIDbContext adds the DbSet to the IDbContext interface. Only an existing interface is
+// updated: the DbContext created by UpdateDbContext does not implement one.
 func (g *EFCoreGenerator) UpdateIDbContext(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	idbContextPath := paths.GetIDbContextPath(sch.Solution.ModuleName)
+	idbContextPath, _ := paths.ResolveIDbContext(sch.Solution.ModuleName)
+	if idbContextPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(idbContextPath); err != nil {
+		return nil
+	}
 
 	// Check if DbSet already exists
 	searchPattern := fmt.Sprintf("DbSet<%s>", entity.Name)
@@ -215,30 +468,6 @@ func (g *EFCoreGenerator) UpdateIDbContext(sch *schema.Schema, entity *schema.En
 	entityPlural := templates.Pluralize(entity.Name)
 	dbSetProperty := fmt.Sprintf("\n    DbSet<%s> %s { get; }\n", entity.Name, entityPlural)
 
-	// Create initial IDbContext file if it doesn't exist
-	createInitialContent := func() (string, error) {
-		namespaceRoot := sch.Solution.NamespaceRoot
-		moduleName := sch.Solution.ModuleName
-		moduleNamespace := sch.Solution.GetModuleNameWithSuffix()
-
-		// Build initial IDbContext file structure
-		content := fmt.Sprintf(`using Microsoft.EntityFrameworkCore;
-using Volo.Abp.EntityFrameworkCore;
-using %s.Domain.Entities.%s;
-
-namespace %s.EntityFrameworkCore
-{
-    public interface I%sDbContext : IEfCoreDbContext
-    {
-        /* Add DbSet properties here. Example:
-         * DbSet<Question> Questions { get; }
-         */
-%s    }
-}
-`, namespaceRoot, moduleNamespace, namespaceRoot, moduleName, dbSetProperty)
-		return content, nil
-	}
-
 	return g.writer.UpdateFileIdempotent(idbContextPath, searchPattern, func(content string) (string, error) {
 		// Find the closing brace of the interface and insert before it
 		pattern := regexp.MustCompile(`(\s+)(}\s*$)`)
@@ -247,26 +476,94 @@ namespace %s.EntityFrameworkCore
 		}
 
 		updated := pattern.ReplaceAllString(content, dbSetProperty+"$1$2")
-		return updated, nil
-	}, createInitialContent)
+		return addUsing(updated, sch.Solution.NamespaceRoot+".Domain.Entities."+sch.Solution.GetModuleNameWithSuffix()), nil
+	}, nil)
 }
 
-// UpdateModelCreating adds entity configuration to OnModelCreating
+// configureModulePattern matches the opening of the Configure{Module} extension method,
+// including ABP's leading Check.NotNull guard
+var configureModulePattern = `(?m)^([ \t]*)public static void Configure%s\(\s*this ModelBuilder builder\s*\)\s*\{(\s*Check\.NotNull\(builder,\s*nameof\(builder\)\);)?`
+
+// onModelCreatingPattern matches the opening of OnModelCreating, including the base call
+var onModelCreatingPattern = regexp.MustCompile(`protected override void OnModelCreating\(ModelBuilder builder\)\s*\{(\s*base\.OnModelCreating\(builder\);)?`)
+
+// UpdateModelCreating adds the entity configuration to the module's Configure{Module} extension method
+// and makes sure OnModelCreating calls it
 func (g *EFCoreGenerator) UpdateModelCreating(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	dbContextPath := paths.GetDbContextPath(sch.Solution.ModuleName)
+	dbContextPath, _ := paths.ResolveDbContext(sch.Solution.ModuleName)
+	applyConfiguration := fmt.Sprintf("ApplyConfiguration(new %sConfiguration())", entity.Name)
 
-	searchPattern := fmt.Sprintf("ApplyConfiguration(new %sConfiguration())", entity.Name)
+	// Configurations applied inline by earlier versions stay where they are
+	dbContext, err := os.ReadFile(dbContextPath)
+	if err == nil && strings.Contains(string(dbContext), applyConfiguration) {
+		return nil
+	}
 
-	configLine := fmt.Sprintf("\n            builder.ApplyConfiguration(new %sConfiguration());\n", entity.Name)
+	if err := g.updateModelCreatingExtensions(sch, entity, paths); err != nil {
+		return err
+	}
 
-	return g.writer.UpdateFileIdempotent(dbContextPath, searchPattern, func(content string) (string, error) {
-		// Find OnModelCreating method and insert configuration
-		pattern := regexp.MustCompile(`(protected override void OnModelCreating\(ModelBuilder builder\)\s*{)`)
-		if !pattern.MatchString(content) {
+	// A DbContext created by UpdateDbContext already calls Configure{Module}
+	if err != nil {
+		return nil
+	}
+
+	configureCall := fmt.Sprintf("builder.Configure%s();", sch.Solution.ModuleName)
+	return g.writer.UpdateFileIdempotent(dbContextPath, configureCall, func(content string) (string, error) {
+		match := onModelCreatingPattern.FindStringSubmatchIndex(content)
+		if match == nil {
 			return "", fmt.Errorf("OnModelCreating method not found")
 		}
-
-		updated := pattern.ReplaceAllString(content, "$1"+configLine)
-		return updated, nil
+		return content[:match[1]] + "\n            " + configureCall + content[match[1]:], nil
 	}, nil)
 }
+
+// updateModelCreatingExtensions adds ApplyConfiguration for the entity to {Module}DbContextModelCreatingExtensions,
+// creating the file when the solution does not have one
+func (g *EFCoreGenerator) updateModelCreatingExtensions(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	extensionsPath := paths.GetModelCreatingExtensionsPath(sch.Solution.ModuleName)
+	moduleName := sch.Solution.ModuleName
+	configurationsNamespace := fmt.Sprintf("%s.EntityFrameworkCore.Configurations.%s", sch.Solution.NamespaceRoot, sch.Solution.GetModuleNameWithSuffix())
+	configLine := fmt.Sprintf("builder.ApplyConfiguration(new %sConfiguration());", entity.Name)
+
+	createInitialContent := func() (string, error) {
+		content := fmt.Sprintf(`using Microsoft.EntityFrameworkCore;
+using Volo.Abp;
+using %s;
+
+namespace %s.EntityFrameworkCore
+{
+    public static class %sDbContextModelCreatingExtensions
+    {
+        public static void Configure%s(this ModelBuilder builder)
+        {
+            Check.NotNull(builder, nameof(builder));
+
+            %s
+        }
+    }
+}
+`, configurationsNamespace, sch.Solution.NamespaceRoot, moduleName, moduleName, configLine)
+		return content, nil
+	}
+
+	pattern := regexp.MustCompile(fmt.Sprintf(configureModulePattern, regexp.QuoteMeta(moduleName)))
+	return g.writer.UpdateFileIdempotent(extensionsPath, fmt.Sprintf("ApplyConfiguration(new %sConfiguration())", entity.Name), func(content string) (string, error) {
+		match := pattern.FindStringSubmatchIndex(content)
+		if match == nil {
+			return "", fmt.Errorf("Configure%s method not found", moduleName)
+		}
+		indent := content[match[2]:match[3]] + "    "
+
+		// Append after the existing statements so configurations keep the schema order
+		updated := content[:match[1]] + "\n" + indent + configLine + content[match[1]:]
+		openBrace := match[0] + strings.Index(content[match[0]:match[1]], "{")
+		if closeBrace := matchingBrace(content, openBrace); closeBrace != -1 {
+			lineStart := strings.LastIndex(content[:closeBrace], "\n") + 1
+			if strings.TrimSpace(content[lineStart:closeBrace]) == "" {
+				updated = content[:lineStart] + indent + configLine + "\n" + content[lineStart:]
+			}
+		}
+		return addUsing(updated, configurationsNamespace), nil
+	}, createInitialContent)
+}
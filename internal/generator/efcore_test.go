@@ -0,0 +1,925 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestEFCoreGenerator_StronglyTypedId(t *testing.T) {
+	product := schema.Entity{
+		Name:           "Product",
+		PrimaryKeyType: "ProductId",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	entityGen := NewEntityGenerator(loader, w)
+	if err := entityGen.GenerateStronglyTypedId(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateStronglyTypedId() error = %v", err)
+	}
+
+	efcoreGen := NewEFCoreGenerator(loader, w)
+	if err := efcoreGen.GenerateValueConverter(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateValueConverter() error = %v", err)
+	}
+	if err := efcoreGen.GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	idStruct := generatedContent(t, w, "Identifiers/CatalogModule/ProductId.cs")
+	if !strings.Contains(idStruct, "public readonly record struct ProductId(Guid Value)") {
+		t.Errorf("strongly-typed ID struct not generated:\n%s", idStruct)
+	}
+	// Route and query values bind through the type converter
+	for _, want := range []string{
+		"[TypeConverter(typeof(ProductIdTypeConverter))]",
+		"public static bool TryParse(string value, out ProductId id)",
+		"public class ProductIdTypeConverter : TypeConverter",
+	} {
+		if !strings.Contains(idStruct, want) {
+			t.Errorf("strongly-typed ID struct missing %q:\n%s", want, idStruct)
+		}
+	}
+
+	converter := generatedContent(t, w, "ValueConverters/CatalogModule/ProductIdValueConverter.cs")
+	if !strings.Contains(converter, "ValueConverter<ProductId, Guid>") {
+		t.Errorf("value converter not generated:\n%s", converter)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if !strings.Contains(config, "builder.Property(x => x.Id).HasConversion(new ProductIdValueConverter());") {
+		t.Errorf("configuration missing HasConversion:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_BuiltInKeyHasNoConversion(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	efcoreGen := NewEFCoreGenerator(loader, w)
+	if err := efcoreGen.GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if strings.Contains(config, "HasConversion") {
+		t.Errorf("configuration unexpectedly contains HasConversion:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_DefaultValues(t *testing.T) {
+	product := schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", DefaultValue: "Unnamed"},
+			{Name: "IsActive", Type: "bool", DefaultValue: "true"},
+			{Name: "Stock", Type: "int", DefaultValue: "10"},
+			{Name: "Status", Type: "ProductStatus", IsEnum: true, EnumName: "ProductStatus", DefaultValue: "Draft"},
+			{Name: "AvailableFrom", Type: "DateOnly", DefaultValue: "2024-01-31"},
+			{Name: "UpdatedAt", Type: "DateTimeOffset", DefaultValue: "now"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	for _, want := range []string{
+		`builder.Property(x => x.Name).HasDefaultValue("Unnamed");`,
+		`builder.Property(x => x.IsActive).HasDefaultValue(true);`,
+		`builder.Property(x => x.Stock).HasDefaultValue(10);`,
+		`builder.Property(x => x.Status).HasDefaultValue(ProductStatus.Draft);`,
+		`builder.Property(x => x.AvailableFrom).HasDefaultValue(new DateOnly(2024, 1, 31));`,
+		`builder.Property(x => x.UpdatedAt).HasDefaultValueSql("CURRENT_TIMESTAMP");`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	if !strings.Contains(entity, "public bool IsActive { get; set; } = true;") {
+		t.Errorf("entity missing typed default for IsActive:\n%s", entity)
+	}
+}
+
+func TestEFCoreGenerator_DecimalPrecision(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Price", Type: "decimal", Precision: 18, Scale: 2},
+			{Name: "Weight", Type: "decimal"},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if !strings.Contains(config, "builder.Property(x => x.Price).HasPrecision(18, 2);") {
+		t.Errorf("configuration missing precision for Price:\n%s", config)
+	}
+	if strings.Contains(config, "x.Weight).HasPrecision") {
+		t.Errorf("configuration sets precision for Weight without metadata:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_ConcurrencyToken(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Version", Type: "byte[]", IsConcurrencyToken: true},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if !strings.Contains(config, "builder.Property(x => x.Version).IsRowVersion();") {
+		t.Errorf("configuration missing row version:\n%s", config)
+	}
+	if strings.Contains(config, "x.Name).IsRowVersion()") {
+		t.Errorf("configuration marks Name as row version:\n%s", config)
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	if !strings.Contains(entity, "public byte[] Version { get; set; }") {
+		t.Errorf("entity missing Version property:\n%s", entity)
+	}
+	for _, p := range sch.Entities[0].GetInputProperties() {
+		if p.Name == "Version" {
+			t.Error("concurrency token is an input property")
+		}
+	}
+}
+
+func TestEFCoreGenerator_ColumnName(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", ColumnName: "product_name"},
+			{Name: "Price", Type: "decimal"},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if !strings.Contains(config, `builder.Property(x => x.Name).HasColumnName("product_name");`) {
+		t.Errorf("configuration missing column name:\n%s", config)
+	}
+	if strings.Contains(config, "x.Price).HasColumnName") {
+		t.Errorf("configuration renames a column without columnName:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_ConfigureByConvention(t *testing.T) {
+	tests := []struct {
+		target schema.TargetFramework
+		want   bool
+	}{
+		{target: schema.TargetABP9Monolith, want: true},
+		{target: schema.TargetABP8Microservice, want: true},
+		{target: schema.TargetASPNETCore9, want: false},
+		{target: schema.TargetASPNETCore10, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.target), func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.TargetFramework = tt.target
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateConfiguration() error = %v", err)
+			}
+
+			config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+			for _, line := range []string{"builder.ConfigureByConvention();", "using Volo.Abp.EntityFrameworkCore.Modeling;"} {
+				if got := strings.Contains(config, line); got != tt.want {
+					t.Errorf("configuration contains %q = %v, want %v:\n%s", line, got, tt.want, config)
+				}
+			}
+		})
+	}
+}
+
+func TestEFCoreGenerator_StringEnumConversion(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Order",
+		Properties: []schema.Property{
+			{Name: "Status", Type: "OrderStatus", IsEnum: true, EnumName: "OrderStatus"},
+			{Name: "Priority", Type: "OrderPriority", IsEnum: true, EnumName: "OrderPriority"},
+		},
+		Enums: []schema.EnumDefinition{
+			{Name: "OrderStatus", UnderlyingType: "string", Values: []schema.EnumValue{{Name: "Pending"}, {Name: "Shipped"}}},
+			{Name: "OrderPriority", Values: []schema.EnumValue{{Name: "Low"}, {Name: "High"}}},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/OrderConfiguration.cs")
+	if !strings.Contains(config, "builder.Property(x => x.Status).HasConversion<string>().HasMaxLength(7);") {
+		t.Errorf("configuration does not store the string enum by name:\n%s", config)
+	}
+	if strings.Contains(config, "x.Priority).HasConversion") {
+		t.Errorf("configuration converts an int enum:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_OneToOneRelations(t *testing.T) {
+	customer := schema.Entity{
+		Name:       "Customer",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToOne: []schema.OneToOneRelation{
+				{TargetEntity: "Address", NavigationProperty: "ShippingAddress", IsOwned: true},
+				{TargetEntity: "Profile", IsRequired: true, CascadeDelete: true},
+			},
+		},
+	}
+	profile := schema.Entity{
+		Name:       "Profile",
+		Properties: []schema.Property{{Name: "Bio", Type: "string"}},
+	}
+	sch := newTestSchema(t, customer, profile)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &sch.Entities[0]); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/CustomerConfiguration.cs")
+	for _, want := range []string{
+		"builder.OwnsOne(x => x.ShippingAddress);",
+		"builder.HasOne(x => x.Profile)\n               .WithOne()\n               .HasForeignKey<Customer>(x => x.ProfileId)\n               .IsRequired(true)\n               .OnDelete(DeleteBehavior.Cascade);",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Customer.cs")
+	for _, want := range []string{
+		"public Address ShippingAddress { get; set; }",
+		"public virtual Profile Profile { get; set; }",
+		"public Guid ProfileId { get; set; }",
+	} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity missing %q:\n%s", want, entity)
+		}
+	}
+	if strings.Contains(entity, "AddressId") {
+		t.Errorf("owned relation unexpectedly adds a foreign key:\n%s", entity)
+	}
+}
+
+func TestEFCoreGenerator_DeleteBehavior(t *testing.T) {
+	order := schema.Entity{
+		Name:       "Order",
+		Properties: []schema.Property{{Name: "Number", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToMany: []schema.OneToManyRelation{
+				{TargetEntity: "OrderLine", CascadeDelete: true},
+				{TargetEntity: "Shipment"},
+			},
+			ManyToOne: []schema.ManyToOneRelation{
+				{TargetEntity: "Customer", IsRequired: true},
+				{TargetEntity: "Store", ForeignKeyName: "SellingStoreId", CascadeDelete: true},
+			},
+		},
+	}
+	sch := newTestSchema(t, order)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &sch.Entities[0]); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/OrderConfiguration.cs")
+	for _, want := range []string{
+		"builder.HasMany(x => x.OrderLines)\n               .WithOne()\n               .HasForeignKey(\"OrderId\")\n               .IsRequired(false)\n               .OnDelete(DeleteBehavior.Cascade);",
+		"builder.HasMany(x => x.Shipments)\n               .WithOne()\n               .HasForeignKey(\"OrderId\")\n               .IsRequired(false)\n               .OnDelete(DeleteBehavior.Restrict);",
+		"builder.HasOne(x => x.Customer)\n               .WithMany()\n               .HasForeignKey(x => x.CustomerId)\n               .IsRequired(true)\n               .OnDelete(DeleteBehavior.Restrict);",
+		"builder.HasOne(x => x.Store)\n               .WithMany()\n               .HasForeignKey(x => x.SellingStoreId)\n               .IsRequired(false)\n               .OnDelete(DeleteBehavior.Cascade);",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+}
+
+func TestEFCoreGenerator_ManyToOneNavigations(t *testing.T) {
+	category := schema.Entity{
+		Name:       "Category",
+		Properties: []schema.Property{{Name: "Title", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToMany: []schema.OneToManyRelation{{TargetEntity: "Product"}},
+		},
+	}
+	product := schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		Relations: &schema.Relations{
+			ManyToOne: []schema.ManyToOneRelation{
+				{TargetEntity: "Category", IsRequired: true},
+				{TargetEntity: "Category", NavigationProperty: "PreviousCategory", ForeignKeyName: "PreviousCategoryId"},
+			},
+		},
+	}
+	sch := newTestSchema(t, category, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	efcore := NewEFCoreGenerator(loader, w)
+	for i := range sch.Entities {
+		if err := NewRelationshipHandler().ProcessRelationships(sch, &sch.Entities[i]); err != nil {
+			t.Fatalf("ProcessRelationships() error = %v", err)
+		}
+	}
+	for i := range sch.Entities {
+		if err := efcore.GenerateConfiguration(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("GenerateConfiguration() error = %v", err)
+		}
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[1], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	categoryConfig := generatedContent(t, w, "Configurations/CatalogModule/CategoryConfiguration.cs")
+	if want := "builder.HasMany(x => x.Products)\n               .WithOne(x => x.Category)\n               .HasForeignKey(\"CategoryId\")"; !strings.Contains(categoryConfig, want) {
+		t.Errorf("category configuration missing %q:\n%s", want, categoryConfig)
+	}
+
+	productConfig := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	for _, want := range []string{
+		"builder.HasOne(x => x.Category)\n               .WithMany(x => x.Products)\n               .HasForeignKey(x => x.CategoryId)",
+		"builder.HasOne(x => x.PreviousCategory)\n               .WithMany()\n               .HasForeignKey(x => x.PreviousCategoryId)",
+	} {
+		if !strings.Contains(productConfig, want) {
+			t.Errorf("product configuration missing %q:\n%s", want, productConfig)
+		}
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	for _, want := range []string{
+		"public virtual Category Category { get; set; }",
+		"public virtual Category PreviousCategory { get; set; }",
+		"[ForeignKey(\"PreviousCategory\")]",
+	} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity missing %q:\n%s", want, entity)
+		}
+	}
+	if strings.Contains(entity, "CategoryIdId") {
+		t.Errorf("foreign key attribute names a missing navigation:\n%s", entity)
+	}
+}
+
+func TestEFCoreGenerator_Indexes(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Sku", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "CategoryId", Type: "Guid"},
+		},
+		Indexes: []schema.IndexDefinition{
+			{Properties: []string{"Sku"}, Unique: true},
+			{Properties: []string{"CategoryId", "Name"}},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	for _, want := range []string{
+		"builder.HasIndex(x => x.Sku).IsUnique();",
+		"builder.HasIndex(x => new { x.CategoryId, x.Name });",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+}
+
+func TestEFCoreGenerator_ForeignKeyIndexes(t *testing.T) {
+	order := schema.Entity{
+		Name: "Order",
+		Properties: []schema.Property{
+			{Name: "Number", Type: "string"},
+			{Name: "CouponId", Type: "Guid", IsForeignKey: true, TargetEntity: "Coupon"},
+		},
+		Relations: &schema.Relations{
+			ManyToOne: []schema.ManyToOneRelation{
+				{TargetEntity: "Customer", IsRequired: true},
+				{TargetEntity: "Store", SkipIndex: true},
+				{TargetEntity: "Warehouse"},
+			},
+			OneToOne: []schema.OneToOneRelation{
+				{TargetEntity: "Invoice"},
+				{TargetEntity: "Address", IsOwned: true},
+			},
+		},
+		// Covers WarehouseId as its leading column
+		Indexes: []schema.IndexDefinition{{Properties: []string{"WarehouseId", "Number"}}},
+	}
+	sch := newTestSchema(t, order)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &sch.Entities[0]); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/OrderConfiguration.cs")
+	want := `        // Index foreign keys declared without a relation
+        builder.HasIndex(x => x.CouponId);
+`
+	if !strings.Contains(config, want) {
+		t.Errorf("configuration missing foreign key indexes %q:\n%s", want, config)
+	}
+	// Relation foreign keys keep the EF Core convention index, which skipIndex cannot remove
+	for _, unwanted := range []string{
+		"HasIndex(x => x.StoreId)", "HasIndex(x => x.WarehouseId)", "HasIndex(x => x.AddressId)",
+		"HasIndex(x => x.InvoiceId)", "HasIndex(x => x.CustomerId)",
+		"RemoveIndex",
+	} {
+		if strings.Contains(config, unwanted) {
+			t.Errorf("configuration should not contain %q:\n%s", unwanted, config)
+		}
+	}
+}
+
+const testModelCreatingExtensions = `using Microsoft.EntityFrameworkCore;
+using Volo.Abp;
+
+namespace Acme.Shop.EntityFrameworkCore;
+
+public static class CatalogDbContextModelCreatingExtensions
+{
+    public static void ConfigureCatalog(
+        this ModelBuilder builder)
+    {
+        Check.NotNull(builder, nameof(builder));
+    }
+}
+`
+
+const testCatalogDbContext = `using Microsoft.EntityFrameworkCore;
+using Volo.Abp.EntityFrameworkCore;
+
+namespace Acme.Shop.EntityFrameworkCore;
+
+public class CatalogDbContext : AbpDbContext<CatalogDbContext>
+{
+    public CatalogDbContext(DbContextOptions<CatalogDbContext> options)
+        : base(options)
+    {
+    }
+
+    protected override void OnModelCreating(ModelBuilder builder)
+    {
+        base.OnModelCreating(builder);
+    }
+}
+`
+
+func TestEFCoreGenerator_ModelCreatingExtensions(t *testing.T) {
+	writers := map[string]func() *writer.Writer{
+		"default": func() *writer.Writer { return writer.NewWriter(false, false, false) },
+		"merge":   func() *writer.Writer { return writer.NewWriterWithMerge(false, false, false, true) },
+		"force":   func() *writer.Writer { return writer.NewWriter(false, true, false) },
+	}
+
+	for name, newWriter := range writers {
+		for _, existing := range []bool{true, false} {
+			testName := name + "/created"
+			if existing {
+				testName = name + "/existing"
+			}
+			t.Run(testName, func(t *testing.T) {
+				sch := newTestSchema(t,
+					schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+					schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string"}}},
+				)
+				paths := newTestLayerPaths(t)
+
+				dir := filepath.Join(paths.EntityFrameworkCore, "EntityFrameworkCore")
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				extensionsPath := paths.GetModelCreatingExtensionsPath("Catalog")
+				dbContextPath := paths.GetDbContextPath("Catalog")
+				if existing {
+					if err := os.WriteFile(extensionsPath, []byte(testModelCreatingExtensions), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := os.WriteFile(dbContextPath, []byte(testCatalogDbContext), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				gen := NewEFCoreGenerator(templates.NewLoader(""), newWriter())
+				for i := 0; i < 2; i++ {
+					for j := range sch.Entities {
+						if err := gen.UpdateModelCreating(sch, &sch.Entities[j], paths); err != nil {
+							t.Fatalf("UpdateModelCreating() run %d error = %v", i+1, err)
+						}
+					}
+				}
+
+				extensions, err := os.ReadFile(extensionsPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				wants := []string{
+					"builder.ApplyConfiguration(new ProductConfiguration());\n",
+					"builder.ApplyConfiguration(new CategoryConfiguration());\n",
+					"using Acme.Shop.EntityFrameworkCore.Configurations.CatalogModule;",
+				}
+				if existing {
+					wants = append(wants, "Check.NotNull(builder, nameof(builder));\n        builder.ApplyConfiguration(new ProductConfiguration());\n        builder.ApplyConfiguration(new CategoryConfiguration());\n    }")
+				}
+				for _, want := range wants {
+					if !strings.Contains(string(extensions), want) {
+						t.Errorf("extensions missing %q:\n%s", want, extensions)
+					}
+				}
+				if got := strings.Count(string(extensions), "new ProductConfiguration()"); got != 1 {
+					t.Errorf("ProductConfiguration applied %d times; want 1:\n%s", got, extensions)
+				}
+
+				dbContext, err := os.ReadFile(dbContextPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.Count(string(dbContext), "builder.ConfigureCatalog();"); got != 1 {
+					t.Errorf("ConfigureCatalog called %d times; want 1:\n%s", got, dbContext)
+				}
+				if strings.Contains(string(dbContext), "ApplyConfiguration") {
+					t.Errorf("DbContext still applies configurations inline:\n%s", dbContext)
+				}
+			})
+		}
+	}
+}
+
+func TestEFCoreGenerator_RegisterRepository(t *testing.T) {
+	tests := []struct {
+		name   string
+		module string
+		wants  []string
+	}{
+		{
+			name: "existing AddAbpDbContext",
+			module: `using Volo.Abp.Modularity;
+
+namespace Acme.Shop.EntityFrameworkCore;
+
+public class ShopEntityFrameworkCoreModule : AbpModule
+{
+    public override void ConfigureServices(ServiceConfigurationContext context)
+    {
+        context.Services.AddAbpDbContext<ShopDbContext>(opts =>
+        {
+            opts.AddDefaultRepositories(includeAllEntities: true);
+        });
+    }
+}
+`,
+			wants: []string{
+				"(opts =>\n        {\n            opts.AddDefaultRepositories(includeAllEntities: true);\n            opts.AddRepository<Product, EfCoreProductRepository>();\n            opts.AddRepository<Category, EfCoreCategoryRepository>();\n        });",
+			},
+		},
+		{
+			name: "no ConfigureServices",
+			module: `using Volo.Abp.Modularity;
+
+namespace Acme.Shop.EntityFrameworkCore
+{
+    public class ShopEntityFrameworkCoreModule : AbpModule
+    {
+    }
+}
+`,
+			wants: []string{
+				"context.Services.AddAbpDbContext<CatalogDbContext>(options =>",
+				"options.AddDefaultRepositories(includeAllEntities: true);",
+				"options.AddRepository<Product, EfCoreProductRepository>();\n                options.AddRepository<Category, EfCoreCategoryRepository>();",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t,
+				schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+				schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string"}}},
+			)
+			paths := newTestLayerPaths(t)
+			paths.EFCoreModule = filepath.Join(paths.EntityFrameworkCore, "ShopEntityFrameworkCoreModule.cs")
+			if err := os.MkdirAll(paths.EntityFrameworkCore, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(paths.EFCoreModule, []byte(tt.module), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			gen := NewEFCoreGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+			for i := 0; i < 2; i++ {
+				for j := range sch.Entities {
+					if err := gen.RegisterRepository(sch, &sch.Entities[j], paths); err != nil {
+						t.Fatalf("RegisterRepository() run %d error = %v", i+1, err)
+					}
+				}
+			}
+
+			updated, err := os.ReadFile(paths.EFCoreModule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			module := string(updated)
+			if got := strings.Count(module, "AddRepository<Product, EfCoreProductRepository>()"); got != 1 {
+				t.Errorf("Product repository registered %d times; want 1:\n%s", got, module)
+			}
+			wants := append(tt.wants,
+				"using Acme.Shop.Domain.Entities.CatalogModule;",
+				"using Acme.Shop.EntityFrameworkCore.Repositories.CatalogModule;",
+			)
+			for _, want := range wants {
+				if !strings.Contains(module, want) {
+					t.Errorf("module missing %q:\n%s", want, module)
+				}
+			}
+		})
+	}
+}
+
+func TestEFCoreGenerator_UpdateDbContextReusesDetectedContext(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	paths := newTestLayerPaths(t)
+
+	dir := filepath.Join(paths.EntityFrameworkCore, "EntityFrameworkCore")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shopContext := filepath.Join(dir, "ShopDbContext.cs")
+	content := strings.ReplaceAll(testCatalogDbContext, "CatalogDbContext", "ShopDbContext")
+	if err := os.WriteFile(shopContext, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewEFCoreGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+	if err := gen.UpdateDbContext(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("UpdateDbContext() error = %v", err)
+	}
+	if err := gen.UpdateIDbContext(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("UpdateIDbContext() error = %v", err)
+	}
+
+	updated, err := os.ReadFile(shopContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(updated), "public virtual DbSet<Product> Products { get; set; }") {
+		t.Errorf("DbSet not added to the detected ShopDbContext:\n%s", updated)
+	}
+	if _, err := os.Stat(paths.GetDbContextPath("Catalog")); err == nil {
+		t.Errorf("a duplicate CatalogDbContext was created")
+	}
+
+	ifacePath := filepath.Join(dir, "IShopDbContext.cs")
+	if _, err := os.Stat(ifacePath); err == nil {
+		t.Errorf("an IShopDbContext nothing implements was created")
+	}
+
+	// An existing interface named after the detected DbContext gets the DbSet
+	existing := "using Volo.Abp.EntityFrameworkCore;\n\nnamespace Acme.Shop.EntityFrameworkCore\n{\n    public interface IShopDbContext : IEfCoreDbContext\n    {\n    }\n}\n"
+	if err := os.WriteFile(ifacePath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.UpdateIDbContext(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("UpdateIDbContext() error = %v", err)
+	}
+	iface, err := os.ReadFile(ifacePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"DbSet<Product> Products { get; }", "using Acme.Shop.Domain.Entities.CatalogModule;"} {
+		if !strings.Contains(string(iface), want) {
+			t.Errorf("IShopDbContext missing %q:\n%s", want, iface)
+		}
+	}
+}
+
+func TestEFCoreGenerator_RepositoryIncludesDetailNavigations(t *testing.T) {
+	order := schema.Entity{
+		Name:       "Order",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Number", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToOne:  []schema.OneToOneRelation{{TargetEntity: "Invoice", WithDetails: true}},
+			OneToMany: []schema.OneToManyRelation{{TargetEntity: "OrderLine", WithDetails: true}, {TargetEntity: "Note"}},
+		},
+	}
+	sch := newTestSchema(t, order)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEFCoreGenerator(loader, w).GenerateRepository(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateRepository() error = %v", err)
+	}
+	if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	repository := generatedContent(t, w, "Repositories/CatalogModule/EfCoreOrderRepository.cs")
+	want := "public override async Task<IQueryable<Order>> WithDetailsAsync()\n    {\n        return (await GetQueryableAsync())\n            .Include(x => x.Invoice)\n            .Include(x => x.OrderLines);\n    }"
+	if !strings.Contains(repository, want) {
+		t.Errorf("repository missing WithDetailsAsync override:\n%s", repository)
+	}
+	if strings.Contains(repository, "x.Notes") {
+		t.Errorf("repository includes Notes, which is not marked withDetails:\n%s", repository)
+	}
+
+	dto := generatedContent(t, w, "Order/OrderDto.cs")
+	for _, want := range []string{
+		"public InvoiceDto Invoice { get; set; }",
+		"public List<OrderLineDto> OrderLines { get; set; }",
+		"using Acme.Shop.Application.Contracts.OrderLineModule;",
+	} {
+		if !strings.Contains(dto, want) {
+			t.Errorf("read DTO missing %q:\n%s", want, dto)
+		}
+	}
+
+	service := generatedContent(t, w, "Services/CatalogModule/OrderAppService.cs")
+	if !strings.Contains(service, "Repository.GetAsync(id, includeDetails: true)") {
+		t.Errorf("app service does not load details in GetAsync:\n%s", service)
+	}
+}
+
+func TestEFCoreGenerator_TablePrefix(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	sch.Solution.TablePrefix = "Saas"
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewEFCoreGenerator(loader, w)
+	if err := gen.GenerateDbProperties(sch, paths); err != nil {
+		t.Fatalf("GenerateDbProperties() error = %v", err)
+	}
+	if err := gen.GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	properties := generatedContent(t, w, "CatalogDbProperties.cs")
+	if !strings.Contains(properties, `public static string DbTablePrefix { get; set; } = "Saas";`) {
+		t.Errorf("DbProperties does not use the table prefix:\n%s", properties)
+	}
+	config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+	if !strings.Contains(config, `builder.ToTable(CatalogDbProperties.DbTablePrefix + "Products", CatalogDbProperties.DbSchema);`) {
+		t.Errorf("configuration does not prefix the table name:\n%s", config)
+	}
+}
+
+func TestEFCoreGenerator_TenantIdType(t *testing.T) {
+	tests := []struct {
+		tenantIdType  string
+		wantProperty  string
+		wantInterface bool
+		wantMaxLength bool
+	}{
+		{"Guid", "public Guid? TenantId { get; set; }", true, false},
+		{"long", "public long? TenantId { get; set; }", false, false},
+		{"string", "public string TenantId { get; set; }", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tenantIdType, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.MultiTenancy = &schema.MultiTenancy{Enabled: true, EnableDataIsolation: true, TenantIdType: tt.tenantIdType}
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateConfiguration() error = %v", err)
+			}
+
+			entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+			if !strings.Contains(entity, tt.wantProperty) {
+				t.Errorf("entity missing %q:\n%s", tt.wantProperty, entity)
+			}
+			if got := strings.Contains(entity, ", IMultiTenant"); got != tt.wantInterface {
+				t.Errorf("entity implements IMultiTenant = %v, want %v:\n%s", got, tt.wantInterface, entity)
+			}
+
+			config := generatedContent(t, w, "Configurations/CatalogModule/ProductConfiguration.cs")
+			if !strings.Contains(config, "builder.HasIndex(x => x.TenantId);") {
+				t.Errorf("configuration does not index the tenant ID:\n%s", config)
+			}
+			if got := strings.Contains(config, "builder.Property(x => x.TenantId).HasMaxLength(64);"); got != tt.wantMaxLength {
+				t.Errorf("configuration limits tenant ID length = %v, want %v:\n%s", got, tt.wantMaxLength, config)
+			}
+		})
+	}
+}
+
+func TestEFCoreGenerator_TablePerHierarchy(t *testing.T) {
+	sch := newHierarchySchema(t)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewEFCoreGenerator(loader, w)
+	for i := range sch.Entities {
+		if err := gen.GenerateConfiguration(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("GenerateConfiguration() error = %v", err)
+		}
+	}
+
+	base := generatedContent(t, w, "CatalogModule/CustomerConfiguration.cs")
+	want := "builder.HasDiscriminator<string>(\"Discriminator\")\n" +
+		"               .HasValue<Customer>(\"Customer\")\n" +
+		"               .HasValue<BusinessCustomer>(\"BusinessCustomer\");"
+	if !strings.Contains(base, want) {
+		t.Errorf("base configuration missing discriminator %q:\n%s", want, base)
+	}
+
+	derived := generatedContent(t, w, "CatalogModule/BusinessCustomerConfiguration.cs")
+	if !strings.Contains(derived, "builder.HasBaseType<Customer>();") {
+		t.Errorf("derived configuration missing HasBaseType:\n%s", derived)
+	}
+	// The hierarchy shares the base entity's table
+	for _, unwanted := range []string{"ToTable(", "ConfigureByConvention()"} {
+		if strings.Contains(derived, unwanted) {
+			t.Errorf("derived configuration contains %q:\n%s", unwanted, derived)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
 	"path/filepath"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -53,25 +55,88 @@ func (g *EntityGenerator) prepareEntityData(sch *schema.Schema, entity *schema.E
 	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
 
 	return map[string]interface{}{
-		"SolutionName":            sch.Solution.Name,
-		"ModuleName":              sch.Solution.ModuleName,
-		"ModuleNameWithSuffix":    sch.Solution.GetModuleNameWithSuffix(),
-		"NamespaceRoot":           sch.Solution.NamespaceRoot,
-		"EntityName":              entity.Name,
-		"TableName":               entity.TableName,
-		"EntityType":              entity.EntityType,
-		"PrimaryKeyType":          primaryKeyType,
-		"Properties":              entity.Properties,
-		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
-		"ForeignKeyProperties":    entity.GetForeignKeyProperties(),
-		"HasRelations":            entity.HasRelations(),
-		"Relations":               entity.Relations,
-		"OneToManyRelations":      getOneToManyRelations(entity),
-		"ManyToManyRelations":     getManyToManyRelations(entity),
-		"HasEvents":               entity.EntityType != "ValueObject" && entity.EntityType != "Entity",
-		"IsValueObject":           entity.EntityType == "ValueObject",
-		"IsAggregateRoot":         entity.EntityType == "AggregateRoot" || entity.EntityType == "FullAuditedAggregateRoot",
+		"SolutionName":              sch.Solution.Name,
+		"ModuleName":                sch.Solution.ModuleName,
+		"ModuleNameWithSuffix":      sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":             sch.Solution.NamespaceRoot,
+		"EntityName":                entity.Name,
+		"TableName":                 entity.TableName,
+		"EntityType":                entity.EntityType,
+		"BaseClass":                 entity.BaseClass,
+		"BaseEntity":                entity.BaseEntity,
+		"PrimaryKeyType":            primaryKeyType,
+		"Properties":                entity.Properties,
+		"NonForeignKeyProperties":   entity.GetNonForeignKeyProperties(),
+		"InputProperties":           entity.GetInputProperties(),
+		"ConstructorProperties":     sch.GetConstructorProperties(entity),
+		"BaseConstructorProperties": baseConstructorProperties(sch, entity),
+		"ForeignKeyProperties":      entity.GetForeignKeyProperties(),
+		"HasRelations":              entity.HasRelations(),
+		"Relations":                 entity.Relations,
+		"OneToOneRelations":         getOneToOneRelations(entity),
+		"OneToManyRelations":        getOneToManyRelations(entity),
+		"ManyToOneRelations":        getManyToOneRelations(entity),
+		"ManyToManyRelations":       getManyToManyRelations(entity),
+		"ForeignKeyNavigations":     foreignKeyNavigations(entity),
+		"CollectionNavigations":     getCollectionNavigations(sch, entity),
+		"HasEvents":                 entity.IsAggregateRoot(),
+		"IsValueObject":             entity.EntityType == "ValueObject",
+		"IsAggregateRoot":           entity.IsAggregateRoot(),
+		"HasStronglyTypedId":        entity.HasStronglyTypedId(),
+		"ReferencesStronglyTypedId": referencesStronglyTypedId(sch, entity),
+		"MultiTenancy":              NewMultiTenancyHelper().BuildMultiTenancyConfig(sch, entity),
+	}
+}
+
+// baseConstructorProperties returns the constructor properties the entity passes on to its base entity
+func baseConstructorProperties(sch *schema.Schema, entity *schema.Entity) []schema.Property {
+	bases := sch.GetBaseEntities(entity)
+	if len(bases) == 0 {
+		return nil
+	}
+	return sch.GetConstructorProperties(bases[len(bases)-1])
+}
+
+// referencesStronglyTypedId checks if any of the entity's foreign keys targets an entity with a strongly-typed ID
+func referencesStronglyTypedId(sch *schema.Schema, entity *schema.Entity) bool {
+	for _, prop := range entity.GetForeignKeyProperties() {
+		if target := sch.FindEntity(prop.TargetEntity); target != nil && target.HasStronglyTypedId() {
+			return true
+		}
 	}
+	return false
+}
+
+// GenerateStronglyTypedId generates the struct wrapping a custom primary key type
+func (g *EntityGenerator) GenerateStronglyTypedId(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	// A derived entity shares the ID type of its base entity
+	if !entity.HasStronglyTypedId() || entity.BaseEntity != "" {
+		return nil
+	}
+
+	tmpl, err := g.tmplLoader.Load("strongly_typed_id.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to load strongly-typed ID template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityName":           entity.Name,
+		"IdTypeName":           entity.PrimaryKeyType,
+		"UnderlyingType":       entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute strongly-typed ID template: %w", err)
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	idPath := filepath.Join(paths.DomainShared, "Identifiers", moduleFolder, entity.PrimaryKeyType+".cs")
+	return g.writer.WriteFile(idPath, buf.String())
 }
 
 // GenerateRepository generates repository interface
@@ -96,6 +161,8 @@ func (g *EntityGenerator) GenerateRepository(sch *schema.Schema, entity *schema.
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"HasCustomRepository":  entity.HasCustomRepository(),
 	}
 
 	// Execute template
@@ -133,6 +200,7 @@ func (g *EntityGenerator) GenerateConstants(sch *schema.Schema, entity *schema.E
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"ValidationConstants":  validationConstants,
+		"IsCreationAudited":    entity.IsCreationAudited(),
 	}
 
 	// Execute template
@@ -149,8 +217,8 @@ func (g *EntityGenerator) GenerateConstants(sch *schema.Schema, entity *schema.E
 
 // GenerateEvents generates event types and ETOs
 func (g *EntityGenerator) GenerateEvents(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if entity.EntityType == "ValueObject" || entity.EntityType == "Entity" {
-		return nil // These types don't generate distributed events
+	if !entity.IsAggregateRoot() {
+		return nil // Only aggregate roots publish distributed events
 	}
 
 	// Generate event types
@@ -202,6 +270,7 @@ func (g *EntityGenerator) generateETO(sch *schema.Schema, entity *schema.Entity,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
 		"Properties":           entity.Properties,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
 	}
 
 	var buf bytes.Buffer
@@ -235,6 +304,12 @@ func (g *EntityGenerator) GenerateDataSeeder(sch *schema.Schema, entity *schema.
 		"EntityName":              entity.Name,
 		"PrimaryKeyType":          primaryKeyType,
 		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
+		"InputProperties":         sch.GetConstructorProperties(entity),
+		"HasEnumProperties":       entity.HasEnumProperties(),
+		"SeedId":                  seedIdExpression(sch, entity),
+		"UsesGuidGenerator":       entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType) == "Guid",
+		"SeedRows":                getSeedRows(sch, entity),
+		"GeneratedSeed":           buildGeneratedSeed(sch, entity, entity.GetEffectiveSeedCount(sch.Solution.SeedCount)),
 	}
 
 	var buf bytes.Buffer
@@ -247,6 +322,163 @@ func (g *EntityGenerator) GenerateDataSeeder(sch *schema.Schema, entity *schema.
 	return g.writer.WriteFile(seederPath, buf.String())
 }
 
+// seedIdExpression returns the C# expression of the id of a seeded row
+func seedIdExpression(sch *schema.Schema, entity *schema.Entity) string {
+	if entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType) != "Guid" {
+		return "default"
+	}
+	return newIdExpression(sch, entity, "_guidGenerator")
+}
+
+// newIdExpression returns the C# expression of the id of a new entity. Guid keys, wrapped in
+// their strongly-typed id if any, come from the named IGuidGenerator; other keys are left to the
+// database and start as the default value of the key type.
+func newIdExpression(sch *schema.Schema, entity *schema.Entity, guidGenerator string) string {
+	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+	if entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType) != "Guid" {
+		return fmt.Sprintf("default(%s)", primaryKeyType)
+	}
+	if entity.HasStronglyTypedId() {
+		return fmt.Sprintf("new %s(%s.Create())", primaryKeyType, guidGenerator)
+	}
+	return guidGenerator + ".Create()"
+}
+
+// getSeedRows renders the entity's seedData rows as C# literals in constructor argument order.
+// Properties missing from a row are seeded with their default value, or null when nullable.
+func getSeedRows(sch *schema.Schema, entity *schema.Entity) [][]string {
+	inputs := sch.GetConstructorProperties(entity)
+	rows := make([][]string, 0, len(entity.SeedData))
+	for _, row := range entity.SeedData {
+		values := make([]string, 0, len(inputs))
+		for _, prop := range inputs {
+			value, ok := row[prop.Name]
+			switch {
+			case ok:
+				values = append(values, prop.SeedValueLiteral(value))
+			case prop.HasDefaultValue():
+				values = append(values, prop.SeedValueLiteral(prop.DefaultValue))
+			case prop.Nullable:
+				values = append(values, "null")
+			default:
+				values = append(values, "default("+templates.CSharpType(prop)+")")
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows
+}
+
+// GeneratedSeed describes the rows a data seeder generates in a loop, after its seedData rows
+type GeneratedSeed struct {
+	Count      int
+	RandomSeed int      // Seed of the System.Random, so every run inserts the same rows
+	Values     []string // C# expressions for the constructor arguments after the id, in order
+	UsesText   bool     // Values call the SeedText helper
+	UsesGuid   bool     // Values call the SeedGuid helper
+	UsesEnum   bool     // Values call the SeedEnum helper
+}
+
+// buildGeneratedSeed plans count generated seed rows for the entity, or returns nil when count is zero.
+// Values are picked from the property types with a Random seeded from the entity name.
+func buildGeneratedSeed(sch *schema.Schema, entity *schema.Entity, count int) *GeneratedSeed {
+	// A solution-wide seedCount skips entities whose keys the seeder cannot generate
+	if count <= 0 || !sch.CanGenerateKeys(entity) {
+		return nil
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(entity.Name))
+	seed := &GeneratedSeed{Count: count, RandomSeed: int(hash.Sum32() & 0x7fffffff)}
+
+	for _, prop := range sch.GetConstructorProperties(entity) {
+		seed.Values = append(seed.Values, seed.valueExpression(prop))
+	}
+	return seed
+}
+
+// valueExpression returns the C# expression producing the property's value for row i
+func (s *GeneratedSeed) valueExpression(prop schema.Property) string {
+	if prop.IsEnum {
+		s.UsesEnum = true
+		return fmt.Sprintf("SeedEnum<%s>(random)", strings.TrimSuffix(prop.Type, "?"))
+	}
+
+	switch strings.TrimSuffix(prop.Type, "?") {
+	case "string":
+		if prop.MaxLength > 0 {
+			s.UsesText = true
+			return fmt.Sprintf(`SeedText($"%s {i + 1}", %d)`, prop.Name, prop.MaxLength)
+		}
+		return fmt.Sprintf(`$"%s {i + 1}"`, prop.Name)
+	case "int":
+		return "random.Next(1, 1000)"
+	case "long":
+		return "(long)random.Next(1, 1000000)"
+	case "short":
+		return "(short)random.Next(1, 1000)"
+	case "byte":
+		return "(byte)random.Next(0, 256)"
+	case "decimal":
+		scale := prop.Scale
+		if scale == 0 {
+			scale = 2
+		}
+		return fmt.Sprintf("Math.Round((decimal)random.NextDouble() * 1000m, %d)", scale)
+	case "double":
+		return "Math.Round(random.NextDouble() * 1000, 2)"
+	case "float":
+		return "(float)Math.Round(random.NextDouble() * 1000, 2)"
+	case "bool":
+		return "random.Next(2) == 0"
+	case "DateTime":
+		return "new DateTime(2024, 1, 1, 0, 0, 0, DateTimeKind.Utc).AddMinutes(random.Next(0, 525600))"
+	case "DateTimeOffset":
+		return "new DateTimeOffset(2024, 1, 1, 0, 0, 0, TimeSpan.Zero).AddMinutes(random.Next(0, 525600))"
+	case "DateOnly":
+		return "new DateOnly(2024, 1, 1).AddDays(random.Next(0, 365))"
+	case "TimeOnly":
+		return "new TimeOnly(0, 0).AddMinutes(random.Next(0, 1440))"
+	case "TimeSpan":
+		return "TimeSpan.FromMinutes(random.Next(0, 1440))"
+	case "Guid":
+		s.UsesGuid = true
+		return "SeedGuid(random)"
+	default:
+		return "default"
+	}
+}
+
+// getSeedFilterProperties returns the constructor properties whose seeded values can be
+// matched with == in a repository query
+func getSeedFilterProperties(sch *schema.Schema, entity *schema.Entity) []schema.Property {
+	var props []schema.Property
+	for _, prop := range sch.GetConstructorProperties(entity) {
+		if prop.IsEnum || schema.IsFilterableType(prop.Type) {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// getOneToOneRelations returns the entity's one-to-one relations with default navigation and foreign key names
+func getOneToOneRelations(entity *schema.Entity) []schema.OneToOneRelation {
+	if entity.Relations == nil {
+		return nil
+	}
+	relations := make([]schema.OneToOneRelation, 0, len(entity.Relations.OneToOne))
+	for _, rel := range entity.Relations.OneToOne {
+		if rel.NavigationProperty == "" {
+			rel.NavigationProperty = rel.TargetEntity
+		}
+		if rel.ForeignKeyName == "" {
+			rel.ForeignKeyName = rel.TargetEntity + "Id"
+		}
+		relations = append(relations, rel)
+	}
+	return relations
+}
+
 func getOneToManyRelations(entity *schema.Entity) []schema.OneToManyRelation {
 	if entity.Relations == nil {
 		return nil
@@ -254,9 +486,124 @@ func getOneToManyRelations(entity *schema.Entity) []schema.OneToManyRelation {
 	return entity.Relations.OneToMany
 }
 
+// getManyToOneRelations returns the entity's many-to-one relations with default navigation and foreign key names
+func getManyToOneRelations(entity *schema.Entity) []schema.ManyToOneRelation {
+	if entity.Relations == nil {
+		return nil
+	}
+	relations := make([]schema.ManyToOneRelation, 0, len(entity.Relations.ManyToOne))
+	for _, rel := range entity.Relations.ManyToOne {
+		if rel.NavigationProperty == "" {
+			rel.NavigationProperty = rel.TargetEntity
+		}
+		if rel.ForeignKeyName == "" {
+			rel.ForeignKeyName = rel.TargetEntity + "Id"
+		}
+		relations = append(relations, rel)
+	}
+	return relations
+}
+
+// foreignKeyNavigations maps the foreign keys of the entity's one-to-one and many-to-one
+// relations to their reference navigation properties
+func foreignKeyNavigations(entity *schema.Entity) map[string]string {
+	navigations := make(map[string]string)
+	for _, rel := range getOneToOneRelations(entity) {
+		if !rel.IsOwned {
+			navigations[rel.ForeignKeyName] = rel.NavigationProperty
+		}
+	}
+	for _, rel := range getManyToOneRelations(entity) {
+		navigations[rel.ForeignKeyName] = rel.NavigationProperty
+	}
+	return navigations
+}
+
+// getManyToManyRelations returns the entity's many-to-many relations with default navigation and join entity names
 func getManyToManyRelations(entity *schema.Entity) []schema.ManyToManyRelation {
 	if entity.Relations == nil {
 		return nil
 	}
-	return entity.Relations.ManyToMany
+	relations := make([]schema.ManyToManyRelation, 0, len(entity.Relations.ManyToMany))
+	for _, rel := range entity.Relations.ManyToMany {
+		applyManyToManyDefaults(entity.Name, &rel)
+		relations = append(relations, rel)
+	}
+	return relations
+}
+
+// CollectionNavigation describes a collection navigation property on an entity
+type CollectionNavigation struct {
+	TargetEntity       string // Element type of the collection
+	NavigationProperty string // Collection property name
+	ItemName           string // Singular name used for Add/Remove methods
+	DuplicateErrorCode string // Business exception code thrown when adding an item twice
+}
+
+// getCollectionNavigations returns the one-to-many and many-to-many collection navigations of an entity
+func getCollectionNavigations(sch *schema.Schema, entity *schema.Entity) []CollectionNavigation {
+	var navigations []CollectionNavigation
+	add := func(targetEntity, navigationProperty string) {
+		if navigationProperty == "" {
+			navigationProperty = templates.Pluralize(targetEntity)
+		}
+		itemName := templates.Singularize(navigationProperty)
+		navigations = append(navigations, CollectionNavigation{
+			TargetEntity:       targetEntity,
+			NavigationProperty: navigationProperty,
+			ItemName:           itemName,
+			DuplicateErrorCode: fmt.Sprintf("%s:%sDuplicate%s", sch.Solution.NamespaceRoot, entity.Name, itemName),
+		})
+	}
+
+	for _, rel := range getOneToManyRelations(entity) {
+		add(rel.TargetEntity, rel.NavigationProperty)
+	}
+	for _, rel := range getManyToManyRelations(entity) {
+		add(rel.TargetEntity, rel.NavigationProperty)
+		if rel.IsSelfReference {
+			add(rel.TargetEntity, rel.InverseProperty)
+		}
+	}
+	return navigations
+}
+
+// DetailNavigation describes a navigation property that is eager-loaded with the entity's details
+type DetailNavigation struct {
+	TargetEntity       string // Type of the related entity
+	NavigationProperty string // Navigation property name
+	IsCollection       bool   // Whether the navigation is a collection
+}
+
+// getDetailNavigations returns the navigations of relations marked withDetails. Owned one-to-one
+// relations are always loaded with their owner and are not listed.
+func getDetailNavigations(entity *schema.Entity) []DetailNavigation {
+	if entity.Relations == nil {
+		return nil
+	}
+
+	var navigations []DetailNavigation
+	addCollection := func(targetEntity, navigationProperty string) {
+		if navigationProperty == "" {
+			navigationProperty = templates.Pluralize(targetEntity)
+		}
+		navigations = append(navigations, DetailNavigation{TargetEntity: targetEntity, NavigationProperty: navigationProperty, IsCollection: true})
+	}
+
+	for _, rel := range getOneToOneRelations(entity) {
+		if rel.WithDetails && !rel.IsOwned {
+			navigations = append(navigations, DetailNavigation{TargetEntity: rel.TargetEntity, NavigationProperty: rel.NavigationProperty})
+		}
+	}
+	for _, rel := range entity.Relations.OneToMany {
+		if rel.WithDetails {
+			addCollection(rel.TargetEntity, rel.NavigationProperty)
+		}
+	}
+	for _, rel := range getManyToManyRelations(entity) {
+		if rel.WithDetails {
+			addCollection(rel.TargetEntity, rel.NavigationProperty)
+		}
+	}
+	return navigations
 }
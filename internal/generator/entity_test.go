@@ -0,0 +1,425 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestEntityGenerator_GeneratedHeader(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	header := "// <auto-generated> by abp-gen v1.0.0 from schema.json"
+	w.SetHeader(header)
+
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	if !strings.HasPrefix(content, header+"\n") {
+		t.Errorf("entity file does not start with header %q:\n%s", header, content)
+	}
+}
+
+func TestEntityGenerator_NullableTypes(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", Nullable: true},
+			{Name: "Stock", Type: "int", Nullable: true},
+			{Name: "ReleasedAt", Type: "DateTimeOffset", Nullable: true},
+			{Name: "Status", Type: "ProductStatus", IsEnum: true, EnumName: "ProductStatus", Nullable: true},
+			{Name: "Price", Type: "decimal"},
+		},
+		Enums: []schema.EnumDefinition{{
+			Name:           "ProductStatus",
+			UnderlyingType: "int",
+			Values:         []schema.EnumValue{{Name: "Draft", Value: "0"}},
+		}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	for _, want := range []string{
+		"public string Name { get; set; }",
+		"public int? Stock { get; set; }",
+		"public DateTimeOffset? ReleasedAt { get; set; }",
+		"public ProductStatus? Status { get; set; }",
+		"public decimal Price { get; set; }",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("entity missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestEntityGenerator_NullableParameters(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "AggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Cost", Type: "decimal", Nullable: true},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewManagerGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() manager error = %v", err)
+	}
+
+	// The app service passes input.Cost, a decimal?, to every one of these parameters
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	for _, want := range []string{
+		"public Product(Guid id, string name, decimal? cost)",
+		"public void SetCost(decimal? cost)",
+		"public void Update(string name, decimal? cost)",
+	} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity missing %q:\n%s", want, entity)
+		}
+	}
+	manager := generatedContent(t, w, "Managers/CatalogModule/ProductManager.cs")
+	if strings.Contains(manager, "decimal cost") || strings.Count(manager, "decimal? cost") != 2 {
+		t.Errorf("manager CreateAsync and UpdateAsync do not take decimal? cost:\n%s", manager)
+	}
+}
+
+func TestEntityGenerator_MergeUpdatesGeneratedHeader(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	paths := newTestLayerPaths(t)
+	loader := templates.NewLoader("")
+
+	first := writer.NewWriter(false, false, false)
+	first.SetHeader("// <auto-generated> by abp-gen v1.0.0 from schema.json")
+	if err := NewEntityGenerator(loader, first).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("first Generate() error = %v", err)
+	}
+
+	second := writer.NewWriterWithMerge(false, false, false, true)
+	second.SetMergeAll(true)
+	second.SetHeader("// <auto-generated> by abp-gen v1.1.0 from schema.json")
+	if err := NewEntityGenerator(loader, second).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("second Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(paths.DomainEntities, "CatalogModule", "Product.cs"))
+	if err != nil {
+		t.Fatalf("failed to read merged entity: %v", err)
+	}
+	content := string(data)
+
+	if !strings.HasPrefix(content, "// <auto-generated> by abp-gen v1.1.0 from schema.json\n") {
+		t.Errorf("merged entity header not updated:\n%s", content)
+	}
+	if strings.Contains(content, "v1.0.0") {
+		t.Errorf("merged entity still contains the old header:\n%s", content)
+	}
+	if n := strings.Count(content, "<auto-generated>"); n != 1 {
+		t.Errorf("merged entity contains %d headers; want 1:\n%s", n, content)
+	}
+}
+
+func TestEntityGenerator_CollectionNavigationMethods(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Order",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Number", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToMany: []schema.OneToManyRelation{
+				{TargetEntity: "OrderItem", NavigationProperty: "OrderItems", CascadeDelete: true},
+			},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Order.cs")
+	ctor := content[strings.Index(content, "public Order(Guid id"):]
+
+	expected := []string{
+		"public virtual ICollection<OrderItem> OrderItems { get; protected set; }",
+		"public void AddOrderItem(OrderItem orderItem)",
+		"Check.NotNull(orderItem, nameof(orderItem));",
+		"if (OrderItems.Any(x => x.Id.Equals(orderItem.Id)))",
+		"public void RemoveOrderItem(OrderItem orderItem)",
+		"using System.Linq;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("entity missing %q:\n%s", want, content)
+		}
+	}
+	if !strings.Contains(ctor, "OrderItems = new List<OrderItem>();") {
+		t.Errorf("constructor does not initialize OrderItems:\n%s", ctor)
+	}
+}
+
+func TestEntityGenerator_AggregateRootTypes(t *testing.T) {
+	tests := []struct {
+		entityType string
+		dtoBase    string
+		sorting    string
+	}{
+		{"AggregateRoot", "ProductDto : EntityDto<Guid>", `DefaultSorting = "Id desc"`},
+		{"CreationAuditedAggregateRoot", "ProductDto : CreationAuditedEntityDto<Guid>", `DefaultSorting = "CreationTime desc"`},
+		{"AuditedAggregateRoot", "ProductDto : AuditedEntityDto<Guid>", `DefaultSorting = "CreationTime desc"`},
+		{"FullAuditedAggregateRoot", "ProductDto : FullAuditedEntityDto<Guid>", `DefaultSorting = "CreationTime desc"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entityType, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:       "Product",
+				EntityType: tt.entityType,
+				Properties: []schema.Property{{Name: "Name", Type: "string"}},
+			})
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			gen := NewEntityGenerator(loader, w)
+			if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if err := gen.GenerateConstants(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateConstants() error = %v", err)
+			}
+			if err := gen.GenerateEvents(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateEvents() error = %v", err)
+			}
+			if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("DTO Generate() error = %v", err)
+			}
+
+			entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+			if !strings.Contains(entity, "public void PublishDistributedEvent(ProductEto eto)") {
+				t.Errorf("aggregate root cannot publish events:\n%s", entity)
+			}
+			generatedContent(t, w, "ProductEto.cs")
+
+			if dto := generatedContent(t, w, "Product/ProductDto.cs"); !strings.Contains(dto, tt.dtoBase) {
+				t.Errorf("DTO missing %q:\n%s", tt.dtoBase, dto)
+			}
+			if constants := generatedContent(t, w, "ProductConstants.cs"); !strings.Contains(constants, tt.sorting) {
+				t.Errorf("constants missing %q:\n%s", tt.sorting, constants)
+			}
+		})
+	}
+}
+
+func TestEntityGenerator_CustomBaseClass(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		BaseClass:  "Acme.Shared.MyAuditedEntity",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewEntityGenerator(loader, w)
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/Product.cs")
+	if !strings.Contains(entity, "public class Product : Acme.Shared.MyAuditedEntity<Guid>") {
+		t.Errorf("entity does not derive from the custom base class:\n%s", entity)
+	}
+	// EntityType still decides aggregate root behavior
+	if !strings.Contains(entity, "public void PublishDistributedEvent(ProductEto eto)") {
+		t.Errorf("aggregate root behavior lost with a custom base class:\n%s", entity)
+	}
+}
+
+// newHierarchySchema returns a schema where BusinessCustomer derives from Customer
+func newHierarchySchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	return newTestSchema(t,
+		schema.Entity{
+			Name:       "Customer",
+			EntityType: "FullAuditedAggregateRoot",
+			Properties: []schema.Property{{Name: "Name", Type: "string"}, {Name: "Email", Type: "string"}},
+		},
+		schema.Entity{
+			Name:       "BusinessCustomer",
+			BaseEntity: "Customer",
+			Properties: []schema.Property{{Name: "CompanyName", Type: "string"}},
+		},
+	)
+}
+
+func TestEntityGenerator_BaseEntity(t *testing.T) {
+	sch := newHierarchySchema(t)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewEntityGenerator(loader, w)
+	for i := range sch.Entities {
+		if err := gen.Generate(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	derived := generatedContent(t, w, "Entities/CatalogModule/BusinessCustomer.cs")
+	for _, want := range []string{
+		"public class BusinessCustomer : Customer\n",
+		"public BusinessCustomer(Guid id, string name, string email, string companyName) : base(id, name, email)",
+		"public void Update(string name, string email, string companyName)",
+	} {
+		if !strings.Contains(derived, want) {
+			t.Errorf("derived entity missing %q:\n%s", want, derived)
+		}
+	}
+	// Base members are inherited, not redeclared
+	for _, unwanted := range []string{"public string Name", "public void SetName", "PublishDistributedEvent(CustomerEto"} {
+		if strings.Contains(derived, unwanted) {
+			t.Errorf("derived entity redeclares %q:\n%s", unwanted, derived)
+		}
+	}
+
+	base := generatedContent(t, w, "Entities/CatalogModule/Customer.cs")
+	if !strings.Contains(base, "public class Customer : FullAuditedAggregateRoot<Guid>") {
+		t.Errorf("base entity declaration changed:\n%s", base)
+	}
+}
+
+func TestEntityGenerator_GeneratedSeedRows(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", MaxLength: 8},
+			{Name: "Price", Type: "decimal", Precision: 18, Scale: 4},
+			{Name: "IsActive", Type: "bool"},
+			{Name: "ReleasedAt", Type: "DateTime", Nullable: true},
+		},
+		SeedCount: 25,
+	})
+	sch.Solution.SeedCount = 5
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateDataSeeder(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateDataSeeder() error = %v", err)
+	}
+
+	seeder := generatedContent(t, w, "ProductDataSeeder.cs")
+	for _, want := range []string{
+		"using System.Collections.Generic;",
+		"for (var i = 0; i < 25; i++)",
+		"generated.Add(new Product(\n                        _guidGenerator.Create(),\n" +
+			"                        SeedText($\"Name {i + 1}\", 8),\n" +
+			"                        Math.Round((decimal)random.NextDouble() * 1000m, 4),\n" +
+			"                        random.Next(2) == 0,\n" +
+			"                        new DateTime(2024, 1, 1, 0, 0, 0, DateTimeKind.Utc).AddMinutes(random.Next(0, 525600))));",
+		"await _repository.InsertManyAsync(generated, autoSave: true);",
+		"private static string SeedText(string value, int maxLength)",
+	} {
+		if !strings.Contains(seeder, want) {
+			t.Errorf("seeder missing %q\n%s", want, seeder)
+		}
+	}
+	for _, unwanted := range []string{"TODO: Add seed data", "SeedGuid", "SeedEnum"} {
+		if strings.Contains(seeder, unwanted) {
+			t.Errorf("seeder unexpectedly contains %q\n%s", unwanted, seeder)
+		}
+	}
+
+	// The Random seed is derived from the entity name, so regenerating yields the same rows
+	first := buildGeneratedSeed(sch, &sch.Entities[0], 1)
+	if again := buildGeneratedSeed(sch, &sch.Entities[0], 1); again.RandomSeed != first.RandomSeed {
+		t.Errorf("RandomSeed = %d then %d; want a stable seed", first.RandomSeed, again.RandomSeed)
+	}
+	if !strings.Contains(seeder, fmt.Sprintf("var random = new Random(%d);", first.RandomSeed)) {
+		t.Errorf("seeder does not use the entity's random seed %d\n%s", first.RandomSeed, seeder)
+	}
+}
+
+func TestEntityGenerator_NullableSeedRows(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Discount", Type: "decimal", Nullable: true},
+		},
+		SeedData: []schema.SeedRow{
+			{"Name": "Widget", "Discount": nil},
+			{"Name": "Gadget"},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateDataSeeder(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateDataSeeder() error = %v", err)
+	}
+
+	seeder := generatedContent(t, w, "ProductDataSeeder.cs")
+	for _, want := range []string{
+		`new object[] { "Widget", null },`,
+		`new object[] { "Gadget", null },`,
+		"(decimal?)row[1]), autoSave: true);",
+	} {
+		if !strings.Contains(seeder, want) {
+			t.Errorf("seeder missing %q\n%s", want, seeder)
+		}
+	}
+}
+
+func TestEntityGenerator_SeedsStronglyTypedIds(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:           "Product",
+		PrimaryKeyType: "ProductId",
+		Properties:     []schema.Property{{Name: "Name", Type: "string"}},
+		SeedData:       []schema.SeedRow{{"Name": "Widget"}},
+		SeedCount:      2,
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateDataSeeder(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateDataSeeder() error = %v", err)
+	}
+
+	seeder := generatedContent(t, w, "ProductDataSeeder.cs")
+	for _, want := range []string{
+		"private readonly IGuidGenerator _guidGenerator;",
+		"await _repository.InsertAsync(new Product(\n                        new ProductId(_guidGenerator.Create()),",
+		"generated.Add(new Product(\n                        new ProductId(_guidGenerator.Create()),",
+	} {
+		if !strings.Contains(seeder, want) {
+			t.Errorf("seeder missing %q\n%s", want, seeder)
+		}
+	}
+	if strings.Contains(seeder, "default,") {
+		t.Errorf("seeder reuses the default id:\n%s", seeder)
+	}
+}
@@ -79,7 +79,8 @@ func (g *EnumGenerator) generateEnumClass(sch *schema.Schema, entity *schema.Ent
 		"EntityName":           entity.Name,
 		"EnumName":             enum.Name,
 		"UnderlyingType":       enum.UnderlyingType,
-		"Values":               enum.Values,
+		"Values":               enum.ResolvedValues(),
+		"IsFlags":              enum.IsFlags,
 		"Description":          enum.Description,
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
@@ -111,7 +112,8 @@ func (g *EnumGenerator) generateEnumLookup(sch *schema.Schema, entity *schema.En
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"EnumName":             enum.Name,
-		"Values":               enum.Values,
+		"Values":               enum.ResolvedValues(),
+		"IsFlags":              enum.IsFlags,
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
@@ -142,7 +144,7 @@ func (g *EnumGenerator) generateEnumLocalization(sch *schema.Schema, entity *sch
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"EnumName":             enum.Name,
-		"Values":               enum.Values,
+		"Values":               enum.ResolvedValues(),
 		"Cultures":             sch.Options.LocalizationCultures,
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
@@ -176,7 +178,7 @@ func (g *EnumGenerator) generateEnumDTO(sch *schema.Schema, entity *schema.Entit
 		"EntityName":           entity.Name,
 		"EnumName":             enum.Name,
 		"UnderlyingType":       enum.UnderlyingType,
-		"Values":               enum.Values,
+		"Values":               enum.ResolvedValues(),
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
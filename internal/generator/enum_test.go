@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestEnumGenerator_FlagsEnum(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Document",
+		Properties: []schema.Property{{Name: "Access", Type: "AccessRights", IsEnum: true, EnumName: "AccessRights"}},
+		Enums: []schema.EnumDefinition{{
+			Name:           "AccessRights",
+			UnderlyingType: "int",
+			IsFlags:        true,
+			GenerateLookup: true,
+			Values: []schema.EnumValue{
+				{Name: "None", Value: "0"},
+				{Name: "Read"},
+				{Name: "Write", Value: "4"},
+				{Name: "Delete"},
+			},
+		}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEnumGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	enum := generatedContent(t, w, "Enums/CatalogModule/AccessRights.cs")
+	for _, want := range []string{
+		"[Flags]\n    public enum AccessRights : int",
+		"None = 0,",
+		"Read = 1,",
+		"Write = 4,",
+		"Delete = 2\n",
+	} {
+		if !strings.Contains(enum, want) {
+			t.Errorf("enum missing %q:\n%s", want, enum)
+		}
+	}
+
+	lookup := generatedContent(t, w, "Enums/CatalogModule/AccessRightsExtensions.cs")
+	for _, want := range []string{
+		"public static bool HasAllFlags(this AccessRights value, AccessRights flags)",
+		"public static bool HasAnyFlag(this AccessRights value, AccessRights flags)",
+		"public static IEnumerable<AccessRights> GetFlags(this AccessRights value)",
+	} {
+		if !strings.Contains(lookup, want) {
+			t.Errorf("lookup missing %q:\n%s", want, lookup)
+		}
+	}
+}
+
+func TestEnumGenerator_PlainEnumHasNoFlags(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Status", Type: "ProductStatus", IsEnum: true, EnumName: "ProductStatus"}},
+		Enums: []schema.EnumDefinition{{
+			Name:           "ProductStatus",
+			UnderlyingType: "int",
+			GenerateLookup: true,
+			Values:         []schema.EnumValue{{Name: "Draft", Value: "0"}, {Name: "Published", Value: "1"}},
+		}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEnumGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if enum := generatedContent(t, w, "Enums/CatalogModule/ProductStatus.cs"); strings.Contains(enum, "[Flags]") {
+		t.Errorf("plain enum has [Flags]:\n%s", enum)
+	}
+	if lookup := generatedContent(t, w, "Enums/CatalogModule/ProductStatusExtensions.cs"); strings.Contains(lookup, "HasAllFlags") {
+		t.Errorf("plain enum lookup has flag helpers:\n%s", lookup)
+	}
+}
+
+func TestEnumGenerator_StringEnumUsesDefaultUnderlyingType(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Order",
+		Properties: []schema.Property{{Name: "Status", Type: "OrderStatus", IsEnum: true, EnumName: "OrderStatus"}},
+		Enums: []schema.EnumDefinition{{
+			Name:           "OrderStatus",
+			UnderlyingType: "string",
+			Values:         []schema.EnumValue{{Name: "Pending"}, {Name: "Shipped"}},
+		}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEnumGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// C# enums cannot derive from string
+	enum := generatedContent(t, w, "Enums/CatalogModule/OrderStatus.cs")
+	if !strings.Contains(enum, "public enum OrderStatus\n") {
+		t.Errorf("string enum not declared with the default underlying type:\n%s", enum)
+	}
+}
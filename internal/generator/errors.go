@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// GenerationError is a generator failure with the context needed to diagnose it. Template and
+// Path are empty when the failure did not concern a template or an output file.
+type GenerationError struct {
+	Generator string // Registration name
+	Entity    string // Empty for per-module generators
+	Template  string // Template that failed to load or execute
+	Path      string // Output file being written
+	Err       error
+}
+
+func (e *GenerationError) Error() string {
+	subject := e.Generator + " generator"
+	if e.Entity != "" {
+		subject += " for " + e.Entity
+	}
+
+	var context []string
+	if e.Template != "" {
+		context = append(context, "template "+e.Template)
+	}
+	if e.Path != "" {
+		context = append(context, "output "+e.Path)
+	}
+	if len(context) > 0 {
+		subject += " (" + strings.Join(context, ", ") + ")"
+	}
+	return fmt.Sprintf("%s failed: %v", subject, e.Err)
+}
+
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// newGenerationError attributes err to a generator run, taking the template and output file
+// from the template and writer errors it wraps
+func newGenerationError(generator string, entity *schema.Entity, err error) *GenerationError {
+	genErr := &GenerationError{Generator: generator, Err: err}
+	if entity != nil {
+		genErr.Entity = entity.Name
+	}
+
+	var loadErr *templates.TemplateError
+	var execErr template.ExecError
+	switch {
+	case errors.As(err, &loadErr):
+		genErr.Template = loadErr.Name
+		if loadErr.Path != "" {
+			genErr.Template += " from " + loadErr.Path
+		}
+	case errors.As(err, &execErr):
+		genErr.Template = execErr.Name
+	}
+
+	var fileErr *writer.FileError
+	if errors.As(err, &fileErr) {
+		genErr.Path = fileErr.Path
+	}
+	return genErr
+}
@@ -32,8 +32,8 @@ func (g *EventHandlerGenerator) Generate(sch *schema.Schema, entity *schema.Enti
 		return nil
 	}
 
-	if entity.EntityType == "ValueObject" || entity.EntityType == "Entity" {
-		return nil // These types don't generate distributed events
+	if !entity.IsAggregateRoot() {
+		return nil // Only aggregate roots publish distributed events
 	}
 
 	// Ensure event handlers directory exists
@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// GrpcGenerator generates gRPC service contracts and the services delegating to the app service
+type GrpcGenerator struct {
+	tmplLoader *templates.Loader
+	writer     *writer.Writer
+}
+
+// NewGrpcGenerator creates a new gRPC generator
+func NewGrpcGenerator(tmplLoader *templates.Loader, w *writer.Writer) *GrpcGenerator {
+	return &GrpcGenerator{
+		tmplLoader: tmplLoader,
+		writer:     w,
+	}
+}
+
+// GrpcField is a property exchanged through a gRPC message
+type GrpcField struct {
+	Name        string // C# property name on the DTO and the generated message class
+	ProtoName   string // Field name in the .proto file
+	ProtoType   string // Protobuf type
+	Number      int    // Field number
+	ToMessage   string // C# expression converting the DTO value "dto" to the message value
+	FromMessage string // C# expression converting the message value of "request" to the DTO value
+}
+
+// grpcScalar describes how a C# property type is carried in a protobuf message
+type grpcScalar struct {
+	protoType string // Type of a non-nullable value
+	wrapper   string // Type of a nullable value
+}
+
+// grpcScalars maps the supported C# types to protobuf types. Decimals and Guids are carried as
+// strings, since protobuf has no equivalent type.
+var grpcScalars = map[string]grpcScalar{
+	"string":   {"string", "google.protobuf.StringValue"},
+	"int":      {"int32", "google.protobuf.Int32Value"},
+	"long":     {"int64", "google.protobuf.Int64Value"},
+	"short":    {"int32", "google.protobuf.Int32Value"},
+	"byte":     {"int32", "google.protobuf.Int32Value"},
+	"bool":     {"bool", "google.protobuf.BoolValue"},
+	"double":   {"double", "google.protobuf.DoubleValue"},
+	"float":    {"float", "google.protobuf.FloatValue"},
+	"decimal":  {"string", "google.protobuf.StringValue"},
+	"Guid":     {"string", "google.protobuf.StringValue"},
+	"DateTime": {"google.protobuf.Timestamp", "google.protobuf.Timestamp"},
+}
+
+// Generate generates the .proto contract and gRPC service of an entity. Only microservice
+// targets with options.generateGrpc get gRPC services.
+func (g *GrpcGenerator) Generate(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	if !sch.Options.GenerateGrpc || !sch.Solution.TargetFramework.IsMicroservice() || entity.EntityType == "ValueObject" {
+		return nil
+	}
+
+	data, err := g.prepareGrpcData(sch, entity)
+	if err != nil {
+		return err
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+
+	protoPath := filepath.Join(paths.HttpApi, "Protos", moduleFolder, protoFieldName(entity.Name)+".proto")
+	if err := g.render("grpc_proto.tmpl", protoPath, data); err != nil {
+		return err
+	}
+
+	servicePath := filepath.Join(paths.HttpApi, "Grpc", moduleFolder, entity.Name+"GrpcService.cs")
+	return g.render("grpc_service.tmpl", servicePath, data)
+}
+
+// render executes a gRPC template and writes the result to path
+func (g *GrpcGenerator) render(name, path string, data map[string]interface{}) error {
+	tmpl, err := g.tmplLoader.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute %s template: %w", name, err)
+	}
+	return g.writer.WriteFile(path, buf.String())
+}
+
+// prepareGrpcData prepares the data shared by the .proto and service templates
+func (g *GrpcGenerator) prepareGrpcData(sch *schema.Schema, entity *schema.Entity) (map[string]interface{}, error) {
+	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+	idProperty := schema.Property{Name: "Id", Type: entity.GetPrimaryKeyUnderlyingType(sch.Solution.PrimaryKeyType)}
+	idField, ok := grpcFieldFor(idProperty)
+	if !ok || idProperty.Type == "DateTime" {
+		return nil, fmt.Errorf("gRPC services do not support the primary key type '%s' of %s", idProperty.Type, entity.Name)
+	}
+	if entity.HasStronglyTypedId() {
+		idField.FromMessage = fmt.Sprintf("(%s)%s", primaryKeyType, idField.FromMessage)
+	}
+
+	var readProps, inputProps []schema.Property
+	for _, e := range append(sch.GetBaseEntities(entity), entity) {
+		for _, prop := range e.GetNonForeignKeyProperties() {
+			if !prop.IsFile {
+				readProps = append(readProps, prop)
+			}
+		}
+		inputProps = append(inputProps, e.GetInputProperties()...)
+	}
+
+	readFields, skipped := grpcFields(readProps, idField)
+	createFields, _ := grpcFields(inputProps)
+	updateFields, _ := grpcFields(inputProps, idField)
+
+	// Well-known types need their imports in the .proto file
+	usesTimestamp, usesWrappers := false, false
+	for _, field := range append(readFields, createFields...) {
+		switch {
+		case field.ProtoType == "google.protobuf.Timestamp":
+			usesTimestamp = true
+		case strings.HasPrefix(field.ProtoType, "google.protobuf."):
+			usesWrappers = true
+		}
+	}
+
+	operations := getServiceOperations(entity)
+	return map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityName":           entity.Name,
+		"ProtoPackage":         strings.ToLower(sch.Solution.NamespaceRoot + "." + sch.Solution.ModuleName),
+		"PrimaryKeyType":       primaryKeyType,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"HasEnumProperties":    entity.HasEnumProperties(),
+		"ListInputType":        listInputType(entity),
+		"Operations":           operations,
+		"IdField":              idField,
+		"ReadFields":           readFields,
+		"CreateFields":         createFields,
+		"UpdateFields":         updateFields,
+		"SkippedProperties":    skipped,
+		"UsesTimestamp":        usesTimestamp,
+		"UsesWrappers":         usesWrappers,
+		"UsesEmpty":            operations.Delete,
+		"RequireAuthorization": entity.RequiresAuthorization(),
+	}, nil
+}
+
+// grpcFields numbers the fields of a message, starting with leading, and returns the names of
+// properties whose type cannot be carried in a protobuf message
+func grpcFields(props []schema.Property, leading ...GrpcField) ([]GrpcField, []string) {
+	fields := append([]GrpcField{}, leading...)
+	var skipped []string
+	for _, prop := range props {
+		field, ok := grpcFieldFor(prop)
+		if !ok {
+			skipped = append(skipped, prop.Name)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	for i := range fields {
+		fields[i].Number = i + 1
+	}
+	return fields, skipped
+}
+
+// grpcFieldFor maps a property to a message field. Nullable values use the protobuf wrapper
+// types, which the C# code generator exposes as nullable properties.
+func grpcFieldFor(prop schema.Property) (GrpcField, bool) {
+	typeName := prop.Type
+	if prop.IsEnum {
+		typeName = "int"
+	}
+	scalar, ok := grpcScalars[typeName]
+	if !ok {
+		return GrpcField{}, false
+	}
+
+	field := GrpcField{Name: prop.Name, ProtoName: protoFieldName(prop.Name), ProtoType: scalar.protoType}
+	if prop.Nullable {
+		field.ProtoType = scalar.wrapper
+	}
+
+	value := "dto." + prop.Name
+	message := "request." + prop.Name
+	switch {
+	case prop.IsEnum && prop.Nullable:
+		field.ToMessage, field.FromMessage = "(int?)"+value, fmt.Sprintf("(%s?)%s", prop.Type, message)
+	case prop.IsEnum:
+		field.ToMessage, field.FromMessage = "(int)"+value, fmt.Sprintf("(%s)%s", prop.Type, message)
+	case typeName == "string" && !prop.Nullable:
+		field.ToMessage, field.FromMessage = value+" ?? string.Empty", message
+	case typeName == "short" || typeName == "byte":
+		field.ToMessage, field.FromMessage = value, fmt.Sprintf("(%s)%s", templates.Nullable(typeName, prop.Nullable), message)
+	case typeName == "decimal" && prop.Nullable:
+		field.ToMessage = value + "?.ToString(CultureInfo.InvariantCulture)"
+		field.FromMessage = fmt.Sprintf("%s == null ? (decimal?)null : decimal.Parse(%s, CultureInfo.InvariantCulture)", message, message)
+	case typeName == "decimal":
+		field.ToMessage = value + ".ToString(CultureInfo.InvariantCulture)"
+		field.FromMessage = fmt.Sprintf("decimal.Parse(%s, CultureInfo.InvariantCulture)", message)
+	case typeName == "Guid" && prop.Nullable:
+		field.ToMessage = value + "?.ToString()"
+		field.FromMessage = fmt.Sprintf("%s == null ? (Guid?)null : Guid.Parse(%s)", message, message)
+	case typeName == "Guid":
+		field.ToMessage, field.FromMessage = value+".ToString()", fmt.Sprintf("Guid.Parse(%s)", message)
+	case typeName == "DateTime" && prop.Nullable:
+		field.ToMessage = fmt.Sprintf("%s == null ? null : Timestamp.FromDateTime(DateTime.SpecifyKind(%s.Value, DateTimeKind.Utc))", value, value)
+		field.FromMessage = message + "?.ToDateTime()"
+	case typeName == "DateTime":
+		field.ToMessage = fmt.Sprintf("Timestamp.FromDateTime(DateTime.SpecifyKind(%s, DateTimeKind.Utc))", value)
+		field.FromMessage = message + ".ToDateTime()"
+	default:
+		field.ToMessage, field.FromMessage = value, message
+	}
+	return field, true
+}
+
+// protoFieldName converts a PascalCase name to the snake_case protobuf style. The C# code
+// generator turns each underscore-separated word back into PascalCase, so names round-trip.
+func protoFieldName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestGrpcGenerator_Generate(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Enums: []schema.EnumDefinition{
+			{Name: "ProductStatus", Values: []schema.EnumValue{{Name: "Draft"}, {Name: "Published"}}},
+		},
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsRequired: true},
+			{Name: "Price", Type: "decimal"},
+			{Name: "ReleasedAt", Type: "DateTime", Nullable: true},
+			{Name: "Status", Type: "ProductStatus", IsEnum: true, EnumName: "ProductStatus"},
+			{Name: "Dimensions", Type: "Size"},
+		},
+	})
+	sch.Solution.TargetFramework = schema.TargetABP9Microservice
+	sch.Options.GenerateGrpc = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewGrpcGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	proto := generatedContent(t, w, "Acme.Shop.HttpApi/Protos/CatalogModule/product.proto")
+	for _, want := range []string{
+		`option csharp_namespace = "Acme.Shop.HttpApi.Grpc.CatalogModule";`,
+		"package acme.shop.catalog;",
+		`import "google/protobuf/empty.proto";`,
+		`import "google/protobuf/timestamp.proto";`,
+		"rpc Get (GetProductRequest) returns (ProductMessage);",
+		"rpc Delete (DeleteProductRequest) returns (google.protobuf.Empty);",
+		"// Dimensions is not mapped: its type has no protobuf equivalent",
+		"  string id = 1;\n  string name = 2;\n  string price = 3;\n  google.protobuf.Timestamp released_at = 4;\n  int32 status = 5;\n}",
+		"message UpdateProductRequest {\n  string id = 1;\n  string name = 2;",
+	} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("proto missing %q:\n%s", want, proto)
+		}
+	}
+	if strings.Contains(proto, "wrappers.proto") {
+		t.Errorf("proto imports wrappers without nullable scalars:\n%s", proto)
+	}
+
+	service := generatedContent(t, w, "Acme.Shop.HttpApi/Grpc/CatalogModule/ProductGrpcService.cs")
+	for _, want := range []string{
+		"public class ProductGrpcService : ProductGrpc.ProductGrpcBase",
+		"var result = await _appService.GetAsync(Guid.Parse(request.Id));",
+		"Price = decimal.Parse(request.Price, CultureInfo.InvariantCulture),",
+		"ReleasedAt = request.ReleasedAt?.ToDateTime(),",
+		"Status = (ProductStatus)request.Status,",
+		"await _appService.UpdateAsync(Guid.Parse(request.Id), input);",
+		"Name = dto.Name ?? string.Empty,",
+		"ReleasedAt = dto.ReleasedAt == null ? null : Timestamp.FromDateTime(DateTime.SpecifyKind(dto.ReleasedAt.Value, DateTimeKind.Utc)),",
+		"[Authorize(ProductManagement.Delete)]",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("service missing %q:\n%s", want, service)
+		}
+	}
+}
+
+func TestGrpcGenerator_SkipsMonolithTargets(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Solution.TargetFramework = schema.TargetABP9Monolith
+	sch.Options.GenerateGrpc = true
+	loader, w := newTestGenerators()
+
+	if err := NewGrpcGenerator(loader, w).Generate(sch, &sch.Entities[0], newTestLayerPaths(t)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(w.Operations) != 0 {
+		t.Errorf("Generate() wrote %d files for a monolith target", len(w.Operations))
+	}
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// newTestSchema returns a validated schema for a sample Shop solution with the given entities
+func newTestSchema(t *testing.T, entities ...schema.Entity) *schema.Schema {
+	t.Helper()
+
+	sch := &schema.Schema{
+		Solution: schema.Solution{
+			Name:           "Shop",
+			ModuleName:     "Catalog",
+			NamespaceRoot:  "Acme.Shop",
+			ABPVersion:     "9.0",
+			PrimaryKeyType: "Guid",
+			DBProvider:     "efcore",
+		},
+		Entities: entities,
+	}
+	if err := sch.Validate(); err != nil {
+		t.Fatalf("schema validation failed: %v", err)
+	}
+	return sch
+}
+
+// newTestLayerPaths returns layer paths rooted in a temporary directory
+func newTestLayerPaths(t *testing.T) *detector.LayerPaths {
+	t.Helper()
+
+	root := t.TempDir()
+	domain := filepath.Join(root, "Acme.Shop.Domain")
+	domainShared := filepath.Join(root, "Acme.Shop.Domain.Shared")
+	contracts := filepath.Join(root, "Acme.Shop.Application.Contracts")
+	app := filepath.Join(root, "Acme.Shop.Application")
+	httpApi := filepath.Join(root, "Acme.Shop.HttpApi")
+	efCore := filepath.Join(root, "Acme.Shop.EntityFrameworkCore")
+	mongo := filepath.Join(root, "Acme.Shop.MongoDB")
+
+	return &detector.LayerPaths{
+		Domain:                   domain,
+		DomainShared:             domainShared,
+		ApplicationContracts:     contracts,
+		Application:              app,
+		HttpApi:                  httpApi,
+		EntityFrameworkCore:      efCore,
+		MongoDB:                  mongo,
+		DomainEntities:           filepath.Join(domain, "Entities"),
+		DomainRepositories:       filepath.Join(domain, "Repositories"),
+		DomainManagers:           filepath.Join(domain, "Managers"),
+		DomainData:               filepath.Join(domain, "Data"),
+		DomainSharedConstants:    filepath.Join(domainShared, "Constants"),
+		DomainSharedEvents:       filepath.Join(domainShared, "Events"),
+		DomainSharedEnums:        filepath.Join(domainShared, "Enums"),
+		DomainSharedLocalization: filepath.Join(domainShared, "Localization", "Catalog"),
+		ContractsPermissions:     filepath.Join(contracts, "Permissions"),
+		ContractsDTOs:            contracts,
+		ContractsServices:        filepath.Join(contracts, "Services"),
+		ApplicationServices:      filepath.Join(app, "Services"),
+		ApplicationAutoMapper:    filepath.Join(app, "AutoMapper"),
+		ApplicationValidators:    filepath.Join(app, "Validators"),
+		ApplicationEventHandlers: filepath.Join(app, "EventHandlers"),
+		HttpApiControllers:       filepath.Join(httpApi, "Controllers"),
+		EFCoreConfigurations:     filepath.Join(efCore, "EntityFrameworkCore", "Configurations"),
+		EFCoreRepositories:       filepath.Join(efCore, "EntityFrameworkCore", "Repositories"),
+		MongoDBRepositories:      filepath.Join(mongo, "MongoDB", "Repositories"),
+	}
+}
+
+// newTestGenerators returns an embedded template loader and a dry-run writer
+func newTestGenerators() (*templates.Loader, *writer.Writer) {
+	return templates.NewLoader(""), writer.NewWriter(true, false, false)
+}
+
+// generatedContent returns the content recorded for the file whose path ends with suffix
+func generatedContent(t *testing.T, w *writer.Writer, suffix string) string {
+	t.Helper()
+
+	suffix = filepath.FromSlash(suffix)
+	for _, op := range w.Operations {
+		if strings.HasSuffix(op.Path, suffix) {
+			return op.Content
+		}
+	}
+
+	var paths []string
+	for _, op := range w.Operations {
+		paths = append(paths, op.Path)
+	}
+	t.Fatalf("no generated file ending with %q; generated:\n%s", suffix, strings.Join(paths, "\n"))
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -13,6 +14,25 @@ import (
 	"github.com/mohamedhabibwork/abp-gen/internal/writer"
 )
 
+// testProvider is a database provider the repository tests of a "both" solution run against
+type testProvider struct {
+	Namespace   string // Namespace and folder of the provider's repository tests, e.g. "MongoDB"
+	ClassPrefix string // Infix of the provider's test base and module, e.g. "MongoDb" for CatalogMongoDbTestModule
+}
+
+// repositoryTestProviders returns the providers repository tests are generated for separately.
+// It is empty unless an ABP solution uses both EF Core and MongoDB, whose test modules follow
+// ABP's {Module}EntityFrameworkCoreTestModule and {Module}MongoDbTestModule names.
+func repositoryTestProviders(sch *schema.Schema) []testProvider {
+	if sch.Solution.DBProvider != "both" || sch.Solution.TargetFramework.IsASPNETCore() {
+		return nil
+	}
+	return []testProvider{
+		{Namespace: "EntityFrameworkCore", ClassPrefix: "EntityFrameworkCore"},
+		{Namespace: "MongoDB", ClassPrefix: "MongoDb"},
+	}
+}
+
 // IntegrationTestGenerator generates integration tests
 type IntegrationTestGenerator struct {
 	tmplLoader *templates.Loader
@@ -59,7 +79,7 @@ func (g *IntegrationTestGenerator) Generate(sch *schema.Schema, entity *schema.E
 	}
 
 	// Generate domain tests (for aggregate roots with domain logic)
-	if entity.EntityType == "AggregateRoot" || entity.EntityType == "FullAuditedAggregateRoot" {
+	if entity.IsAggregateRoot() {
 		if err := g.generateDomainTests(sch, entity, paths); err != nil {
 			return fmt.Errorf("failed to generate domain tests: %w", err)
 		}
@@ -76,25 +96,25 @@ func (g *IntegrationTestGenerator) generateTestBase(sch *schema.Schema, paths *d
 	// Choose template based on target framework
 	switch sch.Solution.TargetFramework {
 	case schema.TargetASPNETCore9, schema.TargetASPNETCore10:
-		templateName = "test_base_aspnetcore.tmpl"
+		templateName = testTemplateName(sch, "test_base_aspnetcore.tmpl")
 	case schema.TargetABP8Microservice, schema.TargetABP9Microservice, schema.TargetABP10Microservice:
-		templateName = "test_base_abp_microservice.tmpl"
+		templateName = testTemplateName(sch, "test_base_abp_microservice.tmpl")
 	case schema.TargetABP8Monolith, schema.TargetABP9Monolith, schema.TargetABP10Monolith:
 		// For monolith, try microservice template as fallback, then generic ABP template
-		templateName = "test_base_abp_microservice.tmpl"
+		templateName = testTemplateName(sch, "test_base_abp_microservice.tmpl")
 		tmpl, err = g.tmplLoader.Load(templateName)
 		if err != nil {
 			// Fallback to generic ABP template
-			templateName = "test_base_abp.tmpl"
+			templateName = testTemplateName(sch, "test_base_abp.tmpl")
 			tmpl, err = g.tmplLoader.Load(templateName)
 		}
 	default:
 		// For auto or unknown, try microservice template first
-		templateName = "test_base_abp_microservice.tmpl"
+		templateName = testTemplateName(sch, "test_base_abp_microservice.tmpl")
 		tmpl, err = g.tmplLoader.Load(templateName)
 		if err != nil {
 			// Fallback to generic ABP template
-			templateName = "test_base_abp.tmpl"
+			templateName = testTemplateName(sch, "test_base_abp.tmpl")
 			tmpl, err = g.tmplLoader.Load(templateName)
 		}
 	}
@@ -108,29 +128,55 @@ func (g *IntegrationTestGenerator) generateTestBase(sch *schema.Schema, paths *d
 		return fmt.Errorf("failed to load test base template '%s': %w", templateName, err)
 	}
 
-	data := map[string]interface{}{
-		"SolutionName":         sch.Solution.Name,
-		"ModuleName":           sch.Solution.ModuleName,
-		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
-		"NamespaceRoot":        sch.Solution.NamespaceRoot,
-		"TargetFramework":      sch.Solution.TargetFramework,
-		"DBProvider":           sch.Solution.DBProvider,
-		"MultiTenancy":         sch.Solution.MultiTenancy,
+	// The module's test base, plus one per provider whose repository tests run separately
+	for _, provider := range append([]testProvider{{}}, repositoryTestProviders(sch)...) {
+		testBaseName := sch.Solution.ModuleName + provider.ClassPrefix + "TestBase"
+
+		data := map[string]interface{}{
+			"SolutionName":         sch.Solution.Name,
+			"ModuleName":           sch.Solution.ModuleName,
+			"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+			"NamespaceRoot":        sch.Solution.NamespaceRoot,
+			"TargetFramework":      sch.Solution.TargetFramework,
+			"DBProvider":           sch.Solution.DBProvider,
+			"MultiTenancy":         sch.Solution.MultiTenancy,
+			"TestBaseName":         testBaseName,
+			"TestModuleName":       sch.Solution.ModuleName + provider.ClassPrefix + "TestModule",
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to execute test base template: %w", err)
+		}
+
+		// Determine test project path
+		testPath := g.getTestProjectPath(paths, sch)
+		baseTestPath := filepath.Join(testPath, testBaseName+".cs")
+		if err := g.writer.WriteFile(baseTestPath, buf.String()); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to execute test base template: %w", err)
+func (g *IntegrationTestGenerator) generateRepositoryTests(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	providers := repositoryTestProviders(sch)
+	if len(providers) == 0 {
+		return g.generateProviderRepositoryTests(sch, entity, paths, testProvider{})
 	}
 
-	// Determine test project path
-	testPath := g.getTestProjectPath(paths, sch)
-	baseTestPath := filepath.Join(testPath, sch.Solution.ModuleName+"TestBase.cs")
-	return g.writer.WriteFile(baseTestPath, buf.String())
+	for _, provider := range providers {
+		if err := g.generateProviderRepositoryTests(sch, entity, paths, provider); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (g *IntegrationTestGenerator) generateRepositoryTests(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	tmpl, err := g.tmplLoader.Load("integration_test_repository.tmpl")
+// generateProviderRepositoryTests generates the entity's repository tests on the provider's test base;
+// the zero provider uses the module's test base
+func (g *IntegrationTestGenerator) generateProviderRepositoryTests(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths, provider testProvider) error {
+	tmpl, err := g.tmplLoader.Load(testTemplateName(sch, "integration_test_repository.tmpl"))
 	if err != nil {
 		return fmt.Errorf("failed to load repository test template: %w", err)
 	}
@@ -144,10 +190,19 @@ func (g *IntegrationTestGenerator) generateRepositoryTests(sch *schema.Schema, e
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"NewId":                newIdExpression(sch, entity, "GuidGenerator"),
 		"Properties":           entity.Properties,
 		"TargetFramework":      sch.Solution.TargetFramework,
 		"CustomRepository":     entity.CustomRepository,
 		"Relations":            entity.Relations,
+		"HasEnumProperties":    entity.HasEnumProperties(),
+		"SeedRows":             getSeedRows(sch, entity),
+		"SeedProperties":       sch.GetConstructorProperties(entity),
+		"SeedFilterProperties": getSeedFilterProperties(sch, entity),
+		"Provider":             provider.Namespace,
+		"TestBaseName":         sch.Solution.ModuleName + provider.ClassPrefix + "TestBase",
+		// Only full-audited aggregates implement ISoftDelete
+		"UseSoftDelete": sch.Options.UseSoftDelete && entity.EntityType == "FullAuditedAggregateRoot",
 	}
 
 	var buf bytes.Buffer
@@ -157,17 +212,18 @@ func (g *IntegrationTestGenerator) generateRepositoryTests(sch *schema.Schema, e
 
 	testPath := g.getTestProjectPath(paths, sch)
 	moduleFolder := sch.Solution.GetModuleFolderName()
-	repoTestPath := filepath.Join(testPath, "Repositories", moduleFolder, entity.Name+"RepositoryTests.cs")
+	repoTestPath := filepath.Join(testPath, "Repositories", moduleFolder, provider.Namespace, entity.Name+"RepositoryTests.cs")
 	return g.writer.WriteFile(repoTestPath, buf.String())
 }
 
 func (g *IntegrationTestGenerator) generateServiceTests(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	tmpl, err := g.tmplLoader.Load("integration_test_service.tmpl")
+	tmpl, err := g.tmplLoader.Load(testTemplateName(sch, "integration_test_service.tmpl"))
 	if err != nil {
 		return fmt.Errorf("failed to load service test template: %w", err)
 	}
 
 	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+	requiredInputs, requiredInputNames := requiredTestInputs(sch, entity)
 
 	data := map[string]interface{}{
 		"SolutionName":         sch.Solution.Name,
@@ -176,9 +232,14 @@ func (g *IntegrationTestGenerator) generateServiceTests(sch *schema.Schema, enti
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"NewId":                newIdExpression(sch, entity, "GuidGenerator"),
 		"Properties":           entity.Properties,
 		"TargetFramework":      sch.Solution.TargetFramework,
 		"Relations":            entity.Relations,
+		"ListInputType":        listInputType(entity),
+		"Operations":           getServiceOperations(entity),
+		"RequiredInputs":       requiredInputs,
+		"RequiredInputNames":   requiredInputNames,
 	}
 
 	var buf bytes.Buffer
@@ -192,8 +253,76 @@ func (g *IntegrationTestGenerator) generateServiceTests(sch *schema.Schema, enti
 	return g.writer.WriteFile(serviceTestPath, buf.String())
 }
 
+// TestInput is a member of an input DTO that the generated tests set, with a sample value
+type TestInput struct {
+	Name  string // DTO member name
+	Value string // C# expression of a sample value
+}
+
+// requiredTestInputs returns the required members of the Create and Update DTOs, including those
+// inherited from base entities, with sample values. An object initializer that leaves one of them
+// out does not compile (CS9035), so the tests cannot leave them commented out.
+func requiredTestInputs(sch *schema.Schema, entity *schema.Entity) ([]TestInput, map[string]bool) {
+	names := make(map[string]bool)
+	if !useModernDtos(sch) {
+		return nil, names
+	}
+
+	var inputs []TestInput
+	for _, e := range append(sch.GetBaseEntities(entity), entity) {
+		for _, prop := range e.GetInputProperties() {
+			if prop.IsRequired && !prop.HasDefaultValue() {
+				inputs = append(inputs, TestInput{Name: prop.Name, Value: testSampleValue(prop)})
+				names[prop.Name] = true
+			}
+		}
+	}
+	return inputs, names
+}
+
+// testSampleValue returns a C# expression of a valid sample value of the property's type
+func testSampleValue(prop schema.Property) string {
+	if prop.IsEnum {
+		return "default"
+	}
+	switch prop.Type {
+	case "string":
+		value := "Test " + prop.Name
+		if prop.MaxLength > 0 && len(value) > prop.MaxLength {
+			value = value[:prop.MaxLength]
+		}
+		return strconv.Quote(value)
+	case "int", "long", "short", "byte", "sbyte", "ushort", "uint", "ulong":
+		return "1"
+	case "decimal":
+		return "1m"
+	case "double":
+		return "1d"
+	case "float":
+		return "1f"
+	case "bool":
+		return "true"
+	case "char":
+		return "'A'"
+	case "DateTime":
+		return "DateTime.UtcNow"
+	case "DateTimeOffset":
+		return "DateTimeOffset.UtcNow"
+	case "DateOnly":
+		return "DateOnly.FromDateTime(DateTime.UtcNow)"
+	case "TimeOnly":
+		return "TimeOnly.FromDateTime(DateTime.UtcNow)"
+	case "TimeSpan":
+		return "TimeSpan.FromMinutes(1)"
+	case "Guid":
+		return "Guid.NewGuid()"
+	default:
+		return "default!"
+	}
+}
+
 func (g *IntegrationTestGenerator) generateDomainTests(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	tmpl, err := g.tmplLoader.Load("integration_test_domain.tmpl")
+	tmpl, err := g.tmplLoader.Load(testTemplateName(sch, "integration_test_domain.tmpl"))
 	if err != nil {
 		return fmt.Errorf("failed to load domain test template: %w", err)
 	}
@@ -207,10 +336,11 @@ func (g *IntegrationTestGenerator) generateDomainTests(sch *schema.Schema, entit
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"NewId":                newIdExpression(sch, entity, "GuidGenerator"),
 		"Properties":           entity.Properties,
 		"TargetFramework":      sch.Solution.TargetFramework,
 		"DomainEvents":         entity.DomainEvents,
-		"Manager":              entity.EntityType == "AggregateRoot" || entity.EntityType == "FullAuditedAggregateRoot",
+		"Manager":              entity.ShouldGenerateManager(sch.Options.GenerateDeleteGuards),
 	}
 
 	var buf bytes.Buffer
@@ -253,6 +383,10 @@ func (g *IntegrationTestGenerator) GenerateTestProject(sch *schema.Schema, paths
 		"NamespaceRoot":   sch.Solution.NamespaceRoot,
 		"TargetFramework": sch.Solution.TargetFramework,
 		"ABPVersion":      sch.Solution.ABPVersion,
+		"DotNetFramework": dotNetFramework(sch),
+		"TestFramework":   sch.Options.TestFramework,
+		"IsABP":           !sch.Solution.TargetFramework.IsASPNETCore(),
+		"DBProvider":      sch.Solution.DBProvider,
 	}
 
 	var buf bytes.Buffer
@@ -264,3 +398,28 @@ func (g *IntegrationTestGenerator) GenerateTestProject(sch *schema.Schema, paths
 	projectFile := filepath.Join(testPath, fmt.Sprintf("%s.%s.Tests.csproj", sch.Solution.Name, sch.Solution.ModuleName))
 	return g.writer.WriteFile(projectFile, buf.String())
 }
+
+// testTemplateName returns the variant of a test template for the configured test framework,
+// e.g. integration_test_service_nunit.tmpl for NUnit
+func testTemplateName(sch *schema.Schema, name string) string {
+	if sch.Options.TestFramework == "nunit" {
+		return strings.TrimSuffix(name, ".tmpl") + "_nunit.tmpl"
+	}
+	return name
+}
+
+// dotNetFramework returns the target framework moniker of the test project. ABP majors
+// match the .NET majors they run on, e.g. ABP 9.x targets net9.0.
+func dotNetFramework(sch *schema.Schema) string {
+	switch sch.Solution.TargetFramework {
+	case schema.TargetASPNETCore9:
+		return "net9.0"
+	case schema.TargetASPNETCore10:
+		return "net10.0"
+	}
+	major := strings.SplitN(strings.TrimPrefix(sch.Solution.ABPVersion, "v"), ".", 2)[0]
+	if _, err := strconv.Atoi(major); err != nil {
+		return "net9.0"
+	}
+	return "net" + major + ".0"
+}
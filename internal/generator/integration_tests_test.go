@@ -0,0 +1,270 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestIntegrationTestGenerator_SoftDeleteRepositoryTests(t *testing.T) {
+	tests := []struct {
+		name          string
+		entityType    string
+		useSoftDelete bool
+		wantSoft      bool
+	}{
+		{"soft delete on full-audited aggregate", "FullAuditedAggregateRoot", true, true},
+		{"soft delete disabled", "FullAuditedAggregateRoot", false, false},
+		{"entity without ISoftDelete", "AggregateRoot", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:       "Product",
+				EntityType: tt.entityType,
+				Properties: []schema.Property{{Name: "Name", Type: "string"}},
+			})
+			sch.Options.GenerateIntegrationTests = true
+			sch.Options.UseSoftDelete = tt.useSoftDelete
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewIntegrationTestGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			content := generatedContent(t, w, "ProductRepositoryTests.cs")
+			softDeleteChecks := []string{
+				"using (_dataFilter.Disable<ISoftDelete>())",
+				"public async Task Should_Get_Soft_Deleted_Product_With_Filter_Disabled()",
+				"deleted.IsDeleted.ShouldBeTrue();",
+			}
+			for _, want := range softDeleteChecks {
+				if got := strings.Contains(content, want); got != tt.wantSoft {
+					t.Errorf("repository tests contain %q = %v; want %v\n%s", want, got, tt.wantSoft, content)
+				}
+			}
+			if !strings.Contains(content, "await Should.ThrowAsync<EntityNotFoundException>") {
+				t.Errorf("delete test no longer checks the entity is hidden:\n%s", content)
+			}
+		})
+	}
+}
+
+func TestIntegrationTestGenerator_SeededRepositoryTheory(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Stock", Type: "short"},
+			{Name: "Price", Type: "decimal", DefaultValue: "1.5"},
+		},
+		SeedData: []schema.SeedRow{
+			{"Name": "Widget", "Stock": 3.0, "Price": 9.99},
+			{"Name": "Gadget"},
+		},
+	})
+	sch.Options.GenerateIntegrationTests = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateDataSeeder(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateDataSeeder() error = %v", err)
+	}
+	if err := NewIntegrationTestGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	seeder := generatedContent(t, w, "ProductDataSeeder.cs")
+	for _, want := range []string{
+		`new object[] { "Widget", (short)3, 9.99m },`,
+		`new object[] { "Gadget", default(short), 1.5m },`,
+		"foreach (var row in SeedRows)",
+		"(string)row[0],\n                        (short)row[1],\n                        (decimal)row[2]), autoSave: true);",
+	} {
+		if !strings.Contains(seeder, want) {
+			t.Errorf("seeder missing %q\n%s", want, seeder)
+		}
+	}
+	if strings.Contains(seeder, "TODO: Add seed data") {
+		t.Errorf("seeder still has the seed data placeholder:\n%s", seeder)
+	}
+
+	tests := generatedContent(t, w, "ProductRepositoryTests.cs")
+	for _, want := range []string{
+		"public static IEnumerable<object[]> SeededProductRows => ProductDataSeeder.SeedRows;",
+		"[MemberData(nameof(SeededProductRows))]",
+		"public async Task Should_Find_Seeded_Product(string name, short stock, decimal price)",
+		"await GetRequiredService<ProductDataSeeder>().SeedAsync(new DataSeedContext());",
+		"x.Name == name &&\n                x.Stock == stock &&\n                x.Price == price);",
+	} {
+		if !strings.Contains(tests, want) {
+			t.Errorf("repository tests missing %q\n%s", want, tests)
+		}
+	}
+}
+
+func TestIntegrationTestGenerator_NUnit(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		SeedData:   []schema.SeedRow{{"Name": "Widget"}},
+	})
+	sch.Options.GenerateIntegrationTests = true
+	sch.Options.TestFramework = "nunit"
+	sch.Solution.TargetFramework = schema.TargetABP9Monolith
+	sch.Solution.ABPVersion = "9.1"
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	generator := NewIntegrationTestGenerator(loader, w)
+	if err := generator.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := generator.GenerateTestProject(sch, paths); err != nil {
+		t.Fatalf("GenerateTestProject() error = %v", err)
+	}
+
+	for _, suffix := range []string{
+		"CatalogTestBase.cs",
+		"ProductRepositoryTests.cs",
+		"ProductServiceTests.cs",
+		"ProductTests.cs",
+	} {
+		content := generatedContent(t, w, suffix)
+		if !strings.Contains(content, "using NUnit.Framework;") {
+			t.Errorf("%s does not use NUnit:\n%s", suffix, content)
+		}
+		if strings.Contains(content, "Xunit") || strings.Contains(content, "[Fact]") {
+			t.Errorf("%s still references xUnit:\n%s", suffix, content)
+		}
+	}
+
+	repository := generatedContent(t, w, "ProductRepositoryTests.cs")
+	for _, want := range []string{
+		"    [TestFixture]\n    public class ProductRepositoryTests",
+		"        [Test]\n        public async Task Should_Create_Product()",
+		"        [TestCaseSource(nameof(SeededProductRows))]\n",
+	} {
+		if !strings.Contains(repository, want) {
+			t.Errorf("repository tests missing %q\n%s", want, repository)
+		}
+	}
+
+	project := generatedContent(t, w, "Shop.Catalog.Tests.csproj")
+	for _, want := range []string{
+		"<TargetFramework>net9.0</TargetFramework>",
+		`<PackageReference Include="NUnit" Version="4.0.1" />`,
+		`<PackageReference Include="NUnit3TestAdapter" Version="4.5.0" />`,
+	} {
+		if !strings.Contains(project, want) {
+			t.Errorf("test project missing %q\n%s", want, project)
+		}
+	}
+	if strings.Contains(project, "xunit") {
+		t.Errorf("test project still references xUnit:\n%s", project)
+	}
+}
+
+func TestIntegrationTestGenerator_BothProviders(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Options.GenerateIntegrationTests = true
+	sch.Solution.DBProvider = "both"
+	sch.Solution.TargetFramework = schema.TargetABP9Monolith
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	generator := NewIntegrationTestGenerator(loader, w)
+	if err := generator.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := generator.GenerateTestProject(sch, paths); err != nil {
+		t.Fatalf("GenerateTestProject() error = %v", err)
+	}
+
+	bases := map[string]string{
+		"CatalogTestBase.cs":                    "public abstract class CatalogTestBase : AbpIntegratedTest<CatalogTestModule>",
+		"CatalogEntityFrameworkCoreTestBase.cs": "public abstract class CatalogEntityFrameworkCoreTestBase : AbpIntegratedTest<CatalogEntityFrameworkCoreTestModule>",
+		"CatalogMongoDbTestBase.cs":             "public abstract class CatalogMongoDbTestBase : AbpIntegratedTest<CatalogMongoDbTestModule>",
+	}
+	for suffix, want := range bases {
+		if content := generatedContent(t, w, suffix); !strings.Contains(content, want) {
+			t.Errorf("%s missing %q:\n%s", suffix, want, content)
+		}
+	}
+
+	repositories := map[string][]string{
+		"Repositories/CatalogModule/EntityFrameworkCore/ProductRepositoryTests.cs": {
+			"namespace Acme.Shop.Tests.CatalogModule.Repositories.EntityFrameworkCore",
+			"public class ProductRepositoryTests : CatalogEntityFrameworkCoreTestBase",
+		},
+		"Repositories/CatalogModule/MongoDB/ProductRepositoryTests.cs": {
+			"namespace Acme.Shop.Tests.CatalogModule.Repositories.MongoDB",
+			"public class ProductRepositoryTests : CatalogMongoDbTestBase",
+		},
+	}
+	for suffix, wants := range repositories {
+		content := generatedContent(t, w, suffix)
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q:\n%s", suffix, want, content)
+			}
+		}
+	}
+
+	// Service tests keep running on the module's test base
+	if content := generatedContent(t, w, "ProductServiceTests.cs"); !strings.Contains(content, ": CatalogTestBase") {
+		t.Errorf("service tests no longer use the module test base:\n%s", content)
+	}
+
+	project := generatedContent(t, w, "Shop.Catalog.Tests.csproj")
+	for _, want := range []string{
+		`<PackageReference Include="Volo.Abp.MongoDB" Version="9.0.0" />`,
+		`Shop.Catalog.MongoDB.csproj" />`,
+		`Shop.Catalog.EntityFrameworkCore.csproj" />`,
+	} {
+		if !strings.Contains(project, want) {
+			t.Errorf("test project missing %q\n%s", want, project)
+		}
+	}
+}
+
+func TestIntegrationTestGenerator_SetsRequiredMembers(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsRequired: true, MaxLength: 8},
+			{Name: "Price", Type: "decimal", IsRequired: true},
+			{Name: "Stock", Type: "int", IsRequired: true, DefaultValue: "0"},
+			{Name: "Notes", Type: "string"},
+		},
+	})
+	sch.Options.GenerateIntegrationTests = true
+	sch.Solution.TargetFramework = schema.TargetASPNETCore9
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewIntegrationTestGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "ProductServiceTests.cs")
+	for _, want := range []string{
+		"                Name = \"Test Nam\",\n                Price = 1m,\n                // Stock = /* set value */\n                // Notes = /* set value */",
+		"                Name = \"Test Nam\",\n                Price = 1m,\n                // Stock = /* set updated value */",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("service tests missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "// Name = ") || strings.Contains(content, "// Price = ") {
+		t.Errorf("required members left commented out:\n%s", content)
+	}
+}
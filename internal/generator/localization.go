@@ -24,97 +24,114 @@ func NewLocalizationGenerator(w *writer.Writer) *LocalizationGenerator {
 	}
 }
 
-// MergeLocalizationFile merges localization content into existing file
-func (g *LocalizationGenerator) MergeLocalizationFile(sch *schema.Schema, paths *detector.LayerPaths, culture string, newContent map[string]interface{}) error {
-	if sch.Options.LocalizationMerge == nil || !sch.Options.LocalizationMerge.Enabled {
-		return nil // Localization merge not enabled
+// Generate writes the module's localization entries to a single {culture}.json file per culture,
+// merging them into the texts of an existing file with the configured conflict strategy
+func (g *LocalizationGenerator) Generate(sch *schema.Schema, paths *detector.LayerPaths) error {
+	if !sch.Options.UseLocalization {
+		return nil
+	}
+
+	texts := g.buildModuleLocalizationContent(sch)
+
+	cultures := sch.Options.LocalizationCultures
+	if len(cultures) == 0 {
+		cultures = []string{"en"}
 	}
 
-	// Determine target path
-	targetPath := sch.Options.LocalizationMerge.TargetPath
-	if targetPath == "" {
-		targetPath = paths.DomainSharedLocalization
+	for _, culture := range cultures {
+		if err := g.MergeLocalizationFile(sch, paths, culture, texts); err != nil {
+			return fmt.Errorf("failed to merge localization for culture %s: %w", culture, err)
+		}
+	}
+
+	return nil
+}
+
+// MergeLocalizationFile merges localization texts into the culture's file under Localization/{Module}
+func (g *LocalizationGenerator) MergeLocalizationFile(sch *schema.Schema, paths *detector.LayerPaths, culture string, newTexts map[string]interface{}) error {
+	// Determine target path and strategy
+	targetPath := paths.DomainSharedLocalization
+	strategy := "append"
+	if lm := sch.Options.LocalizationMerge; lm != nil && lm.Enabled {
+		if lm.TargetPath != "" {
+			targetPath = lm.TargetPath
+		}
+		if lm.ConflictStrategy != "" {
+			strategy = lm.ConflictStrategy
+		}
 	}
 
 	// Build full file path
 	fileName := fmt.Sprintf("%s.json", culture)
 	filePath := filepath.Join(targetPath, fileName)
 
-	// Check if file exists
-	var existingContent map[string]interface{}
+	// Read the existing file, keeping its texts and any other top-level keys
+	existingContent := make(map[string]interface{})
 	if fileData, err := os.ReadFile(filePath); err == nil {
-		// File exists, parse it
 		if err := json.Unmarshal(fileData, &existingContent); err != nil {
 			return fmt.Errorf("failed to parse existing localization file: %w", err)
 		}
-	} else {
-		// File doesn't exist, start fresh
-		existingContent = make(map[string]interface{})
 	}
 
-	// Determine merge strategy
-	strategy := sch.Options.LocalizationMerge.ConflictStrategy
-	if strategy == "" {
-		strategy = "append"
+	existingTexts, _ := existingContent["texts"].(map[string]interface{})
+	if existingTexts == nil {
+		existingTexts = make(map[string]interface{})
 	}
 
-	// Create JSON merger with strategy
-	jsonMerger := merger.NewJSONMergerWithStrategy(strategy)
-
 	// Convert to JSON strings for merger
-	existingJSON, err := json.MarshalIndent(existingContent, "", "  ")
+	existingJSON, err := json.Marshal(existingTexts)
 	if err != nil {
-		return fmt.Errorf("failed to marshal existing content: %w", err)
+		return fmt.Errorf("failed to marshal existing texts: %w", err)
 	}
 
-	newJSON, err := json.MarshalIndent(newContent, "", "  ")
+	newJSON, err := json.Marshal(newTexts)
 	if err != nil {
-		return fmt.Errorf("failed to marshal new content: %w", err)
+		return fmt.Errorf("failed to marshal new texts: %w", err)
 	}
 
 	// Perform merge
+	jsonMerger := merger.NewJSONMergerWithStrategy(strategy)
 	mergedJSON, conflicts, err := jsonMerger.Merge(string(existingJSON), string(newJSON))
 	if err != nil {
 		return fmt.Errorf("failed to merge localization files: %w", err)
 	}
 
-	// Handle conflicts based on strategy
-	if len(conflicts) > 0 {
-		switch strategy {
-		case "overwrite":
-			// Conflicts are already resolved by overwriting
-		case "skip":
-			// Keep existing, no change needed
-			return nil
-		default:
-			// For append, log conflicts but continue
-			for _, conflict := range conflicts {
-				fmt.Printf("Localization conflict: %s\n", conflict.Description)
-			}
-		}
+	// Conflicts are only reported for append, which keeps the existing values
+	for _, conflict := range conflicts {
+		fmt.Fprintf(g.writer.Output(), "Localization conflict in %s: %s\n", fileName, conflict.Description)
+	}
+
+	var mergedTexts map[string]interface{}
+	if err := json.Unmarshal([]byte(mergedJSON), &mergedTexts); err != nil {
+		return fmt.Errorf("failed to parse merged texts: %w", err)
+	}
+
+	existingContent["culture"] = culture
+	existingContent["texts"] = mergedTexts
+
+	content, err := json.MarshalIndent(existingContent, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal localization file: %w", err)
 	}
 
 	// Write merged content
-	return g.writer.WriteFile(filePath, mergedJSON)
+	return g.writer.WriteFile(filePath, string(content)+"\n")
 }
 
-// GenerateEntityLocalization generates localization entries for an entity
-func (g *LocalizationGenerator) GenerateEntityLocalization(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if !sch.Options.UseLocalization {
-		return nil
-	}
+// buildModuleLocalizationContent collects the localization texts of every entity in the module
+func (g *LocalizationGenerator) buildModuleLocalizationContent(sch *schema.Schema) map[string]interface{} {
+	content := make(map[string]interface{})
 
-	// Build localization content
-	locContent := g.buildEntityLocalizationContent(sch, entity)
+	// Permission group display name
+	content[fmt.Sprintf("Permission:%s", sch.Solution.ModuleName)] = sch.Solution.ModuleName
 
-	// Merge for each culture
-	for _, culture := range sch.Options.LocalizationCultures {
-		if err := g.MergeLocalizationFile(sch, paths, culture, locContent); err != nil {
-			return fmt.Errorf("failed to merge localization for culture %s: %w", culture, err)
+	for i := range sch.Entities {
+		for key, value := range g.buildEntityLocalizationContent(sch, &sch.Entities[i]) {
+			content[key] = value
 		}
 	}
 
-	return nil
+	return content
 }
 
 func (g *LocalizationGenerator) buildEntityLocalizationContent(sch *schema.Schema, entity *schema.Entity) map[string]interface{} {
@@ -127,14 +144,33 @@ func (g *LocalizationGenerator) buildEntityLocalizationContent(sch *schema.Schem
 	for _, prop := range entity.Properties {
 		key := fmt.Sprintf("%s.%s", entity.Name, prop.Name)
 		content[key] = prop.Name
+		if prop.DisplayName != "" {
+			content[key] = prop.DisplayName
+		}
+	}
+
+	// Add permissions, matching the keys used by the permission definition provider
+	if entity.EntityType != "ValueObject" && entity.RequiresAuthorization() {
+		permissionBase := fmt.Sprintf("Permission:%s", entity.Name)
+		content[permissionBase] = entity.Name
+		content[permissionBase+".Create"] = fmt.Sprintf("Create %s", entity.Name)
+		content[permissionBase+".Update"] = fmt.Sprintf("Edit %s", entity.Name)
+		content[permissionBase+".Delete"] = fmt.Sprintf("Delete %s", entity.Name)
 	}
 
-	// Add permissions
-	permissionBase := fmt.Sprintf("Permission:%s", entity.Name)
-	content[permissionBase] = entity.Name
-	content[permissionBase+".Create"] = fmt.Sprintf("Create %s", entity.Name)
-	content[permissionBase+".Edit"] = fmt.Sprintf("Edit %s", entity.Name)
-	content[permissionBase+".Delete"] = fmt.Sprintf("Delete %s", entity.Name)
+	// Add delete guard error messages
+	if sch.Options.GenerateDeleteGuards {
+		for _, guard := range BuildDeleteGuards(sch, entity) {
+			content[guard.ErrorCode] = fmt.Sprintf("Cannot delete this %s because it has related %s.", entity.Name, guard.TargetEntityPlural)
+		}
+	}
+
+	// Add the errors of the Add{Item} methods of collection navigations
+	if entity.IsAggregateRoot() {
+		for _, nav := range getCollectionNavigations(sch, entity) {
+			content[nav.DuplicateErrorCode] = fmt.Sprintf("This %s already contains the %s.", entity.Name, nav.ItemName)
+		}
+	}
 
 	// Add enum localizations
 	for _, enum := range entity.Enums {
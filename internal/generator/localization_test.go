@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestLocalizationGenerator_MergesIntoCultureFile(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+		schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string", DisplayName: "Category title"}}},
+	)
+	sch.Options.UseLocalization = true
+	sch.Options.LocalizationCultures = []string{"en"}
+	paths := newTestLayerPaths(t)
+
+	filePath := filepath.Join(paths.DomainSharedLocalization, "en.json")
+	if err := os.MkdirAll(paths.DomainSharedLocalization, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{"culture": "en", "texts": {"Menu:Home": "Home", "Product": "Item"}}`
+	if err := os.WriteFile(filePath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewLocalizationGenerator(writer.NewWriter(false, true, false)).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read localization file: %v", err)
+	}
+	var file struct {
+		Culture string            `json:"culture"`
+		Texts   map[string]string `json:"texts"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("localization file is not valid JSON: %v\n%s", err, data)
+	}
+
+	if file.Culture != "en" {
+		t.Errorf("culture = %q, want en", file.Culture)
+	}
+	want := map[string]string{
+		"Menu:Home":                 "Home",
+		"Product":                   "Item", // append keeps existing values
+		"Product.Name":              "Name",
+		"Category":                  "Category",
+		"Category.Title":            "Category title",
+		"Permission:Catalog":        "Catalog",
+		"Permission:Product.Update": "Edit Product",
+		"Permission:Category":       "Category",
+	}
+	for key, value := range want {
+		if file.Texts[key] != value {
+			t.Errorf("texts[%q] = %q, want %q", key, file.Texts[key], value)
+		}
+	}
+}
+
+func TestLocalizationGenerator_OverwriteStrategy(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	sch.Options.UseLocalization = true
+	sch.Options.LocalizationCultures = []string{"en"}
+	sch.Options.LocalizationMerge = &schema.LocalizationMerge{Enabled: true, ConflictStrategy: "overwrite"}
+	paths := newTestLayerPaths(t)
+
+	filePath := filepath.Join(paths.DomainSharedLocalization, "en.json")
+	if err := os.MkdirAll(paths.DomainSharedLocalization, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, []byte(`{"culture": "en", "texts": {"Menu:Home": "Home", "Product": "Item"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewLocalizationGenerator(writer.NewWriter(false, true, false)).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read localization file: %v", err)
+	}
+	var file struct {
+		Texts map[string]string `json:"texts"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("localization file is not valid JSON: %v", err)
+	}
+	if file.Texts["Product"] != "Product" || file.Texts["Menu:Home"] != "Home" {
+		t.Errorf("texts = %v, want Product overwritten and Menu:Home kept", file.Texts)
+	}
+}
+
+func TestLocalizationGenerator_DuplicateChildErrors(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{
+			Name:       "Order",
+			EntityType: "FullAuditedAggregateRoot",
+			Properties: []schema.Property{{Name: "Number", Type: "string"}},
+			Relations:  &schema.Relations{OneToMany: []schema.OneToManyRelation{{TargetEntity: "OrderLine"}}},
+		},
+		schema.Entity{Name: "OrderLine", EntityType: "Entity", Properties: []schema.Property{{Name: "Quantity", Type: "int"}}},
+	)
+
+	texts := NewLocalizationGenerator(writer.NewWriter(true, false, false)).buildModuleLocalizationContent(sch)
+	if got := texts["Acme.Shop:OrderDuplicateOrderLine"]; got != "This Order already contains the OrderLine." {
+		t.Errorf("texts[Acme.Shop:OrderDuplicateOrderLine] = %v, want the duplicate child message", got)
+	}
+
+	loader, w := newTestGenerators()
+	if err := NewEntityGenerator(loader, w).Generate(sch, &sch.Entities[0], newTestLayerPaths(t)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	entity := generatedContent(t, w, "Entities/CatalogModule/Order.cs")
+	if !strings.Contains(entity, `throw new BusinessException("Acme.Shop:OrderDuplicateOrderLine")`) {
+		t.Errorf("entity does not throw the localized error code:\n%s", entity)
+	}
+}
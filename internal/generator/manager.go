@@ -27,8 +27,8 @@ func NewManagerGenerator(tmplLoader *templates.Loader, w *writer.Writer) *Manage
 
 // Generate generates the domain manager
 func (g *ManagerGenerator) Generate(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if entity.EntityType == "ValueObject" {
-		return nil // Value objects don't have managers
+	if !entity.ShouldGenerateManager(sch.Options.GenerateDeleteGuards) {
+		return nil // Value objects and opted-out entities don't have managers
 	}
 
 	tmpl, err := g.tmplLoader.Load("manager.tmpl")
@@ -38,6 +38,11 @@ func (g *ManagerGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 
 	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
 
+	var guards []DeleteGuard
+	if sch.Options.GenerateDeleteGuards {
+		guards = BuildDeleteGuards(sch, entity)
+	}
+
 	data := map[string]interface{}{
 		"SolutionName":            sch.Solution.Name,
 		"ModuleName":              sch.Solution.ModuleName,
@@ -47,7 +52,11 @@ func (g *ManagerGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 		"PrimaryKeyType":          primaryKeyType,
 		"Properties":              entity.Properties,
 		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
+		"InputProperties":         sch.GetConstructorProperties(entity),
 		"HasRelations":            entity.HasRelations(),
+		"HasStronglyTypedId":      entity.HasStronglyTypedId(),
+		"DeleteGuards":            guards,
+		"GuardRepositories":       guardRepositories(guards),
 	}
 
 	var buf bytes.Buffer
@@ -60,3 +69,52 @@ func (g *ManagerGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 	managerPath := filepath.Join(paths.Domain, "Managers", moduleFolder, entity.Name+"Manager.cs")
 	return g.writer.WriteFile(managerPath, buf.String())
 }
+
+// DeleteGuard describes a check that blocks deleting an entity while dependent children exist
+type DeleteGuard struct {
+	TargetEntity       string // Child entity name
+	TargetEntityPlural string // Pluralized child entity name used in messages
+	ForeignKeyName     string // Foreign key on the child pointing to the parent
+	RepositoryField    string // Manager field holding the child repository
+	ErrorCode          string // Business exception code thrown by the guard
+}
+
+// BuildDeleteGuards returns guards for the entity's one-to-many relations that don't cascade on delete
+func BuildDeleteGuards(sch *schema.Schema, entity *schema.Entity) []DeleteGuard {
+	var guards []DeleteGuard
+	for _, rel := range entity.GetRestrictedOneToManyRelations() {
+		foreignKey := rel.ForeignKeyName
+		if foreignKey == "" {
+			foreignKey = entity.Name + "Id"
+		}
+		plural := templates.Pluralize(rel.TargetEntity)
+
+		// Self-referencing children are queried through the entity's own repository
+		repositoryField := "_repository"
+		if rel.TargetEntity != entity.Name {
+			repositoryField = "_" + templates.LowerFirst(rel.TargetEntity) + "Repository"
+		}
+
+		guards = append(guards, DeleteGuard{
+			TargetEntity:       rel.TargetEntity,
+			TargetEntityPlural: plural,
+			ForeignKeyName:     foreignKey,
+			RepositoryField:    repositoryField,
+			ErrorCode:          fmt.Sprintf("%s:%sHasRelated%s", sch.Solution.NamespaceRoot, entity.Name, plural),
+		})
+	}
+	return guards
+}
+
+// guardRepositories returns the distinct child repositories the manager must inject for its guards
+func guardRepositories(guards []DeleteGuard) []DeleteGuard {
+	seen := map[string]bool{"_repository": true}
+	var repositories []DeleteGuard
+	for _, guard := range guards {
+		if !seen[guard.RepositoryField] {
+			seen[guard.RepositoryField] = true
+			repositories = append(repositories, guard)
+		}
+	}
+	return repositories
+}
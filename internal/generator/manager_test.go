@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestManagerGenerator_RestrictDeleteGuard(t *testing.T) {
+	category := schema.Entity{
+		Name:       "Category",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToMany: []schema.OneToManyRelation{
+				{TargetEntity: "Product", ForeignKeyName: "CategoryId", CascadeDelete: false},
+			},
+		},
+	}
+	product := schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "CategoryId", Type: "Guid", IsForeignKey: true, TargetEntity: "Category"},
+		},
+	}
+	sch := newTestSchema(t, category, product)
+	sch.Options.GenerateDeleteGuards = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewManagerGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Managers/CatalogModule/CategoryManager.cs")
+	deletePath := content[strings.Index(content, "public async Task DeleteAsync"):]
+
+	expected := []string{
+		"// Restrict delete: Category has related Products",
+		"if (await _productRepository.AnyAsync(x => x.CategoryId == entity.Id))",
+		`throw new BusinessException("Acme.Shop:CategoryHasRelatedProducts")`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(deletePath, want) {
+			t.Errorf("delete path missing %q:\n%s", want, deletePath)
+		}
+	}
+	if !strings.Contains(content, "IProductRepository productRepository,") {
+		t.Errorf("manager does not inject the child repository:\n%s", content)
+	}
+	if strings.Index(deletePath, "CategoryHasRelatedProducts") > strings.Index(deletePath, "await _repository.DeleteAsync(entity)") {
+		t.Errorf("guard must run before the entity is deleted:\n%s", deletePath)
+	}
+}
+
+func TestManagerGenerator_NoDeleteGuardWhenDisabled(t *testing.T) {
+	category := schema.Entity{
+		Name:       "Category",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToMany: []schema.OneToManyRelation{{TargetEntity: "Product"}},
+		},
+	}
+	sch := newTestSchema(t, category)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewManagerGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Managers/CatalogModule/CategoryManager.cs")
+	if strings.Contains(content, "HasRelatedProducts") || strings.Contains(content, "IProductRepository") {
+		t.Errorf("delete guard generated without GenerateDeleteGuards:\n%s", content)
+	}
+}
+
+func TestManagerGenerator_SkipsEntitiesWithoutManager(t *testing.T) {
+	country := schema.Entity{
+		Name:       "Country",
+		EntityType: "Entity",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	}
+	sch := newTestSchema(t, country)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewManagerGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(w.Operations) != 0 {
+		t.Fatalf("manager generated for a plain entity: %s", w.Operations[0].Path)
+	}
+
+	if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content := generatedContent(t, w, "CountryAppService.cs")
+	for _, want := range []string{
+		"var entity = new Country(",
+		"entity.Update(input.Name);",
+		"await Repository.DeleteAsync(entity, autoSave: true);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("app service missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "CountryManager") || strings.Contains(content, "Domain.Managers") {
+		t.Errorf("app service depends on a manager that is not generated:\n%s", content)
+	}
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// MappingModuleGenerator generates the module-level mapping aggregator and registers it in the Application module
+type MappingModuleGenerator struct {
+	tmplLoader *templates.Loader
+	writer     *writer.Writer
+}
+
+// NewMappingModuleGenerator creates a new mapping module generator
+func NewMappingModuleGenerator(tmplLoader *templates.Loader, w *writer.Writer) *MappingModuleGenerator {
+	return &MappingModuleGenerator{
+		tmplLoader: tmplLoader,
+		writer:     w,
+	}
+}
+
+// Generate generates the aggregator for the module's entity maps. It is module-scoped and runs once per generation.
+func (g *MappingModuleGenerator) Generate(sch *schema.Schema, paths *detector.LayerPaths) error {
+	if len(sch.Entities) == 0 {
+		return nil
+	}
+
+	if sch.Options.MappingLibrary == "mapperly" {
+		if err := g.generateAggregator(sch, "module_mapperly_mappers.tmpl", g.mapperlyPath(sch, paths)); err != nil {
+			return err
+		}
+	} else {
+		if err := g.generateAggregator(sch, "module_automapper_profile.tmpl", g.autoMapperPath(sch, paths)); err != nil {
+			return err
+		}
+	}
+
+	return g.registerMappings(sch, paths)
+}
+
+// autoMapperPath returns the path of the module AutoMapper profile
+func (g *MappingModuleGenerator) autoMapperPath(sch *schema.Schema, paths *detector.LayerPaths) string {
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	return filepath.Join(paths.ApplicationAutoMapper, moduleFolder, sch.Solution.ModuleName+"ApplicationAutoMapperProfile.cs")
+}
+
+// mapperlyPath returns the path of the module Mapperly registration class
+func (g *MappingModuleGenerator) mapperlyPath(sch *schema.Schema, paths *detector.LayerPaths) string {
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	return filepath.Join(paths.Application, "Mapperly", moduleFolder, sch.Solution.ModuleName+"ApplicationMappers.cs")
+}
+
+// generateAggregator renders an aggregator template listing every entity of the module
+func (g *MappingModuleGenerator) generateAggregator(sch *schema.Schema, templateName, outputPath string) error {
+	tmpl, err := g.tmplLoader.Load(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to load mapping module template: %w", err)
+	}
+
+	entityNames := make([]string, 0, len(sch.Entities))
+	for _, entity := range sch.Entities {
+		entityNames = append(entityNames, entity.Name)
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityNames":          entityNames,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute mapping module template: %w", err)
+	}
+
+	return g.writer.WriteFile(outputPath, buf.String())
+}
+
+// registerMappings adds the object mapper registration to the Application module's ConfigureServices idempotently
+func (g *MappingModuleGenerator) registerMappings(sch *schema.Schema, paths *detector.LayerPaths) error {
+	modulePath := paths.GetApplicationModulePath(sch.Solution.ModuleName)
+	if modulePath == "" {
+		return nil
+	}
+
+	// Registration is only possible in an existing module class
+	if _, err := os.Stat(modulePath); err != nil {
+		return nil
+	}
+
+	moduleClass := paths.GetApplicationModuleClass(sch.Solution.ModuleName)
+	moduleNamespace := sch.Solution.GetModuleNameWithSuffix()
+
+	var searchPattern string
+	var statements, usings []string
+	if sch.Options.MappingLibrary == "mapperly" {
+		searchPattern = fmt.Sprintf("Add%sMappers()", sch.Solution.ModuleName)
+		statements = []string{
+			fmt.Sprintf("context.Services.AddMapperlyObjectMapper<%s>();", moduleClass),
+			fmt.Sprintf("context.Services.Add%sMappers();", sch.Solution.ModuleName),
+		}
+		usings = []string{
+			"Volo.Abp.Mapperly",
+			fmt.Sprintf("%s.Application.Mapperly.%s", sch.Solution.NamespaceRoot, moduleNamespace),
+		}
+	} else {
+		searchPattern = fmt.Sprintf("AddMaps<%s>", moduleClass)
+		statements = []string{
+			fmt.Sprintf("context.Services.AddAutoMapperObjectMapper<%s>();", moduleClass),
+			"Configure<AbpAutoMapperOptions>(options =>",
+			"{",
+			// Not validated: the generated profiles leave keys, audit members and navigations unmapped
+			fmt.Sprintf("    options.AddMaps<%s>();", moduleClass),
+			"});",
+		}
+		usings = []string{"Volo.Abp.AutoMapper"}
+	}
+
+	return g.writer.UpdateFileIdempotent(modulePath, searchPattern, func(content string) (string, error) {
+		updated, err := addConfigureServicesStatements(content, statements)
+		if err != nil {
+			return "", err
+		}
+		updated = addUsing(updated, "Volo.Abp.Modularity")
+		for _, namespace := range usings {
+			updated = addUsing(updated, namespace)
+		}
+		return updated, nil
+	}, nil)
+}
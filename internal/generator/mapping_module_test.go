@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+const testApplicationModule = `using Volo.Abp.Modularity;
+
+namespace Acme.Shop.Application
+{
+    [DependsOn(typeof(CatalogDomainModule))]
+    public class CatalogApplicationModule : AbpModule
+    {
+    }
+}
+`
+
+func TestMappingModuleGenerator_AutoMapperRegistration(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+		schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string"}}},
+	)
+	sch.Options.MappingLibrary = "automapper"
+	paths := newTestLayerPaths(t)
+
+	// Standard solutions name the Application module after the solution, not the schema's module
+	paths.ApplicationModule = filepath.Join(paths.Application, "ShopApplicationModule.cs")
+	paths.ApplicationModuleClass = "ShopApplicationModule"
+	modulePath := paths.ApplicationModule
+	if err := os.MkdirAll(paths.Application, 0755); err != nil {
+		t.Fatal(err)
+	}
+	module := strings.Replace(testApplicationModule, "CatalogApplicationModule", "ShopApplicationModule", 1)
+	if err := os.WriteFile(modulePath, []byte(module), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewMappingModuleGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+	for i := 0; i < 2; i++ {
+		if err := gen.Generate(sch, paths); err != nil {
+			t.Fatalf("Generate() run %d error = %v", i+1, err)
+		}
+	}
+
+	updated, err := os.ReadFile(modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	module = string(updated)
+	if got := strings.Count(module, "options.AddMaps<ShopApplicationModule>();"); got != 1 {
+		t.Errorf("AddMaps registered %d times; want 1:\n%s", got, module)
+	}
+	for _, want := range []string{
+		"public override void ConfigureServices(ServiceConfigurationContext context)",
+		"context.Services.AddAutoMapperObjectMapper<ShopApplicationModule>();",
+		"using Volo.Abp.AutoMapper;",
+	} {
+		if !strings.Contains(module, want) {
+			t.Errorf("module missing %q:\n%s", want, module)
+		}
+	}
+
+	profile, err := os.ReadFile(filepath.Join(paths.ApplicationAutoMapper, "CatalogModule", "CatalogApplicationAutoMapperProfile.cs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"public class CatalogApplicationAutoMapperProfile : Profile",
+		`<see cref="ProductProfile"/>`,
+		`<see cref="CategoryProfile"/>`,
+	} {
+		if !strings.Contains(string(profile), want) {
+			t.Errorf("profile missing %q:\n%s", want, profile)
+		}
+	}
+}
+
+func TestMappingModuleGenerator_MapperlyRegistration(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Options.MappingLibrary = "mapperly"
+	paths := newTestLayerPaths(t)
+
+	modulePath := filepath.Join(paths.Application, "CatalogApplicationModule.cs")
+	if err := os.MkdirAll(paths.Application, 0755); err != nil {
+		t.Fatal(err)
+	}
+	module := strings.Replace(testApplicationModule, "    {\n    }", `    {
+        public override void ConfigureServices(ServiceConfigurationContext context)
+        {
+            Configure<AbpDistributedCacheOptions>(options => { });
+        }
+    }`, 1)
+	if err := os.WriteFile(modulePath, []byte(module), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewMappingModuleGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false)).Generate(sch, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	updated, err := os.ReadFile(modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(updated)
+	if got := strings.Count(content, "ConfigureServices(ServiceConfigurationContext context)"); got != 1 {
+		t.Errorf("ConfigureServices declared %d times; want 1:\n%s", got, content)
+	}
+	for _, want := range []string{
+		"context.Services.AddMapperlyObjectMapper<CatalogApplicationModule>();",
+		"context.Services.AddCatalogMappers();",
+		"using Acme.Shop.Application.Mapperly.CatalogModule;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("module missing %q:\n%s", want, content)
+		}
+	}
+
+	mappers, err := os.ReadFile(filepath.Join(paths.Application, "Mapperly", "CatalogModule", "CatalogApplicationMappers.cs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mappers), "services.AddSingleton<ProductMapper>();") {
+		t.Errorf("mappers missing ProductMapper registration:\n%s", mappers)
+	}
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fileScopedNamespacePattern matches a C# 10 file-scoped namespace declaration
+var fileScopedNamespacePattern = regexp.MustCompile(`(?m)^namespace\s+[\w.]+\s*;`)
+
+// appendModuleMethod inserts a method before the module class closing brace.
+// Lines are indented relative to the class body.
+func appendModuleMethod(content string, methodLines []string) (string, error) {
+	pattern := regexp.MustCompile(`(\s*)(}\s*}\s*$)`)
+	indent := "        "
+	closing := "\n    $2"
+	if fileScopedNamespacePattern.MatchString(content) {
+		pattern = regexp.MustCompile(`(\s*)(}\s*$)`)
+		indent = "    "
+		closing = "\n$2"
+	}
+	match := pattern.FindStringSubmatchIndex(content)
+	if match == nil {
+		return "", fmt.Errorf("module class closing brace not found")
+	}
+
+	var lines []string
+	// Separate the method from existing members with a blank line
+	if !strings.HasSuffix(content[:match[2]], "{") {
+		lines = append(lines, "")
+	}
+	for _, line := range methodLines {
+		lines = append(lines, indent+line)
+	}
+
+	method := "\n" + strings.Join(lines, "\n")
+	return content[:match[2]] + pattern.ReplaceAllString(content[match[2]:], method+closing), nil
+}
+
+// matchingBrace returns the index of the brace closing the one at openBrace, or -1 when it is unbalanced
+func matchingBrace(content string, openBrace int) int {
+	depth := 0
+	for i := openBrace; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// configureServicesPattern matches the opening of a ConfigureServices or ConfigureServicesAsync override
+var configureServicesPattern = regexp.MustCompile(`(ConfigureServices(Async)?\(\s*ServiceConfigurationContext\s+context\s*\)\s*\{)`)
+
+// addConfigureServicesStatements inserts statements at the start of the module's ConfigureServices,
+// adding the override when the module does not declare one
+func addConfigureServicesStatements(content string, statements []string) (string, error) {
+	if configureServicesPattern.MatchString(content) {
+		body := ""
+		for _, statement := range statements {
+			body += "\n            " + statement
+		}
+		loc := configureServicesPattern.FindStringIndex(content)
+		return content[:loc[1]] + body + content[loc[1]:], nil
+	}
+
+	lines := []string{
+		"public override void ConfigureServices(ServiceConfigurationContext context)",
+		"{",
+	}
+	for _, statement := range statements {
+		lines = append(lines, "    "+statement)
+	}
+	lines = append(lines, "}")
+	return appendModuleMethod(content, lines)
+}
+
+// addUsing adds a using directive after the last existing one unless it is already present
+func addUsing(content, namespace string) string {
+	directive := "using " + namespace + ";"
+	if regexp.MustCompile(`(?m)^using\s+` + regexp.QuoteMeta(namespace) + `\s*;`).MatchString(content) {
+		return content
+	}
+
+	usings := regexp.MustCompile(`(?m)^using\s+[\w.]+\s*;[^\n]*$`).FindAllStringIndex(content, -1)
+	if len(usings) == 0 {
+		return directive + "\n" + content
+	}
+
+	end := usings[len(usings)-1][1]
+	return content[:end] + "\n" + directive + content[end:]
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -31,7 +32,11 @@ func (g *MongoDBGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 		return nil
 	}
 
-	return g.GenerateRepository(sch, entity, paths)
+	if err := g.GenerateRepository(sch, entity, paths); err != nil {
+		return err
+	}
+
+	return g.GenerateConfiguration(sch, entity, paths)
 }
 
 // GenerateRepository generates MongoDB repository implementation
@@ -50,6 +55,7 @@ func (g *MongoDBGenerator) GenerateRepository(sch *schema.Schema, entity *schema
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"PrimaryKeyType":       primaryKeyType,
+		"HasCustomRepository":  entity.HasCustomRepository(),
 	}
 
 	var buf bytes.Buffer
@@ -75,7 +81,10 @@ func (g *MongoDBGenerator) GenerateConfiguration(sch *schema.Schema, entity *sch
 		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
+		"PrimaryKeyType":       entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType),
 		"TableName":            entity.TableName,
+		"Indexes":              entity.Indexes,
+		"EmbeddedDocuments":    getEmbeddedDocuments(entity),
 	}
 
 	var buf bytes.Buffer
@@ -89,3 +98,33 @@ func (g *MongoDBGenerator) GenerateConfiguration(sch *schema.Schema, entity *sch
 	configPath := filepath.Join(paths.MongoDB, "MongoDB", moduleFolder, entity.Name+"MongoDbConfiguration.cs")
 	return g.writer.WriteFile(configPath, buf.String())
 }
+
+// EmbeddedDocument is a value object stored as a nested BSON document inside its owner
+type EmbeddedDocument struct {
+	Member string // Property or navigation holding the value object
+	Type   string // Value object class
+}
+
+// getEmbeddedDocuments returns the value-object properties and owned one-to-one relations
+// of the entity, which MongoDB embeds instead of referencing
+func getEmbeddedDocuments(entity *schema.Entity) []EmbeddedDocument {
+	var docs []EmbeddedDocument
+	for _, prop := range entity.Properties {
+		if prop.IsValueObject {
+			docs = append(docs, EmbeddedDocument{Member: prop.Name, Type: strings.TrimSuffix(prop.Type, "?")})
+		}
+	}
+	if entity.Relations != nil {
+		for _, rel := range entity.Relations.OneToOne {
+			if !rel.IsOwned {
+				continue
+			}
+			member := rel.NavigationProperty
+			if member == "" {
+				member = rel.TargetEntity
+			}
+			docs = append(docs, EmbeddedDocument{Member: member, Type: rel.TargetEntity})
+		}
+	}
+	return docs
+}
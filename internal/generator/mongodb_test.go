@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestMongoDBGenerator_Indexes(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Sku", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "CategoryId", Type: "Guid"},
+		},
+		Indexes: []schema.IndexDefinition{
+			{Properties: []string{"Sku"}, Unique: true},
+			{Properties: []string{"CategoryId", "Name"}},
+		},
+	})
+	sch.Solution.DBProvider = "mongodb"
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewMongoDBGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	config := generatedContent(t, w, "MongoDB/CatalogModule/ProductMongoDbConfiguration.cs")
+	for _, want := range []string{
+		"public static void CreateIndexes(IMongoCollection<Product> collection)",
+		"Builders<Product>.IndexKeys.Ascending(x => x.Sku),\n                new CreateIndexOptions { Name = \"IX_Product_Sku\", Unique = true }),",
+		"Builders<Product>.IndexKeys.Ascending(x => x.CategoryId).Ascending(x => x.Name),\n                new CreateIndexOptions { Name = \"IX_Product_CategoryId_Name\" }),",
+		"public class ProductMongoDbIndexDataSeedContributor : IDataSeedContributor, ITransientDependency",
+		"IRepository<Product, Guid> repository",
+		"ProductMongoDbConfiguration.CreateIndexes(await _repository.GetCollectionAsync());",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+}
+
+func TestMongoDBGenerator_EmbeddedValueObjects(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{
+			Name: "Order",
+			Properties: []schema.Property{
+				{Name: "Number", Type: "string"},
+				{Name: "BillingAddress", Type: "Address", IsValueObject: true},
+			},
+			Relations: &schema.Relations{
+				OneToOne: []schema.OneToOneRelation{
+					{TargetEntity: "Address", NavigationProperty: "ShippingAddress", IsOwned: true},
+				},
+			},
+		},
+		schema.Entity{
+			Name:       "Address",
+			EntityType: "ValueObject",
+			Properties: []schema.Property{{Name: "Street", Type: "string"}},
+		},
+	)
+	sch.Solution.DBProvider = "mongodb"
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewMongoDBGenerator(loader, w)
+	for i := range sch.Entities {
+		if err := gen.Generate(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("Generate(%s) error = %v", sch.Entities[i].Name, err)
+		}
+	}
+
+	config := generatedContent(t, w, "MongoDB/CatalogModule/OrderMongoDbConfiguration.cs")
+	if strings.Contains(config, "IDataSeedContributor") || strings.Contains(config, "using System.Threading.Tasks;") {
+		t.Errorf("configuration without indexes got an index seed contributor:\n%s", config)
+	}
+	for _, want := range []string{
+		"map.MapMember(x => x.BillingAddress);",
+		"map.MapMember(x => x.ShippingAddress);",
+		"if (!BsonClassMap.IsClassMapRegistered(typeof(Address)))",
+		"BsonClassMap.RegisterClassMap<Address>(map =>",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+
+	for _, op := range w.Manifest() {
+		if strings.HasSuffix(op.Path, "AddressMongoDbConfiguration.cs") {
+			t.Errorf("value object got its own collection configuration: %s", op.Path)
+		}
+	}
+}
+
+func TestGetEmbeddedDocuments_DefaultsNavigationToTarget(t *testing.T) {
+	entity := &schema.Entity{
+		Name: "Order",
+		Relations: &schema.Relations{
+			OneToOne: []schema.OneToOneRelation{{TargetEntity: "Address", IsOwned: true}},
+		},
+	}
+
+	docs := getEmbeddedDocuments(entity)
+	if len(docs) != 1 || docs[0].Member != "Address" || docs[0].Type != "Address" {
+		t.Errorf("getEmbeddedDocuments() = %+v, want the Address member", docs)
+	}
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
 )
 
 // MultiTenancyHelper provides utilities for multi-tenancy features
@@ -48,6 +49,20 @@ func (h *MultiTenancyHelper) GetTenantIdProperty(sch *schema.Schema) string {
 	return sch.Solution.MultiTenancy.TenantIdProperty
 }
 
+// GetTenantIdType returns the C# type of the tenant ID: Guid, long, or string
+func (h *MultiTenancyHelper) GetTenantIdType(sch *schema.Schema) string {
+	if !h.IsEnabled(sch) || sch.Solution.MultiTenancy.TenantIdType == "" {
+		return "Guid"
+	}
+	return sch.Solution.MultiTenancy.TenantIdType
+}
+
+// ImplementsIMultiTenant checks if entities can implement ABP's IMultiTenant,
+// which requires a Guid? property named TenantId
+func (h *MultiTenancyHelper) ImplementsIMultiTenant(sch *schema.Schema) bool {
+	return h.GetTenantIdType(sch) == "Guid" && h.GetTenantIdProperty(sch) == "TenantId"
+}
+
 // NeedsMultiTenancyAttribute checks if entity needs [MultiTenant] attribute
 func (h *MultiTenancyHelper) NeedsMultiTenancyAttribute(sch *schema.Schema, entity *schema.Entity) bool {
 	return h.ShouldAddTenantFilter(sch, entity)
@@ -81,12 +96,16 @@ func (h *MultiTenancyHelper) ShouldAllowCrossTenant(sch *schema.Schema) bool {
 // BuildMultiTenancyConfig builds configuration data for templates
 func (h *MultiTenancyHelper) BuildMultiTenancyConfig(sch *schema.Schema, entity *schema.Entity) map[string]interface{} {
 	return map[string]interface{}{
-		"Enabled":            h.IsEnabled(sch),
-		"Strategy":           h.GetStrategy(sch),
-		"TenantIdProperty":   h.GetTenantIdProperty(sch),
-		"NeedsFilter":        h.ShouldAddTenantFilter(sch, entity),
-		"NeedsAttribute":     h.NeedsMultiTenancyAttribute(sch, entity),
-		"ConnectionStrategy": h.GetConnectionStringStrategy(sch),
-		"AllowCrossTenant":   h.ShouldAllowCrossTenant(sch),
+		"Enabled":                h.IsEnabled(sch),
+		"Strategy":               h.GetStrategy(sch),
+		"TenantIdProperty":       h.GetTenantIdProperty(sch),
+		"TenantIdType":           templates.Nullable(h.GetTenantIdType(sch), true),
+		"IsStringTenantId":       h.GetTenantIdType(sch) == "string",
+		"ImplementsIMultiTenant": h.ImplementsIMultiTenant(sch),
+		"DeclaresTenantId":       entity.FindProperty(h.GetTenantIdProperty(sch)) == nil,
+		"NeedsFilter":            h.ShouldAddTenantFilter(sch, entity),
+		"NeedsAttribute":         h.NeedsMultiTenancyAttribute(sch, entity),
+		"ConnectionStrategy":     h.GetConnectionStringStrategy(sch),
+		"AllowCrossTenant":       h.ShouldAllowCrossTenant(sch),
 	}
 }
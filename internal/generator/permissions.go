@@ -3,6 +3,7 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
@@ -28,8 +29,8 @@ func NewPermissionsGenerator(tmplLoader *templates.Loader, w *writer.Writer) *Pe
 
 // Generate generates or updates permission files
 func (g *PermissionsGenerator) Generate(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if entity.EntityType == "ValueObject" {
-		return nil // Value objects don't have permissions
+	if entity.EntityType == "ValueObject" || !entity.RequiresAuthorization() {
+		return nil // Value objects and anonymous entities don't have permissions
 	}
 
 	// Update permissions file
@@ -37,10 +38,48 @@ func (g *PermissionsGenerator) Generate(sch *schema.Schema, entity *schema.Entit
 		return err
 	}
 
+	// Permission display names are localized with the module's resource
+	if err := g.ensureLocalizationResource(sch, paths); err != nil {
+		return err
+	}
+
 	// Update permission provider
 	return g.updatePermissionProvider(sch, entity, paths)
 }
 
+// ensureLocalizationResource creates the module's localization resource class unless it exists
+func (g *PermissionsGenerator) ensureLocalizationResource(sch *schema.Schema, paths *detector.LayerPaths) error {
+	resourceName := sch.Solution.GetLocalizationResourceName()
+	resourcePath := paths.GetLocalizationResourcePath(resourceName)
+	if resourcePath == "" {
+		return nil
+	}
+
+	createResource := func() (string, error) {
+		tmpl, err := g.tmplLoader.Load("localization_resource.tmpl")
+		if err != nil {
+			return "", fmt.Errorf("failed to load localization resource template: %w", err)
+		}
+
+		data := map[string]interface{}{
+			"NamespaceRoot": sch.Solution.NamespaceRoot,
+			"ModuleName":    sch.Solution.ModuleName,
+			"ResourceName":  resourceName,
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to execute localization resource template: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	// An existing resource file is left as it is
+	return g.writer.UpdateFileIdempotent(resourcePath, "class "+resourceName, func(content string) (string, error) {
+		return content, nil
+	}, createResource)
+}
+
 // updatePermissionsFile updates the permissions constants file
 func (g *PermissionsGenerator) updatePermissionsFile(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
 	moduleFolder := sch.Solution.GetModuleFolderName()
@@ -99,7 +138,7 @@ namespace %s.Application.Contracts.Permissions.%s
 	}
 
 	// Update file idempotently
-	return g.writer.UpdateFileIdempotent(permissionsPath, searchPattern, func(content string) (string, error) {
+	err = g.writer.UpdateFileIdempotent(permissionsPath, searchPattern, func(content string) (string, error) {
 		// Find GetAll() method and insert before it
 		getAllPattern := regexp.MustCompile(`(\s+)(public static string\[\] GetAll\(\))`)
 		if !getAllPattern.MatchString(content) {
@@ -109,6 +148,50 @@ namespace %s.Application.Contracts.Permissions.%s
 		updated := getAllPattern.ReplaceAllString(content, newPermissions+"$1$2")
 		return updated, nil
 	}, createInitialContent)
+	if err != nil {
+		return err
+	}
+
+	// Nothing more to do when the file was only previewed (dry-run) or skipped
+	if _, statErr := os.Stat(permissionsPath); statErr != nil {
+		return nil
+	}
+
+	// Add the entity's permissions to the GetAll() array idempotently
+	return g.writer.UpdateFileIdempotentMatch(permissionsPath, permissionConstantPattern(entity.Name, "Delete"), func(content string) (string, error) {
+		return appendGetAllPermissions(content, entity.Name)
+	}, nil)
+}
+
+// permissionConstantPattern matches a reference to one of an entity's permission constants, but
+// not to those of an entity whose name ends with it, such as SubCategory for Category
+func permissionConstantPattern(entityName, permission string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(entityName+"Management."+permission) + `\b`)
+}
+
+// getAllArrayPattern matches the array returned by the permissions GetAll() method
+var getAllArrayPattern = regexp.MustCompile(`(public static string\[\] GetAll\(\)\s*\{\s*return new\[\]\s*\{)([^}]*?)(\s*\};)`)
+
+// appendGetAllPermissions appends an entity's permission constants to the GetAll() array
+func appendGetAllPermissions(content string, entityName string) (string, error) {
+	match := getAllArrayPattern.FindStringSubmatchIndex(content)
+	if match == nil {
+		return "", fmt.Errorf("GetAll() return array not found in permissions file")
+	}
+
+	indent := "\n                "
+	var entries []string
+	for _, permission := range []string{"Default", "Create", "Update", "Delete"} {
+		entries = append(entries, fmt.Sprintf("%sManagement.%s", entityName, permission))
+	}
+	newEntries := indent + strings.Join(entries, ","+indent)
+
+	existing := strings.TrimRight(content[match[4]:match[5]], " \t\r\n")
+	if strings.TrimSpace(existing) != "" {
+		newEntries = strings.TrimSuffix(existing, ",") + "," + newEntries
+	}
+
+	return content[:match[4]] + newEntries + content[match[5]:], nil
 }
 
 // updatePermissionProvider updates the permission definition provider
@@ -117,7 +200,7 @@ func (g *PermissionsGenerator) updatePermissionProvider(sch *schema.Schema, enti
 	providerPath := paths.GetPermissionProviderPath(moduleFolder, sch.Solution.ModuleName)
 
 	// Check if entity permissions already exist
-	searchPattern := fmt.Sprintf("%sManagement.Default", entity.Name)
+	searchPattern := permissionConstantPattern(entity.Name, "Default")
 
 	// Template for new permission definitions
 	tmpl, err := g.tmplLoader.Load("permission_provider.tmpl")
@@ -128,11 +211,15 @@ func (g *PermissionsGenerator) updatePermissionProvider(sch *schema.Schema, enti
 	entityNameLower := strings.ToLower(entity.Name[:1]) + entity.Name[1:]
 	moduleNameLower := strings.ToLower(sch.Solution.ModuleName[:1]) + sch.Solution.ModuleName[1:]
 
+	resourceName := sch.Solution.GetLocalizationResourceName()
+	localizationNamespace := sch.Solution.NamespaceRoot + ".Localization"
+
 	data := map[string]interface{}{
 		"ModuleName":      sch.Solution.ModuleName,
 		"ModuleNameLower": moduleNameLower,
 		"EntityName":      entity.Name,
 		"EntityNameLower": entityNameLower,
+		"ResourceName":    resourceName,
 	}
 
 	var buf bytes.Buffer
@@ -153,7 +240,7 @@ func (g *PermissionsGenerator) updatePermissionProvider(sch *schema.Schema, enti
 		moduleNamespace := sch.Solution.GetModuleNameWithSuffix()
 		content := fmt.Sprintf(`using Volo.Abp.Authorization.Permissions;
 using Volo.Abp.Localization;
-using %s.Localization.%sModule;
+using %s;
 
 namespace %s.Application.Contracts.Permissions.%s
 {
@@ -162,17 +249,27 @@ namespace %s.Application.Contracts.Permissions.%s
         public override void Define(IPermissionDefinitionContext context)
         {
             var %sGroup = context.GetGroupOrNull(%sPermissions.GroupName)
-                ?? context.AddGroup(%sPermissions.GroupName, L("Permission:%s"));
+                ?? context.AddGroup(%sPermissions.GroupName, LocalizableString.Create<%s>("Permission:%s"));
 
 %s        }
     }
 }
-`, namespaceRoot, moduleName, namespaceRoot, moduleNamespace, moduleName, moduleNameLower, moduleName, moduleName, moduleName, newDefinitions)
+`, localizationNamespace, namespaceRoot, moduleNamespace, moduleName, moduleNameLower, moduleName, moduleName, resourceName, moduleName, newDefinitions)
 		return content, nil
 	}
 
+	// Providers generated before the resource was referenced name their permissions with an
+	// L() helper they never declared; migrate them even when the entity is already defined
+	if existing, err := os.ReadFile(providerPath); err == nil && legacyPermissionNamePattern.Match(existing) {
+		if err := g.writer.UpdateFile(providerPath, func(content string) (string, error) {
+			return migrateLegacyPermissionNames(content, resourceName, localizationNamespace), nil
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Update file idempotently
-	return g.writer.UpdateFileIdempotent(providerPath, searchPattern, func(content string) (string, error) {
+	return g.writer.UpdateFileIdempotentMatch(providerPath, searchPattern, func(content string) (string, error) {
 		// Find the closing braces of the Define method and insert before them
 		// Pattern: } (end of method) } (end of class) } (end of namespace)
 		pattern := regexp.MustCompile(`(\s+)(}\s+}\s+}\s*$)`)
@@ -181,38 +278,17 @@ namespace %s.Application.Contracts.Permissions.%s
 		}
 
 		updated := pattern.ReplaceAllString(content, newDefinitions+"$1$2")
-		return updated, nil
+		return migrateLegacyPermissionNames(updated, resourceName, localizationNamespace), nil
 	}, createInitialContent)
 }
 
-// GenerateLocalization generates or updates localization files
-func (g *PermissionsGenerator) GenerateLocalization(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if !sch.Options.UseLocalization {
-		return nil
-	}
-
-	for _, culture := range sch.Options.LocalizationCultures {
-		if err := g.updateLocalizationFile(sch, entity, paths, culture); err != nil {
-			return fmt.Errorf("failed to update %s localization: %w", culture, err)
-		}
-	}
-
-	return nil
-}
-
-// updateLocalizationFile updates a localization JSON file
-func (g *PermissionsGenerator) updateLocalizationFile(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths, culture string) error {
-	// This is a simplified version - in production, you'd want to properly parse and merge JSON
-	// For now, we'll just note that the file should be updated manually or with a JSON library
-
-	// The localization file updates would require proper JSON parsing
-	// which we'll handle in the template or with a JSON library
-
-	// Placeholder for now - in full implementation, would:
-	// 1. Read existing JSON
-	// 2. Add new keys if not present
-	// 3. Sort keys
-	// 4. Write back
+// legacyPermissionNamePattern matches the L("Permission:...") display names of older providers
+var legacyPermissionNamePattern = regexp.MustCompile(`\bL\(("Permission:[^"]*")\)`)
 
-	return nil
+// migrateLegacyPermissionNames rewrites the L("Permission:...") display names of a provider to
+// LocalizableString.Create<resourceName>(...) and adds the usings they need
+func migrateLegacyPermissionNames(content, resourceName, localizationNamespace string) string {
+	content = legacyPermissionNamePattern.ReplaceAllString(content, "LocalizableString.Create<"+resourceName+">($1)")
+	content = addUsing(content, "Volo.Abp.Localization")
+	return addUsing(content, localizationNamespace)
 }
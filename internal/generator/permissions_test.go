@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestPermissionsGenerator_GetAllIncludesEveryEntity(t *testing.T) {
+	writers := map[string]func() *writer.Writer{
+		"default": func() *writer.Writer { return writer.NewWriter(false, false, false) },
+		"merge":   func() *writer.Writer { return writer.NewWriterWithMerge(false, false, false, true) },
+		"force":   func() *writer.Writer { return writer.NewWriter(false, true, false) },
+	}
+
+	for name, newWriter := range writers {
+		t.Run(name, func(t *testing.T) {
+			sch := newTestSchema(t,
+				schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+				schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+			)
+			paths := newTestLayerPaths(t)
+			gen := NewPermissionsGenerator(templates.NewLoader(""), newWriter())
+
+			// Generate twice to check the GetAll() update is idempotent
+			for run := 0; run < 2; run++ {
+				for i := range sch.Entities {
+					if err := gen.Generate(sch, &sch.Entities[i], paths); err != nil {
+						t.Fatalf("Generate(%s) error = %v", sch.Entities[i].Name, err)
+					}
+				}
+			}
+
+			data, err := os.ReadFile(paths.GetPermissionsFilePath(sch.Solution.GetModuleFolderName(), sch.Solution.ModuleName))
+			if err != nil {
+				t.Fatalf("failed to read permissions file: %v", err)
+			}
+			content := string(data)
+
+			getAll := regexp.MustCompile(`(?s)GetAll\(\).*?return new\[\]\s*\{(.*?)\};`).FindStringSubmatch(content)
+			if getAll == nil {
+				t.Fatalf("GetAll() array not found:\n%s", content)
+			}
+
+			entries := regexp.MustCompile(`\w+Management\.\w+`).FindAllString(getAll[1], -1)
+			if len(entries) != 8 {
+				t.Fatalf("GetAll() returns %d permissions; want 8: %v\n%s", len(entries), entries, content)
+			}
+			for _, want := range []string{"ProductManagement.Delete", "CategoryManagement.Default", "CategoryManagement.Delete"} {
+				found := false
+				for _, entry := range entries {
+					if entry == want {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("GetAll() missing %s: %v", want, entries)
+				}
+			}
+			if n := strings.Count(content, "public const string Default = GroupName + \".Category"); n > 1 {
+				t.Errorf("Category permissions declared %d times; want once", n)
+			}
+		})
+	}
+}
+
+func TestPermissionsGenerator_EntityNameSuffixOfAnother(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{Name: "SubCategory", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+		schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+	)
+	paths := newTestLayerPaths(t)
+	gen := NewPermissionsGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+
+	for i := range sch.Entities {
+		if err := gen.Generate(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("Generate(%s) error = %v", sch.Entities[i].Name, err)
+		}
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	permissions, err := os.ReadFile(paths.GetPermissionsFilePath(moduleFolder, sch.Solution.ModuleName))
+	if err != nil {
+		t.Fatalf("failed to read permissions file: %v", err)
+	}
+	if got := regexp.MustCompile(`\bCategoryManagement\.Delete\b`).FindAllString(string(permissions), -1); len(got) != 1 {
+		t.Errorf("CategoryManagement.Delete appears %d times in GetAll(); want 1:\n%s", len(got), permissions)
+	}
+
+	provider, err := os.ReadFile(paths.GetPermissionProviderPath(moduleFolder, sch.Solution.ModuleName))
+	if err != nil {
+		t.Fatalf("failed to read permission provider: %v", err)
+	}
+	if !regexp.MustCompile(`\bCategoryManagement\.Default\b`).Match(provider) {
+		t.Errorf("provider skipped Category because SubCategory was defined:\n%s", provider)
+	}
+}
+
+func TestPermissionsGenerator_ProviderUsesLocalizationResource(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	paths := newTestLayerPaths(t)
+	gen := NewPermissionsGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(paths.GetPermissionProviderPath(sch.Solution.GetModuleFolderName(), sch.Solution.ModuleName))
+	if err != nil {
+		t.Fatalf("failed to read permission provider: %v", err)
+	}
+	provider := string(data)
+	for _, want := range []string{
+		"using Acme.Shop.Localization;",
+		`context.AddGroup(CatalogPermissions.GroupName, LocalizableString.Create<CatalogResource>("Permission:Catalog"))`,
+		`LocalizableString.Create<CatalogResource>("Permission:Product.Delete")`,
+	} {
+		if !strings.Contains(provider, want) {
+			t.Errorf("permission provider missing %q:\n%s", want, provider)
+		}
+	}
+	if strings.Contains(provider, `L("`) {
+		t.Errorf("permission provider still uses the undefined L() helper:\n%s", provider)
+	}
+
+	resourcePath := filepath.Join(paths.DomainShared, "Localization", "CatalogResource.cs")
+	data, err = os.ReadFile(resourcePath)
+	if err != nil {
+		t.Fatalf("failed to read localization resource: %v", err)
+	}
+	for _, want := range []string{"namespace Acme.Shop.Localization", `[LocalizationResourceName("Catalog")]`, "public class CatalogResource"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("localization resource missing %q:\n%s", want, data)
+		}
+	}
+
+	// An existing resource class is kept
+	existing := "namespace Acme.Shop.Localization { public class CatalogResource { } }\n"
+	if err := os.WriteFile(resourcePath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sch.Entities = append(sch.Entities, schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	if err := gen.Generate(sch, &sch.Entities[1], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if data, _ := os.ReadFile(resourcePath); string(data) != existing {
+		t.Errorf("existing localization resource was rewritten:\n%s", data)
+	}
+}
+
+func TestPermissionsGenerator_MigratesLegacyPermissionNames(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+	paths := newTestLayerPaths(t)
+	gen := NewPermissionsGenerator(templates.NewLoader(""), writer.NewWriter(false, true, false))
+
+	providerPath := paths.GetPermissionProviderPath(sch.Solution.GetModuleFolderName(), sch.Solution.ModuleName)
+	legacy := `using Volo.Abp.Authorization.Permissions;
+
+namespace Acme.Shop.Application.Contracts.Permissions.CatalogModule
+{
+    public class CatalogPermissionDefinitionProvider : PermissionDefinitionProvider
+    {
+        public override void Define(IPermissionDefinitionContext context)
+        {
+            var catalogGroup = context.GetGroupOrNull(CatalogPermissions.GroupName)
+                ?? context.AddGroup(CatalogPermissions.GroupName, L("Permission:Catalog"));
+
+        var productPermission = catalogGroup.AddPermission(
+            CatalogPermissions.ProductManagement.Default, L("Permission:Product"));
+        productPermission.AddChild(CatalogPermissions.ProductManagement.Create, L("Permission:Product.Create"));
+        }
+    }
+}
+`
+	if err := os.MkdirAll(filepath.Dir(providerPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(providerPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(providerPath)
+	if err != nil {
+		t.Fatalf("failed to read permission provider: %v", err)
+	}
+	provider := string(data)
+	if strings.Contains(provider, `L("`) {
+		t.Errorf("legacy L() display names were kept:\n%s", provider)
+	}
+	for _, want := range []string{
+		"using Volo.Abp.Localization;",
+		"using Acme.Shop.Localization;",
+		`context.AddGroup(CatalogPermissions.GroupName, LocalizableString.Create<CatalogResource>("Permission:Catalog"))`,
+		`CatalogPermissions.ProductManagement.Create, LocalizableString.Create<CatalogResource>("Permission:Product.Create")`,
+	} {
+		if !strings.Contains(provider, want) {
+			t.Errorf("permission provider missing %q:\n%s", want, provider)
+		}
+	}
+	if n := strings.Count(provider, "var productPermission"); n != 1 {
+		t.Errorf("Product permissions defined %d times:\n%s", n, provider)
+	}
+}
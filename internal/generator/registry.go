@@ -0,0 +1,557 @@
+package generator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/detector"
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+// Scope describes how often a generator runs during a generation
+type Scope string
+
+const (
+	// ScopePerEntity generators run once for every entity in the schema
+	ScopePerEntity Scope = "per-entity"
+	// ScopePerModule generators run once per module
+	ScopePerModule Scope = "per-module"
+)
+
+// Generators holds the generator instances used by a generation run
+type Generators struct {
+	Entity           *EntityGenerator
+	ValueObject      *ValueObjectGenerator
+	Enum             *EnumGenerator
+	CustomRepository *CustomRepositoryGenerator
+	DomainEvents     *DomainEventsGenerator
+	Manager          *ManagerGenerator
+	DTO              *DTOGenerator
+	Validator        *ValidatorGenerator
+	Service          *ServiceGenerator
+	Permissions      *PermissionsGenerator
+	Localization     *LocalizationGenerator
+	EventHandler     *EventHandlerGenerator
+	EFCore           *EFCoreGenerator  // nil unless the schema uses EF Core
+	MongoDB          *MongoDBGenerator // nil unless the schema uses MongoDB
+	IntegrationTest  *IntegrationTestGenerator
+	BackgroundWorker *BackgroundWorkerGenerator
+	MappingModule    *MappingModuleGenerator
+	Grpc             *GrpcGenerator
+	AppSettings      *AppSettingsGenerator
+
+	selected map[string]bool // registration names to run; nil runs every generator
+}
+
+// NewGenerators creates the generators needed for the schema's database provider
+func NewGenerators(tmplLoader *templates.Loader, w *writer.Writer, sch *schema.Schema) *Generators {
+	g := &Generators{
+		Entity:           NewEntityGenerator(tmplLoader, w),
+		ValueObject:      NewValueObjectGenerator(tmplLoader, w),
+		Enum:             NewEnumGenerator(tmplLoader, w),
+		CustomRepository: NewCustomRepositoryGenerator(tmplLoader, w),
+		DomainEvents:     NewDomainEventsGenerator(tmplLoader, w),
+		Manager:          NewManagerGenerator(tmplLoader, w),
+		DTO:              NewDTOGenerator(tmplLoader, w),
+		Validator:        NewValidatorGenerator(tmplLoader, w),
+		Service:          NewServiceGenerator(tmplLoader, w),
+		Permissions:      NewPermissionsGenerator(tmplLoader, w),
+		Localization:     NewLocalizationGenerator(w),
+		EventHandler:     NewEventHandlerGenerator(tmplLoader, w),
+		IntegrationTest:  NewIntegrationTestGenerator(tmplLoader, w),
+		BackgroundWorker: NewBackgroundWorkerGenerator(tmplLoader, w),
+		MappingModule:    NewMappingModuleGenerator(tmplLoader, w),
+		Grpc:             NewGrpcGenerator(tmplLoader, w),
+		AppSettings:      NewAppSettingsGenerator(w),
+	}
+
+	if sch.Solution.DBProvider == "efcore" || sch.Solution.DBProvider == "both" {
+		g.EFCore = NewEFCoreGenerator(tmplLoader, w)
+	}
+
+	if sch.Solution.DBProvider == "mongodb" || sch.Solution.DBProvider == "both" {
+		g.MongoDB = NewMongoDBGenerator(tmplLoader, w)
+	}
+
+	return g
+}
+
+// Registration describes a generator in the dispatch registry
+type Registration struct {
+	Name        string
+	Description string
+	Layers      []string // ABP layers the generator writes to
+	Outputs     []string // Files written, relative to their layer
+	Scope       Scope
+	Shared      bool // Updates files every entity contributes to, so it never runs concurrently
+	run         func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error
+}
+
+// Registry lists every generator in dispatch order. Generation runs exactly these entries.
+var Registry = []Registration{
+	{
+		Name:        "enum",
+		Description: "Enums declared on the entity, with lookup extensions, localization and DTOs",
+		Layers:      []string{"Domain.Shared", "Application.Contracts"},
+		Outputs:     []string{"Enums/{Module}/{Enum}.cs", "Enums/{Module}/{Enum}Extensions.cs", "Localization/{Module}/{Enum}_enums.json", "{Module}/{Entity}/{Enum}Dto.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Enum.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate enums for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "entity",
+		Description: "Entity or value object class, strongly-typed ID and value object factory",
+		Layers:      []string{"Domain", "Domain.Shared"},
+		Outputs:     []string{"Entities/{Module}/{Entity}.cs", "Entities/{Module}/{Entity}Factory.cs", "Identifiers/{Module}/{EntityId}.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if entity.EntityType == "ValueObject" {
+				if err := g.ValueObject.Generate(sch, entity, paths); err != nil {
+					return fmt.Errorf("failed to generate value object %s: %w", entity.Name, err)
+				}
+				if err := g.ValueObject.GenerateFactory(sch, entity, paths); err != nil {
+					return fmt.Errorf("failed to generate value object factory for %s: %w", entity.Name, err)
+				}
+				return nil
+			}
+
+			if err := g.Entity.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate entity %s: %w", entity.Name, err)
+			}
+			if err := g.Entity.GenerateStronglyTypedId(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate strongly-typed ID for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "repository",
+		Description: "Repository interface",
+		Layers:      []string{"Domain"},
+		Outputs:     []string{"Repositories/{Module}/I{Entity}Repository.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Entity.GenerateRepository(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate repository for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "custom-repository",
+		Description: "Custom repository interface and EF Core/MongoDB implementations",
+		Layers:      []string{"Domain", "EntityFrameworkCore", "MongoDB"},
+		Outputs:     []string{"Repositories/{Module}/I{Entity}CustomRepository.cs", "Repositories/{Module}/EfCore{Entity}Repository.Custom.cs", "Repositories/{Module}/Mongo{Entity}Repository.Custom.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.CustomRepository.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate custom repository for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "domain-events",
+		Description: "Custom domain and distributed events with their handlers",
+		Layers:      []string{"Domain", "Domain.Shared", "Application"},
+		Outputs:     []string{"Events/{Module}/{Event}.cs", "EventHandlers/{Module}/{Handler}.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.DomainEvents.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate domain events for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "manager",
+		Description: "Domain manager for business logic",
+		Layers:      []string{"Domain"},
+		Outputs:     []string{"Managers/{Module}/{Entity}Manager.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Manager.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate manager for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "constants",
+		Description: "Entity constants such as validation lengths",
+		Layers:      []string{"Domain.Shared"},
+		Outputs:     []string{"Constants/{Module}/{Entity}Constants.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Entity.GenerateConstants(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate constants for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "events",
+		Description: "Event transfer object and event type names",
+		Layers:      []string{"Domain.Shared"},
+		Outputs:     []string{"Events/{Module}/{Entity}Eto.cs", "Events/{Module}/{Entity}EtoTypes.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Entity.GenerateEvents(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate events for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "seeder",
+		Description: "Data seeder",
+		Layers:      []string{"Domain"},
+		Outputs:     []string{"Data/{Module}/{Entity}DataSeeder.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Entity.GenerateDataSeeder(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate data seeder for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "dto",
+		Description: "Create, update, read and list input DTOs and the application service interface",
+		Layers:      []string{"Application.Contracts"},
+		Outputs:     []string{"{Module}/{Entity}/Create{Entity}Dto.cs", "{Module}/{Entity}/Update{Entity}Dto.cs", "{Module}/{Entity}/{Entity}Dto.cs", "{Module}/{Entity}/Get{Entity}ListInput.cs", "Services/{Module}/I{Entity}AppService.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.DTO.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate DTOs for %s: %w", entity.Name, err)
+			}
+			if err := g.DTO.GenerateAppServiceInterface(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate app service interface for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "validator",
+		Description: "FluentValidation validators for the create and update DTOs",
+		Layers:      []string{"Application"},
+		Outputs:     []string{"Validators/{Module}/Create{Entity}DtoValidator.cs", "Validators/{Module}/Update{Entity}DtoValidator.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Validator.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate validators for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "service",
+		Description: "Application service and query-string filter",
+		Layers:      []string{"Application"},
+		Outputs:     []string{"Services/{Module}/{Entity}AppService.cs", "Services/{Module}/{Entity}QueryFilter.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Service.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate service for %s: %w", entity.Name, err)
+			}
+			if err := g.Service.GenerateQueryFilter(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate query filter for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "mapper",
+		Description: "AutoMapper profile or Mapperly mapper, depending on options.mappingLibrary",
+		Layers:      []string{"Application"},
+		Outputs:     []string{"AutoMapper/{Module}/{Entity}Profile.cs", "Mapperly/{Module}/{Entity}Mapper.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if sch.Options.MappingLibrary == "mapperly" {
+				if err := g.Service.GenerateMapperlyProfile(sch, entity, paths); err != nil {
+					return fmt.Errorf("failed to generate Mapperly profile for %s: %w", entity.Name, err)
+				}
+				return nil
+			}
+			if err := g.Service.GenerateAutoMapperProfile(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate AutoMapper profile for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "controller",
+		Description: "HTTP API controller",
+		Layers:      []string{"HttpApi"},
+		Outputs:     []string{"Controllers/{Module}/{Entity}Controller.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Service.GenerateController(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate controller for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "grpc",
+		Description: "gRPC contract and service delegating to the app service (options.generateGrpc, microservice targets)",
+		Layers:      []string{"HttpApi"},
+		Outputs:     []string{"Protos/{Module}/{entity}.proto", "Grpc/{Module}/{Entity}GrpcService.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Grpc.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate gRPC service for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "permissions",
+		Description: "Permission constants and definition provider entries",
+		Layers:      []string{"Application.Contracts", "Domain.Shared"},
+		Outputs:     []string{"Permissions/{Module}/{ModuleName}Permissions.cs", "Permissions/{Module}/{ModuleName}PermissionDefinitionProvider.cs", "Localization/{ModuleName}Resource.cs"},
+		Scope:       ScopePerEntity,
+		Shared:      true,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Permissions.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate permissions for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "event-handlers",
+		Description: "Created, updated and deleted event handlers",
+		Layers:      []string{"Application"},
+		Outputs:     []string{"EventHandlers/{Module}/{Entity}CreatedEventHandler.cs", "EventHandlers/{Module}/{Entity}UpdatedEventHandler.cs", "EventHandlers/{Module}/{Entity}DeletedEventHandler.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.EventHandler.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate event handlers for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "efcore",
+		Description: "EF Core configuration, repository, DbContext and module registration (efcore/both providers)",
+		Layers:      []string{"EntityFrameworkCore", "Domain.Shared"},
+		Outputs:     []string{"EntityFrameworkCore/Configurations/{Module}/{Entity}Configuration.cs", "EntityFrameworkCore/Repositories/{Module}/EfCore{Entity}Repository.cs", "EntityFrameworkCore/{ModuleName}DbContext.cs", "EntityFrameworkCore/I{ModuleName}DbContext.cs", "EntityFrameworkCore/{ModuleName}DbContextModelCreatingExtensions.cs", "Constants/{Module}/{ModuleName}DbProperties.cs"},
+		Scope:       ScopePerEntity,
+		Shared:      true,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if g.EFCore == nil {
+				return nil
+			}
+			if err := g.EFCore.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate EF Core files for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "mongodb",
+		Description: "MongoDB repository and collection configuration (mongodb/both providers)",
+		Layers:      []string{"MongoDB"},
+		Outputs:     []string{"MongoDB/Repositories/{Module}/Mongo{Entity}Repository.cs", "MongoDB/{Module}/{Entity}MongoDbConfiguration.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if g.MongoDB == nil {
+				return nil
+			}
+			if err := g.MongoDB.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate MongoDB files for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "integration-tests",
+		Description: "Repository, service and domain integration tests (options.generateIntegrationTests)",
+		Layers:      []string{"test"},
+		Outputs:     []string{"Repositories/{Module}/{Entity}RepositoryTests.cs", "Repositories/{Module}/{Provider}/{Entity}RepositoryTests.cs (dbProvider both)", "Services/{Module}/{Entity}ServiceTests.cs", "Domain/{Module}/{Entity}Tests.cs"},
+		Scope:       ScopePerEntity,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.IntegrationTest.Generate(sch, entity, paths); err != nil {
+				return fmt.Errorf("failed to generate integration tests for %s: %w", entity.Name, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "worker",
+		Description: "Periodic background workers from solution.workers, registered in the Domain module",
+		Layers:      []string{"Domain"},
+		Outputs:     []string{"BackgroundWorkers/{Module}/{Worker}.cs", "{ModuleName}DomainModule.cs"},
+		Scope:       ScopePerModule,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.BackgroundWorker.Generate(sch, paths); err != nil {
+				return fmt.Errorf("failed to generate background workers: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "localization",
+		Description: "Entity, property and permission texts merged into one localization file per culture",
+		Layers:      []string{"Domain.Shared"},
+		Outputs:     []string{"Localization/{ModuleName}/{culture}.json"},
+		Scope:       ScopePerModule,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.Localization.Generate(sch, paths); err != nil {
+				return fmt.Errorf("failed to generate localization: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "mapper-module",
+		Description: "Module AutoMapper profile or Mapperly registration, registered in the Application module",
+		Layers:      []string{"Application"},
+		Outputs:     []string{"AutoMapper/{Module}/{ModuleName}ApplicationAutoMapperProfile.cs", "Mapperly/{Module}/{ModuleName}ApplicationMappers.cs", "{ModuleName}ApplicationModule.cs"},
+		Scope:       ScopePerModule,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.MappingModule.Generate(sch, paths); err != nil {
+				return fmt.Errorf("failed to generate module mappings: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "appsettings",
+		Description: "EF Core and MongoDB connection strings added to the applications' appsettings.json (both providers)",
+		Layers:      []string{"HttpApi.Host", "DbMigrator"},
+		Outputs:     []string{"appsettings.json", "appsettings.{ModuleName}.json"},
+		Scope:       ScopePerModule,
+		run: func(g *Generators, sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+			if err := g.AppSettings.Generate(sch, paths); err != nil {
+				return fmt.Errorf("failed to generate connection strings: %w", err)
+			}
+			return nil
+		},
+	},
+}
+
+// Select restricts the run to the named generators. Generators listed in only are kept
+// (all of them when only is empty), then those listed in skip are removed.
+func (g *Generators) Select(only, skip []string) error {
+	if len(only) == 0 && len(skip) == 0 {
+		g.selected = nil
+		return nil
+	}
+
+	known := make(map[string]bool, len(Registry))
+	for _, registration := range Registry {
+		known[registration.Name] = true
+	}
+	for _, name := range append(append([]string{}, only...), skip...) {
+		if !known[name] {
+			return fmt.Errorf("unknown generator %q (run with --list-generators to see the available names)", name)
+		}
+	}
+
+	selected := make(map[string]bool, len(Registry))
+	for _, registration := range Registry {
+		selected[registration.Name] = len(only) == 0
+	}
+	for _, name := range only {
+		selected[name] = true
+	}
+	for _, name := range skip {
+		selected[name] = false
+	}
+	g.selected = selected
+	return nil
+}
+
+// Enabled reports whether the named generator takes part in the run
+func (g *Generators) Enabled(name string) bool {
+	return g.selected == nil || g.selected[name]
+}
+
+// RunForEntity runs every per-entity generator in registry order
+func (g *Generators) RunForEntity(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	return g.run(sch, entity, paths, func(r Registration) bool { return r.Scope == ScopePerEntity })
+}
+
+// RunForEntitiesParallel runs the per-entity generators for every entity on up to workers
+// goroutines. Each entity's own files are generated concurrently; the shared generators then
+// run for each entity in order, so files such as the DbContext are updated one entity at a time.
+// done, if not nil, is called once an entity's own files are generated.
+func (g *Generators) RunForEntitiesParallel(sch *schema.Schema, entities []*schema.Entity, paths *detector.LayerPaths, workers int, done func(*schema.Entity)) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan *schema.Entity)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entity := range jobs {
+				err := g.run(sch, entity, paths, func(r Registration) bool { return r.Scope == ScopePerEntity && !r.Shared })
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if err == nil && done != nil {
+					done(entity)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, entity := range entities {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- entity
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	for _, entity := range entities {
+		if err := g.run(sch, entity, paths, func(r Registration) bool { return r.Scope == ScopePerEntity && r.Shared }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunForModule runs every per-module generator in registry order
+func (g *Generators) RunForModule(sch *schema.Schema, paths *detector.LayerPaths) error {
+	return g.run(sch, nil, paths, func(r Registration) bool { return r.Scope == ScopePerModule })
+}
+
+// run runs the enabled generators accepted by include, in registry order. Failures are
+// returned as a *GenerationError.
+func (g *Generators) run(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths, include func(Registration) bool) error {
+	for _, registration := range Registry {
+		if !include(registration) || !g.Enabled(registration.Name) {
+			continue
+		}
+		if err := registration.run(g, sch, entity, paths); err != nil {
+			return newGenerationError(registration.Name, entity, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+	"github.com/mohamedhabibwork/abp-gen/internal/templates"
+	"github.com/mohamedhabibwork/abp-gen/internal/writer"
+)
+
+func TestRegistry_EntriesAreComplete(t *testing.T) {
+	names := make(map[string]bool)
+	for _, registration := range Registry {
+		if names[registration.Name] {
+			t.Errorf("duplicate generator name %q", registration.Name)
+		}
+		names[registration.Name] = true
+
+		if registration.run == nil {
+			t.Errorf("generator %q has no run function", registration.Name)
+		}
+		if len(registration.Layers) == 0 || len(registration.Outputs) == 0 {
+			t.Errorf("generator %q must document its layers and outputs", registration.Name)
+		}
+		if registration.Scope != ScopePerEntity && registration.Scope != ScopePerModule {
+			t.Errorf("generator %q has unknown scope %q", registration.Name, registration.Scope)
+		}
+	}
+}
+
+func TestGenerators_Select(t *testing.T) {
+	g := &Generators{}
+
+	if err := g.Select([]string{"entity", "dto"}, nil); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if !g.Enabled("entity") || !g.Enabled("dto") || g.Enabled("service") {
+		t.Errorf("--only did not restrict the run to entity and dto")
+	}
+
+	if err := g.Select(nil, []string{"integration-tests"}); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if g.Enabled("integration-tests") || !g.Enabled("entity") {
+		t.Errorf("--skip did not remove only integration-tests")
+	}
+
+	if err := g.Select([]string{"dto", "service"}, []string{"service"}); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if !g.Enabled("dto") || g.Enabled("service") {
+		t.Errorf("--skip should win over --only")
+	}
+
+	if err := g.Select([]string{"tests"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown generator name")
+	}
+}
+
+func TestGenerators_RunForEntitiesParallel(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+		schema.Entity{Name: "Category", Properties: []schema.Property{{Name: "Title", Type: "string"}}},
+		schema.Entity{Name: "Supplier", Properties: []schema.Property{{Name: "Email", Type: "string"}}},
+		schema.Entity{Name: "Warehouse", Properties: []schema.Property{{Name: "Code", Type: "string"}}},
+	)
+	var entities []*schema.Entity
+	for i := range sch.Entities {
+		entities = append(entities, &sch.Entities[i])
+	}
+
+	sequentialPaths := newTestLayerPaths(t)
+	sequential := NewGenerators(templates.NewLoader(""), writer.NewWriter(false, true, false), sch)
+	for _, entity := range entities {
+		if err := sequential.RunForEntity(sch, entity, sequentialPaths); err != nil {
+			t.Fatalf("RunForEntity(%s) error = %v", entity.Name, err)
+		}
+	}
+
+	parallelPaths := newTestLayerPaths(t)
+	parallel := NewGenerators(templates.NewLoader(""), writer.NewWriter(false, true, false), sch)
+	var generated []string
+	err := parallel.RunForEntitiesParallel(sch, entities, parallelPaths, 3, func(entity *schema.Entity) {
+		generated = append(generated, entity.Name)
+	})
+	if err != nil {
+		t.Fatalf("RunForEntitiesParallel() error = %v", err)
+	}
+
+	sort.Strings(generated)
+	if got := filepath.Join(generated...); got != filepath.Join("Category", "Product", "Supplier", "Warehouse") {
+		t.Errorf("done called for %v, want every entity once", generated)
+	}
+
+	want := readTree(t, filepath.Dir(sequentialPaths.Domain))
+	got := readTree(t, filepath.Dir(parallelPaths.Domain))
+	if len(want) == 0 {
+		t.Fatalf("sequential run generated no files")
+	}
+	if len(got) != len(want) {
+		t.Errorf("parallel run generated %d files, sequential run %d", len(got), len(want))
+	}
+	for path, content := range want {
+		if got[path] != content {
+			t.Errorf("%s differs between the sequential and parallel runs", path)
+		}
+	}
+}
+
+// readTree returns the content of every file under root keyed by its path relative to root
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", root, err)
+	}
+	return files
+}
+
+func TestGenerators_GenerationErrorContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     string // Custom entity.tmpl; empty uses the embedded template
+		blockOutput  bool   // Replace the entities directory with a file
+		wantTemplate string
+		wantPath     bool
+	}{
+		{name: "execute", template: `{{template "missing"}}`, wantTemplate: "entity.tmpl"},
+		{name: "write", blockOutput: true, wantPath: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customPath := ""
+			if tt.template != "" {
+				customPath = t.TempDir()
+				if err := os.MkdirAll(filepath.Join(customPath, "common"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(customPath, "common", "entity.tmpl"), []byte(tt.template), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sch := newTestSchema(t, schema.Entity{Name: "Product", Properties: []schema.Property{{Name: "Name", Type: "string"}}})
+			paths := newTestLayerPaths(t)
+			if tt.blockOutput {
+				if err := os.MkdirAll(filepath.Dir(paths.DomainEntities), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(paths.DomainEntities, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			g := NewGenerators(templates.NewLoader(customPath), writer.NewWriter(false, true, false), sch)
+			if err := g.Select([]string{"entity"}, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			err := g.RunForEntity(sch, &sch.Entities[0], paths)
+			var genErr *GenerationError
+			if !errors.As(err, &genErr) {
+				t.Fatalf("RunForEntity() error = %v, want a *GenerationError", err)
+			}
+			if genErr.Generator != "entity" || genErr.Entity != "Product" {
+				t.Errorf("error attributed to %s/%s, want entity/Product", genErr.Generator, genErr.Entity)
+			}
+			if !strings.HasPrefix(genErr.Template, tt.wantTemplate) || (tt.wantTemplate == "") != (genErr.Template == "") {
+				t.Errorf("Template = %q, want %q", genErr.Template, tt.wantTemplate)
+			}
+			if tt.wantPath && !strings.HasPrefix(genErr.Path, paths.DomainEntities) {
+				t.Errorf("Path = %q, want a file in %s", genErr.Path, paths.DomainEntities)
+			}
+			if !strings.Contains(err.Error(), "entity generator for Product") {
+				t.Errorf("Error() = %q, want the generator and entity", err.Error())
+			}
+		})
+	}
+}
@@ -21,34 +21,37 @@ func NewRelationshipHandler() *RelationshipHandler {
 // - Updating DTOs to include related data
 // - Adding relationship management methods to services
 func (h *RelationshipHandler) ProcessRelationships(sch *schema.Schema, entity *schema.Entity) error {
+	// Foreign keys take their type from the target entity's primary key
+	h.resolveForeignKeyTypes(sch, entity)
+
 	if entity.Relations == nil {
 		return nil
 	}
 
 	// Process One-to-One relationships
-	for _, rel := range entity.Relations.OneToOne {
-		if err := h.processOneToOne(sch, entity, &rel); err != nil {
+	for i := range entity.Relations.OneToOne {
+		if err := h.processOneToOne(sch, entity, &entity.Relations.OneToOne[i]); err != nil {
 			return err
 		}
 	}
 
 	// Process One-to-Many relationships
-	for _, rel := range entity.Relations.OneToMany {
-		if err := h.processOneToMany(sch, entity, &rel); err != nil {
+	for i := range entity.Relations.OneToMany {
+		if err := h.processOneToMany(sch, entity, &entity.Relations.OneToMany[i]); err != nil {
 			return err
 		}
 	}
 
 	// Process Many-to-One relationships
-	for _, rel := range entity.Relations.ManyToOne {
-		if err := h.processManyToOne(sch, entity, &rel); err != nil {
+	for i := range entity.Relations.ManyToOne {
+		if err := h.processManyToOne(sch, entity, &entity.Relations.ManyToOne[i]); err != nil {
 			return err
 		}
 	}
 
 	// Process Many-to-Many relationships
-	for _, rel := range entity.Relations.ManyToMany {
-		if err := h.processManyToMany(sch, entity, &rel); err != nil {
+	for i := range entity.Relations.ManyToMany {
+		if err := h.processManyToMany(sch, entity, &entity.Relations.ManyToMany[i]); err != nil {
 			return err
 		}
 	}
@@ -56,6 +59,29 @@ func (h *RelationshipHandler) ProcessRelationships(sch *schema.Schema, entity *s
 	return nil
 }
 
+// foreignKeyType returns the type of a foreign key referencing the target entity.
+// Targets outside the schema fall back to the solution's primary key type.
+func foreignKeyType(sch *schema.Schema, targetEntity string) string {
+	if target := sch.FindEntity(targetEntity); target != nil {
+		return target.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+	}
+	return sch.Solution.PrimaryKeyType
+}
+
+// resolveForeignKeyTypes sets each declared foreign key's type to its target entity's primary key type
+func (h *RelationshipHandler) resolveForeignKeyTypes(sch *schema.Schema, entity *schema.Entity) {
+	for i := range entity.Properties {
+		prop := &entity.Properties[i]
+		if !prop.IsForeignKey {
+			continue
+		}
+		// Keep the declared type when the target is not part of the schema
+		if target := sch.FindEntity(prop.TargetEntity); target != nil {
+			prop.Type = target.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+		}
+	}
+}
+
 // processOneToOne processes a one-to-one relationship
 func (h *RelationshipHandler) processOneToOne(sch *schema.Schema, entity *schema.Entity, rel *schema.OneToOneRelation) error {
 	// One-to-One relationships are handled in templates:
@@ -74,6 +100,26 @@ func (h *RelationshipHandler) processOneToOne(sch *schema.Schema, entity *schema
 		rel.ForeignKeyName = rel.TargetEntity + "Id"
 	}
 
+	// Owned types are stored with the owner and have no foreign key
+	if rel.IsOwned {
+		return nil
+	}
+
+	// Add the foreign key property unless the schema declares it
+	for _, prop := range entity.Properties {
+		if prop.Name == rel.ForeignKeyName {
+			return nil
+		}
+	}
+	entity.Properties = append(entity.Properties, schema.Property{
+		Name:         rel.ForeignKeyName,
+		Type:         foreignKeyType(sch, rel.TargetEntity),
+		IsRequired:   rel.IsRequired,
+		Nullable:     !rel.IsRequired,
+		IsForeignKey: true,
+		TargetEntity: rel.TargetEntity,
+	})
+
 	return nil
 }
 
@@ -95,6 +141,21 @@ func (h *RelationshipHandler) processManyToOne(sch *schema.Schema, entity *schem
 		rel.ForeignKeyName = rel.TargetEntity + "Id"
 	}
 
+	// Add the foreign key property unless the schema declares it
+	for _, prop := range entity.Properties {
+		if prop.Name == rel.ForeignKeyName {
+			return nil
+		}
+	}
+	entity.Properties = append(entity.Properties, schema.Property{
+		Name:         rel.ForeignKeyName,
+		Type:         foreignKeyType(sch, rel.TargetEntity),
+		IsRequired:   rel.IsRequired,
+		Nullable:     !rel.IsRequired,
+		IsForeignKey: true,
+		TargetEntity: rel.TargetEntity,
+	})
+
 	return nil
 }
 
@@ -126,6 +187,37 @@ func (h *RelationshipHandler) processManyToMany(sch *schema.Schema, entity *sche
 	// 2. A join entity is created if specified
 	// 3. DTOs include navigation data
 	// 4. Services include methods to link/unlink entities
+	applyManyToManyDefaults(entity.Name, rel)
+	return nil
+}
+
+// applyManyToManyDefaults fills in the navigation and join entity names of a many-to-many relation.
+// A self-referencing relation gets distinct names for both sides: the entity links to its
+// Target{Entities} through Source{Entity}Id/Target{Entity}Id and is linked from its Source{Entities}.
+func applyManyToManyDefaults(entityName string, rel *schema.ManyToManyRelation) {
+	if rel.TargetEntity == entityName {
+		rel.IsSelfReference = true
+	}
+
+	if rel.IsSelfReference {
+		plural := templates.Pluralize(rel.TargetEntity)
+		if rel.NavigationProperty == "" {
+			rel.NavigationProperty = "Target" + plural
+		}
+		if rel.InverseProperty == "" {
+			rel.InverseProperty = "Source" + plural
+		}
+		if rel.SourceForeignKeyName == "" {
+			rel.SourceForeignKeyName = "Source" + rel.TargetEntity + "Id"
+		}
+		if rel.TargetForeignKeyName == "" {
+			rel.TargetForeignKeyName = "Target" + rel.TargetEntity + "Id"
+		}
+		if rel.JoinEntity == "" {
+			rel.JoinEntity = entityName + rel.NavigationProperty
+		}
+		return
+	}
 
 	// Ensure navigation property name is set
 	if rel.NavigationProperty == "" {
@@ -135,19 +227,23 @@ func (h *RelationshipHandler) processManyToMany(sch *schema.Schema, entity *sche
 	// Ensure join entity name is set
 	if rel.JoinEntity == "" {
 		// Generate join entity name from both entity names
-		entities := []string{entity.Name, rel.TargetEntity}
+		entities := []string{entityName, rel.TargetEntity}
 		// Sort to ensure consistent naming
 		if entities[0] > entities[1] {
 			entities[0], entities[1] = entities[1], entities[0]
 		}
 		rel.JoinEntity = entities[0] + entities[1]
 	}
-
-	return nil
 }
 
-// GenerateJoinEntity generates a join entity for many-to-many relationships
+// GenerateJoinEntity generates a join entity for many-to-many relationships.
+// The keys of a self-referencing join entity are prefixed Source and Target to keep them apart.
 func (h *RelationshipHandler) GenerateJoinEntity(entity1Name, entity2Name string, primaryKeyType string) *schema.Entity {
+	key1, key2 := entity1Name+"Id", entity2Name+"Id"
+	if entity1Name == entity2Name {
+		key1, key2 = "Source"+key1, "Target"+key2
+	}
+
 	// Generate a join entity
 	joinEntity := &schema.Entity{
 		Name:       entity1Name + entity2Name,
@@ -155,14 +251,14 @@ func (h *RelationshipHandler) GenerateJoinEntity(entity1Name, entity2Name string
 		EntityType: "Entity",
 		Properties: []schema.Property{
 			{
-				Name:         entity1Name + "Id",
+				Name:         key1,
 				Type:         primaryKeyType,
 				IsRequired:   true,
 				IsForeignKey: true,
 				TargetEntity: entity1Name,
 			},
 			{
-				Name:         entity2Name + "Id",
+				Name:         key2,
 				Type:         primaryKeyType,
 				IsRequired:   true,
 				IsForeignKey: true,
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestRelationshipHandler_ManyToOneForeignKeyUsesTargetKeyType(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{
+			Name:       "Order",
+			Properties: []schema.Property{{Name: "Number", Type: "string"}},
+			Relations: &schema.Relations{
+				ManyToOne: []schema.ManyToOneRelation{{TargetEntity: "Warehouse", IsRequired: true}},
+			},
+		},
+		schema.Entity{
+			Name:           "Warehouse",
+			PrimaryKeyType: "long",
+			Properties:     []schema.Property{{Name: "Code", Type: "string"}},
+		},
+	)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	order := sch.Entities[0]
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &order); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+	if err := NewEntityGenerator(loader, w).Generate(sch, &order, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Order.cs")
+	if !strings.Contains(content, "public long WarehouseId { get; set; }") {
+		t.Errorf("entity missing long foreign key:\n%s", content)
+	}
+	if !strings.Contains(content, "public Order(Guid id, string number)") {
+		t.Errorf("entity key type changed:\n%s", content)
+	}
+}
+
+func TestRelationshipHandler_ResolvesDeclaredForeignKeyType(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{
+			Name: "Order",
+			Properties: []schema.Property{
+				{Name: "WarehouseId", Type: "Guid", IsForeignKey: true, TargetEntity: "Warehouse"},
+				{Name: "CustomerId", Type: "Guid", IsForeignKey: true, TargetEntity: "Customer"},
+			},
+			Relations: &schema.Relations{
+				ManyToOne: []schema.ManyToOneRelation{{TargetEntity: "Warehouse"}},
+			},
+		},
+		schema.Entity{
+			Name:           "Warehouse",
+			PrimaryKeyType: "long",
+			Properties:     []schema.Property{{Name: "Code", Type: "string"}},
+		},
+	)
+
+	order := sch.Entities[0]
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &order); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+
+	if len(order.Properties) != 2 {
+		t.Fatalf("Properties = %+v; want the declared foreign keys only", order.Properties)
+	}
+	if got := order.Properties[0].Type; got != "long" {
+		t.Errorf("WarehouseId type = %q; want long", got)
+	}
+	// Customer is not part of the schema, so its declared type is kept
+	if got := order.Properties[1].Type; got != "Guid" {
+		t.Errorf("CustomerId type = %q; want Guid", got)
+	}
+}
+
+func TestRelationshipHandler_SelfReferencingManyToMany(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "User",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "UserName", Type: "string"}},
+		Relations: &schema.Relations{
+			ManyToMany: []schema.ManyToManyRelation{{TargetEntity: "User", NavigationProperty: "Following", InverseProperty: "Followers"}},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	user := sch.Entities[0]
+	if err := NewRelationshipHandler().ProcessRelationships(sch, &user); err != nil {
+		t.Fatalf("ProcessRelationships() error = %v", err)
+	}
+	rel := user.Relations.ManyToMany[0]
+	if !rel.IsSelfReference || rel.SourceForeignKeyName != "SourceUserId" || rel.TargetForeignKeyName != "TargetUserId" || rel.JoinEntity != "UserFollowing" {
+		t.Errorf("self-reference defaults = %+v", rel)
+	}
+
+	if err := NewEntityGenerator(loader, w).Generate(sch, &user, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &user, paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	entity := generatedContent(t, w, "Entities/CatalogModule/User.cs")
+	for _, want := range []string{
+		"public virtual ICollection<User> Following { get; protected set; }",
+		"public virtual ICollection<User> Followers { get; protected set; }",
+	} {
+		if !strings.Contains(entity, want) {
+			t.Errorf("entity missing %q:\n%s", want, entity)
+		}
+	}
+
+	config := generatedContent(t, w, "Configurations/CatalogModule/UserConfiguration.cs")
+	for _, want := range []string{
+		".WithMany(x => x.Followers)",
+		`right => right.HasOne(typeof(User)).WithMany().HasForeignKey("TargetUserId")`,
+		`left => left.HasOne(typeof(User)).WithMany().HasForeignKey("SourceUserId")`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("configuration missing %q:\n%s", want, config)
+		}
+	}
+
+	join := NewRelationshipHandler().GenerateJoinEntity("User", "User", "Guid")
+	if join.Properties[0].Name != "SourceUserId" || join.Properties[1].Name != "TargetUserId" {
+		t.Errorf("join entity keys = %s, %s; want SourceUserId, TargetUserId", join.Properties[0].Name, join.Properties[1].Name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -38,6 +39,7 @@ func (g *ServiceGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 	}
 
 	primaryKeyType := entity.GetEffectivePrimaryKeyType(sch.Solution.PrimaryKeyType)
+	customEndpoints := getCustomEndpoints(entity)
 
 	data := map[string]interface{}{
 		"SolutionName":            sch.Solution.Name,
@@ -46,18 +48,37 @@ func (g *ServiceGenerator) Generate(sch *schema.Schema, entity *schema.Entity, p
 		"NamespaceRoot":           sch.Solution.NamespaceRoot,
 		"EntityName":              entity.Name,
 		"PrimaryKeyType":          primaryKeyType,
+		"NewId":                   newIdExpression(sch, entity, "GuidGenerator"),
 		"EntityType":              entity.EntityType,
 		"Properties":              entity.Properties,
 		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
+		"InputProperties":         sch.GetConstructorProperties(entity),
 		"ForeignKeyProperties":    entity.GetForeignKeyProperties(),
 		"HasRelations":            entity.HasRelations(),
 		"OneToManyRelations":      getOneToManyRelations(entity),
 		"ManyToManyRelations":     getManyToManyRelations(entity),
-		"IsAggregateRoot":         entity.EntityType == "AggregateRoot" || entity.EntityType == "FullAuditedAggregateRoot",
-		"HasEvents":               entity.EntityType != "ValueObject" && entity.EntityType != "Entity",
+		"IsAggregateRoot":         entity.IsAggregateRoot(),
+		"IsCreationAudited":       entity.IsCreationAudited(),
+		"HasConcurrencyStamp":     sch.HasConcurrencyStamp(entity),
+		"HasEvents":               entity.IsAggregateRoot(),
 		"HasEnumProperties":       entity.HasEnumProperties(),
 		"EnumNames":               entity.GetEnumNames(),
 		"NeedsFluentValidation":   entity.NeedsFluentValidation() || sch.Options.ValidationType == "fluentvalidation",
+		"HasStronglyTypedId":      entity.HasStronglyTypedId(),
+		"HasQueryFilter":          hasQueryFilter(sch, entity),
+		"HasListInput":            hasListInput(entity),
+		"ListInputType":           listInputType(entity),
+		"ListFilters":             getQueryFilterFields(entity),
+		"HasDetails":              len(getDetailNavigations(entity)) > 0,
+		"Operations":              getServiceOperations(entity),
+		"IsCrud":                  entity.HasAllOperations(),
+		"SortableProperties":      entity.SortableProperties,
+		"Description":             entity.Description,
+		"CustomEndpoints":         customEndpoints,
+		"UsesCustomRepository":    usesCustomRepository(customEndpoints),
+		"FileProperties":          entity.GetFileProperties(),
+		"RequireAuthorization":    entity.RequiresAuthorization(),
+		"HasManager":              entity.ShouldGenerateManager(sch.Options.GenerateDeleteGuards),
 	}
 
 	var buf bytes.Buffer
@@ -78,13 +99,19 @@ func (g *ServiceGenerator) GenerateAutoMapperProfile(sch *schema.Schema, entity
 	}
 
 	data := map[string]interface{}{
-		"SolutionName":         sch.Solution.Name,
-		"ModuleName":           sch.Solution.ModuleName,
-		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
-		"NamespaceRoot":        sch.Solution.NamespaceRoot,
-		"EntityName":           entity.Name,
-		"IsValueObject":        entity.EntityType == "ValueObject",
-		"HasEvents":            entity.EntityType != "ValueObject" && entity.EntityType != "Entity",
+		"SolutionName":             sch.Solution.Name,
+		"ModuleName":               sch.Solution.ModuleName,
+		"ModuleNameWithSuffix":     sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":            sch.Solution.NamespaceRoot,
+		"EntityName":               entity.Name,
+		"IsValueObject":            entity.EntityType == "ValueObject",
+		"IsImmutable":              entity.IsImmutableValueObject(),
+		"HasEvents":                entity.IsAggregateRoot(),
+		"EtoProperties":            entity.GetNonForeignKeyProperties(),
+		"ForeignKeyProperties":     entity.GetForeignKeyProperties(),
+		"DetailNavigations":        getDetailNavigations(entity),
+		"DetailDtoEntities":        detailDtoEntities(entity),
+		"UndeclaredDetailEntities": undeclaredDetailEntities(sch, entity),
 	}
 
 	var buf bytes.Buffer
@@ -111,7 +138,10 @@ func (g *ServiceGenerator) GenerateMapperlyProfile(sch *schema.Schema, entity *s
 		"NamespaceRoot":        sch.Solution.NamespaceRoot,
 		"EntityName":           entity.Name,
 		"IsValueObject":        entity.EntityType == "ValueObject",
-		"HasEvents":            entity.EntityType != "ValueObject" && entity.EntityType != "Entity",
+		"HasEvents":            entity.IsAggregateRoot(),
+		"EtoProperties":        entity.GetNonForeignKeyProperties(),
+		"ForeignKeyProperties": entity.GetForeignKeyProperties(),
+		"DetailDtoEntities":    detailDtoEntities(entity),
 	}
 
 	var buf bytes.Buffer
@@ -131,7 +161,7 @@ func (g *ServiceGenerator) GenerateMapperlyProfile(sch *schema.Schema, entity *s
 
 // GenerateController generates HTTP API controller
 func (g *ServiceGenerator) GenerateController(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
-	if entity.EntityType == "ValueObject" || !sch.Solution.GenerateControllers {
+	if entity.EntityType == "ValueObject" || !entity.ShouldGenerateController(sch.Solution.GenerateControllers) {
 		return nil
 	}
 
@@ -150,6 +180,14 @@ func (g *ServiceGenerator) GenerateController(sch *schema.Schema, entity *schema
 		"EntityName":           entity.Name,
 		"EntityNamePlural":     templates.Pluralize(entity.Name),
 		"PrimaryKeyType":       primaryKeyType,
+		"HasStronglyTypedId":   entity.HasStronglyTypedId(),
+		"HasQueryFilter":       hasQueryFilter(sch, entity),
+		"ListInputType":        listInputType(entity),
+		"Operations":           getServiceOperations(entity),
+		"Description":          entity.Description,
+		"CustomEndpoints":      getCustomEndpoints(entity),
+		"FileProperties":       entity.GetFileProperties(),
+		"RequireAuthorization": entity.RequiresAuthorization(),
 	}
 
 	var buf bytes.Buffer
@@ -161,3 +199,188 @@ func (g *ServiceGenerator) GenerateController(sch *schema.Schema, entity *schema
 	filePath := filepath.Join(paths.HttpApiControllers, moduleFolder, entity.Name+"Controller.cs")
 	return g.writer.WriteFile(filePath, buf.String())
 }
+
+// GenerateQueryFilter generates the query-string filter parser used by the query endpoint
+func (g *ServiceGenerator) GenerateQueryFilter(sch *schema.Schema, entity *schema.Entity, paths *detector.LayerPaths) error {
+	if !hasQueryFilter(sch, entity) {
+		return nil
+	}
+
+	tmpl, err := g.tmplLoader.Load("query_filter.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to load query filter template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"SolutionName":         sch.Solution.Name,
+		"ModuleName":           sch.Solution.ModuleName,
+		"ModuleNameWithSuffix": sch.Solution.GetModuleNameWithSuffix(),
+		"NamespaceRoot":        sch.Solution.NamespaceRoot,
+		"EntityName":           entity.Name,
+		"Fields":               getQueryFilterFields(entity),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute query filter template: %w", err)
+	}
+
+	moduleFolder := sch.Solution.GetModuleFolderName()
+	filePath := filepath.Join(paths.ApplicationServices, moduleFolder, entity.Name+"QueryFilter.cs")
+	return g.writer.WriteFile(filePath, buf.String())
+}
+
+// QueryFilterField describes a whitelisted property accepted by the query filter parser
+type QueryFilterField struct {
+	Name       string // Property name
+	Key        string // Lower-case query-string key
+	Type       string // C# type of the property
+	IsString   bool   // Whether the field also supports the ".contains" operator
+	TryParse   string // C# expression parsing "value" into ValueVar
+	ValueVar   string // Name of the parsed value variable
+	FilterType string // Nullable C# type stored on the filter
+}
+
+// hasQueryFilter checks if the query endpoint should be generated for the entity
+func hasQueryFilter(sch *schema.Schema, entity *schema.Entity) bool {
+	return sch.Options.GenerateQueryFilters && entity.EntityType != "ValueObject" && len(entity.GetFilterableProperties()) > 0 && entity.HasOperation("list")
+}
+
+// ServiceOperations flags the app service operations generated for an entity
+type ServiceOperations struct {
+	Create bool
+	Read   bool
+	Update bool
+	Delete bool
+	List   bool
+}
+
+// getServiceOperations returns the app service operations selected by the entity's Operations
+func getServiceOperations(entity *schema.Entity) ServiceOperations {
+	return ServiceOperations{
+		Create: entity.HasOperation("create"),
+		Read:   entity.HasOperation("read"),
+		Update: entity.HasOperation("update"),
+		Delete: entity.HasOperation("delete"),
+		List:   entity.HasOperation("list"),
+	}
+}
+
+// CustomEndpoint is a schema custom endpoint prepared for the controller and app service templates
+type CustomEndpoint struct {
+	Name           string
+	HTTPAttribute  string // ASP.NET Core attribute, e.g. "HttpGet"
+	Route          string
+	ResultType     string // Result type, empty when the endpoint returns nothing
+	TaskType       string // Return type of the action, "Task" or "Task<ResultType>"
+	Parameters     []EndpointParameter
+	Arguments      string // Endpoint parameters passed on to the app service
+	RepositoryCall string // Custom repository call made by the app service, empty for a stub
+	IsAwaitable    bool   // Whether the repository call returns a Task
+	MapFrom        string // Repository result type mapped to ResultType, empty when returned as is
+	Description    string
+}
+
+// EndpointParameter is a custom endpoint parameter with its model binding attribute
+type EndpointParameter struct {
+	Name    string
+	Type    string
+	Binding string // "[FromQuery] " or "[FromBody] ", empty for route parameters
+}
+
+// getCustomEndpoints prepares the entity's custom endpoints. Route parameters are bound by
+// name; other parameters come from the query string for GET and DELETE and from the body otherwise.
+func getCustomEndpoints(entity *schema.Entity) []CustomEndpoint {
+	var endpoints []CustomEndpoint
+	for _, ep := range entity.CustomEndpoints {
+		endpoint := CustomEndpoint{
+			Name:          ep.Name,
+			HTTPAttribute: "Http" + templates.PascalCase(ep.HTTPMethod),
+			Route:         ep.Route,
+			ResultType:    ep.ReturnType,
+			TaskType:      "Task",
+			Description:   ep.Description,
+		}
+		if ep.ReturnType != "" {
+			endpoint.TaskType = "Task<" + ep.ReturnType + ">"
+		}
+
+		binding := "[FromBody] "
+		if ep.HTTPMethod == "GET" || ep.HTTPMethod == "DELETE" {
+			binding = "[FromQuery] "
+		}
+		routeParams := make(map[string]bool)
+		for _, name := range ep.RouteParameters() {
+			routeParams[name] = true
+		}
+		var args []string
+		for _, param := range ep.Parameters {
+			p := EndpointParameter{Name: param.Name, Type: param.Type, Binding: binding}
+			if routeParams[param.Name] {
+				p.Binding = ""
+			}
+			endpoint.Parameters = append(endpoint.Parameters, p)
+			args = append(args, param.Name)
+		}
+		endpoint.Arguments = strings.Join(args, ", ")
+
+		if method := entity.FindRepositoryMethod(ep.RepositoryMethod); method != nil {
+			var repoArgs []string
+			for _, param := range method.Parameters {
+				repoArgs = append(repoArgs, param.Name)
+			}
+			endpoint.RepositoryCall = fmt.Sprintf("%s(%s)", method.Name, strings.Join(repoArgs, ", "))
+
+			result, awaitable := method.Result()
+			endpoint.IsAwaitable = awaitable
+			if ep.ReturnType != "" && result != ep.ReturnType {
+				endpoint.MapFrom = result
+			}
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// usesCustomRepository checks if any custom endpoint calls the entity's custom repository
+func usesCustomRepository(endpoints []CustomEndpoint) bool {
+	for _, endpoint := range endpoints {
+		if endpoint.RepositoryCall != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// getQueryFilterFields returns the filter fields for the entity's filterable properties
+func getQueryFilterFields(entity *schema.Entity) []QueryFilterField {
+	var fields []QueryFilterField
+	for _, prop := range entity.GetFilterableProperties() {
+		valueVar := templates.LowerFirst(prop.Name) + "Value"
+		field := QueryFilterField{
+			Name:       prop.Name,
+			Key:        strings.ToLower(prop.Name),
+			Type:       prop.Type,
+			IsString:   prop.Type == "string",
+			ValueVar:   valueVar,
+			FilterType: prop.Type + "?",
+		}
+
+		switch {
+		case prop.Type == "string":
+			field.FilterType = "string"
+		case prop.IsEnum:
+			field.TryParse = fmt.Sprintf("Enum.TryParse<%s>(value, true, out var %s)", prop.Type, valueVar)
+		case prop.Type == "bool" || prop.Type == "Guid":
+			field.TryParse = fmt.Sprintf("%s.TryParse(value, out var %s)", prop.Type, valueVar)
+		case prop.Type == "DateTime":
+			field.TryParse = fmt.Sprintf("DateTime.TryParse(value, CultureInfo.InvariantCulture, DateTimeStyles.RoundtripKind, out var %s)", valueVar)
+		default:
+			field.TryParse = fmt.Sprintf("%s.TryParse(value, NumberStyles.Any, CultureInfo.InvariantCulture, out var %s)", prop.Type, valueVar)
+		}
+
+		fields = append(fields, field)
+	}
+	return fields
+}
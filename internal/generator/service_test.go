@@ -0,0 +1,645 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestServiceGenerator_AutoMapperProfileMapsEto(t *testing.T) {
+	product := schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsRequired: true},
+			{Name: "Price", Type: "decimal"},
+			{Name: "Category", Type: "Guid", IsForeignKey: true, TargetEntity: "Category"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewServiceGenerator(loader, w)
+	if err := gen.GenerateAutoMapperProfile(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAutoMapperProfile() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "AutoMapper/CatalogModule/ProductProfile.cs")
+
+	expected := []string{
+		"CreateMap<Product, ProductEto>()",
+		".ForMember(dest => dest.Id, opt => opt.MapFrom(src => src.Id))",
+		".ForMember(dest => dest.Name, opt => opt.MapFrom(src => src.Name))",
+		".ForMember(dest => dest.Price, opt => opt.MapFrom(src => src.Price))",
+		".ForMember(dest => dest.CategoryName, opt => opt.Ignore());",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("profile missing %q\n%s", want, content)
+		}
+	}
+}
+
+func TestServiceGenerator_MapperlyProfileMapsEto(t *testing.T) {
+	product := schema.Entity{
+		Name: "Product",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Category", Type: "Guid", IsForeignKey: true, TargetEntity: "Category"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewServiceGenerator(loader, w)
+	if err := gen.GenerateMapperlyProfile(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateMapperlyProfile() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Mapperly/CatalogModule/ProductMapper.cs")
+
+	for _, want := range []string{
+		"[MapperIgnoreTarget(nameof(ProductEto.CategoryName))]",
+		"public partial ProductEto MapToEto(Product source);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("mapper missing %q\n%s", want, content)
+		}
+	}
+}
+
+func TestServiceGenerator_ProfilesMapDetailNavigations(t *testing.T) {
+	order := schema.Entity{
+		Name:       "Order",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Number", Type: "string"}},
+		Relations: &schema.Relations{
+			OneToOne:  []schema.OneToOneRelation{{TargetEntity: "Invoice", WithDetails: true}},
+			OneToMany: []schema.OneToManyRelation{{TargetEntity: "OrderLine", WithDetails: true}},
+		},
+	}
+	orderLine := schema.Entity{
+		Name:       "OrderLine",
+		EntityType: "Entity",
+		Properties: []schema.Property{{Name: "Quantity", Type: "int"}},
+	}
+	sch := newTestSchema(t, order, orderLine)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewServiceGenerator(loader, w)
+	if err := gen.GenerateAutoMapperProfile(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAutoMapperProfile() error = %v", err)
+	}
+	if err := gen.GenerateMapperlyProfile(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateMapperlyProfile() error = %v", err)
+	}
+
+	profile := generatedContent(t, w, "AutoMapper/CatalogModule/OrderProfile.cs")
+	for _, want := range []string{
+		"using Acme.Shop.Application.Contracts.OrderModule;",
+		"using Acme.Shop.Application.Contracts.OrderLineModule;",
+		"using Acme.Shop.Application.Contracts.InvoiceModule;",
+		".ForMember(dest => dest.OrderLines, opt => opt.MapFrom(src => src.OrderLines));",
+		".ForMember(dest => dest.OrderLines, opt => opt.Ignore());",
+		"CreateMap<Invoice, InvoiceDto>();",
+	} {
+		if !strings.Contains(profile, want) {
+			t.Errorf("profile missing %q\n%s", want, profile)
+		}
+	}
+	// OrderLine is declared, so its own profile maps its DTO
+	if strings.Contains(profile, "CreateMap<OrderLine, OrderLineDto>()") {
+		t.Errorf("profile duplicates the OrderLine mapping of its own profile:\n%s", profile)
+	}
+
+	mapper := generatedContent(t, w, "Mapperly/CatalogModule/OrderMapper.cs")
+	for _, want := range []string{
+		"using Acme.Shop.Application.Contracts.OrderLineModule;",
+		"public partial OrderLineDto Map(OrderLine source);",
+		"public partial InvoiceDto Map(Invoice source);",
+	} {
+		if !strings.Contains(mapper, want) {
+			t.Errorf("mapper missing %q\n%s", want, mapper)
+		}
+	}
+}
+
+func TestServiceGenerator_QueryFilterParser(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string", IsFilterable: true},
+			{Name: "Price", Type: "decimal", IsFilterable: true},
+			{Name: "Secret", Type: "string"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	sch.Options.GenerateQueryFilters = true
+	sch.Solution.GenerateControllers = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	gen := NewServiceGenerator(loader, w)
+	if err := gen.GenerateQueryFilter(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateQueryFilter() error = %v", err)
+	}
+	if err := gen.GenerateController(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateController() error = %v", err)
+	}
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// The CRUD base ApplySorting and ApplyPaging take Get{Entity}ListInput, not the query input
+	service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+	query := service[strings.Index(service, "public virtual async Task<PagedResultDto<ProductDto>> QueryAsync"):]
+	for _, unwanted := range []string{"ApplySorting(query, input)", "ApplyPaging(query, input)"} {
+		if strings.Contains(query, unwanted) {
+			t.Errorf("QueryAsync calls %q with a PagedAndSortedResultRequestDto:\n%s", unwanted, query)
+		}
+	}
+	if !strings.Contains(query, "query = query.PageBy(input);") || !strings.Contains(service, "using System.Linq.Dynamic.Core;") {
+		t.Errorf("QueryAsync does not sort and page locally:\n%s", service)
+	}
+
+	parser := generatedContent(t, w, "Services/CatalogModule/ProductQueryFilter.cs")
+	parse := parser[strings.Index(parser, "public static ProductQueryFilter Parse"):strings.Index(parser, "public IQueryable<Product> Apply")]
+
+	// name=foo is accepted and mapped to the Name property
+	accepted := "case \"name\":\n                        filter.Name = value;"
+	if !strings.Contains(parse, accepted) {
+		t.Errorf("parser does not accept name=foo:\n%s", parse)
+	}
+	if !strings.Contains(parser, ".WhereIf(Name != null, x => x.Name == Name)") {
+		t.Errorf("parser does not filter on Name:\n%s", parser)
+	}
+
+	// Fields outside the whitelist fall through to the rejecting default branch
+	if strings.Contains(parse, "case \"secret\"") {
+		t.Errorf("parser accepts non-filterable field Secret:\n%s", parse)
+	}
+	rejected := "default:\n                        throw new BusinessException(\"Acme.Shop:UnknownFilterField\")"
+	if !strings.Contains(parse, rejected) {
+		t.Errorf("parser does not reject unknown fields:\n%s", parse)
+	}
+
+	controller := generatedContent(t, w, "Controllers/CatalogModule/ProductController.cs")
+	for _, want := range []string{
+		"[Route(\"query\")]",
+		`.Where(x => !x.Key.StartsWith("__", StringComparison.Ordinal) && !FrameworkQueryKeys.Contains(x.Key))`,
+		`"ui-culture",`,
+	} {
+		if !strings.Contains(controller, want) {
+			t.Errorf("controller missing %q:\n%s", want, controller)
+		}
+	}
+}
+
+func TestServiceGenerator_PartialOperations(t *testing.T) {
+	product := schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Operations: []string{"read", "list"},
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	sch.Solution.GenerateControllers = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewDTOGenerator(loader, w).GenerateAppServiceInterface(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAppServiceInterface() error = %v", err)
+	}
+	gen := NewServiceGenerator(loader, w)
+	if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := gen.GenerateController(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateController() error = %v", err)
+	}
+
+	contract := generatedContent(t, w, "Services/CatalogModule/IProductAppService.cs")
+	for _, want := range []string{
+		"public interface IProductAppService : IApplicationService",
+		"Task<ProductDto> GetAsync(Guid id);",
+		"Task<PagedResultDto<ProductDto>> GetListAsync(PagedAndSortedResultRequestDto input);",
+	} {
+		if !strings.Contains(contract, want) {
+			t.Errorf("interface missing %q\n%s", want, contract)
+		}
+	}
+	if strings.Contains(contract, "ICrudAppService") || strings.Contains(contract, "CreateAsync") {
+		t.Errorf("interface exposes operations outside the selected set:\n%s", contract)
+	}
+
+	service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+	for _, want := range []string{
+		"ApplicationService,\n        IProductAppService",
+		"public virtual async Task<ProductDto> GetAsync(Guid id)",
+		"public virtual async Task<PagedResultDto<ProductDto>> GetListAsync(PagedAndSortedResultRequestDto input)",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("app service missing %q\n%s", want, service)
+		}
+	}
+	for _, unwanted := range []string{"CrudAppService<", "CreateAsync(", "UpdateAsync(", "DeleteAsync(", "GetEntityByIdAsync("} {
+		if strings.Contains(service, unwanted) {
+			t.Errorf("app service contains %q\n%s", unwanted, service)
+		}
+	}
+
+	controller := generatedContent(t, w, "Controllers/CatalogModule/ProductController.cs")
+	for _, unwanted := range []string{"[HttpPost]", "[HttpPut]", "[HttpDelete]"} {
+		if strings.Contains(controller, unwanted) {
+			t.Errorf("controller contains %q\n%s", unwanted, controller)
+		}
+	}
+}
+
+func TestServiceGenerator_UpdateChecksConcurrencyStamp(t *testing.T) {
+	sch := newTestSchema(t,
+		schema.Entity{Name: "Product", EntityType: "FullAuditedAggregateRoot", Properties: []schema.Property{{Name: "Name", Type: "string"}}},
+		schema.Entity{Name: "Tag", EntityType: "Entity", Properties: []schema.Property{{Name: "Label", Type: "string"}}},
+	)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	for i := range sch.Entities {
+		if err := NewDTOGenerator(loader, w).Generate(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("Generate(%s) DTOs error = %v", sch.Entities[i].Name, err)
+		}
+		if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[i], paths); err != nil {
+			t.Fatalf("Generate(%s) error = %v", sch.Entities[i].Name, err)
+		}
+	}
+
+	for _, suffix := range []string{"Product/UpdateProductDto.cs", "Product/ProductDto.cs"} {
+		if dto := generatedContent(t, w, suffix); !strings.Contains(dto, "public string ConcurrencyStamp { get; set; }") {
+			t.Errorf("%s missing ConcurrencyStamp:\n%s", suffix, dto)
+		}
+	}
+	service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+	want := "var entity = await Repository.GetAsync(id);\n\n" +
+		"                // The stamp the client read makes the update fail with AbpDbConcurrencyException\n" +
+		"                // when the entity was changed since\n" +
+		"                if (input.ConcurrencyStamp != null)\n" +
+		"                {\n" +
+		"                    entity.ConcurrencyStamp = input.ConcurrencyStamp;\n" +
+		"                }\n"
+	if !strings.Contains(service, want) {
+		t.Errorf("UpdateAsync does not apply the client's ConcurrencyStamp:\n%s", service)
+	}
+
+	// Plain entities have no ConcurrencyStamp
+	if dto := generatedContent(t, w, "Tag/UpdateTagDto.cs"); strings.Contains(dto, "ConcurrencyStamp") {
+		t.Errorf("UpdateTagDto declares ConcurrencyStamp:\n%s", dto)
+	}
+	if service := generatedContent(t, w, "Services/CatalogModule/TagAppService.cs"); strings.Contains(service, "ConcurrencyStamp") {
+		t.Errorf("TagAppService sets ConcurrencyStamp:\n%s", service)
+	}
+}
+
+func TestServiceGenerator_StronglyTypedIdKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		underlyingType string
+		wantId         string
+	}{
+		{"Guid-backed", "Guid", "new ProductId(GuidGenerator.Create())"},
+		{"long-backed", "long", "default(ProductId)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:                     "Product",
+				PrimaryKeyType:           "ProductId",
+				PrimaryKeyUnderlyingType: tt.underlyingType,
+				Properties:               []schema.Property{{Name: "Name", Type: "string"}},
+			})
+			sch.Options.GenerateIntegrationTests = true
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if err := NewIntegrationTestGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() tests error = %v", err)
+			}
+
+			service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+			if want := "CreateAsync(\n                    " + tt.wantId + ","; !strings.Contains(service, want) {
+				t.Errorf("CreateAsync does not pass %q:\n%s", tt.wantId, service)
+			}
+			if strings.Contains(service, "                    0,") {
+				t.Errorf("CreateAsync passes a bare 0 id:\n%s", service)
+			}
+			for _, suffix := range []string{"ProductRepositoryTests.cs", "Domain/CatalogModule/ProductTests.cs"} {
+				content := generatedContent(t, w, suffix)
+				if strings.Contains(content, "var id = 0;") {
+					t.Errorf("%s uses a bare 0 id:\n%s", suffix, content)
+				}
+				if !strings.Contains(content, "var id = "+tt.wantId+";") {
+					t.Errorf("%s missing id %q:\n%s", suffix, tt.wantId, content)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceGenerator_ControllerOverride(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		solution bool
+		entity   *bool
+		want     bool
+	}{
+		{"solution enabled", true, nil, true},
+		{"solution disabled", false, nil, false},
+		{"entity opts out", true, &disabled, false},
+		{"entity opts in", false, &enabled, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:               "Product",
+				GenerateController: tt.entity,
+				Properties:         []schema.Property{{Name: "Name", Type: "string"}},
+			})
+			sch.Solution.GenerateControllers = tt.solution
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			if err := NewServiceGenerator(loader, w).GenerateController(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateController() error = %v", err)
+			}
+			if got := len(w.Operations) > 0; got != tt.want {
+				t.Errorf("controller generated = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceGenerator_AnonymousAccess(t *testing.T) {
+	anonymous := false
+	tests := []struct {
+		name       string
+		operations []string
+	}{
+		{"crud", nil},
+		{"partial operations", []string{"read", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newTestSchema(t, schema.Entity{
+				Name:                 "Country",
+				RequireAuthorization: &anonymous,
+				Operations:           tt.operations,
+				Properties:           []schema.Property{{Name: "Name", Type: "string"}},
+			})
+			sch.Solution.GenerateControllers = true
+			paths := newTestLayerPaths(t)
+			loader, w := newTestGenerators()
+
+			gen := NewServiceGenerator(loader, w)
+			if err := gen.Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if err := gen.GenerateController(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("GenerateController() error = %v", err)
+			}
+
+			for _, suffix := range []string{"CountryAppService.cs", "CountryController.cs"} {
+				content := generatedContent(t, w, suffix)
+				if !strings.Contains(content, "[AllowAnonymous]") {
+					t.Errorf("%s is not open to anonymous callers:\n%s", suffix, content)
+				}
+				if strings.Contains(content, "Permissions") || strings.Contains(content, "CountryManagement") {
+					t.Errorf("%s references permissions:\n%s", suffix, content)
+				}
+			}
+
+			operations := len(w.Operations)
+			if err := NewPermissionsGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(w.Operations) != operations {
+				t.Errorf("permissions generated for an anonymous entity: %v", w.Operations[operations:])
+			}
+		})
+	}
+}
+
+func TestServiceGenerator_SortableProperties(t *testing.T) {
+	product := schema.Entity{
+		Name:               "Product",
+		EntityType:         "FullAuditedAggregateRoot",
+		SortableProperties: []string{"Name", "Price"},
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Price", Type: "decimal"},
+		},
+	}
+	sch := newTestSchema(t, product)
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs")
+	for _, want := range []string{
+		"input.Sorting = NormalizeSorting(input.Sorting);",
+		"new HashSet<string>(StringComparer.OrdinalIgnoreCase)\n        {\n            \"Name\",\n            \"Price\",\n        };",
+		"protected virtual string NormalizeSorting(string sorting)",
+		"return \"Id\";",
+	} {
+		if !strings.Contains(service, want) {
+			t.Errorf("app service missing %q\n%s", want, service)
+		}
+	}
+
+	sch.Entities[0].SortableProperties = nil
+	loader, w = newTestGenerators()
+	if err := NewServiceGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if service := generatedContent(t, w, "Services/CatalogModule/ProductAppService.cs"); strings.Contains(service, "NormalizeSorting") {
+		t.Errorf("app service guards sorting without sortableProperties:\n%s", service)
+	}
+}
+
+func TestServiceGenerator_ControllerDescriptions(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:        "Product",
+		EntityType:  "FullAuditedAggregateRoot",
+		Description: "A product sold in the <Shop> catalog & its variants",
+		Properties:  []schema.Property{{Name: "Name", Type: "string"}},
+	})
+	sch.Solution.GenerateControllers = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewServiceGenerator(loader, w).GenerateController(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateController() error = %v", err)
+	}
+
+	controller := generatedContent(t, w, "Controllers/CatalogModule/ProductController.cs")
+	for _, want := range []string{
+		"    /// <summary>\n    /// A product sold in the &lt;Shop&gt; catalog &amp; its variants\n    /// </summary>\n    [Route(",
+		"        /// <summary>\n        /// Gets the Product with the given id.\n        /// </summary>\n",
+		"        /// <remarks>A product sold in the &lt;Shop&gt; catalog &amp; its variants</remarks>\n        [HttpPost]",
+		"/// Deletes the Product with the given id.",
+	} {
+		if !strings.Contains(controller, want) {
+			t.Errorf("controller missing %q\n%s", want, controller)
+		}
+	}
+}
+
+func TestServiceGenerator_CustomEndpoints(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{{Name: "Name", Type: "string"}},
+		CustomRepository: &schema.CustomRepository{Methods: []schema.RepositoryMethod{{
+			Name:       "GetTopSellingAsync",
+			ReturnType: "Task<List<Product>>",
+			Parameters: []schema.MethodParameter{{Name: "year", Type: "int"}, {Name: "count", Type: "int"}},
+		}}},
+		CustomEndpoints: []schema.Endpoint{
+			{
+				Name:             "GetTopSellingAsync",
+				HTTPMethod:       "GET",
+				Route:            "reports/top-selling/{year:int}",
+				ReturnType:       "List<ProductDto>",
+				Parameters:       []schema.MethodParameter{{Name: "year", Type: "int"}, {Name: "count", Type: "int"}},
+				RepositoryMethod: "GetTopSellingAsync",
+				Description:      "Best sellers of a year",
+			},
+			{
+				Name:       "ImportAsync",
+				HTTPMethod: "POST",
+				Route:      "import",
+				Parameters: []schema.MethodParameter{{Name: "input", Type: "ImportProductsDto"}},
+			},
+		},
+	})
+	sch.Solution.GenerateControllers = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	services := NewServiceGenerator(loader, w)
+	if err := services.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := services.GenerateController(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateController() error = %v", err)
+	}
+	if err := NewDTOGenerator(loader, w).GenerateAppServiceInterface(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAppServiceInterface() error = %v", err)
+	}
+
+	checks := map[string][]string{
+		"Controllers/CatalogModule/ProductController.cs": {
+			"        /// <summary>\n        /// Best sellers of a year\n        /// </summary>\n        [HttpGet]\n        [Route(\"reports/top-selling/{year:int}\")]\n",
+			"public virtual async Task<List<ProductDto>> GetTopSellingAsync(int year, [FromQuery] int count)",
+			"var result = await _appService.GetTopSellingAsync(year, count);",
+			"        [HttpPost]\n        [Route(\"import\")]\n",
+			"public virtual async Task ImportAsync([FromBody] ImportProductsDto input)",
+			"                await _appService.ImportAsync(input);\n",
+		},
+		"IProductAppService.cs": {
+			"Task<List<ProductDto>> GetTopSellingAsync(int year, int count);",
+			"Task ImportAsync(ImportProductsDto input);",
+			"using System.Threading.Tasks;",
+		},
+		"Services/CatalogModule/ProductAppService.cs": {
+			"protected IProductRepository ProductRepository => LazyServiceProvider.LazyGetRequiredService<IProductRepository>();",
+			"var result = await ProductRepository.GetTopSellingAsync(year, count);",
+			"return ObjectMapper.Map<List<Product>, List<ProductDto>>(result);",
+			"public virtual Task ImportAsync(ImportProductsDto input)",
+			"throw new NotImplementedException(\"ImportAsync is not yet implemented\");",
+		},
+	}
+	for suffix, wants := range checks {
+		content := generatedContent(t, w, suffix)
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q\n%s", suffix, want, content)
+			}
+		}
+	}
+}
+
+func TestServiceGenerator_FileProperties(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		EntityType: "FullAuditedAggregateRoot",
+		Properties: []schema.Property{
+			{Name: "Name", Type: "string"},
+			{Name: "Manual", Type: "file"},
+		},
+	})
+	sch.Solution.GenerateControllers = true
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	services := NewServiceGenerator(loader, w)
+	if err := services.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := services.GenerateController(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateController() error = %v", err)
+	}
+	dtos := NewDTOGenerator(loader, w)
+	if err := dtos.Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := dtos.GenerateAppServiceInterface(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateAppServiceInterface() error = %v", err)
+	}
+
+	checks := map[string][]string{
+		"Controllers/CatalogModule/ProductController.cs": {
+			"        [HttpPost]\n        [Route(\"{id}/manual\")]\n        [Authorize(ProductManagement.Update)]\n",
+			"public virtual async Task UploadManualAsync(Guid id, IRemoteStreamContent file)",
+			"        [HttpGet]\n        [Route(\"{id}/manual\")]\n",
+			"return await _appService.DownloadManualAsync(id);",
+		},
+		"IProductAppService.cs": {
+			"using Volo.Abp.Content;",
+			"Task UploadManualAsync(Guid id, IRemoteStreamContent file);",
+			"Task<IRemoteStreamContent> DownloadManualAsync(Guid id);",
+		},
+		"Services/CatalogModule/ProductAppService.cs": {
+			"entity.SetManual(content.ToArray());",
+			"return new RemoteStreamContent(new MemoryStream(entity.Manual), \"Manual\", \"application/octet-stream\");",
+		},
+	}
+	for suffix, wants := range checks {
+		content := generatedContent(t, w, suffix)
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q\n%s", suffix, want, content)
+			}
+		}
+	}
+
+	for _, suffix := range []string{"ProductDto.cs", "CreateProductDto.cs", "UpdateProductDto.cs"} {
+		if content := generatedContent(t, w, suffix); strings.Contains(content, "Manual") {
+			t.Errorf("%s should not expose the file property\n%s", suffix, content)
+		}
+	}
+}
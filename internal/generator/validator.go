@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/detector"
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
@@ -101,8 +102,68 @@ func (g *ValidatorGenerator) prepareValidatorData(sch *schema.Schema, entity *sc
 		"ModuleNameWithSuffix":    sch.Solution.GetModuleNameWithSuffix(),
 		"NamespaceRoot":           sch.Solution.NamespaceRoot,
 		"EntityName":              entity.Name,
+		"BaseEntity":              entity.BaseEntity,
 		"Properties":              entity.Properties,
 		"NonForeignKeyProperties": entity.GetNonForeignKeyProperties(),
+		"InputProperties":         entity.GetInputProperties(),
 		"ForeignKeyProperties":    entity.GetForeignKeyProperties(),
+		"CompareRules":            compareRules(entity),
 	}
 }
+
+// compareRule is a class-level validator rule comparing two DTO properties
+type compareRule struct {
+	Condition string // C# boolean expression over the DTO "x"
+	Message   string // Quoted C# string literal
+}
+
+// compareOperatorText describes each Compare operator in the default error message
+var compareOperatorText = map[string]string{
+	">":  "greater than",
+	">=": "greater than or equal to",
+	"<":  "less than",
+	"<=": "less than or equal to",
+	"==": "equal to",
+	"!=": "different from",
+}
+
+// compareRules returns the Compare validation rules between properties of the Create and Update
+// DTOs. A nullable side skips the comparison when it has no value.
+func compareRules(entity *schema.Entity) []compareRule {
+	inputs := make(map[string]schema.Property)
+	for _, prop := range entity.GetInputProperties() {
+		inputs[prop.Name] = prop
+	}
+
+	var rules []compareRule
+	for _, prop := range entity.GetInputProperties() {
+		for _, rule := range prop.ValidationRules {
+			if rule.Type != "Compare" {
+				continue
+			}
+			other, ok := inputs[rule.Value]
+			if !ok {
+				continue
+			}
+
+			operator := rule.Operator
+			if operator == "" {
+				operator = ">"
+			}
+			condition := fmt.Sprintf("x.%s %s x.%s", prop.Name, operator, other.Name)
+			if other.Nullable {
+				condition = fmt.Sprintf("x.%s == null || %s", other.Name, condition)
+			}
+			if prop.Nullable {
+				condition = fmt.Sprintf("x.%s == null || %s", prop.Name, condition)
+			}
+
+			message := rule.ErrorMessage
+			if message == "" {
+				message = fmt.Sprintf("%s must be %s %s", prop.Name, compareOperatorText[operator], other.Name)
+			}
+			rules = append(rules, compareRule{Condition: condition, Message: strconv.Quote(message)})
+		}
+	}
+	return rules
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func TestValidatorGenerator_MaxLengthFromConstants(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name:       "Product",
+		Properties: []schema.Property{{Name: "Name", Type: "string", MaxLength: 128}},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewEntityGenerator(loader, w).GenerateConstants(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConstants() error = %v", err)
+	}
+	if err := NewValidatorGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := NewEFCoreGenerator(loader, w).GenerateConfiguration(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("GenerateConfiguration() error = %v", err)
+	}
+
+	constants := generatedContent(t, w, "Constants/CatalogModule/ProductConstants.cs")
+	if !strings.Contains(constants, "public const int NameMaxLength = 128;") {
+		t.Errorf("constants missing NameMaxLength:\n%s", constants)
+	}
+
+	// The length is only spelled out in the constants class
+	const constant = "ProductConstants.ValidationConstants.NameMaxLength"
+	for _, file := range []struct{ suffix, want string }{
+		{"Validators/CatalogModule/CreateProductDtoValidator.cs", ".MaximumLength(" + constant + ")"},
+		{"Validators/CatalogModule/UpdateProductDtoValidator.cs", ".MaximumLength(" + constant + ")"},
+		{"Configurations/CatalogModule/ProductConfiguration.cs", ".HasMaxLength(" + constant + ")"},
+	} {
+		content := generatedContent(t, w, file.suffix)
+		if !strings.Contains(content, file.want) {
+			t.Errorf("%s missing %q:\n%s", file.suffix, file.want, content)
+		}
+		if strings.Contains(content, "128") {
+			t.Errorf("%s inlines the max length:\n%s", file.suffix, content)
+		}
+	}
+}
+
+func TestValidatorGenerator_CompareRules(t *testing.T) {
+	sch := newTestSchema(t, schema.Entity{
+		Name: "Promotion",
+		Properties: []schema.Property{
+			{Name: "StartDate", Type: "DateTime"},
+			{Name: "EndDate", Type: "DateTime", ValidationRules: []schema.ValidationRule{
+				{Type: "Compare", Value: "StartDate"},
+			}},
+			{Name: "MinQuantity", Type: "int"},
+			{Name: "MaxQuantity", Type: "int", Nullable: true, ValidationRules: []schema.ValidationRule{
+				{Type: "Compare", Value: "MinQuantity", Operator: ">=", ErrorMessage: `Use a "max" of at least the min`},
+			}},
+		},
+	})
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewValidatorGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, suffix := range []string{
+		"Validators/CatalogModule/CreatePromotionDtoValidator.cs",
+		"Validators/CatalogModule/UpdatePromotionDtoValidator.cs",
+	} {
+		content := generatedContent(t, w, suffix)
+		for _, want := range []string{
+			"RuleFor(x => x)\n                .Must(x => x.EndDate > x.StartDate)\n                .WithMessage(\"EndDate must be greater than StartDate\");",
+			".Must(x => x.MaxQuantity == null || x.MaxQuantity >= x.MinQuantity)",
+			`.WithMessage("Use a \"max\" of at least the min");`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q:\n%s", suffix, want, content)
+			}
+		}
+	}
+}
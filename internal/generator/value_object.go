@@ -69,6 +69,8 @@ func (g *ValueObjectGenerator) Generate(sch *schema.Schema, entity *schema.Entit
 		"EntityName":           entity.Name,
 		"Properties":           entity.Properties,
 		"Config":               config,
+		"IsImmutable":          entity.IsImmutableValueObject(),
+		"HasFactory":           config.FactoryMethod != "",
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
@@ -103,6 +105,7 @@ func (g *ValueObjectGenerator) GenerateFactory(sch *schema.Schema, entity *schem
 		"FactoryMethod":        entity.ValueObjectConfig.FactoryMethod,
 		"Properties":           entity.Properties,
 		"ValidationRules":      entity.ValueObjectConfig.ValidationRules,
+		"IsImmutable":          entity.IsImmutableValueObject(),
 		"TargetFramework":      sch.Solution.TargetFramework,
 	}
 
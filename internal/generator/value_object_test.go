@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/schema"
+)
+
+func newAddressValueObject(config *schema.ValueObjectConfig) schema.Entity {
+	return schema.Entity{
+		Name:       "Address",
+		EntityType: "ValueObject",
+		Properties: []schema.Property{
+			{Name: "Street", Type: "string"},
+			{Name: "City", Type: "string"},
+		},
+		ValueObjectConfig: config,
+	}
+}
+
+func TestValueObjectGenerator_ImmutableHasNoPublicSetters(t *testing.T) {
+	sch := newTestSchema(t, newAddressValueObject(&schema.ValueObjectConfig{IsImmutable: true, FactoryMethod: "Create"}))
+	entity := &sch.Entities[0]
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+	gen := NewValueObjectGenerator(loader, w)
+
+	if err := gen.Generate(sch, entity, paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := gen.GenerateFactory(sch, entity, paths); err != nil {
+		t.Fatalf("GenerateFactory() error = %v", err)
+	}
+	if err := NewServiceGenerator(loader, w).GenerateAutoMapperProfile(sch, entity, paths); err != nil {
+		t.Fatalf("GenerateAutoMapperProfile() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Address.cs")
+	if strings.Contains(content, "set;") {
+		t.Errorf("immutable value object has setters:\n%s", content)
+	}
+	for _, want := range []string{
+		"public string Street { get; private init; }",
+		"internal Address(string street, string city)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("immutable value object missing %q:\n%s", want, content)
+		}
+	}
+
+	factory := generatedContent(t, w, "Entities/CatalogModule/AddressFactory.cs")
+	if !strings.Contains(factory, "public static Address Create(string street, string city)") {
+		t.Errorf("factory does not create the value object:\n%s", factory)
+	}
+
+	profile := generatedContent(t, w, "AutoMapper/CatalogModule/AddressProfile.cs")
+	if strings.Contains(profile, "CreateMap<AddressDto, Address>()") {
+		t.Errorf("immutable value object must not be mapped from its DTO:\n%s", profile)
+	}
+}
+
+func TestValueObjectGenerator_MutableHasPublicSetters(t *testing.T) {
+	sch := newTestSchema(t, newAddressValueObject(&schema.ValueObjectConfig{IsImmutable: false}))
+	paths := newTestLayerPaths(t)
+	loader, w := newTestGenerators()
+
+	if err := NewValueObjectGenerator(loader, w).Generate(sch, &sch.Entities[0], paths); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := generatedContent(t, w, "Entities/CatalogModule/Address.cs")
+	for _, want := range []string{
+		"public string Street { get; set; }",
+		"public Address(string street, string city)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("mutable value object missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package merger
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -106,58 +107,135 @@ func (m *ASTMerger) mergeClasses(original string, existingClass *CSharpClass, ne
 	return result, nil
 }
 
-// mergeProperties merges properties from new class into existing
-func (m *ASTMerger) mergeProperties(content string, existing *CSharpClass, new *CSharpClass) string {
-	var toAdd []string
+// memberPattern matches the first line of a constructor or method declaration
+var memberPattern = regexp.MustCompile(`^\s*(?:(?:public|protected|private|internal|static|virtual|override|async|abstract|sealed|new)\s+)+(?:[\w<>\[\],.?]+\s+)?\w+\s*\(`)
 
-	// Find properties that don't exist in existing class
-	for _, newProp := range new.Properties {
-		if !m.parser.HasProperty(existing, newProp.Name) {
-			toAdd = append(toAdd, "    "+strings.TrimSpace(newProp.RawContent))
+// findClassLines returns the line indexes of the named class's opening and closing braces, or -1 when not found.
+// The class is matched by its exact name so that e.g. ProductFactory is never mistaken for Product.
+func findClassLines(lines []string, className string) (openLine int, closeLine int) {
+	classPattern := regexp.MustCompile(`\bclass\s+` + regexp.QuoteMeta(className) + `\b`)
+
+	openLine, closeLine = -1, -1
+	braceCount := 0
+	for i, line := range lines {
+		if openLine == -1 {
+			if !classPattern.MatchString(line) {
+				continue
+			}
+			// The opening brace may be on the declaration line or a following one
+			for j := i; j < len(lines); j++ {
+				if strings.Contains(lines[j], "{") {
+					openLine = j
+					break
+				}
+			}
+			if openLine == -1 {
+				return -1, -1
+			}
+		}
+		if i < openLine {
+			continue
+		}
+
+		braceCount += strings.Count(line, "{")
+		braceCount -= strings.Count(line, "}")
+		if braceCount == 0 {
+			closeLine = i
+			break
 		}
 	}
 
-	if len(toAdd) == 0 {
-		return content
+	if closeLine == -1 {
+		return -1, -1
 	}
+	return openLine, closeLine
+}
+
+// indentLines re-indents a (possibly multi-line) member to the given indentation
+func indentLines(raw string, indent string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(raw), "\n") {
+		lines = append(lines, indent+strings.TrimSpace(line))
+	}
+	return lines
+}
+
+// leadingWhitespace returns the indentation of a line
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
 
-	// Find insertion point (after last property or before first method)
+// mergeProperties merges properties from new class into existing.
+// New scalar properties are placed after the last scalar property and new navigation
+// properties after the last property, both before any constructor or method.
+func (m *ASTMerger) mergeProperties(content string, existing *CSharpClass, new *CSharpClass) string {
 	lines := strings.Split(content, "\n")
-	insertIndex := -1
+	openLine, closeLine := findClassLines(lines, existing.Name)
+	if openLine == -1 {
+		return content
+	}
 
-	// Find last property
-	for i := len(lines) - 1; i >= 0; i-- {
-		if strings.Contains(lines[i], "{ get; set; }") {
-			insertIndex = i + 1
+	// Properties are declared before the first constructor or method
+	firstMemberLine := closeLine
+	for i := openLine + 1; i < closeLine; i++ {
+		if memberPattern.MatchString(lines[i]) {
+			firstMemberLine = i
 			break
 		}
 	}
 
-	// If no properties found, insert after class opening brace
-	if insertIndex == -1 {
-		for i, line := range lines {
-			if strings.Contains(line, "class "+existing.Name) {
-				// Find opening brace
-				for j := i; j < len(lines); j++ {
-					if strings.Contains(lines[j], "{") {
-						insertIndex = j + 1
-						break
-					}
-				}
-				break
-			}
+	// Locate the existing properties of this class by line (1-based in CSharpProperty.Line)
+	existingNames := make(map[string]bool)
+	lastScalar, lastProperty := openLine, openLine
+	indent := leadingWhitespace(lines[openLine]) + "    "
+	for _, prop := range m.parser.parseProperties(content) {
+		line := prop.Line - 1
+		if line <= openLine || line >= closeLine {
+			continue
+		}
+		existingNames[prop.Name] = true
+		if line >= firstMemberLine {
+			continue
+		}
+		if !prop.IsNavigation() {
+			lastScalar = line
+		}
+		if line > lastProperty {
+			lastProperty = line
+		}
+		indent = leadingWhitespace(lines[line])
+	}
+
+	var scalars, navigations []string
+	for _, newProp := range new.Properties {
+		if existingNames[newProp.Name] || m.parser.HasProperty(existing, newProp.Name) {
+			continue
+		}
+		existingNames[newProp.Name] = true
+		if newProp.IsNavigation() {
+			navigations = append(navigations, indentLines(newProp.RawContent, indent)...)
+		} else {
+			scalars = append(scalars, indentLines(newProp.RawContent, indent)...)
 		}
 	}
 
-	if insertIndex == -1 {
+	if len(scalars) == 0 && len(navigations) == 0 {
 		return content
 	}
 
-	// Insert properties
-	newLines := make([]string, 0, len(lines)+len(toAdd))
-	newLines = append(newLines, lines[:insertIndex]...)
-	newLines = append(newLines, toAdd...)
-	newLines = append(newLines, lines[insertIndex:]...)
+	if lastProperty < lastScalar {
+		lastProperty = lastScalar
+	}
+
+	insertions := map[int][]string{}
+	insertions[lastScalar] = append(insertions[lastScalar], scalars...)
+	insertions[lastProperty] = append(insertions[lastProperty], navigations...)
+
+	newLines := make([]string, 0, len(lines)+len(scalars)+len(navigations))
+	for i, line := range lines {
+		newLines = append(newLines, line)
+		newLines = append(newLines, insertions[i]...)
+	}
 
 	return strings.Join(newLines, "\n")
 }
@@ -174,7 +252,7 @@ func (m *ASTMerger) mergeMethods(content string, existing *CSharpClass, new *CSh
 		}
 
 		if !m.parser.HasMethod(existing, newMethod.Signature) {
-			toAdd = append(toAdd, "\n"+strings.TrimSpace(newMethod.RawContent))
+			toAdd = append(toAdd, strings.TrimSpace(newMethod.RawContent))
 		}
 	}
 
@@ -184,28 +262,14 @@ func (m *ASTMerger) mergeMethods(content string, existing *CSharpClass, new *CSh
 
 	// Find insertion point (before closing class brace)
 	lines := strings.Split(content, "\n")
-	insertIndex := -1
-
-	// Find closing brace of class
-	braceCount := 0
-	inClass := false
-
-	for i, line := range lines {
-		if strings.Contains(line, "class "+existing.Name) {
-			inClass = true
-		}
-		if inClass {
-			braceCount += strings.Count(line, "{")
-			braceCount -= strings.Count(line, "}")
-			if braceCount == 0 && strings.TrimSpace(line) == "}" {
-				insertIndex = i
-				break
-			}
-		}
+	openLine, insertIndex := findClassLines(lines, existing.Name)
+	if openLine == -1 {
+		return content
 	}
 
-	if insertIndex == -1 {
-		return content
+	indent := leadingWhitespace(lines[openLine]) + "    "
+	for i := range toAdd {
+		toAdd[i] = "\n" + indent + toAdd[i]
 	}
 
 	// Insert methods
@@ -0,0 +1,132 @@
+package merger
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/prompts"
+)
+
+// PendingMerge is an existing file whose merge was computed without prompting,
+// waiting for the decision covering the whole batch
+type PendingMerge struct {
+	Path       string
+	NewContent string     // Generated content
+	Mergeable  bool       // False when the file type does not support merging
+	Conflicts  []Conflict // Conflicts left by the merge
+	result     *mergeResult
+}
+
+// BatchResult is the outcome of a pending merge once the batch has been resolved
+type BatchResult struct {
+	Path    string
+	Content string
+	Write   bool // False when the file is skipped
+}
+
+// PreviewMerge computes the merge of newContent into the existing file at path without
+// prompting, so every pending merge can be summarized before any decision is asked for
+func (e *Engine) PreviewMerge(path string, newContent string) (*PendingMerge, error) {
+	fileExists, err := e.detector.CheckFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &PendingMerge{Path: path, NewContent: newContent}
+	if !fileExists.Exists || !e.detector.CanMerge(fileExists.FileType) {
+		return pending, nil
+	}
+
+	result, err := e.computeMerge(path, fileExists.FileType, newContent)
+	if err != nil {
+		return nil, fmt.Errorf("merge failed for %s: %w", path, err)
+	}
+	pending.Mergeable = true
+	pending.Conflicts = result.conflicts
+	pending.result = result
+	return pending, nil
+}
+
+// ResolveBatch prints one summary of the pending merges, asks for a single decision
+// (unless merge-all mode already made it) and returns the content to write for each file
+func (e *Engine) ResolveBatch(pending []*PendingMerge) ([]BatchResult, error) {
+	conflicted := printBatchSummary(e.out, pending)
+
+	var decision MergeDecision
+	switch {
+	case e.MergeAll && e.MergeMode != "":
+		decision = e.MergeMode
+	case e.NonInteractive:
+		return nil, fmt.Errorf("merge decision required for %d file(s) but prompts are disabled in non-interactive mode (use --merge-all)", len(pending))
+	default:
+		var err error
+		decision, err = prompts.PromptBatchMergeDecision(len(pending), conflicted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]BatchResult, 0, len(pending))
+	for _, p := range pending {
+		result := BatchResult{Path: p.Path}
+
+		switch decision {
+		case MergeDecisionOverwrite:
+			result.Content, result.Write = p.NewContent, true
+
+		case MergeDecisionMerge:
+			if p.Mergeable {
+				merged, err := e.finishMerge(p.Path, p.result)
+				if err != nil {
+					return nil, err
+				}
+				result.Content, result.Write = merged, true
+			}
+
+		case MergeDecisionMergeClean:
+			if p.Mergeable && len(p.Conflicts) == 0 {
+				merged, err := e.finishMerge(p.Path, p.result)
+				if err != nil {
+					return nil, err
+				}
+				result.Content, result.Write = merged, true
+			}
+
+		case MergeDecisionPerFile, MergeDecisionShowDiff:
+			content, write, err := e.MergeFile(p.Path, p.NewContent)
+			if err != nil {
+				return nil, err
+			}
+			result.Content, result.Write = content, write
+		}
+
+		if !result.Write && e.Verbose {
+			fmt.Fprintf(e.out, "[SKIP] %s\n", p.Path)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// printBatchSummary lists each pending merge with its status and returns the number of
+// files with conflicts
+func printBatchSummary(out io.Writer, pending []*PendingMerge) int {
+	conflicted := 0
+	fmt.Fprintf(out, "\n=== Pending merges (%d) ===\n", len(pending))
+	for _, p := range pending {
+		switch {
+		case !p.Mergeable:
+			fmt.Fprintf(out, "[NO MERGE]  %s (file type doesn't support merging)\n", p.Path)
+		case len(p.Conflicts) > 0:
+			conflicted++
+			fmt.Fprintf(out, "[CONFLICTS] %s - %d conflict(s)\n", p.Path, len(p.Conflicts))
+			for _, conflict := range p.Conflicts {
+				fmt.Fprintf(out, "              %s\n", conflict.Description)
+			}
+		default:
+			fmt.Fprintf(out, "[CLEAN]     %s\n", p.Path)
+		}
+	}
+	fmt.Fprintln(out)
+	return conflicted
+}
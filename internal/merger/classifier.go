@@ -25,6 +25,7 @@ const (
 	FileTypeLocalizationJSON
 	FileTypeAutoMapperProfile
 	FileTypeEventHandler
+	FileTypeEnumExtensions
 )
 
 // MergeStrategy represents the strategy to use for merging
@@ -129,6 +130,11 @@ func (c *Classifier) ClassifyFile(path string) FileType {
 		return FileTypeEventHandler
 	}
 
+	// Enum extension and lookup files
+	if strings.HasSuffix(filename, "Extensions.cs") && (strings.Contains(path, "/Enums/") || strings.Contains(path, "\\Enums\\")) {
+		return FileTypeEnumExtensions
+	}
+
 	// Entity files (in Entities folder or inheriting from Entity/AggregateRoot)
 	if strings.Contains(path, "/Entities/") || strings.Contains(path, "\\Entities\\") {
 		return FileTypeEntity
@@ -143,7 +149,9 @@ func (c *Classifier) GetMergeStrategy(fileType FileType) MergeStrategy {
 	case FileTypePermissions,
 		FileTypePermissionProvider,
 		FileTypeDbContext,
-		FileTypeIDbContext:
+		FileTypeIDbContext,
+		FileTypeAutoMapperProfile,
+		FileTypeEnumExtensions:
 		return MergeStrategyPattern
 
 	case FileTypeEntity,
@@ -196,6 +204,8 @@ func (c *Classifier) GetFileTypeName(fileType FileType) string {
 		return "AutoMapper Profile"
 	case FileTypeEventHandler:
 		return "Event Handler"
+	case FileTypeEnumExtensions:
+		return "Enum Extensions"
 	default:
 		return "Unknown"
 	}
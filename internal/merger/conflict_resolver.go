@@ -162,7 +162,7 @@ func (r *ConflictResolver) renameClass(code string) string {
 func (r *ConflictResolver) FormatConflict(conflict Conflict, index int) string {
 	var builder strings.Builder
 
-	builder.WriteString(fmt.Sprintf("\n⚠️  Conflict %d: %s\n", index+1, conflict.Description))
+	builder.WriteString(fmt.Sprintf("\nConflict %d: %s\n", index+1, conflict.Description))
 
 	if conflict.Line > 0 {
 		builder.WriteString(fmt.Sprintf("Line: %d\n", conflict.Line))
@@ -20,6 +20,7 @@ type CSharpClass struct {
 type CSharpProperty struct {
 	Name       string
 	Type       string
+	Modifiers  string // e.g. "virtual"
 	Attributes []string
 	GetSet     string
 	RawContent string
@@ -75,24 +76,33 @@ func (p *CSharpParser) ParseClass(content string) (*CSharpClass, error) {
 	return class, nil
 }
 
+// propertyPattern matches auto-properties, including restricted setters and initializers
+var propertyPattern = regexp.MustCompile(`(?m)^\s*(?:\[([^\]]+)\]\s*)*public\s+((?:(?:virtual|override|new|required)\s+)*)(\w+(?:<[^>]+>)?(?:\?)?)\s+(\w+)\s*\{\s*(get;\s*(?:(?:private|protected|internal)\s+)?(?:set|init);|get;)\s*\}(?:[ \t]*=[^;\n]+;)?`)
+
 // parseProperties extracts all properties from C# code
 func (p *CSharpParser) parseProperties(content string) []CSharpProperty {
 	var properties []CSharpProperty
 
-	// Pattern for properties with get/set
-	propertyPattern := regexp.MustCompile(`(?m)^\s*(?:\[([^\]]+)\]\s*)*public\s+(\w+(?:<[^>]+>)?(?:\?)?)\s+(\w+)\s*\{\s*(get;\s*set;|get;\s*private\s+set;|get;\s*init;)\s*\}`)
+	matches := propertyPattern.FindAllStringSubmatchIndex(content, -1)
+	for _, loc := range matches {
+		group := func(i int) string {
+			if loc[2*i] < 0 {
+				return ""
+			}
+			return content[loc[2*i]:loc[2*i+1]]
+		}
 
-	matches := propertyPattern.FindAllStringSubmatch(content, -1)
-	for _, match := range matches {
 		prop := CSharpProperty{
-			Type:       match[2],
-			Name:       match[3],
-			GetSet:     match[4],
-			RawContent: match[0],
+			Modifiers:  strings.TrimSpace(group(2)),
+			Type:       group(3),
+			Name:       group(4),
+			GetSet:     group(5),
+			RawContent: group(0),
+			Line:       strings.Count(content[:loc[8]], "\n") + 1,
 		}
 
-		if match[1] != "" {
-			prop.Attributes = strings.Split(match[1], "][")
+		if attributes := group(1); attributes != "" {
+			prop.Attributes = strings.Split(attributes, "][")
 		}
 
 		properties = append(properties, prop)
@@ -116,7 +126,16 @@ func (p *CSharpParser) parseMethods(content string) []CSharpMethod {
 			continue
 		}
 
-		body := p.extractMethodBody(content[methodIndex:])
+		// Expression-bodied members end at their semicolon rather than at a brace block
+		var body string
+		rest := content[methodIndex+len(match[0]):]
+		if strings.HasPrefix(strings.TrimSpace(rest), "=>") {
+			if end := strings.Index(rest, ";"); end != -1 {
+				body = rest[:end+1]
+			}
+		} else {
+			body = p.extractMethodBody(content[methodIndex:])
+		}
 
 		method := CSharpMethod{
 			ReturnType: match[2],
@@ -182,6 +201,20 @@ func (p *CSharpParser) createMethodSignature(returnType, name, parameters string
 	return returnType + " " + name + "(" + strings.Join(paramTypes, ",") + ")"
 }
 
+// IsNavigation reports whether the property is a navigation (a virtual reference or a collection)
+// rather than a scalar property
+func (prop CSharpProperty) IsNavigation() bool {
+	if strings.Contains(prop.Modifiers, "virtual") {
+		return true
+	}
+	for _, collection := range []string{"ICollection<", "IList<", "List<", "IEnumerable<", "HashSet<", "IReadOnlyCollection<"} {
+		if strings.HasPrefix(prop.Type, collection) {
+			return true
+		}
+	}
+	return false
+}
+
 // FindProperty finds a property by name in a class
 func (p *CSharpParser) FindProperty(class *CSharpClass, propertyName string) *CSharpProperty {
 	for i := range class.Properties {
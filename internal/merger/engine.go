@@ -2,6 +2,7 @@ package merger
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/prompts"
@@ -15,6 +16,9 @@ const (
 	MergeDecisionMerge     = prompts.MergeDecisionMerge
 	MergeDecisionSkip      = prompts.MergeDecisionSkip
 	MergeDecisionShowDiff  = prompts.MergeDecisionShowDiff
+
+	MergeDecisionMergeClean = prompts.MergeDecisionMergeClean
+	MergeDecisionPerFile    = prompts.MergeDecisionPerFile
 )
 
 // Engine orchestrates merge operations
@@ -27,10 +31,13 @@ type Engine struct {
 	conflictResolver *ConflictResolver
 
 	// Configuration
-	Force     bool
-	MergeAll  bool
-	MergeMode MergeDecision
-	Verbose   bool
+	Force          bool
+	MergeAll       bool
+	MergeMode      MergeDecision
+	Verbose        bool
+	NonInteractive bool // Fail instead of prompting for decisions or conflicts
+
+	out io.Writer // Receives progress messages and diffs
 }
 
 // NewEngine creates a new merge engine
@@ -44,9 +51,15 @@ func NewEngine(force bool, verbose bool) *Engine {
 		conflictResolver: NewConflictResolver(),
 		Force:            force,
 		Verbose:          verbose,
+		out:              os.Stdout,
 	}
 }
 
+// SetOutput sets where progress messages and diffs are printed
+func (e *Engine) SetOutput(out io.Writer) {
+	e.out = out
+}
+
 // MergeFile merges a new file with an existing file if it exists
 func (e *Engine) MergeFile(path string, newContent string) (string, bool, error) {
 	// Check if file exists
@@ -63,7 +76,7 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 	// If force mode, overwrite
 	if e.Force {
 		if e.Verbose {
-			fmt.Printf("[OVERWRITE] %s\n", path)
+			fmt.Fprintf(e.out, "[OVERWRITE] %s\n", path)
 		}
 		return newContent, true, nil
 	}
@@ -72,7 +85,7 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 	if !e.detector.CanMerge(fileExists.FileType) {
 		// File type doesn't support merging
 		if e.Verbose {
-			fmt.Printf("[SKIP] %s (file type doesn't support merging)\n", path)
+			fmt.Fprintf(e.out, "[SKIP] %s (file type doesn't support merging)\n", path)
 		}
 		return "", false, nil
 	}
@@ -81,6 +94,8 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 	var decision MergeDecision
 	if e.MergeAll && e.MergeMode != "" {
 		decision = e.MergeMode
+	} else if e.NonInteractive {
+		return "", false, fmt.Errorf("merge decision required for %s but prompts are disabled in non-interactive mode (use --merge-all)", path)
 	} else {
 		fileTypeName := e.classifier.GetFileTypeName(fileExists.FileType)
 		decision, err = prompts.PromptMergeDecision(path, fileTypeName)
@@ -88,6 +103,14 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 			return "", false, err
 		}
 
+		// Showing the diff is not a final decision; ask again once it has been shown
+		if decision == MergeDecisionShowDiff {
+			decision, err = e.promptAfterDiff(path, newContent)
+			if err != nil {
+				return "", false, err
+			}
+		}
+
 		// Ask if user wants to apply to all
 		if !e.MergeAll {
 			applyToAll, err := prompts.PromptMergeAll()
@@ -101,27 +124,31 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 		}
 	}
 
+	// A merge-all mode of "show diff" still needs a decision per file
+	if decision == MergeDecisionShowDiff {
+		if e.NonInteractive {
+			return "", false, fmt.Errorf("merge decision required for %s but prompts are disabled in non-interactive mode (use --merge-all)", path)
+		}
+		decision, err = e.promptAfterDiff(path, newContent)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
 	// Handle user decision
 	switch decision {
 	case MergeDecisionOverwrite:
 		if e.Verbose {
-			fmt.Printf("[OVERWRITE] %s\n", path)
+			fmt.Fprintf(e.out, "[OVERWRITE] %s\n", path)
 		}
 		return newContent, true, nil
 
 	case MergeDecisionSkip:
 		if e.Verbose {
-			fmt.Printf("[SKIP] %s\n", path)
+			fmt.Fprintf(e.out, "[SKIP] %s\n", path)
 		}
 		return "", false, nil
 
-	case MergeDecisionShowDiff:
-		// Show diff and prompt again
-		// For now, fall through to merge decision
-		// TODO: Implement diff display
-		fmt.Println("Diff display not yet implemented, falling back to merge")
-		return e.performMerge(path, fileExists, newContent)
-
 	case MergeDecisionMerge:
 		return e.performMerge(path, fileExists, newContent)
 
@@ -130,18 +157,72 @@ func (e *Engine) MergeFile(path string, newContent string) (string, bool, error)
 	}
 }
 
+// promptAfterDiff prints the diff between the existing file and the new content,
+// then prompts for the merge, overwrite or skip decision
+func (e *Engine) promptAfterDiff(path string, newContent string) (MergeDecision, error) {
+	existingContent, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read existing file: %w", err)
+	}
+
+	diff := UnifiedDiff(path+" (existing)", path+" (generated)", string(existingContent), newContent)
+	if diff == "" {
+		fmt.Fprintf(e.out, "\nNo differences: %s is identical to the generated content\n\n", path)
+	} else {
+		fmt.Fprintln(e.out)
+		fmt.Fprint(e.out, diff)
+		fmt.Fprintln(e.out)
+	}
+
+	return prompts.PromptMergeDecisionAfterDiff(path)
+}
+
 // performMerge performs the actual merge operation
 func (e *Engine) performMerge(path string, fileExists *FileExistence, newContent string) (string, bool, error) {
+	result, err := e.computeMerge(path, fileExists.FileType, newContent)
+	if err != nil {
+		return "", false, err
+	}
+
+	merged, err := e.finishMerge(path, result)
+	if err != nil {
+		return "", false, err
+	}
+	return merged, true, nil
+}
+
+// mergeResult is a computed merge whose conflicts have not been resolved yet
+type mergeResult struct {
+	merged    string
+	existing  string     // Existing content without its generated header
+	generated string     // New content without its generated header
+	header    string     // Generated header to re-apply to the result
+	format    LineFormat // Line endings of the existing file, restored on the result
+	conflicts []Conflict
+}
+
+// computeMerge merges newContent into the existing file at path without prompting
+func (e *Engine) computeMerge(path string, fileType FileType, newContent string) (*mergeResult, error) {
 	// Read existing content
 	existingContent, err := os.ReadFile(path)
 	if err != nil {
-		return "", false, fmt.Errorf("failed to read existing file: %w", err)
+		return nil, fmt.Errorf("failed to read existing file: %w", err)
 	}
 
-	existing := string(existingContent)
+	// Merge with LF line endings; the existing file's line endings are restored afterwards
+	format := DetectLineFormat(string(existingContent))
+
+	// Keep the generated header out of the merge; the new header (or the existing one,
+	// if the new content has none) is re-applied to the merged result.
+	existingHeader, existing := SplitGeneratedHeader(NormalizeLineEndings(string(existingContent)))
+	newHeader, newContent := SplitGeneratedHeader(NormalizeLineEndings(newContent))
+	header := newHeader
+	if header == "" {
+		header = existingHeader
+	}
 
 	// Select merge strategy
-	strategy := e.classifier.GetMergeStrategy(fileExists.FileType)
+	strategy := e.classifier.GetMergeStrategy(fileType)
 
 	var merged string
 	var conflicts []Conflict
@@ -149,46 +230,70 @@ func (e *Engine) performMerge(path string, fileExists *FileExistence, newContent
 	// Perform merge based on strategy
 	switch strategy {
 	case MergeStrategyPattern:
-		merged, conflicts, err = e.patternMerger.Merge(existing, newContent, fileExists.FileType)
+		merged, conflicts, err = e.patternMerger.Merge(existing, newContent, fileType)
 
 	case MergeStrategyAST:
-		merged, conflicts, err = e.astMerger.Merge(existing, newContent, fileExists.FileType)
+		merged, conflicts, err = e.astMerger.Merge(existing, newContent, fileType)
 
 	case MergeStrategyJSON:
 		merged, conflicts, err = e.jsonMerger.Merge(existing, newContent)
 
 	default:
-		return "", false, fmt.Errorf("unsupported merge strategy for file type %v", fileExists.FileType)
+		return nil, fmt.Errorf("unsupported merge strategy for file type %v", fileType)
 	}
 
 	if err != nil {
-		return "", false, fmt.Errorf("merge failed: %w", err)
+		return nil, fmt.Errorf("merge failed: %w", err)
 	}
 
+	return &mergeResult{
+		merged:    merged,
+		existing:  existing,
+		generated: newContent,
+		header:    header,
+		format:    format,
+		conflicts: conflicts,
+	}, nil
+}
+
+// finishMerge prompts for the resolution of any conflicts and returns the merged content
+func (e *Engine) finishMerge(path string, result *mergeResult) (string, error) {
+	merged := result.merged
+	conflicts := result.conflicts
+
 	// Handle conflicts if any
 	if len(conflicts) > 0 {
 		if e.Verbose {
-			fmt.Printf("[CONFLICTS] %s - %d conflict(s) detected\n", path, len(conflicts))
+			fmt.Fprintf(e.out, "[CONFLICTS] %s - %d conflict(s) detected\n", path, len(conflicts))
+		}
+
+		if e.NonInteractive {
+			return "", fmt.Errorf("%d merge conflict(s) in %s require resolution but prompts are disabled in non-interactive mode", len(conflicts), path)
 		}
 
 		// Prompt user to resolve conflicts
 		resolutions, err := prompts.PromptConflictBatch(conflicts)
 		if err != nil {
-			return "", false, fmt.Errorf("failed to resolve conflicts: %w", err)
+			return "", fmt.Errorf("failed to resolve conflicts: %w", err)
 		}
 
 		// Apply resolutions
-		merged, err = e.conflictResolver.ResolveConflicts(existing, conflicts, resolutions, newContent)
+		merged, err = e.conflictResolver.ResolveConflicts(result.existing, conflicts, resolutions, result.generated)
 		if err != nil {
-			return "", false, fmt.Errorf("failed to apply conflict resolutions: %w", err)
+			return "", fmt.Errorf("failed to apply conflict resolutions: %w", err)
 		}
 	}
 
+	if result.header != "" {
+		merged = ApplyGeneratedHeader(merged, result.header)
+	}
+	merged = result.format.Apply(merged)
+
 	if e.Verbose {
-		fmt.Printf("[MERGED] %s\n", path)
+		fmt.Fprintf(e.out, "[MERGED] %s\n", path)
 	}
 
-	return merged, true, nil
+	return merged, nil
 }
 
 // SetMergeAll sets merge-all mode with a specific decision
@@ -0,0 +1,40 @@
+package merger
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedHeaderMarker prefixes the header comment abp-gen writes at the top of generated C# files
+const GeneratedHeaderMarker = "// <auto-generated>"
+
+// SupportsGeneratedHeader reports whether a generated header can be written to the file at path.
+// Only C# files carry the header; JSON has no comment syntax.
+func SupportsGeneratedHeader(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".cs")
+}
+
+// SplitGeneratedHeader separates a leading generated header line from the rest of the content.
+// It returns an empty header and the original content when no header is present.
+func SplitGeneratedHeader(content string) (header string, body string) {
+	if !strings.HasPrefix(content, GeneratedHeaderMarker) {
+		return "", content
+	}
+
+	idx := strings.Index(content, "\n")
+	if idx == -1 {
+		return strings.TrimRight(content, "\r"), ""
+	}
+
+	return strings.TrimRight(content[:idx], "\r"), content[idx+1:]
+}
+
+// ApplyGeneratedHeader replaces any existing generated header in content with header.
+// An empty header removes the existing one. The header line ends like the content's lines.
+func ApplyGeneratedHeader(content string, header string) string {
+	_, body := SplitGeneratedHeader(content)
+	if header == "" {
+		return body
+	}
+	return header + DetectLineFormat(body).LineEnding + body
+}
@@ -0,0 +1,45 @@
+package merger
+
+import "strings"
+
+// LineFormat records the line endings of a file so rewritten content can keep them
+type LineFormat struct {
+	LineEnding      string // "\r\n" or "\n"
+	TrailingNewline bool   // Whether the content ends with a line ending
+}
+
+// DetectLineFormat returns the dominant line ending of content and whether it ends with a newline.
+// Content with as many LF as CRLF line endings, or none at all, is treated as LF.
+func DetectLineFormat(content string) LineFormat {
+	crlf := strings.Count(content, "\r\n")
+	lf := strings.Count(content, "\n") - crlf
+
+	format := LineFormat{LineEnding: "\n", TrailingNewline: content == "" || strings.HasSuffix(content, "\n")}
+	if crlf > lf {
+		format.LineEnding = "\r\n"
+	}
+	return format
+}
+
+// NormalizeLineEndings converts CRLF line endings to LF
+func NormalizeLineEndings(content string) string {
+	return strings.ReplaceAll(content, "\r\n", "\n")
+}
+
+// Apply converts the line endings of content to the format's and adds or removes the
+// trailing newline to match it
+func (f LineFormat) Apply(content string) string {
+	content = NormalizeLineEndings(content)
+	if f.TrailingNewline {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+	} else {
+		content = strings.TrimRight(content, "\n")
+	}
+
+	if f.LineEnding == "\r\n" {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return content
+}
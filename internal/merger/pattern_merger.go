@@ -27,6 +27,10 @@ func (m *PatternMerger) Merge(existing string, newContent string, fileType FileT
 		return m.mergePermissionProvider(existing, newContent)
 	case FileTypeDbContext, FileTypeIDbContext:
 		return m.mergeDbContext(existing, newContent)
+	case FileTypeAutoMapperProfile:
+		return m.mergeAutoMapperProfile(existing, newContent)
+	case FileTypeEnumExtensions:
+		return m.mergeEnumExtensions(existing, newContent)
 	default:
 		return "", nil, fmt.Errorf("unsupported file type for pattern merging: %v", fileType)
 	}
@@ -151,8 +155,123 @@ func (m *PatternMerger) mergeDbContext(existing string, newContent string) (stri
 	return existing, conflicts, nil
 }
 
+// createMapPattern matches a CreateMap<TSource, TDestination>() statement, including chained calls
+var createMapPattern = regexp.MustCompile(`CreateMap<([^>]+)>\s*\(\s*\)[^;]*;`)
+
+// mergeAutoMapperProfile adds CreateMap statements that the existing profile does not declare yet.
+// Maps already present for the same source and destination types are left untouched.
+func (m *PatternMerger) mergeAutoMapperProfile(existing string, newContent string) (string, []Conflict, error) {
+	existingMaps := make(map[string]bool)
+	for _, match := range createMapPattern.FindAllStringSubmatch(existing, -1) {
+		existingMaps[m.normalizeTypeArguments(match[1])] = true
+	}
+
+	var toAdd []string
+	for _, match := range createMapPattern.FindAllStringSubmatch(newContent, -1) {
+		key := m.normalizeTypeArguments(match[1])
+		if existingMaps[key] {
+			continue
+		}
+		existingMaps[key] = true
+		toAdd = append(toAdd, match[0])
+	}
+
+	if len(toAdd) == 0 {
+		return existing, nil, nil
+	}
+
+	merged, err := m.insertIntoProfileConstructor(existing, toAdd)
+	if err != nil {
+		return "", nil, err
+	}
+	return merged, nil, nil
+}
+
+// staticMethodPattern matches a public static method signature up to its opening brace
+var staticMethodPattern = regexp.MustCompile(`(?m)^[ \t]*public\s+static\s+[^=;{}()]+?\s+(\w+)\s*\(([^)]*)\)\s*\{`)
+
+// usingPattern matches a using directive on its own line
+var usingPattern = regexp.MustCompile(`(?m)^using\s+[\w.]+\s*;[ \t]*$`)
+
+// mergeEnumExtensions adds the static methods and using directives of a regenerated enum extensions
+// class that the existing file does not declare yet. Existing methods, including edited ones, are kept.
+func (m *PatternMerger) mergeEnumExtensions(existing string, newContent string) (string, []Conflict, error) {
+	existingMethods := make(map[string]bool)
+	for _, match := range staticMethodPattern.FindAllStringSubmatch(existing, -1) {
+		existingMethods[m.methodKey(match[1], match[2])] = true
+	}
+
+	var toAdd []string
+	for _, loc := range staticMethodPattern.FindAllStringSubmatchIndex(newContent, -1) {
+		key := m.methodKey(newContent[loc[2]:loc[3]], newContent[loc[4]:loc[5]])
+		if existingMethods[key] {
+			continue
+		}
+		closeIndex := m.closingBrace(newContent, loc[1]-1)
+		if closeIndex == -1 {
+			return "", nil, fmt.Errorf("could not find end of method %s", newContent[loc[2]:loc[3]])
+		}
+		existingMethods[key] = true
+		toAdd = append(toAdd, newContent[loc[0]:closeIndex+1])
+	}
+
+	merged := existing
+	if len(toAdd) > 0 {
+		classLoc := regexp.MustCompile(`static\s+class\s+\w+Extensions\b[^{]*\{`).FindStringIndex(existing)
+		if classLoc == nil {
+			return "", nil, fmt.Errorf("could not find enum extensions class")
+		}
+		closeIndex := m.closingBrace(existing, classLoc[1]-1)
+		if closeIndex == -1 {
+			return "", nil, fmt.Errorf("could not find end of enum extensions class")
+		}
+
+		// Insert after the last member, keeping the closing brace's line intact
+		lineStart := strings.LastIndex(existing[:closeIndex], "\n")
+		body := strings.TrimRight(existing[:lineStart], " \t\r\n")
+		merged = body + "\n\n" + strings.Join(toAdd, "\n\n") + existing[lineStart:]
+	}
+
+	for _, directive := range usingPattern.FindAllString(newContent, -1) {
+		if strings.Contains(merged, directive) {
+			continue
+		}
+		usings := usingPattern.FindAllStringIndex(merged, -1)
+		if len(usings) == 0 {
+			merged = directive + "\n" + merged
+			continue
+		}
+		end := usings[len(usings)-1][1]
+		merged = merged[:end] + "\n" + directive + merged[end:]
+	}
+
+	return merged, nil, nil
+}
+
 // Helper methods
 
+// methodKey identifies a method by its name and parameter list, ignoring whitespace
+func (m *PatternMerger) methodKey(name, parameters string) string {
+	return name + "(" + m.normalizeTypeArguments(parameters) + ")"
+}
+
+// closingBrace returns the index of the brace closing the one at openBrace, or -1 when it is unbalanced
+func (m *PatternMerger) closingBrace(content string, openBrace int) int {
+	depth := 0
+	for i := openBrace; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 func (m *PatternMerger) extractPermissionClasses(content string) []string {
 	// Extract public static classes
 	classPattern := regexp.MustCompile(`(?s)public\s+static\s+class\s+\w+\s*\{[^}]*\}`)
@@ -257,3 +376,61 @@ func (m *PatternMerger) insertDbSets(content string, toAdd []string) string {
 
 	return strings.Join(newLines, "\n")
 }
+
+// normalizeTypeArguments strips whitespace from generic type arguments so "A, B" and "A,B" compare equal
+func (m *PatternMerger) normalizeTypeArguments(args string) string {
+	return strings.Join(strings.Fields(args), "")
+}
+
+// insertIntoProfileConstructor appends statements at the end of the profile's parameterless constructor
+func (m *PatternMerger) insertIntoProfileConstructor(content string, statements []string) (string, error) {
+	classPattern := regexp.MustCompile(`class\s+(\w+)\s*:\s*(?:AutoMapper\.)?Profile\b`)
+	classMatch := classPattern.FindStringSubmatch(content)
+	if classMatch == nil {
+		return "", fmt.Errorf("could not find AutoMapper profile class")
+	}
+
+	ctorPattern := regexp.MustCompile(fmt.Sprintf(`(?m)^([ \t]*)public\s+%s\s*\(\s*\)\s*\{`, regexp.QuoteMeta(classMatch[1])))
+	loc := ctorPattern.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return "", fmt.Errorf("could not find constructor of profile %s", classMatch[1])
+	}
+	indent := content[loc[2]:loc[3]] + "    "
+
+	// Find the brace closing the constructor body
+	depth := 0
+	closeIndex := -1
+	for i := loc[1] - 1; i < len(content); i++ {
+		if content[i] == '{' {
+			depth++
+		} else if content[i] == '}' {
+			depth--
+			if depth == 0 {
+				closeIndex = i
+				break
+			}
+		}
+	}
+	if closeIndex == -1 {
+		return "", fmt.Errorf("could not find end of constructor of profile %s", classMatch[1])
+	}
+
+	// Insert after the last non-blank line of the body, keeping the closing brace's line intact
+	body, tail := content[:loc[1]], "\n"+content[loc[2]:loc[3]]+content[closeIndex:]
+	if lineStart := strings.LastIndex(content[:closeIndex], "\n"); lineStart >= loc[1] {
+		body, tail = strings.TrimRight(content[:lineStart], " \t\r\n"), content[lineStart:]
+	}
+
+	var b strings.Builder
+	b.WriteString(body)
+	for i, statement := range statements {
+		if i > 0 || body[len(body)-1] != '{' {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(indent)
+		b.WriteString(statement)
+	}
+	b.WriteString(tail)
+	return b.String(), nil
+}
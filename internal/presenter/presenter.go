@@ -0,0 +1,92 @@
+package presenter
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Mode controls whether output is decorated with color and emoji
+type Mode string
+
+const (
+	// ModeAuto decorates output only when writing to a terminal and NO_COLOR is unset
+	ModeAuto Mode = "auto"
+	// ModeAlways always decorates output
+	ModeAlways Mode = "always"
+	// ModeNever never decorates output
+	ModeNever Mode = "never"
+)
+
+const (
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
+// ParseMode parses a --color flag value
+func ParseMode(value string) (Mode, error) {
+	switch Mode(value) {
+	case ModeAuto, ModeAlways, ModeNever:
+		return Mode(value), nil
+	default:
+		return "", fmt.Errorf("invalid color mode '%s': must be auto, always, or never", value)
+	}
+}
+
+// Presenter writes user-facing status lines, decorating them only when the mode allows it
+type Presenter struct {
+	out       io.Writer
+	decorated bool
+}
+
+// New creates a presenter writing to out
+func New(out io.Writer, mode Mode) *Presenter {
+	decorated := mode == ModeAlways
+	if mode == ModeAuto {
+		decorated = os.Getenv("NO_COLOR") == "" && IsTerminal(out)
+	}
+
+	return &Presenter{
+		out:       out,
+		decorated: decorated,
+	}
+}
+
+// IsTerminal checks if w is a character device such as an interactive terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Decorated reports whether output is decorated with color and emoji
+func (p *Presenter) Decorated() bool {
+	return p.decorated
+}
+
+// Success prints a line reporting a completed step
+func (p *Presenter) Success(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if p.decorated {
+		fmt.Fprintf(p.out, "%s✓%s %s\n", ansiGreen, ansiReset, message)
+		return
+	}
+	fmt.Fprintln(p.out, message)
+}
+
+// Warning prints a line reporting a non-fatal problem
+func (p *Presenter) Warning(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if p.decorated {
+		fmt.Fprintf(p.out, "%s⚠️%s  %s\n", ansiYellow, ansiReset, message)
+		return
+	}
+	fmt.Fprintf(p.out, "Warning: %s\n", message)
+}
@@ -0,0 +1,55 @@
+package presenter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPresenter_NonTerminalOutputIsPlain(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	// A regular file stands in for stdout redirected to a log
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Fatal("IsTerminal() = true for a regular file")
+	}
+
+	p := New(f, ModeAuto)
+	p.Success("Code generation completed successfully!")
+	p.Warning("Force mode enabled")
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Code generation completed successfully!\nWarning: Force mode enabled\n"
+	if string(data) != want {
+		t.Errorf("output = %q; want %q", data, want)
+	}
+}
+
+func TestPresenter_Modes(t *testing.T) {
+	var always bytes.Buffer
+	New(&always, ModeAlways).Success("Generated %s", "Product")
+	if got := always.String(); !strings.Contains(got, "✓") || !strings.Contains(got, "\033[32m") {
+		t.Errorf("ModeAlways output = %q; want a colored check mark", got)
+	}
+
+	var never bytes.Buffer
+	New(&never, ModeNever).Success("Generated %s", "Product")
+	if got := never.String(); got != "Generated Product\n" {
+		t.Errorf("ModeNever output = %q", got)
+	}
+
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Error("ParseMode(\"sometimes\") succeeded; want an error")
+	}
+}
@@ -2,13 +2,15 @@ package prompts
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
 	"github.com/mohamedhabibwork/abp-gen/internal/templates"
 )
 
-// PromptEntity prompts for entity information
-func PromptEntity(defaultPrimaryKeyType string) (*schema.Entity, error) {
+// PromptEntity prompts for entity information. existingEntityNames, the entities already in the
+// schema, are offered as relation targets together with the new entity itself.
+func PromptEntity(defaultPrimaryKeyType string, existingEntityNames []string) (*schema.Entity, error) {
 	name, err := PromptText("Entity name:", "")
 	if err != nil {
 		return nil, err
@@ -25,6 +27,7 @@ func PromptEntity(defaultPrimaryKeyType string) (*schema.Entity, error) {
 		[]string{
 			"FullAuditedAggregateRoot",
 			"AggregateRoot",
+			"CreationAuditedAggregateRoot",
 			"AuditedAggregateRoot",
 			"Entity",
 			"ValueObject",
@@ -48,15 +51,19 @@ func PromptEntity(defaultPrimaryKeyType string) (*schema.Entity, error) {
 	}
 
 	// Prompt for properties
-	fmt.Printf("\n=== Properties for %s ===\n", name)
+	fmt.Fprintf(output, "\n=== Properties for %s ===\n", name)
 	properties, err := PromptProperties()
 	if err != nil {
 		return nil, err
 	}
+	properties, err = ReviewProperties(properties)
+	if err != nil {
+		return nil, err
+	}
 
 	// Prompt for relations
-	fmt.Printf("\n=== Relations for %s ===\n", name)
-	relations, err := PromptRelations()
+	fmt.Fprintf(output, "\n=== Relations for %s ===\n", name)
+	relations, err := PromptRelations(name, append(append([]string{}, existingEntityNames...), name))
 	if err != nil {
 		return nil, err
 	}
@@ -97,3 +104,109 @@ func PromptProperties() ([]schema.Property, error) {
 
 	return properties, nil
 }
+
+// Property review actions
+const (
+	reviewDone     = "Done"
+	reviewEdit     = "Edit a property"
+	reviewRemove   = "Remove a property"
+	reviewMoveUp   = "Move a property up"
+	reviewMoveDown = "Move a property down"
+	reviewAdd      = "Add a property"
+)
+
+// ReviewProperties lists the collected properties and lets the user edit, remove, reorder
+// or add properties until they confirm the list
+func ReviewProperties(properties []schema.Property) ([]schema.Property, error) {
+	for len(properties) > 0 {
+		fmt.Fprintln(output, "\nProperties:")
+		labels := propertyLabels(properties)
+		for _, label := range labels {
+			fmt.Fprintf(output, "  %s\n", label)
+		}
+
+		action, err := PromptSelect(
+			"Review properties:",
+			[]string{reviewDone, reviewEdit, reviewRemove, reviewMoveUp, reviewMoveDown, reviewAdd},
+			reviewDone,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		switch action {
+		case reviewDone:
+			return properties, nil
+		case reviewAdd:
+			property, err := PromptProperty()
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, *property)
+			continue
+		}
+
+		label, err := PromptSelect("Property:", labels, labels[0])
+		if err != nil {
+			return nil, err
+		}
+		index := indexOf(labels, label)
+
+		switch action {
+		case reviewEdit:
+			property, err := PromptPropertyEdit(properties[index])
+			if err != nil {
+				return nil, err
+			}
+			properties[index] = *property
+		case reviewRemove:
+			properties = append(properties[:index], properties[index+1:]...)
+		case reviewMoveUp:
+			if index > 0 {
+				properties[index-1], properties[index] = properties[index], properties[index-1]
+			}
+		case reviewMoveDown:
+			if index < len(properties)-1 {
+				properties[index], properties[index+1] = properties[index+1], properties[index]
+			}
+		}
+	}
+
+	return properties, nil
+}
+
+// propertyLabels describes each property on one numbered line, e.g. "2. Price decimal (required)"
+func propertyLabels(properties []schema.Property) []string {
+	labels := make([]string, len(properties))
+	for i, prop := range properties {
+		label := fmt.Sprintf("%d. %s %s", i+1, prop.Name, prop.Type)
+		var flags []string
+		if prop.IsRequired {
+			flags = append(flags, "required")
+		}
+		if prop.Nullable {
+			flags = append(flags, "nullable")
+		}
+		if prop.MaxLength > 0 {
+			flags = append(flags, fmt.Sprintf("max %d", prop.MaxLength))
+		}
+		if prop.IsForeignKey {
+			flags = append(flags, "FK to "+prop.TargetEntity)
+		}
+		if len(flags) > 0 {
+			label += " (" + strings.Join(flags, ", ") + ")"
+		}
+		labels[i] = label
+	}
+	return labels
+}
+
+// indexOf returns the position of value in values, or -1
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -14,6 +14,10 @@ const (
 	MergeDecisionMerge     MergeDecision = "merge"
 	MergeDecisionSkip      MergeDecision = "skip"
 	MergeDecisionShowDiff  MergeDecision = "showdiff"
+
+	// Batch decisions, offered once all pending merges have been computed
+	MergeDecisionMergeClean MergeDecision = "mergeclean" // Merge files without conflicts, skip the others
+	MergeDecisionPerFile    MergeDecision = "perfile"    // Fall back to deciding file by file
 )
 
 // ConflictResolution represents how a conflict should be resolved
@@ -62,7 +66,7 @@ func PromptMergeDecision(filePath string, fileTypeName string) (MergeDecision, e
 		Default: "Merge intelligently (recommended)",
 	}
 
-	if err := survey.AskOne(prompt, &decision); err != nil {
+	if err := askOne(prompt, &decision); err != nil {
 		return "", err
 	}
 
@@ -80,6 +84,72 @@ func PromptMergeDecision(filePath string, fileTypeName string) (MergeDecision, e
 	}
 }
 
+// PromptMergeDecisionAfterDiff prompts for the final merge decision once the diff has been shown
+func PromptMergeDecisionAfterDiff(filePath string) (MergeDecision, error) {
+	var decision string
+
+	prompt := &survey.Select{
+		Message: fmt.Sprintf("What would you like to do with %s?", filePath),
+		Options: []string{
+			"Merge intelligently (recommended)",
+			"Overwrite with new content",
+			"Skip this file",
+		},
+		Default: "Merge intelligently (recommended)",
+	}
+
+	if err := askOne(prompt, &decision); err != nil {
+		return "", err
+	}
+
+	switch decision {
+	case "Merge intelligently (recommended)":
+		return MergeDecisionMerge, nil
+	case "Overwrite with new content":
+		return MergeDecisionOverwrite, nil
+	default:
+		return MergeDecisionSkip, nil
+	}
+}
+
+// PromptBatchMergeDecision prompts for a single decision covering all pending merges,
+// after their summary has been printed
+func PromptBatchMergeDecision(fileCount int, conflictCount int) (MergeDecision, error) {
+	var decision string
+
+	options := []string{"Merge all (recommended)"}
+	if conflictCount > 0 {
+		options = []string{
+			"Merge all, resolving conflicts file by file",
+			"Merge files without conflicts, skip the rest",
+		}
+	}
+	options = append(options, "Overwrite all with new content", "Skip all", "Decide file by file")
+
+	prompt := &survey.Select{
+		Message: fmt.Sprintf("%d existing file(s) to merge, %d with conflicts. What would you like to do?", fileCount, conflictCount),
+		Options: options,
+		Default: options[0],
+	}
+
+	if err := askOne(prompt, &decision); err != nil {
+		return "", err
+	}
+
+	switch decision {
+	case "Merge all (recommended)", "Merge all, resolving conflicts file by file":
+		return MergeDecisionMerge, nil
+	case "Merge files without conflicts, skip the rest":
+		return MergeDecisionMergeClean, nil
+	case "Overwrite all with new content":
+		return MergeDecisionOverwrite, nil
+	case "Decide file by file":
+		return MergeDecisionPerFile, nil
+	default:
+		return MergeDecisionSkip, nil
+	}
+}
+
 // PromptMergeAll prompts the user if they want to apply the same decision to all files
 func PromptMergeAll() (bool, error) {
 	var applyToAll bool
@@ -89,7 +159,7 @@ func PromptMergeAll() (bool, error) {
 		Default: false,
 	}
 
-	if err := survey.AskOne(prompt, &applyToAll); err != nil {
+	if err := askOne(prompt, &applyToAll); err != nil {
 		return false, err
 	}
 
@@ -98,20 +168,19 @@ func PromptMergeAll() (bool, error) {
 
 // PromptConflictResolution prompts the user for conflict resolution
 func PromptConflictResolution(conflict Conflict, index int, total int) (ConflictResolution, error) {
-	fmt.Printf("\n⚠️  Merge conflict %d of %d\n", index+1, total)
-	fmt.Printf("Type: %s\n", getConflictTypeName(conflict.Type))
-	fmt.Printf("Description: %s\n", conflict.Description)
+	fmt.Fprintln(output)
+	ui.Warning("Merge conflict %d of %d", index+1, total)
+	fmt.Fprintf(output, "Type: %s\n", getConflictTypeName(conflict.Type))
+	fmt.Fprintf(output, "Description: %s\n", conflict.Description)
 
 	if conflict.Line > 0 {
-		fmt.Printf("Line: %d\n", conflict.Line)
+		fmt.Fprintf(output, "Line: %d\n", conflict.Line)
 	}
 
-	fmt.Println("\nExisting code:")
-	fmt.Println("───────────────")
+	fmt.Fprintln(output, "\nExisting code:")
 	printCode(conflict.ExistingCode)
 
-	fmt.Println("\nNew code:")
-	fmt.Println("─────────")
+	fmt.Fprintln(output, "\nNew code:")
 	printCode(conflict.NewCode)
 
 	var resolution string
@@ -127,7 +196,7 @@ func PromptConflictResolution(conflict Conflict, index int, total int) (Conflict
 		Default: "Keep existing",
 	}
 
-	if err := survey.AskOne(prompt, &resolution); err != nil {
+	if err := askOne(prompt, &resolution); err != nil {
 		return ResolutionKeepExisting, err
 	}
 
@@ -165,7 +234,7 @@ func PromptConflictBatch(conflicts []Conflict) (map[int]ConflictResolution, erro
 				Default: false,
 			}
 
-			if err := survey.AskOne(prompt, &applyToAll); err != nil {
+			if err := askOne(prompt, &applyToAll); err != nil {
 				return nil, err
 			}
 
@@ -207,9 +276,9 @@ func printCode(code string) {
 	lines := splitLines(code)
 	for _, line := range lines {
 		if len(line) > 100 {
-			fmt.Println("  " + line[:97] + "...")
+			fmt.Fprintln(output, "  "+line[:97]+"...")
 		} else {
-			fmt.Println("  " + line)
+			fmt.Fprintln(output, "  "+line)
 		}
 	}
 }
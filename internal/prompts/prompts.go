@@ -2,10 +2,34 @@ package prompts
 
 import (
 	"fmt"
-	"github.com/AlecAivazis/survey/v2"
+	"os"
 	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/mohamedhabibwork/abp-gen/internal/presenter"
+)
+
+var (
+	// output receives the prompts and the messages printed around them
+	output terminal.FileWriter = os.Stdout
+
+	// ui prints the status lines among those messages
+	ui = presenter.New(os.Stdout, presenter.ModeAuto)
 )
 
+// SetOutput sets where prompts are written, e.g. stderr when stdout carries machine-readable
+// output, and whether their status lines are decorated
+func SetOutput(w terminal.FileWriter, mode presenter.Mode) {
+	output = w
+	ui = presenter.New(w, mode)
+}
+
+// askOne asks a single question on the configured output
+func askOne(prompt survey.Prompt, response interface{}) error {
+	return survey.AskOne(prompt, response, survey.WithStdio(os.Stdin, output, os.Stderr))
+}
+
 // PromptText prompts for a text input
 func PromptText(message string, defaultValue string) (string, error) {
 	var result string
@@ -13,7 +37,7 @@ func PromptText(message string, defaultValue string) (string, error) {
 		Message: message,
 		Default: defaultValue,
 	}
-	if err := survey.AskOne(prompt, &result); err != nil {
+	if err := askOne(prompt, &result); err != nil {
 		return "", err
 	}
 	return result, nil
@@ -26,7 +50,7 @@ func PromptConfirm(message string, defaultValue bool) (bool, error) {
 		Message: message,
 		Default: defaultValue,
 	}
-	if err := survey.AskOne(prompt, &result); err != nil {
+	if err := askOne(prompt, &result); err != nil {
 		return false, err
 	}
 	return result, nil
@@ -40,7 +64,7 @@ func PromptSelect(message string, options []string, defaultValue string) (string
 		Options: options,
 		Default: defaultValue,
 	}
-	if err := survey.AskOne(prompt, &result); err != nil {
+	if err := askOne(prompt, &result); err != nil {
 		return "", err
 	}
 	return result, nil
@@ -54,7 +78,7 @@ func PromptMultiSelect(message string, options []string, defaults []string) ([]s
 		Options: options,
 		Default: defaults,
 	}
-	if err := survey.AskOne(prompt, &result); err != nil {
+	if err := askOne(prompt, &result); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -67,7 +91,7 @@ func PromptInt(message string, defaultValue int) (int, error) {
 		Message: message,
 		Default: fmt.Sprintf("%d", defaultValue),
 	}
-	if err := survey.AskOne(prompt, &result); err != nil {
+	if err := askOne(prompt, &result); err != nil {
 		return 0, err
 	}
 
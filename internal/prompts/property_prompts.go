@@ -4,97 +4,107 @@ import (
 	"github.com/mohamedhabibwork/abp-gen/internal/schema"
 )
 
+// propertyTypes are the property types offered by the property prompt
+var propertyTypes = []string{
+	"string",
+	"int",
+	"long",
+	"decimal",
+	"DateTime",
+	"bool",
+	"Guid",
+	"byte",
+	"short",
+	"float",
+	"double",
+	"Custom",
+}
+
 // PromptProperty prompts for a single property
 func PromptProperty() (*schema.Property, error) {
-	name, err := PromptText("Property name:", "")
+	return PromptPropertyEdit(schema.Property{Type: "string", IsRequired: true})
+}
+
+// PromptPropertyEdit prompts for a property, offering the answers of current as defaults
+func PromptPropertyEdit(current schema.Property) (*schema.Property, error) {
+	name, err := PromptText("Property name:", current.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	propertyType, err := PromptSelect(
-		"Property type:",
-		[]string{
-			"string",
-			"int",
-			"long",
-			"decimal",
-			"DateTime",
-			"bool",
-			"Guid",
-			"byte",
-			"short",
-			"float",
-			"double",
-			"Custom",
-		},
-		"string",
-	)
+	defaultType := current.Type
+	customType := ""
+	if indexOf(propertyTypes, defaultType) < 0 {
+		defaultType = "Custom"
+		customType = current.Type
+	}
+	propertyType, err := PromptSelect("Property type:", propertyTypes, defaultType)
 	if err != nil {
 		return nil, err
 	}
 
 	// If custom type, prompt for type name
 	if propertyType == "Custom" {
-		propertyType, err = PromptText("Custom type name:", "")
+		propertyType, err = PromptText("Custom type name:", customType)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	isRequired, err := PromptConfirm("Is required?", true)
+	isRequired, err := PromptConfirm("Is required?", current.IsRequired)
 	if err != nil {
 		return nil, err
 	}
 
-	nullable, err := PromptConfirm("Is nullable?", false)
+	nullable, err := PromptConfirm("Is nullable?", current.Nullable)
 	if err != nil {
 		return nil, err
 	}
 
 	var maxLength int
 	if propertyType == "string" {
-		maxLength, err = PromptInt("Max length (0 for no limit):", 0)
+		maxLength, err = PromptInt("Max length (0 for no limit):", current.MaxLength)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	var defaultValue string
-	hasDefault, err := PromptConfirm("Has default value?", false)
+	hasDefault, err := PromptConfirm("Has default value?", current.DefaultValue != "")
 	if err != nil {
 		return nil, err
 	}
 	if hasDefault {
-		defaultValue, err = PromptText("Default value:", "")
+		defaultValue, err = PromptText("Default value:", current.DefaultValue)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Check if it's a foreign key
-	isForeignKey, err := PromptConfirm("Is this a foreign key?", false)
+	isForeignKey, err := PromptConfirm("Is this a foreign key?", current.IsForeignKey)
 	if err != nil {
 		return nil, err
 	}
 
 	var targetEntity string
 	if isForeignKey {
-		targetEntity, err = PromptText("Target entity name:", "")
+		targetEntity, err = PromptText("Target entity name:", current.TargetEntity)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	property := &schema.Property{
-		Name:         name,
-		Type:         propertyType,
-		IsRequired:   isRequired,
-		MaxLength:    maxLength,
-		Nullable:     nullable,
-		DefaultValue: defaultValue,
-		IsForeignKey: isForeignKey,
-		TargetEntity: targetEntity,
-	}
+	// Keep settings the prompt does not ask about, e.g. from an edited schema
+	property := current
+	property.Name = name
+	property.Type = propertyType
+	property.IsRequired = isRequired
+	property.MaxLength = maxLength
+	property.Nullable = nullable
+	property.DefaultValue = defaultValue
+	property.IsForeignKey = isForeignKey
+	property.TargetEntity = targetEntity
 
-	return property, nil
+	return &property, nil
 }
@@ -7,90 +7,123 @@ import (
 	"github.com/mohamedhabibwork/abp-gen/internal/templates"
 )
 
-// PromptRelations prompts for entity relations
-func PromptRelations() (*schema.Relations, error) {
-	hasRelations, err := PromptConfirm("Add relations?", false)
-	if err != nil {
-		return nil, err
-	}
+// Relation kinds offered when adding a relation
+const (
+	relationOneToMany  = "One-to-Many"
+	relationManyToOne  = "Many-to-One"
+	relationOneToOne   = "One-to-One"
+	relationManyToMany = "Many-to-Many"
+)
 
-	if !hasRelations {
-		return nil, nil
-	}
+// otherTargetEntity is the target entity option for typing a name that is not listed, such as an
+// entity that is added later
+const otherTargetEntity = "Other (enter a name)"
 
+// PromptRelations prompts for the relations of entityName, one at a time: the relation kind, the
+// target entity, chosen from existingEntityNames or typed, its navigation and foreign key names and
+// its delete behavior. It returns nil when no relation is added.
+func PromptRelations(entityName string, existingEntityNames []string) (*schema.Relations, error) {
 	relations := &schema.Relations{}
+	message := "Add relation?"
 
-	// One-to-Many relations
-	addOneToMany, err := PromptConfirm("Add One-to-Many relation?", false)
-	if err != nil {
-		return nil, err
-	}
-
-	if addOneToMany {
-		oneToManyRels, err := PromptOneToManyRelations()
+	for {
+		addRelation, err := PromptConfirm(message, false)
 		if err != nil {
 			return nil, err
 		}
-		relations.OneToMany = oneToManyRels
-	}
+		if !addRelation {
+			break
+		}
+		message = "Add another relation?"
 
-	// Many-to-Many relations
-	addManyToMany, err := PromptConfirm("Add Many-to-Many relation?", false)
-	if err != nil {
-		return nil, err
-	}
+		kind, err := PromptSelect(
+			"Relation kind:",
+			[]string{relationOneToMany, relationManyToOne, relationOneToOne, relationManyToMany},
+			relationOneToMany,
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	if addManyToMany {
-		manyToManyRels, err := PromptManyToManyRelations()
+		targetEntity, err := PromptTargetEntity(existingEntityNames)
 		if err != nil {
 			return nil, err
 		}
-		relations.ManyToMany = manyToManyRels
+
+		switch kind {
+		case relationOneToMany:
+			rel, err := PromptOneToManyRelation(entityName, targetEntity)
+			if err != nil {
+				return nil, err
+			}
+			relations.OneToMany = append(relations.OneToMany, *rel)
+		case relationManyToOne:
+			rel, err := PromptManyToOneRelation(targetEntity)
+			if err != nil {
+				return nil, err
+			}
+			relations.ManyToOne = append(relations.ManyToOne, *rel)
+		case relationOneToOne:
+			rel, err := PromptOneToOneRelation(targetEntity)
+			if err != nil {
+				return nil, err
+			}
+			relations.OneToOne = append(relations.OneToOne, *rel)
+		case relationManyToMany:
+			rel, err := PromptManyToManyRelation(entityName, targetEntity)
+			if err != nil {
+				return nil, err
+			}
+			relations.ManyToMany = append(relations.ManyToMany, *rel)
+		}
 	}
 
+	if len(relations.OneToMany) == 0 && len(relations.ManyToOne) == 0 && len(relations.OneToOne) == 0 && len(relations.ManyToMany) == 0 {
+		return nil, nil
+	}
 	return relations, nil
 }
 
-// PromptOneToManyRelations prompts for one-to-many relations
-func PromptOneToManyRelations() ([]schema.OneToManyRelation, error) {
-	var relations []schema.OneToManyRelation
-
-	for {
-		rel, err := PromptOneToManyRelation()
+// PromptTargetEntity prompts for a relation's target entity, offering existingEntityNames and
+// falling back to a typed name
+func PromptTargetEntity(existingEntityNames []string) (string, error) {
+	if len(existingEntityNames) > 0 {
+		options := append(append([]string{}, existingEntityNames...), otherTargetEntity)
+		choice, err := PromptSelect("Target entity:", options, options[0])
 		if err != nil {
-			return nil, err
+			return "", err
 		}
+		if choice != otherTargetEntity {
+			return choice, nil
+		}
+	}
 
-		relations = append(relations, *rel)
-
-		addMore, err := PromptConfirm("Add another One-to-Many relation?", false)
+	for {
+		targetEntity, err := PromptText("Target entity name:", "")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-
-		if !addMore {
-			break
+		if targetEntity != "" {
+			return targetEntity, nil
 		}
+		fmt.Fprintln(output, "A target entity name is required.")
 	}
-
-	return relations, nil
 }
 
-// PromptOneToManyRelation prompts for a single one-to-many relation
-func PromptOneToManyRelation() (*schema.OneToManyRelation, error) {
-	targetEntity, err := PromptText("Target entity name:", "")
+// PromptOneToManyRelation prompts for a one-to-many relation from entityName to targetEntity.
+// The foreign key is declared on the target and defaults to {entityName}Id.
+func PromptOneToManyRelation(entityName, targetEntity string) (*schema.OneToManyRelation, error) {
+	navigationProperty, err := PromptText("Navigation property name:", templates.Pluralize(targetEntity))
 	if err != nil {
 		return nil, err
 	}
 
-	defaultFKName := targetEntity + "Id"
-	foreignKeyName, err := PromptText("Foreign key name:", defaultFKName)
+	foreignKeyName, err := PromptText(fmt.Sprintf("Foreign key name on %s:", targetEntity), entityName+"Id")
 	if err != nil {
 		return nil, err
 	}
 
-	defaultNavProp := templates.Pluralize(targetEntity)
-	navigationProperty, err := PromptText("Navigation property name:", defaultNavProp)
+	cascadeDelete, err := PromptConfirm(fmt.Sprintf("Delete the %s with their %s (cascade)?", navigationProperty, entityName), false)
 	if err != nil {
 		return nil, err
 	}
@@ -100,57 +133,118 @@ func PromptOneToManyRelation() (*schema.OneToManyRelation, error) {
 		ForeignKeyName:     foreignKeyName,
 		NavigationProperty: navigationProperty,
 		IsCollection:       true,
+		CascadeDelete:      cascadeDelete,
+		IsSelfReference:    targetEntity == entityName,
 	}, nil
 }
 
-// PromptManyToManyRelations prompts for many-to-many relations
-func PromptManyToManyRelations() ([]schema.ManyToManyRelation, error) {
-	var relations []schema.ManyToManyRelation
-
-	for {
-		rel, err := PromptManyToManyRelation()
-		if err != nil {
-			return nil, err
-		}
+// PromptManyToOneRelation prompts for a many-to-one relation to targetEntity
+func PromptManyToOneRelation(targetEntity string) (*schema.ManyToOneRelation, error) {
+	navigationProperty, err := PromptText("Navigation property name:", targetEntity)
+	if err != nil {
+		return nil, err
+	}
 
-		relations = append(relations, *rel)
+	foreignKeyName, err := PromptText("Foreign key name:", targetEntity+"Id")
+	if err != nil {
+		return nil, err
+	}
 
-		addMore, err := PromptConfirm("Add another Many-to-Many relation?", false)
-		if err != nil {
-			return nil, err
-		}
+	isRequired, err := PromptConfirm("Is required?", false)
+	if err != nil {
+		return nil, err
+	}
 
-		if !addMore {
-			break
-		}
+	cascadeDelete, err := PromptConfirm(fmt.Sprintf("Delete with the %s (cascade)?", targetEntity), false)
+	if err != nil {
+		return nil, err
 	}
 
-	return relations, nil
+	return &schema.ManyToOneRelation{
+		TargetEntity:       targetEntity,
+		ForeignKeyName:     foreignKeyName,
+		NavigationProperty: navigationProperty,
+		IsRequired:         isRequired,
+		CascadeDelete:      cascadeDelete,
+	}, nil
 }
 
-// PromptManyToManyRelation prompts for a single many-to-many relation
-func PromptManyToManyRelation() (*schema.ManyToManyRelation, error) {
-	targetEntity, err := PromptText("Target entity name:", "")
+// PromptOneToOneRelation prompts for a one-to-one relation to targetEntity. An owned target is
+// stored with the entity, so it has no foreign key or delete behavior to ask for.
+func PromptOneToOneRelation(targetEntity string) (*schema.OneToOneRelation, error) {
+	navigationProperty, err := PromptText("Navigation property name:", targetEntity)
 	if err != nil {
 		return nil, err
 	}
 
-	// Default join entity name (will be sorted in validation)
-	defaultJoinEntity := fmt.Sprintf("%sJoin", targetEntity)
-	joinEntity, err := PromptText("Join entity name:", defaultJoinEntity)
+	isOwned, err := PromptConfirm(fmt.Sprintf("Is %s an owned type (stored with the entity)?", targetEntity), false)
+	if err != nil {
+		return nil, err
+	}
+	if isOwned {
+		return &schema.OneToOneRelation{
+			TargetEntity:       targetEntity,
+			NavigationProperty: navigationProperty,
+			IsOwned:            true,
+		}, nil
+	}
+
+	foreignKeyName, err := PromptText("Foreign key name:", targetEntity+"Id")
 	if err != nil {
 		return nil, err
 	}
 
+	isRequired, err := PromptConfirm("Is required?", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cascadeDelete, err := PromptConfirm(fmt.Sprintf("Delete with the %s (cascade)?", targetEntity), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.OneToOneRelation{
+		TargetEntity:       targetEntity,
+		ForeignKeyName:     foreignKeyName,
+		NavigationProperty: navigationProperty,
+		IsRequired:         isRequired,
+		CascadeDelete:      cascadeDelete,
+	}, nil
+}
+
+// PromptManyToManyRelation prompts for a many-to-many relation from entityName to targetEntity,
+// with the same defaults as a relation declared in a schema file
+func PromptManyToManyRelation(entityName, targetEntity string) (*schema.ManyToManyRelation, error) {
+	isSelfReference := targetEntity == entityName
+
 	defaultNavProp := templates.Pluralize(targetEntity)
+	if isSelfReference {
+		defaultNavProp = "Target" + defaultNavProp
+	}
 	navigationProperty, err := PromptText("Navigation property name:", defaultNavProp)
 	if err != nil {
 		return nil, err
 	}
 
+	// Both entity names in alphabetical order, or the navigation for a self reference
+	names := []string{entityName, targetEntity}
+	if names[0] > names[1] {
+		names[0], names[1] = names[1], names[0]
+	}
+	defaultJoinEntity := names[0] + names[1]
+	if isSelfReference {
+		defaultJoinEntity = entityName + navigationProperty
+	}
+	joinEntity, err := PromptText("Join entity name:", defaultJoinEntity)
+	if err != nil {
+		return nil, err
+	}
+
 	return &schema.ManyToManyRelation{
 		TargetEntity:       targetEntity,
 		JoinEntity:         joinEntity,
 		NavigationProperty: navigationProperty,
+		IsSelfReference:    isSelfReference,
 	}, nil
 }
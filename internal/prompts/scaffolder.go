@@ -14,7 +14,8 @@ import (
 
 // Scaffolder handles creation of new solutions and projects via CLI tools
 type Scaffolder struct {
-	reader *bufio.Reader
+	reader         *bufio.Reader
+	nonInteractive bool
 }
 
 // NewScaffolder creates a new scaffolder
@@ -24,12 +25,22 @@ func NewScaffolder() *Scaffolder {
 	}
 }
 
+// SetNonInteractive makes every prompt fail with an error instead of reading from stdin
+func (s *Scaffolder) SetNonInteractive(enabled bool) {
+	s.nonInteractive = enabled
+}
+
 // PromptCreateSolution prompts user to create a new ABP or ASP.NET solution
 // Returns: (created bool, solutionPath string, error)
 func (s *Scaffolder) PromptCreateSolution(workingDir string, autoScaffold bool) (bool, string, error) {
+	if !autoScaffold && s.nonInteractive {
+		return false, "", fmt.Errorf("solution detection failed: no solution found and creation prompts are disabled in non-interactive mode (pass --solution or --auto-scaffold)")
+	}
+
 	if !autoScaffold {
-		fmt.Println("\n❌ No solution found in the current directory or parent directories.")
-		fmt.Print("Would you like to create a new solution? (y/N): ")
+		fmt.Fprintln(output)
+		ui.Warning("No solution found in the current directory or parent directories.")
+		fmt.Fprint(output, "Would you like to create a new solution? (y/N): ")
 
 		response, _ := s.reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
@@ -67,7 +78,8 @@ func (s *Scaffolder) PromptCreateSolution(workingDir string, autoScaffold bool)
 		return false, "", err
 	}
 
-	fmt.Printf("\n✓ Solution created successfully at: %s\n", solutionPath)
+	fmt.Fprintln(output)
+	ui.Success("Solution created successfully at: %s", solutionPath)
 	return true, solutionPath, nil
 }
 
@@ -87,8 +99,12 @@ func (s *Scaffolder) checkDotNetCLI() bool {
 
 // promptSolutionDetails prompts for solution name and template type
 func (s *Scaffolder) promptSolutionDetails(hasAbpCLI bool, autoScaffold bool) (name, template string, err error) {
+	if s.nonInteractive {
+		return "", "", fmt.Errorf("solution name is required: cannot prompt for the new solution name in non-interactive mode")
+	}
+
 	// Get solution name
-	fmt.Print("\nEnter solution name (e.g., MyCompany.MyProject): ")
+	fmt.Fprint(output, "\nEnter solution name (e.g., MyCompany.MyProject): ")
 	name, _ = s.reader.ReadString('\n')
 	name = strings.TrimSpace(name)
 
@@ -103,18 +119,18 @@ func (s *Scaffolder) promptSolutionDetails(hasAbpCLI bool, autoScaffold bool) (n
 	}
 
 	// Get template type
-	fmt.Println("\nSelect template type:")
+	fmt.Fprintln(output, "\nSelect template type:")
 	if hasAbpCLI {
-		fmt.Println("  1. ABP Application (app) - Monolithic web application")
-		fmt.Println("  2. ABP Microservice (microservice) - Microservice solution")
-		fmt.Println("  3. ABP Module (module) - Reusable module")
-		fmt.Println("  4. ASP.NET Core Web API (webapi) - Simple Web API")
+		fmt.Fprintln(output, "  1. ABP Application (app) - Monolithic web application")
+		fmt.Fprintln(output, "  2. ABP Microservice (microservice) - Microservice solution")
+		fmt.Fprintln(output, "  3. ABP Module (module) - Reusable module")
+		fmt.Fprintln(output, "  4. ASP.NET Core Web API (webapi) - Simple Web API")
 	} else {
-		fmt.Println("  1. ASP.NET Core Web API (webapi)")
-		fmt.Println("  2. ASP.NET Core MVC (mvc)")
+		fmt.Fprintln(output, "  1. ASP.NET Core Web API (webapi)")
+		fmt.Fprintln(output, "  2. ASP.NET Core MVC (mvc)")
 	}
 
-	fmt.Print("Enter choice (1-4 or template name): ")
+	fmt.Fprint(output, "Enter choice (1-4 or template name): ")
 	choice, _ := s.reader.ReadString('\n')
 	choice = strings.TrimSpace(choice)
 
@@ -148,12 +164,12 @@ func (s *Scaffolder) promptSolutionDetails(hasAbpCLI bool, autoScaffold bool) (n
 
 // createABPSolution creates a new ABP solution using the ABP CLI
 func (s *Scaffolder) createABPSolution(workingDir, solutionName, template string) (string, error) {
-	fmt.Printf("\nCreating ABP solution with command: abp new %s -t %s\n", solutionName, template)
-	fmt.Println("This may take a few minutes...")
+	fmt.Fprintf(output, "\nCreating ABP solution with command: abp new %s -t %s\n", solutionName, template)
+	fmt.Fprintln(output, "This may take a few minutes...")
 
 	cmd := exec.Command("abp", "new", solutionName, "-t", template)
 	cmd.Dir = workingDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = output
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -167,11 +183,11 @@ func (s *Scaffolder) createABPSolution(workingDir, solutionName, template string
 
 // createDotNetSolution creates a new .NET solution using the dotnet CLI
 func (s *Scaffolder) createDotNetSolution(workingDir, solutionName, template string) (string, error) {
-	fmt.Printf("\nCreating .NET solution with command: dotnet new %s -n %s\n", template, solutionName)
+	fmt.Fprintf(output, "\nCreating .NET solution with command: dotnet new %s -n %s\n", template, solutionName)
 
 	cmd := exec.Command("dotnet", "new", template, "-n", solutionName)
 	cmd.Dir = workingDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = output
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -186,9 +202,9 @@ func (s *Scaffolder) createDotNetSolution(workingDir, solutionName, template str
 // PromptForMissingInfo prompts user for information that couldn't be auto-detected
 func (s *Scaffolder) PromptForMissingInfo(question string, defaultValue string) string {
 	if defaultValue != "" {
-		fmt.Printf("%s [%s]: ", question, defaultValue)
+		fmt.Fprintf(output, "%s [%s]: ", question, defaultValue)
 	} else {
-		fmt.Printf("%s: ", question)
+		fmt.Fprintf(output, "%s: ", question)
 	}
 
 	response, _ := s.reader.ReadString('\n')
@@ -202,7 +218,7 @@ func (s *Scaffolder) PromptForMissingInfo(question string, defaultValue string)
 
 // ConfirmAutoDetected asks user to confirm auto-detected settings
 func (s *Scaffolder) ConfirmAutoDetected(setting, value string) bool {
-	fmt.Printf("Auto-detected %s: %s. Use this? (Y/n): ", setting, value)
+	fmt.Fprintf(output, "Auto-detected %s: %s. Use this? (Y/n): ", setting, value)
 
 	response, _ := s.reader.ReadString('\n')
 	response = strings.TrimSpace(strings.ToLower(response))
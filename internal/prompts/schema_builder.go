@@ -8,8 +8,8 @@ import (
 
 // BuildSchemaInteractively builds a complete schema through interactive prompts
 func BuildSchemaInteractively() (*schema.Schema, error) {
-	fmt.Println("\n=== ABP Code Generator - Interactive Mode ===")
-	fmt.Println()
+	fmt.Fprintln(output, "\n=== ABP Code Generator - Interactive Mode ===")
+	fmt.Fprintln(output)
 
 	// Build solution configuration
 	solution, err := PromptSolutionConfig()
@@ -58,7 +58,7 @@ func BuildSchemaInteractively() (*schema.Schema, error) {
 		if err := sch.SaveToFile(path); err != nil {
 			return nil, fmt.Errorf("failed to save schema: %w", err)
 		}
-		fmt.Printf("✓ Schema saved to %s\n", path)
+		ui.Success("Schema saved to %s", path)
 	}
 
 	return sch, nil
@@ -66,8 +66,8 @@ func BuildSchemaInteractively() (*schema.Schema, error) {
 
 // PromptSolutionConfig prompts for solution configuration
 func PromptSolutionConfig() (*schema.Solution, error) {
-	fmt.Println("=== Solution Configuration ===")
-	fmt.Println()
+	fmt.Fprintln(output, "=== Solution Configuration ===")
+	fmt.Fprintln(output)
 
 	name, err := PromptText("Solution name:", "")
 	if err != nil {
@@ -130,9 +130,13 @@ func PromptEntities(defaultPrimaryKeyType string) ([]schema.Entity, error) {
 	entityCount := 1
 
 	for {
-		fmt.Printf("\n=== Entity %d ===\n", entityCount)
+		fmt.Fprintf(output, "\n=== Entity %d ===\n", entityCount)
 
-		entity, err := PromptEntity(defaultPrimaryKeyType)
+		var entityNames []string
+		for _, existing := range entities {
+			entityNames = append(entityNames, existing.Name)
+		}
+		entity, err := PromptEntity(defaultPrimaryKeyType, entityNames)
 		if err != nil {
 			return nil, err
 		}
@@ -155,8 +159,8 @@ func PromptEntities(defaultPrimaryKeyType string) ([]schema.Entity, error) {
 
 // PromptGenerationOptions prompts for generation options
 func PromptGenerationOptions() (*schema.Options, error) {
-	fmt.Println("\n=== Generation Options ===")
-	fmt.Println()
+	fmt.Fprintln(output, "\n=== Generation Options ===")
+	fmt.Fprintln(output)
 
 	useAuditedAggregateRoot, err := PromptConfirm("Use audited aggregate root?", true)
 	if err != nil {
@@ -224,32 +228,32 @@ func PromptGenerationOptions() (*schema.Options, error) {
 
 // DisplaySchemaSummary displays a summary of the schema
 func DisplaySchemaSummary(sch *schema.Schema) {
-	fmt.Println("\n=== Schema Summary ===")
-	fmt.Printf("Solution: %s\n", sch.Solution.Name)
-	fmt.Printf("Module: %s\n", sch.Solution.ModuleName)
-	fmt.Printf("Namespace: %s\n", sch.Solution.NamespaceRoot)
-	fmt.Printf("ABP Version: %s\n", sch.Solution.ABPVersion)
-	fmt.Printf("Primary Key Type: %s\n", sch.Solution.PrimaryKeyType)
-	fmt.Printf("Database Provider: %s\n", sch.Solution.DBProvider)
-	fmt.Printf("Generate Controllers: %v\n\n", sch.Solution.GenerateControllers)
-
-	fmt.Printf("Entities (%d):\n", len(sch.Entities))
+	fmt.Fprintln(output, "\n=== Schema Summary ===")
+	fmt.Fprintf(output, "Solution: %s\n", sch.Solution.Name)
+	fmt.Fprintf(output, "Module: %s\n", sch.Solution.ModuleName)
+	fmt.Fprintf(output, "Namespace: %s\n", sch.Solution.NamespaceRoot)
+	fmt.Fprintf(output, "ABP Version: %s\n", sch.Solution.ABPVersion)
+	fmt.Fprintf(output, "Primary Key Type: %s\n", sch.Solution.PrimaryKeyType)
+	fmt.Fprintf(output, "Database Provider: %s\n", sch.Solution.DBProvider)
+	fmt.Fprintf(output, "Generate Controllers: %v\n\n", sch.Solution.GenerateControllers)
+
+	fmt.Fprintf(output, "Entities (%d):\n", len(sch.Entities))
 	for i, entity := range sch.Entities {
-		fmt.Printf("  %d. %s (%s)\n", i+1, entity.Name, entity.EntityType)
-		fmt.Printf("     Properties: %d\n", len(entity.Properties))
+		fmt.Fprintf(output, "  %d. %s (%s)\n", i+1, entity.Name, entity.EntityType)
+		fmt.Fprintf(output, "     Properties: %d\n", len(entity.Properties))
 		if entity.HasRelations() {
-			fmt.Printf("     Relations: One-to-Many(%d), Many-to-Many(%d)\n",
+			fmt.Fprintf(output, "     Relations: One-to-Many(%d), Many-to-Many(%d)\n",
 				len(entity.Relations.OneToMany),
 				len(entity.Relations.ManyToMany))
 		}
 	}
 
-	fmt.Println("\nOptions:")
-	fmt.Printf("  Use Localization: %v", sch.Options.UseLocalization)
+	fmt.Fprintln(output, "\nOptions:")
+	fmt.Fprintf(output, "  Use Localization: %v", sch.Options.UseLocalization)
 	if sch.Options.UseLocalization {
-		fmt.Printf(" (%v)", sch.Options.LocalizationCultures)
+		fmt.Fprintf(output, " (%v)", sch.Options.LocalizationCultures)
 	}
-	fmt.Println()
-	fmt.Printf("  Validation Type: %s\n", sch.Options.ValidationType)
-	fmt.Printf("  Generate Event Handlers: %v\n", sch.Options.GenerateEventHandlers)
+	fmt.Fprintln(output)
+	fmt.Fprintf(output, "  Validation Type: %s\n", sch.Options.ValidationType)
+	fmt.Fprintf(output, "  Generate Event Handlers: %v\n", sch.Options.GenerateEventHandlers)
 }
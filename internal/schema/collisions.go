@@ -0,0 +1,190 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MemberCollision describes a declared property whose name clashes with a generated member
+type MemberCollision struct {
+	Entity   string
+	Property string
+	Source   string // What generates the clashing member, e.g. "manyToOne navigation to Customer"
+}
+
+// String returns a human-readable description of the collision
+func (c MemberCollision) String() string {
+	return fmt.Sprintf("entity '%s': property '%s' collides with the %s", c.Entity, c.Property, c.Source)
+}
+
+// auditMembers lists the members inherited from each ABP base class
+var auditMembers = map[string][]string{
+	"Entity":        {"Id"},
+	"AggregateRoot": {"Id", "ExtraProperties", "ConcurrencyStamp"},
+	"CreationAuditedAggregateRoot": {
+		"Id", "ExtraProperties", "ConcurrencyStamp",
+		"CreationTime", "CreatorId",
+	},
+	"AuditedAggregateRoot": {
+		"Id", "ExtraProperties", "ConcurrencyStamp",
+		"CreationTime", "CreatorId", "LastModificationTime", "LastModifierId",
+	},
+	"FullAuditedAggregateRoot": {
+		"Id", "ExtraProperties", "ConcurrencyStamp",
+		"CreationTime", "CreatorId", "LastModificationTime", "LastModifierId",
+		"IsDeleted", "DeleterId", "DeletionTime",
+	},
+}
+
+// generatedMember is a member name the generator or an ABP base class adds to an entity
+type generatedMember struct {
+	Name         string
+	Source       string
+	IsForeignKey bool // Declared foreign key properties may intentionally match generated FK names
+}
+
+// generatedMembers computes the members added to an entity besides its declared properties
+func (e *Entity) generatedMembers() []generatedMember {
+	entityType := e.EntityType
+	if entityType == "" {
+		entityType = "FullAuditedAggregateRoot"
+	}
+
+	var members []generatedMember
+	for _, name := range auditMembers[entityType] {
+		members = append(members, generatedMember{Name: name, Source: entityType + " base member"})
+	}
+
+	if e.Relations == nil {
+		return members
+	}
+
+	for _, rel := range e.Relations.OneToOne {
+		members = append(members,
+			generatedMember{Name: defaultIfEmpty(rel.NavigationProperty, rel.TargetEntity), Source: "oneToOne navigation to " + rel.TargetEntity},
+		)
+		// Owned types are stored with the owner and have no foreign key
+		if !rel.IsOwned {
+			members = append(members,
+				generatedMember{Name: defaultIfEmpty(rel.ForeignKeyName, rel.TargetEntity+"Id"), Source: "oneToOne foreign key to " + rel.TargetEntity, IsForeignKey: true},
+			)
+		}
+	}
+	for _, rel := range e.Relations.ManyToOne {
+		members = append(members,
+			generatedMember{Name: defaultIfEmpty(rel.NavigationProperty, rel.TargetEntity), Source: "manyToOne navigation to " + rel.TargetEntity},
+			generatedMember{Name: defaultIfEmpty(rel.ForeignKeyName, rel.TargetEntity+"Id"), Source: "manyToOne foreign key to " + rel.TargetEntity, IsForeignKey: true},
+		)
+	}
+	for _, rel := range e.Relations.OneToMany {
+		members = append(members, generatedMember{
+			Name:   defaultIfEmpty(rel.NavigationProperty, Pluralize(rel.TargetEntity)),
+			Source: "oneToMany navigation to " + rel.TargetEntity,
+		})
+	}
+	for _, rel := range e.Relations.ManyToMany {
+		if rel.IsSelfReference || rel.TargetEntity == e.Name {
+			plural := Pluralize(rel.TargetEntity)
+			members = append(members,
+				generatedMember{Name: defaultIfEmpty(rel.NavigationProperty, "Target"+plural), Source: "manyToMany navigation to " + rel.TargetEntity},
+				generatedMember{Name: defaultIfEmpty(rel.InverseProperty, "Source"+plural), Source: "manyToMany inverse navigation from " + rel.TargetEntity},
+			)
+			continue
+		}
+		members = append(members, generatedMember{
+			Name:   defaultIfEmpty(rel.NavigationProperty, Pluralize(rel.TargetEntity)),
+			Source: "manyToMany navigation to " + rel.TargetEntity,
+		})
+	}
+
+	return members
+}
+
+// MemberCollisions reports declared properties that clash with generated members.
+// Such clashes otherwise surface as duplicate-member compile errors after generation.
+func (s *Schema) MemberCollisions() []MemberCollision {
+	var collisions []MemberCollision
+	for i := range s.Entities {
+		collisions = append(collisions, s.Entities[i].memberCollisions()...)
+	}
+	return collisions
+}
+
+// memberCollisions reports the entity's declared properties that clash with its generated members
+func (e *Entity) memberCollisions() []MemberCollision {
+	declared := make(map[string]Property, len(e.Properties))
+	for _, prop := range e.Properties {
+		declared[prop.Name] = prop
+	}
+
+	var collisions []MemberCollision
+	for _, member := range e.generatedMembers() {
+		prop, ok := declared[member.Name]
+		if !ok || (member.IsForeignKey && prop.IsForeignKey) {
+			continue
+		}
+		collisions = append(collisions, MemberCollision{
+			Entity:   e.Name,
+			Property: prop.Name,
+			Source:   member.Source,
+		})
+	}
+	return collisions
+}
+
+// ValidateStrict validates the schema and additionally rejects property/member name collisions
+// and foreign keys whose target entity is not declared in the schema
+func (s *Schema) ValidateStrict() error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	targetErr := s.validateForeignKeyTargets()
+
+	collisions := s.MemberCollisions()
+	if len(collisions) == 0 {
+		return targetErr
+	}
+
+	messages := make([]string, len(collisions))
+	for i, collision := range collisions {
+		messages[i] = collision.String()
+	}
+	return errors.Join(targetErr, fmt.Errorf("member name collisions:\n  %s", strings.Join(messages, "\n  ")))
+}
+
+// validateForeignKeyTargets checks that every foreign key references an entity declared in the schema
+func (s *Schema) validateForeignKeyTargets() error {
+	var errs []error
+	for _, entity := range s.Entities {
+		for _, prop := range entity.Properties {
+			if prop.IsForeignKey && s.FindEntity(prop.TargetEntity) == nil {
+				errs = append(errs, fmt.Errorf("entity '%s': foreign key '%s' targets unknown entity '%s'", entity.Name, prop.Name, prop.TargetEntity))
+			}
+		}
+
+		if entity.Relations == nil {
+			continue
+		}
+		for _, rel := range entity.Relations.ManyToOne {
+			if s.FindEntity(rel.TargetEntity) == nil {
+				errs = append(errs, fmt.Errorf("entity '%s': manyToOne targets unknown entity '%s'", entity.Name, rel.TargetEntity))
+			}
+		}
+		for _, rel := range entity.Relations.OneToOne {
+			if s.FindEntity(rel.TargetEntity) == nil {
+				errs = append(errs, fmt.Errorf("entity '%s': oneToOne targets unknown entity '%s'", entity.Name, rel.TargetEntity))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defaultIfEmpty returns fallback when value is empty
+func defaultIfEmpty(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
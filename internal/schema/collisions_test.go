@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemberCollisions_NavigationProperty(t *testing.T) {
+	sch := newValidSchema(
+		Entity{
+			Name: "Order",
+			Properties: []Property{
+				{Name: "Number", Type: "string"},
+				{Name: "Customer", Type: "string"},
+			},
+			Relations: &Relations{
+				ManyToOne: []ManyToOneRelation{{TargetEntity: "Customer"}},
+			},
+		},
+		Entity{Name: "Customer", Properties: []Property{{Name: "Name", Type: "string"}}},
+	)
+
+	collisions := sch.MemberCollisions()
+	if len(collisions) != 1 {
+		t.Fatalf("MemberCollisions() = %v; want exactly one collision", collisions)
+	}
+	got := collisions[0]
+	if got.Entity != "Order" || got.Property != "Customer" || got.Source != "manyToOne navigation to Customer" {
+		t.Errorf("MemberCollisions()[0] = %+v", got)
+	}
+
+	err := sch.ValidateStrict()
+	if err == nil || !strings.Contains(err.Error(), "property 'Customer' collides with the manyToOne navigation to Customer") {
+		t.Errorf("ValidateStrict() error = %v; want the navigation collision", err)
+	}
+}
+
+func TestMemberCollisions_AllowsDeclaredForeignKeysAndReportsBaseMembers(t *testing.T) {
+	sch := newValidSchema(
+		Entity{
+			Name: "Order",
+			Properties: []Property{
+				{Name: "CustomerId", Type: "Guid", IsForeignKey: true, TargetEntity: "Customer"},
+				{Name: "CreationTime", Type: "DateTime"},
+			},
+			Relations: &Relations{
+				ManyToOne: []ManyToOneRelation{{TargetEntity: "Customer"}},
+			},
+		},
+		Entity{Name: "Customer", EntityType: "Entity", Properties: []Property{{Name: "CreationTime", Type: "DateTime"}}},
+	)
+
+	collisions := sch.MemberCollisions()
+	if len(collisions) != 1 {
+		t.Fatalf("MemberCollisions() = %v; want only the Order.CreationTime collision", collisions)
+	}
+	if collisions[0].Property != "CreationTime" || collisions[0].Entity != "Order" {
+		t.Errorf("MemberCollisions()[0] = %+v", collisions[0])
+	}
+}
+
+func TestValidateStrict_UnknownForeignKeyTarget(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name: "Order",
+		Properties: []Property{
+			{Name: "CustomerId", Type: "Guid", IsForeignKey: true, TargetEntity: "Customer"},
+		},
+	})
+
+	if err := sch.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v; unknown targets are allowed outside strict mode", err)
+	}
+
+	err := sch.ValidateStrict()
+	if err == nil || !strings.Contains(err.Error(), "foreign key 'CustomerId' targets unknown entity 'Customer'") {
+		t.Errorf("ValidateStrict() error = %v; want the unknown target", err)
+	}
+}
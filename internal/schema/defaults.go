@@ -0,0 +1,364 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// guidPattern matches a GUID in its canonical 8-4-4-4-12 form
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// decimalPattern matches a plain decimal number usable as a C# numeric literal
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// dateTimeLayouts are the accepted formats for DateTime default values
+var dateTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// timePattern matches a TimeOnly or TimeSpan default value in hh:mm or hh:mm:ss form
+var timePattern = regexp.MustCompile(`^([0-9]{1,3}):([0-5][0-9])(?::([0-5][0-9]))?$`)
+
+// HasDefaultValue checks if the property declares a default value
+func (p Property) HasDefaultValue() bool {
+	return p.DefaultValue != ""
+}
+
+// IsCurrentTimeDefault checks if a DateTime or DateTimeOffset default means "the current time"
+func (p Property) IsCurrentTimeDefault() bool {
+	if p.Type != "DateTime" && p.Type != "DateTimeOffset" {
+		return false
+	}
+	switch strings.ToLower(p.DefaultValue) {
+	case "now", "utcnow":
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultValueLiteral renders DefaultValue as a C# literal of the property's type.
+// Values are assumed to have passed validation; see validateDefaultValue.
+func (p Property) DefaultValueLiteral() string {
+	value := p.DefaultValue
+
+	if p.IsEnum {
+		if n, err := strconv.Atoi(value); err == nil {
+			// (Status)-1 would parse as a subtraction
+			if n < 0 {
+				return fmt.Sprintf("(%s)(%s)", p.enumTypeName(), value)
+			}
+			return fmt.Sprintf("(%s)%s", p.enumTypeName(), value)
+		}
+		return qualifyEnumValue(p.enumTypeName(), value)
+	}
+
+	switch p.Type {
+	case "string":
+		return quoteCSharpString(value)
+	case "bool":
+		return strings.ToLower(value)
+	case "char":
+		return quoteCSharpChar(value)
+	case "int":
+		return value
+	case "short", "byte", "sbyte", "ushort":
+		// C# has no suffix for narrow integers; the cast keeps EF Core's HasDefaultValue and boxed
+		// seed values typed
+		return fmt.Sprintf("(%s)%s", p.Type, value)
+	case "long":
+		return value + "L"
+	case "uint":
+		return value + "u"
+	case "ulong":
+		return value + "UL"
+	case "decimal":
+		return value + "m"
+	case "double":
+		return value + "d"
+	case "float":
+		return value + "f"
+	case "Guid":
+		if strings.EqualFold(value, "empty") || strings.Trim(value, "0-") == "" {
+			return "Guid.Empty"
+		}
+		return fmt.Sprintf("Guid.Parse(%q)", value)
+	case "DateTime":
+		if p.IsCurrentTimeDefault() {
+			return "DateTime.UtcNow"
+		}
+		t, err := parseDateTimeDefault(value)
+		if err != nil {
+			return "default"
+		}
+		return fmt.Sprintf("new DateTime(%d, %d, %d, %d, %d, %d, DateTimeKind.Utc)",
+			t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second())
+	case "DateTimeOffset":
+		if p.IsCurrentTimeDefault() {
+			return "DateTimeOffset.UtcNow"
+		}
+		t, err := parseDateTimeDefault(value)
+		if err != nil {
+			return "default"
+		}
+		return fmt.Sprintf("new DateTimeOffset(%d, %d, %d, %d, %d, %d, TimeSpan.Zero)",
+			t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second())
+	case "DateOnly":
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "default"
+		}
+		return fmt.Sprintf("new DateOnly(%d, %d, %d)", t.Year(), int(t.Month()), t.Day())
+	case "TimeOnly", "TimeSpan":
+		hours, minutes, seconds, err := parseTimeDefault(value, p.Type == "TimeOnly")
+		if err != nil {
+			return "default"
+		}
+		return fmt.Sprintf("new %s(%d, %d, %d)", p.Type, hours, minutes, seconds)
+	default:
+		// Custom types: use the value as a C# expression
+		return value
+	}
+}
+
+// enumTypeName returns the C# type of an enum property
+func (p Property) enumTypeName() string {
+	if p.EnumName != "" {
+		return p.EnumName
+	}
+	return p.Type
+}
+
+// validateDefaultValue checks that DefaultValue can be rendered as a literal of the property's type
+func validateDefaultValue(prop *Property, enums []EnumDefinition) error {
+	value := prop.DefaultValue
+	if value == "" {
+		return nil
+	}
+
+	if prop.IsEnum {
+		// Numeric values are cast to the enum type
+		if _, err := strconv.Atoi(value); err == nil {
+			return nil
+		}
+
+		member := value
+		enumName := prop.enumTypeName()
+		if strings.HasPrefix(value, enumName+".") {
+			member = strings.TrimPrefix(value, enumName+".")
+		}
+		if !isValidIdentifier(member) {
+			return fmt.Errorf("defaultValue '%s' is not a valid %s member", value, enumName)
+		}
+		for _, enum := range enums {
+			if enum.Name != enumName {
+				continue
+			}
+			for _, enumValue := range enum.Values {
+				if enumValue.Name == member {
+					return nil
+				}
+			}
+			return fmt.Errorf("defaultValue '%s' is not a member of enum %s", value, enumName)
+		}
+		return nil
+	}
+
+	var err error
+	switch prop.Type {
+	case "bool":
+		if lower := strings.ToLower(value); lower != "true" && lower != "false" {
+			err = fmt.Errorf("must be true or false")
+		}
+	case "char":
+		if utf8.RuneCountInString(value) != 1 {
+			err = fmt.Errorf("must be a single character")
+		}
+	case "int":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "long":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "short":
+		_, err = strconv.ParseInt(value, 10, 16)
+	case "byte":
+		_, err = strconv.ParseUint(value, 10, 8)
+	case "sbyte":
+		_, err = strconv.ParseInt(value, 10, 8)
+	case "ushort":
+		_, err = strconv.ParseUint(value, 10, 16)
+	case "uint":
+		_, err = strconv.ParseUint(value, 10, 32)
+	case "ulong":
+		_, err = strconv.ParseUint(value, 10, 64)
+	case "decimal", "double", "float":
+		if !decimalPattern.MatchString(value) {
+			err = fmt.Errorf("must be a plain decimal number")
+		}
+	case "Guid":
+		if !strings.EqualFold(value, "empty") && !guidPattern.MatchString(value) {
+			err = fmt.Errorf("must be a GUID or 'empty'")
+		}
+	case "DateTime", "DateTimeOffset":
+		if !prop.IsCurrentTimeDefault() {
+			_, err = parseDateTimeDefault(value)
+		}
+	case "DateOnly":
+		_, err = time.Parse("2006-01-02", value)
+	case "TimeOnly", "TimeSpan":
+		_, _, _, err = parseTimeDefault(value, prop.Type == "TimeOnly")
+	}
+
+	if err != nil {
+		return fmt.Errorf("defaultValue '%s' is not a valid %s", value, prop.Type)
+	}
+	return nil
+}
+
+// parseDateTimeDefault parses a DateTime default value in one of the accepted layouts
+func parseDateTimeDefault(value string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid DateTime '%s'", value)
+}
+
+// parseTimeDefault parses a TimeOnly or TimeSpan default value in hh:mm or hh:mm:ss form.
+// A time of day must be before 24:00; a TimeSpan may span several days.
+func parseTimeDefault(value string, timeOfDay bool) (hours, minutes, seconds int, err error) {
+	match := timePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, 0, 0, fmt.Errorf("invalid time '%s'", value)
+	}
+	hours, _ = strconv.Atoi(match[1])
+	minutes, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		seconds, _ = strconv.Atoi(match[3])
+	}
+	if timeOfDay && hours > 23 {
+		return 0, 0, 0, fmt.Errorf("invalid time of day '%s'", value)
+	}
+	return hours, minutes, seconds, nil
+}
+
+// qualifyEnumValue prefixes an enum member with its type unless it is already qualified
+func qualifyEnumValue(enumName, value string) string {
+	if strings.HasPrefix(value, enumName+".") {
+		return value
+	}
+	return enumName + "." + value
+}
+
+// quoteCSharpString renders a string as a C# string literal
+func quoteCSharpString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// quoteCSharpChar renders a single character as a C# char literal
+func quoteCSharpChar(value string) string {
+	switch value {
+	case `\`:
+		return `'\\'`
+	case "'":
+		return `'\''`
+	case "\n":
+		return `'\n'`
+	case "\r":
+		return `'\r'`
+	case "\t":
+		return `'\t'`
+	default:
+		return "'" + value + "'"
+	}
+}
+
+// SeedValueLiteral renders a seedData value as a C# expression of the property's type.
+// Values are assumed to have passed validation; see validateSeedData.
+func (p Property) SeedValueLiteral(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	// Seed values are boxed into object[] rows, so the literal must keep the property's type
+	p.DefaultValue, _ = seedValueString(value)
+	return p.DefaultValueLiteral()
+}
+
+// seedValueString converts a decoded JSON scalar to the string form used by default values
+func seedValueString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// validateSeedData checks that seedData rows only name constructor properties, with values
+// of the property's type
+func validateSeedData(entity *Entity, enums []EnumDefinition) []error {
+	inputs := make(map[string]Property)
+	for _, prop := range entity.GetInputProperties() {
+		inputs[prop.Name] = prop
+	}
+
+	var errs []error
+	for i, row := range entity.SeedData {
+		names := make([]string, 0, len(row))
+		for name := range row {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := row[name]
+			prop, ok := inputs[name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("seedData[%d]: '%s' is not a constructor property", i, name))
+				continue
+			}
+			if value == nil {
+				if !prop.Nullable {
+					errs = append(errs, fmt.Errorf("seedData[%d]: '%s' is not nullable", i, name))
+				}
+				continue
+			}
+			if !prop.IsEnum && !IsFilterableType(prop.Type) {
+				errs = append(errs, fmt.Errorf("seedData[%d]: '%s' of type %s cannot be seeded", i, name, prop.Type))
+				continue
+			}
+
+			str, ok := seedValueString(value)
+			if ok {
+				_, isString := value.(string)
+				switch {
+				case prop.IsEnum:
+					ok = str != ""
+				case prop.Type == "string", prop.Type == "Guid", prop.Type == "DateTime":
+					ok = isString
+				default:
+					ok = !isString && str != ""
+				}
+			}
+			prop.DefaultValue = str
+			if !ok || validateDefaultValue(&prop, enums) != nil {
+				errs = append(errs, fmt.Errorf("seedData[%d]: '%s' value %v is not a valid %s", i, name, value, prop.Type))
+			}
+		}
+	}
+	return errs
+}
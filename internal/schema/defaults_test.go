@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProperty_DefaultValueLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		prop     Property
+		expected string
+	}{
+		{"bool", Property{Type: "bool", DefaultValue: "True"}, "true"},
+		{"int", Property{Type: "int", DefaultValue: "42"}, "42"},
+		{"decimal", Property{Type: "decimal", DefaultValue: "9.99"}, "9.99m"},
+		{"string", Property{Type: "string", DefaultValue: `say "hi"`}, `"say \"hi\""`},
+		{"enum member", Property{Type: "OrderStatus", IsEnum: true, EnumName: "OrderStatus", DefaultValue: "Pending"}, "OrderStatus.Pending"},
+		{"qualified enum member", Property{Type: "OrderStatus", IsEnum: true, DefaultValue: "OrderStatus.Pending"}, "OrderStatus.Pending"},
+		{"numeric enum value", Property{Type: "OrderStatus", IsEnum: true, DefaultValue: "0"}, "(OrderStatus)0"},
+		{"negative enum value", Property{Type: "OrderStatus", IsEnum: true, DefaultValue: "-1"}, "(OrderStatus)(-1)"},
+		{"empty guid", Property{Type: "Guid", DefaultValue: "empty"}, "Guid.Empty"},
+		{"current time", Property{Type: "DateTime", DefaultValue: "now"}, "DateTime.UtcNow"},
+		{"fixed date", Property{Type: "DateTime", DefaultValue: "2024-01-31"}, "new DateTime(2024, 1, 31, 0, 0, 0, DateTimeKind.Utc)"},
+		{"char", Property{Type: "char", DefaultValue: "'"}, `'\''`},
+		{"uint", Property{Type: "uint", DefaultValue: "7"}, "7u"},
+		{"short", Property{Type: "short", DefaultValue: "5"}, "(short)5"},
+		{"sbyte", Property{Type: "sbyte", DefaultValue: "-5"}, "(sbyte)-5"},
+		{"ushort", Property{Type: "ushort", DefaultValue: "5"}, "(ushort)5"},
+		{"ulong", Property{Type: "ulong", DefaultValue: "7"}, "7UL"},
+		{"offset current time", Property{Type: "DateTimeOffset", DefaultValue: "utcnow"}, "DateTimeOffset.UtcNow"},
+		{"fixed offset", Property{Type: "DateTimeOffset", DefaultValue: "2024-01-31T08:30:00Z"}, "new DateTimeOffset(2024, 1, 31, 8, 30, 0, TimeSpan.Zero)"},
+		{"date only", Property{Type: "DateOnly", DefaultValue: "2024-01-31"}, "new DateOnly(2024, 1, 31)"},
+		{"time only", Property{Type: "TimeOnly", DefaultValue: "09:30"}, "new TimeOnly(9, 30, 0)"},
+		{"time span", Property{Type: "TimeSpan", DefaultValue: "36:00:15"}, "new TimeSpan(36, 0, 15)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.prop.DefaultValueLiteral()
+			if result != tt.expected {
+				t.Errorf("DefaultValueLiteral() = %q; want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate_DefaultValueMustParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		prop    Property
+		wantErr string
+	}{
+		{"valid bool", Property{Name: "IsActive", Type: "bool", DefaultValue: "true"}, ""},
+		{"invalid bool", Property{Name: "IsActive", Type: "bool", DefaultValue: "yes"}, "is not a valid bool"},
+		{"invalid int", Property{Name: "Quantity", Type: "int", DefaultValue: "1.5"}, "is not a valid int"},
+		{"invalid decimal", Property{Name: "Price", Type: "decimal", DefaultValue: "NaN"}, "is not a valid decimal"},
+		{"invalid char", Property{Name: "Grade", Type: "char", DefaultValue: "AB"}, "is not a valid char"},
+		{"negative uint", Property{Name: "Count", Type: "uint", DefaultValue: "-1"}, "is not a valid uint"},
+		{"invalid guid", Property{Name: "Token", Type: "Guid", DefaultValue: "abc"}, "is not a valid Guid"},
+		{"invalid date only", Property{Name: "Since", Type: "DateOnly", DefaultValue: "2024-02-30"}, "is not a valid DateOnly"},
+		{"invalid time only", Property{Name: "OpensAt", Type: "TimeOnly", DefaultValue: "25:00"}, "is not a valid TimeOnly"},
+		{"valid time span", Property{Name: "Timeout", Type: "TimeSpan", DefaultValue: "25:00"}, ""},
+		{"current time offset", Property{Name: "SeenAt", Type: "DateTimeOffset", DefaultValue: "now"}, ""},
+		{"unknown enum member", Property{Name: "Status", Type: "OrderStatus", IsEnum: true, DefaultValue: "Archived"}, "is not a member of enum OrderStatus"},
+		{"known enum member", Property{Name: "Status", Type: "OrderStatus", IsEnum: true, DefaultValue: "Pending"}, ""},
+		{"numeric enum value", Property{Name: "Status", Type: "OrderStatus", IsEnum: true, DefaultValue: "0"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Order",
+				Properties: []Property{tt.prop},
+				Enums: []EnumDefinition{
+					{Name: "OrderStatus", Values: []EnumValue{{Name: "Pending", Value: "0"}}},
+				},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SeedData(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     SeedRow
+		wantErr string
+	}{
+		{"valid row", SeedRow{"Name": "Widget", "Quantity": 3.0, "Status": "Pending"}, ""},
+		{"unknown property", SeedRow{"Sku": "W-1"}, "seedData[0]: 'Sku' is not a constructor property"},
+		{"number for string", SeedRow{"Name": 5.0}, "seedData[0]: 'Name' value 5 is not a valid string"},
+		{"fraction for int", SeedRow{"Quantity": 1.5}, "seedData[0]: 'Quantity' value 1.5 is not a valid int"},
+		{"null for non-nullable", SeedRow{"Quantity": nil}, "seedData[0]: 'Quantity' is not nullable"},
+		{"unknown enum member", SeedRow{"Status": "Archived"}, "seedData[0]: 'Status' value Archived is not a valid OrderStatus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name: "Order",
+				Properties: []Property{
+					{Name: "Name", Type: "string"},
+					{Name: "Quantity", Type: "int"},
+					{Name: "Status", Type: "OrderStatus", IsEnum: true},
+				},
+				Enums: []EnumDefinition{
+					{Name: "OrderStatus", Values: []EnumValue{{Name: "Pending", Value: "0"}}},
+				},
+				SeedData: []SeedRow{tt.row},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProperty_SeedValueLiteralKeepsNarrowIntegerTypes(t *testing.T) {
+	for _, typeName := range []string{"short", "byte", "sbyte", "ushort"} {
+		prop := Property{Type: typeName}
+		if got, want := prop.SeedValueLiteral(3.0), "("+typeName+")3"; got != want {
+			t.Errorf("SeedValueLiteral() for %s = %q; want %q", typeName, got, want)
+		}
+	}
+}
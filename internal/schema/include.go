@@ -0,0 +1,166 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// includeKey is the top-level schema key listing files or glob patterns whose entities
+// are appended to the schema's entities
+const includeKey = "$include"
+
+// includeResolver composes a schema from the files it includes
+type includeResolver struct {
+	stack    []string        // Files being resolved, outermost first, to detect cycles
+	included []string        // Every included file in resolution order
+	seen     map[string]bool // Files already included, so overlapping patterns add them once
+}
+
+// resolveIncludes replaces string entries of the schema's entities with the entities of the
+// files they name and appends the entities of the files matched by $include. Paths are
+// relative to the file that references them. It returns the composed schema and the files
+// it included; data is returned unchanged when the schema includes nothing.
+func resolveIncludes(data []byte, path string) ([]byte, []string, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		// Leave reporting malformed schemas to the regular decoding
+		return data, nil, nil
+	}
+
+	var entities []json.RawMessage
+	if raw, ok := root["entities"]; ok {
+		if err := json.Unmarshal(raw, &entities); err != nil {
+			return data, nil, nil
+		}
+	}
+
+	var patterns []string
+	if raw, ok := root[includeKey]; ok {
+		if err := json.Unmarshal(raw, &patterns); err != nil {
+			return nil, nil, fmt.Errorf("%s must be an array of file paths or glob patterns: %w", includeKey, err)
+		}
+	}
+
+	if len(patterns) == 0 && !hasIncludeEntries(entities) {
+		return data, nil, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &includeResolver{stack: []string{abs}, seen: map[string]bool{abs: true}}
+
+	resolved, err := r.resolveEntities(entities, filepath.Dir(abs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(resolvePath(filepath.Dir(abs), pattern))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s pattern %q: %w", includeKey, pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, nil, fmt.Errorf("%s pattern %q matched no files", includeKey, pattern)
+		}
+		for _, match := range matches {
+			if r.seen[match] {
+				continue
+			}
+			included, err := r.includeFile(match)
+			if err != nil {
+				return nil, nil, err
+			}
+			resolved = append(resolved, included...)
+		}
+	}
+
+	if resolved == nil {
+		resolved = []json.RawMessage{}
+	}
+	if root["entities"], err = json.Marshal(resolved); err != nil {
+		return nil, nil, err
+	}
+	delete(root, includeKey)
+
+	composed, err := json.Marshal(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return composed, r.included, nil
+}
+
+// resolveEntities replaces each string entry with the entities of the file it names
+func (r *includeResolver) resolveEntities(entries []json.RawMessage, dir string) ([]json.RawMessage, error) {
+	var resolved []json.RawMessage
+	for _, entry := range entries {
+		var ref string
+		if err := json.Unmarshal(entry, &ref); err != nil {
+			resolved = append(resolved, entry)
+			continue
+		}
+
+		included, err := r.includeFile(resolvePath(dir, ref))
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, included...)
+	}
+	return resolved, nil
+}
+
+// includeFile returns the entities of an included file, which holds a single entity or an
+// array of entities and paths of further files
+func (r *includeResolver) includeFile(path string) ([]json.RawMessage, error) {
+	for _, parent := range r.stack {
+		if parent == path {
+			return nil, fmt.Errorf("schema include cycle: %s", strings.Join(append(r.stack, path), " -> "))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read included schema file: %w", err)
+	}
+	r.seen[path] = true
+	r.included = append(r.included, path)
+
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, "[") {
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("failed to parse included schema file %s: invalid JSON", path)
+		}
+		return []json.RawMessage{json.RawMessage(trimmed)}, nil
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse included schema file %s: %w", path, err)
+	}
+
+	r.stack = append(r.stack, path)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+	return r.resolveEntities(entries, filepath.Dir(path))
+}
+
+// hasIncludeEntries reports whether any entity entry is a file path
+func hasIncludeEntries(entries []json.RawMessage) bool {
+	for _, entry := range entries {
+		if strings.HasPrefix(strings.TrimSpace(string(entry)), `"`) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath resolves path relative to dir unless it is absolute
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSchemaFiles writes files relative to a new temporary directory and returns the directory
+func writeSchemaFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadFromFile_ResolvesIncludes(t *testing.T) {
+	dir := writeSchemaFiles(t, map[string]string{
+		"schema.json": `{
+  "solution": {"name": "Shop", "moduleName": "Catalog"},
+  "$include": ["entities/*.json"],
+  "entities": [
+    {"name": "Product", "properties": [{"name": "Name", "type": "string"}]},
+    "shared/tag.json"
+  ]
+}`,
+		"shared/tag.json":        `{"name": "Tag", "properties": [{"name": "Label", "type": "string"}]}`,
+		"entities/category.json": `{"name": "Category", "properties": [{"name": "Title", "type": "string"}]}`,
+		"entities/orders.json":   `[{"name": "Order", "properties": [{"name": "Number", "type": "string"}]}, "../lines/line.json"]`,
+		"lines/line.json":        `{"name": "OrderLine", "properties": [{"name": "Quantity", "type": "int"}]}`,
+	})
+
+	sch, err := LoadFromFile(filepath.Join(dir, "schema.json"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	var names []string
+	for _, entity := range sch.Entities {
+		names = append(names, entity.Name)
+	}
+	if got, want := strings.Join(names, ","), "Product,Tag,Category,Order,OrderLine"; got != want {
+		t.Errorf("entities = %s, want %s", got, want)
+	}
+	if len(sch.IncludedFiles()) != 4 {
+		t.Errorf("IncludedFiles() = %v, want 4 files", sch.IncludedFiles())
+	}
+	if err := sch.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadFromFile_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"schema.json": `{"solution": {"name": "Shop"}, "entities": ["a.json"]}`,
+				"a.json":      `["b.json"]`,
+				"b.json":      `["a.json"]`,
+			},
+			wantErr: "schema include cycle",
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				"schema.json": `{"solution": {"name": "Shop"}, "entities": ["missing.json"]}`,
+			},
+			wantErr: "failed to read included schema file",
+		},
+		{
+			name: "pattern without matches",
+			files: map[string]string{
+				"schema.json": `{"solution": {"name": "Shop"}, "$include": ["entities/*.json"], "entities": []}`,
+			},
+			wantErr: `$include pattern "entities/*.json" matched no files`,
+		},
+		{
+			name: "invalid included JSON",
+			files: map[string]string{
+				"schema.json": `{"solution": {"name": "Shop"}, "entities": ["broken.json"]}`,
+				"broken.json": `{"name": `,
+			},
+			wantErr: "failed to parse included schema file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeSchemaFiles(t, tt.files)
+			_, err := LoadFromFile(filepath.Join(dir, "schema.json"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFromFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
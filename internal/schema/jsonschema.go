@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the exported document
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchemaRequired lists the fields each schema type requires, by JSON name. The solution and
+// its names are left out: they are detected from the solution or prompted for, and the entities
+// may all come from $include.
+var jsonSchemaRequired = map[string][]string{
+	"BackgroundWorker":   {"name", "intervalSeconds"},
+	"Entity":             {"name"},
+	"IndexDefinition":    {"properties"},
+	"Property":           {"name", "type"},
+	"OneToOneRelation":   {"targetEntity"},
+	"ManyToOneRelation":  {"targetEntity"},
+	"OneToManyRelation":  {"targetEntity"},
+	"ManyToManyRelation": {"targetEntity"},
+	"RepositoryMethod":   {"name", "returnType"},
+	"MethodParameter":    {"name", "type"},
+	"DomainEvent":        {"name", "type"},
+	"EventProperty":      {"name", "type"},
+	"EventHandler":       {"name"},
+	"EnumDefinition":     {"name", "values"},
+	"EnumValue":          {"name"},
+	"ValidationRule":     {"type"},
+}
+
+// jsonSchemaEnums lists the accepted values of enumerated fields, keyed by "Type.jsonName"
+var jsonSchemaEnums = map[string][]string{
+	"Solution.targetFramework": {
+		string(TargetAuto), string(TargetASPNETCore9), string(TargetASPNETCore10),
+		string(TargetABP8Monolith), string(TargetABP8Microservice),
+		string(TargetABP9Monolith), string(TargetABP9Microservice),
+		string(TargetABP10Monolith), string(TargetABP10Microservice),
+	},
+	"Solution.primaryKeyType":            {"Guid", "long", "configurable"},
+	"Solution.dbProvider":                {"efcore", "mongodb", "both"},
+	"Solution.generationMode":            {string(GenerationModeExisting), string(GenerationModeNew)},
+	"MultiTenancy.strategy":              {"none", "host", "tenant-per-db", "tenant-per-schema"},
+	"MultiTenancy.tenantIdType":          {"Guid", "long", "string"},
+	"Entity.entityType":                  {"Entity", "AggregateRoot", "CreationAuditedAggregateRoot", "AuditedAggregateRoot", "FullAuditedAggregateRoot", "ValueObject"},
+	"Entity.primaryKeyUnderlyingType":    {"Guid", "long"},
+	"Entity.operations":                  AllOperations,
+	"Options.validationType":             {"fluentvalidation", "native"},
+	"Options.mappingLibrary":             {"automapper", "mapperly"},
+	"LocalizationMerge.conflictStrategy": {"overwrite", "append", "skip"},
+	"DomainEvent.type":                   {"domain", "distributed"},
+	"EventHandler.handlerType":           {"local", "distributed", "integration"},
+	"ValidationRule.operator":            CompareOperators,
+}
+
+// JSONSchema builds a JSON Schema (draft-07) document describing the schema file format.
+// It is reflected from the Schema types and their json tags, so it follows the Go types as they change.
+func JSONSchema() map[string]interface{} {
+	definitions := make(map[string]interface{})
+	root := jsonSchemaObject(reflect.TypeOf(Schema{}), definitions)
+
+	// Entities may also be paths of files holding them, and $include adds the entities of matching files
+	properties := root["properties"].(map[string]interface{})
+	entities := properties["entities"].(map[string]interface{})
+	entities["items"] = map[string]interface{}{
+		"oneOf": []interface{}{entities["items"], map[string]interface{}{"type": "string"}},
+	}
+	properties[includeKey] = map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}
+
+	root["$schema"] = jsonSchemaDraft
+	root["title"] = "abp-gen schema"
+	root["definitions"] = definitions
+	return root
+}
+
+// MarshalJSONSchema encodes the JSON Schema document with indentation
+func MarshalJSONSchema() ([]byte, error) {
+	data, err := json.MarshalIndent(JSONSchema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonSchemaObject describes a struct type, adding the struct types it references to definitions
+func jsonSchemaObject(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := jsonSchemaType(field.Type, definitions)
+		if values, ok := jsonSchemaEnums[t.Name()+"."+name]; ok {
+			if items, isArray := property["items"].(map[string]interface{}); isArray {
+				items["enum"] = values
+			} else {
+				property["enum"] = values
+			}
+		}
+		properties[name] = property
+	}
+
+	object := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if required, ok := jsonSchemaRequired[t.Name()]; ok {
+		object["required"] = required
+	}
+	return object
+}
+
+// jsonSchemaType describes a field type; struct types become references to definitions
+func jsonSchemaType(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonSchemaType(t.Elem(), definitions)
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": jsonSchemaType(t.Elem(), definitions),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": jsonSchemaType(t.Elem(), definitions),
+		}
+	case reflect.Interface:
+		// Any JSON value
+		return map[string]interface{}{}
+	case reflect.Struct:
+		if _, ok := definitions[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			definitions[t.Name()] = nil
+			definitions[t.Name()] = jsonSchemaObject(t, definitions)
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
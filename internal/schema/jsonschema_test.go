@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema_DescribesSchemaTypes(t *testing.T) {
+	data, err := MarshalJSONSchema()
+	if err != nil {
+		t.Fatalf("MarshalJSONSchema() error = %v", err)
+	}
+
+	var doc struct {
+		Schema      string   `json:"$schema"`
+		Required    []string `json:"required"`
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Type string   `json:"type"`
+				Ref  string   `json:"$ref"`
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("JSON Schema is not valid JSON: %v", err)
+	}
+
+	if doc.Schema != "http://json-schema.org/draft-07/schema#" {
+		t.Errorf("$schema = %q; want draft-07", doc.Schema)
+	}
+	if len(doc.Required) != 0 {
+		t.Errorf("root required = %v; want none, as entities may come from $include", doc.Required)
+	}
+	if got := doc.Definitions["Solution"].Required; len(got) != 0 {
+		t.Errorf("Solution required = %v; want none, as the names may be detected", got)
+	}
+
+	entity, ok := doc.Definitions["Entity"]
+	if !ok {
+		t.Fatalf("definitions missing Entity: %s", data)
+	}
+	entityType := reflect.TypeOf(Entity{})
+	for i := 0; i < entityType.NumField(); i++ {
+		name := strings.Split(entityType.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := entity.Properties[name]; !ok {
+			t.Errorf("Entity definition missing field %q", name)
+		}
+	}
+	if got := entity.Properties["entityType"].Enum; len(got) == 0 || got[0] != "Entity" {
+		t.Errorf("entityType enum = %v", got)
+	}
+	if got := entity.Properties["relations"].Ref; got != "#/definitions/Relations" {
+		t.Errorf("relations $ref = %q", got)
+	}
+	if got := doc.Definitions["Solution"].Properties["dbProvider"].Enum; !reflect.DeepEqual(got, []string{"efcore", "mongodb", "both"}) {
+		t.Errorf("dbProvider enum = %v", got)
+	}
+	if got := doc.Definitions["Property"].Required; !reflect.DeepEqual(got, []string{"name", "type"}) {
+		t.Errorf("Property required = %v", got)
+	}
+}
+
+func TestSchemaURI_RoundTrips(t *testing.T) {
+	sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+	sch.SchemaURI = "./abp-gen.schema.json"
+
+	data, err := sch.MarshalMinimal()
+	if err != nil {
+		t.Fatalf("MarshalMinimal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"$schema": "./abp-gen.schema.json"`) {
+		t.Errorf("saved schema dropped $schema:\n%s", data)
+	}
+}
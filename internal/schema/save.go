@@ -0,0 +1,355 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// orderedObject is a JSON object that remembers the order of its keys
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// MarshalMinimal encodes the schema without default-valued fields.
+// Keys keep the order of the file the schema was loaded from; new keys follow in struct order.
+func (s *Schema) MarshalMinimal() ([]byte, error) {
+	return s.marshalPruned(false)
+}
+
+// MarshalCanonical encodes the schema without default-valued fields and with keys sorted,
+// producing a normalized form that is stable for diffing
+func (s *Schema) MarshalCanonical() ([]byte, error) {
+	return s.marshalPruned(true)
+}
+
+// SaveCanonicalToFile saves the schema to a JSON file in canonical form
+func (s *Schema) SaveCanonicalToFile(path string) error {
+	data, err := s.MarshalCanonical()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *Schema) marshalPruned(canonical bool) ([]byte, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if canonical {
+		sortKeys(value)
+	} else if len(s.source) > 0 {
+		// The original file is only a hint for key order; ignore it if it can't be parsed
+		if original, err := decodeOrdered(s.source); err == nil {
+			applyKeyOrder(value, original)
+		}
+	}
+
+	value, _ = pruneDefaults(value, reflect.TypeOf(s).Elem())
+	if value == nil {
+		value = &orderedObject{values: map[string]interface{}{}}
+	}
+
+	var buf bytes.Buffer
+	if err := writeIndented(&buf, value, ""); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered decodes JSON into ordered objects, slices, and scalar values
+func decodeOrdered(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeOrderedValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &orderedObject{values: make(map[string]interface{})}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected object key, got %v", keyTok)
+				}
+				value, err := decodeOrderedValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, exists := obj.values[key]; !exists {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = value
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+
+		case '[':
+			arr := []interface{}{}
+			for dec.More() {
+				value, err := decodeOrderedValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return arr, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+
+	default:
+		return tok, nil
+	}
+}
+
+// applyKeyOrder reorders object keys in value to follow the order used in original
+func applyKeyOrder(value, original interface{}) {
+	switch v := value.(type) {
+	case *orderedObject:
+		orig, ok := original.(*orderedObject)
+		if !ok {
+			return
+		}
+
+		keys := make([]string, 0, len(v.keys))
+		for _, key := range orig.keys {
+			if _, exists := v.values[key]; exists {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range v.keys {
+			if _, exists := orig.values[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		v.keys = keys
+
+		for _, key := range v.keys {
+			applyKeyOrder(v.values[key], orig.values[key])
+		}
+
+	case []interface{}:
+		orig, ok := original.([]interface{})
+		if !ok {
+			return
+		}
+		for i := range v {
+			if i < len(orig) {
+				applyKeyOrder(v[i], orig[i])
+			}
+		}
+	}
+}
+
+// sortKeys sorts object keys alphabetically at every level
+func sortKeys(value interface{}) {
+	switch v := value.(type) {
+	case *orderedObject:
+		sort.Strings(v.keys)
+		for _, key := range v.keys {
+			sortKeys(v.values[key])
+		}
+	case []interface{}:
+		for _, item := range v {
+			sortKeys(item)
+		}
+	}
+}
+
+// pruneDefaults removes null, false, zero, empty string, and empty collection values.
+// It returns false when the value itself is a default and should be omitted. t is the Go type
+// the value was encoded from: optional pointer fields such as requireAuthorization are only
+// present when set, so their explicit false or zero is kept, as are the free-form values of
+// seedData rows.
+func pruneDefaults(value interface{}, t reflect.Type) (interface{}, bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+
+	case bool:
+		return v, v || t == nil
+
+	case string:
+		return v, v != "" || t == nil
+
+	case json.Number:
+		f, err := v.Float64()
+		return v, err != nil || f != 0 || t == nil
+
+	case *orderedObject:
+		fields := jsonFieldTypes(t)
+		keys := v.keys[:0]
+		for _, key := range v.keys {
+			pruned, keep := pruneDefaults(v.values[key], fields(key))
+			if keep {
+				v.values[key] = pruned
+				keys = append(keys, key)
+			} else {
+				delete(v.values, key)
+			}
+		}
+		v.keys = keys
+		return v, len(v.keys) > 0
+
+	case []interface{}:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		// Array elements are kept even when empty so positions stay meaningful
+		for i, item := range v {
+			v[i], _ = pruneDefaults(item, elem)
+		}
+		return v, len(v) > 0
+
+	default:
+		return v, true
+	}
+}
+
+// jsonFieldTypes returns a lookup of the Go type encoded under each JSON key of an object of
+// type t. Scalars under pointer fields, and every value of a map of interfaces, map to nil,
+// which keeps them even when they hold a zero value.
+func jsonFieldTypes(t reflect.Type) func(key string) reflect.Type {
+	if t == nil {
+		return func(string) reflect.Type { return nil }
+	}
+	if t.Kind() == reflect.Map {
+		elem := t.Elem()
+		if elem.Kind() == reflect.Interface {
+			elem = nil
+		}
+		return func(string) reflect.Type { return elem }
+	}
+
+	fields := make(map[string]reflect.Type)
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() != reflect.Struct {
+				fieldType = nil
+			}
+			fields[name] = fieldType
+		}
+	}
+	return func(key string) reflect.Type { return fields[key] }
+}
+
+// writeIndented writes value as JSON indented with two spaces
+func writeIndented(buf *bytes.Buffer, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case *orderedObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+
+		buf.WriteString("{\n")
+		inner := indent + "  "
+		for i, key := range v.keys {
+			buf.WriteString(inner)
+			if err := writeScalar(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := writeIndented(buf, v.values[key], inner); err != nil {
+				return err
+			}
+			if i < len(v.keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+		return nil
+
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+
+		buf.WriteString("[\n")
+		inner := indent + "  "
+		for i, item := range v {
+			buf.WriteString(inner)
+			if err := writeIndented(buf, item, inner); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+		return nil
+
+	case json.Number:
+		buf.WriteString(v.String())
+		return nil
+
+	default:
+		return writeScalar(buf, v)
+	}
+}
+
+// writeScalar writes a JSON scalar without escaping HTML characters
+func writeScalar(buf *bytes.Buffer, value interface{}) error {
+	var scalar bytes.Buffer
+	enc := json.NewEncoder(&scalar)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	buf.WriteString(strings.TrimSuffix(scalar.String(), "\n"))
+	return nil
+}
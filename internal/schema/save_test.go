@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarshalMinimal_OmitsEmptyOptionalBlocks(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name:       "Product",
+		Properties: []Property{{Name: "Name", Type: "string", IsRequired: true}},
+	})
+
+	data, err := sch.MarshalMinimal()
+	if err != nil {
+		t.Fatalf("MarshalMinimal() error = %v", err)
+	}
+	content := string(data)
+
+	for _, unwanted := range []string{`"relations"`, `"enums"`, `"options"`, `"tableName"`, `"nullable"`, `"generateControllers"`, "null"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("minimal schema contains %s:\n%s", unwanted, content)
+		}
+	}
+	for _, wanted := range []string{`"name": "Shop"`, `"moduleName": "Catalog"`, `"isRequired": true`} {
+		if !strings.Contains(content, wanted) {
+			t.Errorf("minimal schema missing %s:\n%s", wanted, content)
+		}
+	}
+}
+
+func TestSaveToFile_PreservesLoadedKeyOrder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+	original := `{
+  "entities": [
+    {
+      "properties": [
+        {
+          "type": "string",
+          "name": "Name"
+        }
+      ],
+      "name": "Product"
+    }
+  ],
+  "solution": {
+    "moduleName": "Catalog",
+    "name": "Shop"
+  }
+}
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+
+	sch, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if err := sch.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read saved schema: %v", err)
+	}
+	if string(saved) != original {
+		t.Errorf("saved schema changed key order or formatting:\ngot:\n%s\nwant:\n%s", saved, original)
+	}
+}
+
+func TestMarshalCanonical_SortsKeys(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name:       "Product",
+		TableName:  "Products",
+		Properties: []Property{{Name: "Name", Type: "string", MaxLength: 128}},
+	})
+
+	data, err := sch.MarshalCanonical()
+	if err != nil {
+		t.Fatalf("MarshalCanonical() error = %v", err)
+	}
+
+	expected := `{
+  "entities": [
+    {
+      "name": "Product",
+      "properties": [
+        {
+          "maxLength": 128,
+          "name": "Name",
+          "type": "string"
+        }
+      ],
+      "tableName": "Products"
+    }
+  ],
+  "solution": {
+    "moduleName": "Catalog",
+    "name": "Shop"
+  }
+}
+`
+	if string(data) != expected {
+		t.Errorf("MarshalCanonical() =\n%s\nwant:\n%s", data, expected)
+	}
+}
+
+func TestSaveToFile_KeepsExplicitFalseOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+	disabled := false
+	sch := newValidSchema(Entity{
+		Name:                 "Country",
+		Properties:           []Property{{Name: "Name", Type: "string"}},
+		RequireAuthorization: &disabled,
+	})
+
+	if err := sch.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if loaded.Entities[0].RequiresAuthorization() {
+		t.Errorf("requireAuthorization: false was lost on save")
+	}
+}
+
+func TestSaveToFile_KeepsManagerOptOut(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+	disabled := false
+	sch := newValidSchema(Entity{
+		Name:               "Product",
+		EntityType:         "FullAuditedAggregateRoot",
+		Properties:         []Property{{Name: "Name", Type: "string"}},
+		GenerateManager:    &disabled,
+		GenerateController: &disabled,
+	})
+
+	if err := sch.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	entity := loaded.Entities[0]
+	if entity.ShouldGenerateManager(false) {
+		t.Errorf("generateManager: false was lost on save")
+	}
+	if entity.ShouldGenerateController(true) {
+		t.Errorf("generateController: false was lost on save")
+	}
+}
@@ -2,14 +2,24 @@ package schema
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/csharp"
 )
 
 // Schema represents the complete ABP code generation schema
 type Schema struct {
-	Solution Solution `json:"solution"`
-	Entities []Entity `json:"entities"`
-	Options  Options  `json:"options"`
+	SchemaURI string   `json:"$schema,omitempty"` // JSON Schema reference for editor validation
+	Solution  Solution `json:"solution"`
+	Entities  []Entity `json:"entities"`
+	Options   Options  `json:"options"`
+
+	source   []byte   // Original JSON the schema was loaded from, used to keep key order on save
+	included []string // Files whose entities were included into the schema on load
 }
 
 // TargetFramework represents the target framework type
@@ -27,6 +37,16 @@ const (
 	TargetAuto              TargetFramework = "auto" // Auto-detect
 )
 
+// IsASPNETCore reports whether the target is a plain ASP.NET Core application rather than an ABP solution
+func (t TargetFramework) IsASPNETCore() bool {
+	return t == TargetASPNETCore9 || t == TargetASPNETCore10
+}
+
+// IsMicroservice reports whether the target is an ABP microservice solution
+func (t TargetFramework) IsMicroservice() bool {
+	return t == TargetABP8Microservice || t == TargetABP9Microservice || t == TargetABP10Microservice
+}
+
 // GenerationMode represents the generation mode
 type GenerationMode string
 
@@ -37,50 +57,96 @@ const (
 
 // Solution represents solution-level configuration
 type Solution struct {
-	Name                string          `json:"name"`
-	ModuleName          string          `json:"moduleName"`
-	NamespaceRoot       string          `json:"namespaceRoot"`
-	ModuleSuffix        string          `json:"moduleSuffix,omitempty"` // Optional suffix for module (e.g., "Module", "Service", or empty)
-	FolderPrefix        string          `json:"folderPrefix,omitempty"` // Optional prefix for folder names
-	ABPVersion          string          `json:"abpVersion"`
-	TargetFramework     TargetFramework `json:"targetFramework"` // Target framework type
-	PrimaryKeyType      string          `json:"primaryKeyType"`  // "Guid" or "long" or "configurable"
-	DBProvider          string          `json:"dbProvider"`      // "efcore" or "mongodb" or "both"
-	GenerateControllers bool            `json:"generateControllers"`
-	MultiTenancy        *MultiTenancy   `json:"multiTenancy,omitempty"`   // Multi-tenancy configuration
-	GenerationMode      GenerationMode  `json:"generationMode,omitempty"` // "existing" or "new" - defaults to "existing"
+	Name                 string             `json:"name"`
+	ModuleName           string             `json:"moduleName"`
+	NamespaceRoot        string             `json:"namespaceRoot"`
+	ModuleSuffix         string             `json:"moduleSuffix,omitempty"` // Optional suffix for module (e.g., "Module", "Service", or empty)
+	FolderPrefix         string             `json:"folderPrefix,omitempty"` // Optional prefix for folder names
+	TablePrefix          string             `json:"tablePrefix,omitempty"`  // Database table prefix, e.g. "App" or "Saas" (defaults to "App")
+	ABPVersion           string             `json:"abpVersion"`
+	TargetFramework      TargetFramework    `json:"targetFramework"` // Target framework type
+	PrimaryKeyType       string             `json:"primaryKeyType"`  // "Guid" or "long" or "configurable"
+	DBProvider           string             `json:"dbProvider"`      // "efcore" or "mongodb" or "both"
+	GenerateControllers  bool               `json:"generateControllers"`
+	MultiTenancy         *MultiTenancy      `json:"multiTenancy,omitempty"`         // Multi-tenancy configuration
+	GenerationMode       GenerationMode     `json:"generationMode,omitempty"`       // "existing" or "new" - defaults to "existing"
+	Workers              []BackgroundWorker `json:"workers,omitempty"`              // Periodic background workers for the module
+	SeedCount            int                `json:"seedCount,omitempty"`            // Rows each data seeder generates with deterministic pseudo-random values
+	LocalizationResource string             `json:"localizationResource,omitempty"` // Localization resource class for permission display names (defaults to "{ModuleName}Resource")
+}
+
+// BackgroundWorker represents a periodic background worker
+type BackgroundWorker struct {
+	Name            string `json:"name"`
+	IntervalSeconds int    `json:"intervalSeconds"`
+	Description     string `json:"description,omitempty"`
 }
 
 // Entity represents a domain entity
 type Entity struct {
 	Name                     string             `json:"name"`
 	TableName                string             `json:"tableName"`
-	EntityType               string             `json:"entityType"` // "Entity", "AggregateRoot", "FullAuditedAggregateRoot", "ValueObject"
-	PrimaryKeyType           string             `json:"primaryKeyType,omitempty"`
+	EntityType               string             `json:"entityType"`                         // "Entity", "AggregateRoot", "CreationAuditedAggregateRoot", "AuditedAggregateRoot", "FullAuditedAggregateRoot", "ValueObject"
+	BaseClass                string             `json:"baseClass,omitempty"`                // Project-specific generic base class used instead of EntityType's, e.g. "MyAuditedEntity" for MyAuditedEntity<TKey>
+	BaseEntity               string             `json:"baseEntity,omitempty"`               // Entity in this schema the entity derives from; the hierarchy shares one table (TPH)
+	PrimaryKeyType           string             `json:"primaryKeyType,omitempty"`           // "Guid", "long", or a custom strongly-typed ID struct name (e.g., "ProductId")
+	PrimaryKeyUnderlyingType string             `json:"primaryKeyUnderlyingType,omitempty"` // Value wrapped by a strongly-typed ID: "Guid" or "long"
 	Properties               []Property         `json:"properties"`
 	Relations                *Relations         `json:"relations,omitempty"`
-	CustomRepository         *CustomRepository  `json:"customRepository,omitempty"`  // Custom repository methods
-	DomainEvents             []DomainEvent      `json:"domainEvents,omitempty"`      // Domain events
-	Enums                    []EnumDefinition   `json:"enums,omitempty"`             // Associated enums
-	ValueObjectConfig        *ValueObjectConfig `json:"valueObjectConfig,omitempty"` // Value object configuration
-	GenerateIntegrationTests bool               `json:"generateIntegrationTests"`    // Generate integration tests
+	CustomRepository         *CustomRepository  `json:"customRepository,omitempty"`     // Custom repository methods
+	DomainEvents             []DomainEvent      `json:"domainEvents,omitempty"`         // Domain events
+	Enums                    []EnumDefinition   `json:"enums,omitempty"`                // Associated enums
+	ValueObjectConfig        *ValueObjectConfig `json:"valueObjectConfig,omitempty"`    // Value object configuration
+	Indexes                  []IndexDefinition  `json:"indexes,omitempty"`              // Database indexes
+	Operations               []string           `json:"operations,omitempty"`           // App service operations: create, read, update, delete, list (default: all)
+	SortableProperties       []string           `json:"sortableProperties,omitempty"`   // Property names list endpoints may sort by (default: any sorting is accepted)
+	SeedData                 []SeedRow          `json:"seedData,omitempty"`             // Rows inserted by the data seeder, keyed by property name
+	SeedCount                int                `json:"seedCount,omitempty"`            // Generated seed rows; overrides the solution's seedCount
+	CustomEndpoints          []Endpoint         `json:"customEndpoints,omitempty"`      // Extra controller actions backed by app service methods
+	GenerateController       *bool              `json:"generateController,omitempty"`   // Override the solution's generateControllers for this entity
+	GenerateManager          *bool              `json:"generateManager,omitempty"`      // Generate a {Entity}Manager domain service (default: aggregate roots and entities with delete guards)
+	RequireAuthorization     *bool              `json:"requireAuthorization,omitempty"` // Guard the service with permissions (default: true); false allows anonymous access
+	GenerateIntegrationTests bool               `json:"generateIntegrationTests"`       // Generate integration tests
+	Description              string             `json:"description,omitempty"`          // Human description emitted as XML doc comments and shown in Swagger
+}
+
+// SeedRow is one seeded entity: property names mapped to JSON scalar values
+type SeedRow map[string]interface{}
+
+// AllOperations lists the app service operations an entity can expose
+var AllOperations = []string{"create", "read", "update", "delete", "list"}
+
+// IndexDefinition represents a database index over one or more properties
+type IndexDefinition struct {
+	Properties []string `json:"properties"`       // Indexed property names, in key order
+	Unique     bool     `json:"unique,omitempty"` // Whether the index enforces uniqueness
 }
 
 // Property represents an entity property
 type Property struct {
-	Name            string           `json:"name"`
-	Type            string           `json:"type"`
-	IsRequired      bool             `json:"isRequired"`
-	MaxLength       int              `json:"maxLength,omitempty"`
-	MinLength       int              `json:"minLength,omitempty"`
-	Nullable        bool             `json:"nullable"`
-	DefaultValue    string           `json:"defaultValue,omitempty"`
-	IsForeignKey    bool             `json:"isForeignKey,omitempty"`
-	TargetEntity    string           `json:"targetEntity,omitempty"`    // For foreign keys
-	IsEnum          bool             `json:"isEnum,omitempty"`          // Whether this is an enum type
-	EnumName        string           `json:"enumName,omitempty"`        // Name of the enum type
-	IsValueObject   bool             `json:"isValueObject,omitempty"`   // Whether this is a value object
-	ValidationRules []ValidationRule `json:"validationRules,omitempty"` // Custom validation rules
+	Name               string           `json:"name"`
+	Type               string           `json:"type"`
+	IsRequired         bool             `json:"isRequired"`
+	MaxLength          int              `json:"maxLength,omitempty"`
+	MinLength          int              `json:"minLength,omitempty"`
+	Precision          int              `json:"precision,omitempty"` // Total digits for decimal columns
+	Scale              int              `json:"scale,omitempty"`     // Digits after the decimal point for decimal columns
+	Nullable           bool             `json:"nullable"`
+	DefaultValue       string           `json:"defaultValue,omitempty"`
+	IsForeignKey       bool             `json:"isForeignKey,omitempty"`
+	TargetEntity       string           `json:"targetEntity,omitempty"`       // For foreign keys
+	IsEnum             bool             `json:"isEnum,omitempty"`             // Whether this is an enum type
+	EnumName           string           `json:"enumName,omitempty"`           // Name of the enum type
+	IsValueObject      bool             `json:"isValueObject,omitempty"`      // Whether this is a value object
+	ValidationRules    []ValidationRule `json:"validationRules,omitempty"`    // Custom validation rules
+	IsFilterable       bool             `json:"isFilterable,omitempty"`       // Whether the property can be filtered on via the query endpoint
+	ReadOnly           bool             `json:"readOnly,omitempty"`           // Computed by the domain; excluded from Create and Update DTOs
+	IsConcurrencyToken bool             `json:"isConcurrencyToken,omitempty"` // Database-generated rowversion column (byte[]); excluded from Create and Update DTOs
+	ColumnName         string           `json:"columnName,omitempty"`         // Database column name when it differs from the property name (EF Core only)
+	Description        string           `json:"description,omitempty"`        // Human description emitted as an XML doc comment on DTO properties
+	IsFile             bool             `json:"isFile,omitempty"`             // byte[] attachment uploaded and downloaded through stream endpoints; set by type "file" or "binary"
+	DisplayName        string           `json:"displayName,omitempty"`        // Human-facing label; becomes the [Display] name and the property's localization text
+	DisplayOrder       int              `json:"displayOrder,omitempty"`       // Field order in generated UIs, emitted as [Display(Order = ...)]
 }
 
 // Relations represents entity relationships
@@ -96,9 +162,11 @@ type OneToOneRelation struct {
 	TargetEntity       string `json:"targetEntity"`
 	ForeignKeyName     string `json:"foreignKeyName"`
 	NavigationProperty string `json:"navigationProperty"`
-	IsRequired         bool   `json:"isRequired"`    // Whether the relationship is required
-	IsOwned            bool   `json:"isOwned"`       // Whether the related entity is owned (EF Core owned type)
-	CascadeDelete      bool   `json:"cascadeDelete"` // Whether to cascade delete
+	IsRequired         bool   `json:"isRequired"`            // Whether the relationship is required
+	IsOwned            bool   `json:"isOwned"`               // Whether the related entity is owned (EF Core owned type)
+	CascadeDelete      bool   `json:"cascadeDelete"`         // Whether to cascade delete
+	WithDetails        bool   `json:"withDetails,omitempty"` // Eager-load the navigation when the entity is fetched with details
+	SkipIndex          bool   `json:"skipIndex,omitempty"`   // Accepted for compatibility: EF Core always indexes relationship foreign keys
 }
 
 // ManyToOneRelation represents a many-to-one relationship
@@ -108,6 +176,7 @@ type ManyToOneRelation struct {
 	NavigationProperty string `json:"navigationProperty"`
 	IsRequired         bool   `json:"isRequired"`
 	CascadeDelete      bool   `json:"cascadeDelete"`
+	SkipIndex          bool   `json:"skipIndex,omitempty"` // Accepted for compatibility: EF Core always indexes relationship foreign keys
 }
 
 // OneToManyRelation represents a one-to-many relationship
@@ -117,7 +186,8 @@ type OneToManyRelation struct {
 	NavigationProperty string `json:"navigationProperty"`
 	IsCollection       bool   `json:"isCollection"`
 	CascadeDelete      bool   `json:"cascadeDelete"`
-	IsSelfReference    bool   `json:"isSelfReference"` // Self-referencing relationship
+	IsSelfReference    bool   `json:"isSelfReference"`       // Self-referencing relationship
+	WithDetails        bool   `json:"withDetails,omitempty"` // Eager-load the navigation when the entity is fetched with details
 }
 
 // ManyToManyRelation represents a many-to-many relationship
@@ -126,6 +196,11 @@ type ManyToManyRelation struct {
 	JoinEntity         string `json:"joinEntity"`
 	NavigationProperty string `json:"navigationProperty"`
 	InverseProperty    string `json:"inverseProperty,omitempty"` // Inverse navigation property name
+	WithDetails        bool   `json:"withDetails,omitempty"`     // Eager-load the navigation when the entity is fetched with details
+	IsSelfReference    bool   `json:"isSelfReference,omitempty"` // Both sides are the same entity, e.g. User <-> User
+	// Join entity foreign keys of a self-referencing relation, defaulting to Source{Entity}Id and Target{Entity}Id
+	SourceForeignKeyName string `json:"sourceForeignKeyName,omitempty"`
+	TargetForeignKeyName string `json:"targetForeignKeyName,omitempty"`
 }
 
 // Options represents generation options
@@ -140,6 +215,10 @@ type Options struct {
 	MappingLibrary           string             `json:"mappingLibrary,omitempty"` // "automapper" or "mapperly" - auto-detected based on ABP version if not set
 	GenerateEventHandlers    bool               `json:"generateEventHandlers"`
 	GenerateIntegrationTests bool               `json:"generateIntegrationTests"`
+	TestFramework            string             `json:"testFramework,omitempty"`     // "xunit" (default) or "nunit" for generated integration tests
+	GenerateDeleteGuards     bool               `json:"generateDeleteGuards"`        // Guard deletes against children of restrict one-to-many relations
+	GenerateQueryFilters     bool               `json:"generateQueryFilters"`        // Generate a query-string filtering endpoint over filterable properties
+	GenerateGrpc             bool               `json:"generateGrpc"`                // Generate gRPC contracts and services next to the controllers (microservice targets)
 	LocalizationMerge        *LocalizationMerge `json:"localizationMerge,omitempty"` // Localization merging options
 }
 
@@ -150,24 +229,36 @@ type LocalizationMerge struct {
 	ConflictStrategy string `json:"conflictStrategy"` // "overwrite", "append", "skip"
 }
 
-// LoadFromFile loads schema from a JSON file
+// LoadFromFile loads schema from a JSON file, resolving the entity files it includes
 func LoadFromFile(path string) (*Schema, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	composed, included, err := resolveIncludes(data, path)
+	if err != nil {
+		return nil, err
+	}
+
 	var schema Schema
-	if err := json.Unmarshal(data, &schema); err != nil {
+	if err := json.Unmarshal(composed, &schema); err != nil {
 		return nil, err
 	}
+	schema.source = data
+	schema.included = included
 
 	return &schema, nil
 }
 
-// SaveToFile saves schema to a JSON file
+// IncludedFiles returns the files whose entities were included into the schema when it was loaded
+func (s *Schema) IncludedFiles() []string {
+	return s.included
+}
+
+// SaveToFile saves schema to a JSON file, omitting default values and keeping the loaded key order
 func (s *Schema) SaveToFile(path string) error {
-	data, err := json.MarshalIndent(s, "", "  ")
+	data, err := s.MarshalMinimal()
 	if err != nil {
 		return err
 	}
@@ -175,6 +266,98 @@ func (s *Schema) SaveToFile(path string) error {
 	return os.WriteFile(path, data, 0644)
 }
 
+// FindEntity returns the entity with the given name, or nil if the schema does not declare it
+func (s *Schema) FindEntity(name string) *Entity {
+	for i := range s.Entities {
+		if s.Entities[i].Name == name {
+			return &s.Entities[i]
+		}
+	}
+	return nil
+}
+
+// FindPropertyEnum returns the enum definition an enum property refers to, or nil if the
+// property is not an enum or its enum is not defined in the schema
+func (s *Schema) FindPropertyEnum(prop Property) *EnumDefinition {
+	if !prop.IsEnum {
+		return nil
+	}
+	name := prop.enumTypeName()
+	for i := range s.Entities {
+		for j := range s.Entities[i].Enums {
+			if s.Entities[i].Enums[j].Name == name {
+				return &s.Entities[i].Enums[j]
+			}
+		}
+	}
+	return nil
+}
+
+// GetBaseEntities returns the entities the entity derives from through baseEntity, root first.
+// It stops at an unknown or repeated base entity.
+func (s *Schema) GetBaseEntities(entity *Entity) []*Entity {
+	var bases []*Entity
+	visited := map[string]bool{entity.Name: true}
+	for name := entity.BaseEntity; name != "" && !visited[name]; {
+		base := s.FindEntity(name)
+		if base == nil {
+			break
+		}
+		visited[name] = true
+		bases = append([]*Entity{base}, bases...)
+		name = base.BaseEntity
+	}
+	return bases
+}
+
+// GetDerivedEntities returns the entities deriving from the entity, directly or indirectly, in schema order
+func (s *Schema) GetDerivedEntities(entity *Entity) []*Entity {
+	var derived []*Entity
+	for i := range s.Entities {
+		candidate := &s.Entities[i]
+		for _, base := range s.GetBaseEntities(candidate) {
+			if base.Name == entity.Name {
+				derived = append(derived, candidate)
+				break
+			}
+		}
+	}
+	return derived
+}
+
+// GetConstructorProperties returns the properties taken by the entity's constructor: the input
+// properties of its base entities, root first, followed by its own
+func (s *Schema) GetConstructorProperties(entity *Entity) []Property {
+	var props []Property
+	for _, base := range s.GetBaseEntities(entity) {
+		props = append(props, base.GetInputProperties()...)
+	}
+	return append(props, entity.GetInputProperties()...)
+}
+
+// AddEntity appends an entity to the schema, rejecting a name that is already taken
+func (s *Schema) AddEntity(entity Entity) error {
+	if s.FindEntity(entity.Name) != nil {
+		return fmt.Errorf("entity '%s' already exists in the schema", entity.Name)
+	}
+	s.Entities = append(s.Entities, entity)
+	return nil
+}
+
+// Clone returns a deep copy of the schema, such as one to validate without the defaults
+// Validate fills in reaching the original
+func (s *Schema) Clone() (*Schema, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy schema: %w", err)
+	}
+	clone := &Schema{source: s.source, included: s.included}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, fmt.Errorf("failed to copy schema: %w", err)
+	}
+	return clone, nil
+}
+
 // GetEffectivePrimaryKeyType returns the effective primary key type for an entity
 func (e *Entity) GetEffectivePrimaryKeyType(solutionDefault string) string {
 	if e.PrimaryKeyType != "" {
@@ -183,6 +366,196 @@ func (e *Entity) GetEffectivePrimaryKeyType(solutionDefault string) string {
 	return solutionDefault
 }
 
+// GetEffectiveSeedCount returns the number of rows the entity's data seeder generates
+func (e *Entity) GetEffectiveSeedCount(solutionDefault int) int {
+	if e.SeedCount > 0 {
+		return e.SeedCount
+	}
+	return solutionDefault
+}
+
+// IsAggregateRoot checks if the entity derives from one of ABP's aggregate root base classes
+func (e *Entity) IsAggregateRoot() bool {
+	switch e.EntityType {
+	case "AggregateRoot", "CreationAuditedAggregateRoot", "AuditedAggregateRoot", "FullAuditedAggregateRoot":
+		return true
+	}
+	return false
+}
+
+// HasConcurrencyStamp checks if the entity inherits ABP's ConcurrencyStamp, which every aggregate
+// root base class declares; derived entities inherit it from the root of their hierarchy
+func (s *Schema) HasConcurrencyStamp(entity *Entity) bool {
+	if bases := s.GetBaseEntities(entity); len(bases) > 0 {
+		return bases[0].IsAggregateRoot()
+	}
+	return entity.IsAggregateRoot()
+}
+
+// IsCreationAudited checks if the entity's base class tracks CreationTime and CreatorId
+func (e *Entity) IsCreationAudited() bool {
+	switch e.EntityType {
+	case "CreationAuditedAggregateRoot", "AuditedAggregateRoot", "FullAuditedAggregateRoot":
+		return true
+	}
+	return false
+}
+
+// ShouldGenerateController checks if the entity gets an HTTP API controller, preferring its own override
+func (e *Entity) ShouldGenerateController(solutionDefault bool) bool {
+	if e.GenerateController != nil {
+		return *e.GenerateController
+	}
+	return solutionDefault
+}
+
+// ShouldGenerateManager checks if the entity gets a domain manager, preferring its own override.
+// By default only aggregate roots get one, along with entities whose delete guards it would hold;
+// value objects never do.
+func (e *Entity) ShouldGenerateManager(deleteGuards bool) bool {
+	if e.EntityType == "ValueObject" {
+		return false
+	}
+	if e.GenerateManager != nil {
+		return *e.GenerateManager
+	}
+	return e.IsAggregateRoot() || (deleteGuards && len(e.GetRestrictedOneToManyRelations()) > 0)
+}
+
+// RequiresAuthorization checks if the entity's services are guarded by its permissions rather than open to anonymous callers
+func (e *Entity) RequiresAuthorization() bool {
+	return e.RequireAuthorization == nil || *e.RequireAuthorization
+}
+
+// HasStronglyTypedId checks if the entity uses a custom struct as its primary key
+func (e *Entity) HasStronglyTypedId() bool {
+	return e.PrimaryKeyType != "" && !IsBuiltInPrimaryKeyType(e.PrimaryKeyType)
+}
+
+// GetPrimaryKeyUnderlyingType returns the value type stored in the database for the primary key
+func (e *Entity) GetPrimaryKeyUnderlyingType(solutionDefault string) string {
+	if !e.HasStronglyTypedId() {
+		return e.GetEffectivePrimaryKeyType(solutionDefault)
+	}
+	if e.PrimaryKeyUnderlyingType != "" {
+		return e.PrimaryKeyUnderlyingType
+	}
+	return "Guid"
+}
+
+// CanGenerateKeys checks if new rows of the entity get a primary key without the caller choosing
+// one: Guid keys, including Guid-backed strongly-typed ids, come from IGuidGenerator and plain
+// long keys from an EF Core identity column
+func (s *Schema) CanGenerateKeys(entity *Entity) bool {
+	if entity.GetPrimaryKeyUnderlyingType(s.Solution.PrimaryKeyType) == "Guid" {
+		return true
+	}
+	return !entity.HasStronglyTypedId() && s.Solution.DBProvider == "efcore"
+}
+
+// IsBuiltInPrimaryKeyType checks if the type is a primary key type supported without a custom struct
+func IsBuiltInPrimaryKeyType(typeName string) bool {
+	return typeName == "Guid" || typeName == "long"
+}
+
+// IsImmutableValueObject checks if the entity is a value object that can only be set on creation.
+// Value objects without a valueObjectConfig are immutable.
+func (e *Entity) IsImmutableValueObject() bool {
+	return e.EntityType == "ValueObject" && (e.ValueObjectConfig == nil || e.ValueObjectConfig.IsImmutable)
+}
+
+// HasCustomRepository checks if the entity declares custom repository methods
+func (e *Entity) HasCustomRepository() bool {
+	return e.CustomRepository != nil && len(e.CustomRepository.Methods) > 0
+}
+
+// GetRestrictedOneToManyRelations returns one-to-many relations whose children block deleting the parent
+func (e *Entity) GetRestrictedOneToManyRelations() []OneToManyRelation {
+	if e.Relations == nil {
+		return nil
+	}
+
+	var relations []OneToManyRelation
+	for _, rel := range e.Relations.OneToMany {
+		if !rel.CascadeDelete {
+			relations = append(relations, rel)
+		}
+	}
+	return relations
+}
+
+// HasOperation checks if the entity's app service exposes the operation; all are exposed when Operations is empty
+func (e *Entity) HasOperation(operation string) bool {
+	if len(e.Operations) == 0 {
+		return true
+	}
+	for _, op := range e.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllOperations checks if the entity's app service exposes the full CRUD set
+func (e *Entity) HasAllOperations() bool {
+	for _, op := range AllOperations {
+		if !e.HasOperation(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindProperty finds a declared property by name
+func (e *Entity) FindProperty(name string) *Property {
+	for i := range e.Properties {
+		if e.Properties[i].Name == name {
+			return &e.Properties[i]
+		}
+	}
+	return nil
+}
+
+// FindRepositoryMethod returns the custom repository method with the given name, or nil
+func (e *Entity) FindRepositoryMethod(name string) *RepositoryMethod {
+	if e.CustomRepository == nil {
+		return nil
+	}
+	for i := range e.CustomRepository.Methods {
+		if e.CustomRepository.Methods[i].Name == name {
+			return &e.CustomRepository.Methods[i]
+		}
+	}
+	return nil
+}
+
+// GetFilterableProperties returns properties whitelisted for query-string filtering
+func (e *Entity) GetFilterableProperties() []Property {
+	var props []Property
+	for _, p := range e.Properties {
+		if p.IsFilterable {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// IsFilterableType checks if values of the type can be parsed from a query string
+func IsFilterableType(typeName string) bool {
+	switch typeName {
+	case "string", "int", "long", "short", "byte", "decimal", "double", "float", "bool", "Guid", "DateTime":
+		return true
+	default:
+		return false
+	}
+}
+
+// CSharpTypeInfo describes the property's C# type for the csharpType template helper
+func (p Property) CSharpTypeInfo() csharp.TypeInfo {
+	return csharp.TypeInfo{Name: p.Type, Nullable: p.Nullable, IsValueType: p.IsEnum}
+}
+
 // GetNonForeignKeyProperties returns properties that are not foreign keys
 func (e *Entity) GetNonForeignKeyProperties() []Property {
 	var props []Property
@@ -194,6 +567,29 @@ func (e *Entity) GetNonForeignKeyProperties() []Property {
 	return props
 }
 
+// GetInputProperties returns the non-foreign-key properties accepted by Create and Update DTOs.
+// File properties are excluded; they are uploaded through their own endpoint.
+func (e *Entity) GetInputProperties() []Property {
+	var props []Property
+	for _, p := range e.Properties {
+		if !p.IsForeignKey && !p.ReadOnly && !p.IsConcurrencyToken && !p.IsFile {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// GetFileProperties returns the attachments uploaded and downloaded through stream endpoints
+func (e *Entity) GetFileProperties() []Property {
+	var props []Property
+	for _, p := range e.Properties {
+		if p.IsFile {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
 // GetForeignKeyProperties returns properties that are foreign keys
 func (e *Entity) GetForeignKeyProperties() []Property {
 	var props []Property
@@ -257,11 +653,16 @@ func (e *Entity) NeedsFluentValidation() bool {
 	return false
 }
 
+// HasDisplayMetadata checks if the property sets a display name or order for generated UIs
+func (p *Property) HasDisplayMetadata() bool {
+	return p.DisplayName != "" || p.DisplayOrder != 0
+}
+
 // NeedsDataAnnotations checks if entity needs System.ComponentModel.DataAnnotations using statements
 func (e *Entity) NeedsDataAnnotations() bool {
 	// DataAnnotations are needed for DTOs with validation attributes
 	for _, prop := range e.Properties {
-		if prop.IsRequired || prop.MaxLength > 0 || prop.MinLength > 0 || len(prop.ValidationRules) > 0 {
+		if prop.IsRequired || prop.MaxLength > 0 || prop.MinLength > 0 || len(prop.ValidationRules) > 0 || prop.HasDisplayMetadata() {
 			return true
 		}
 	}
@@ -282,6 +683,15 @@ func (s *Solution) GetModuleFolderName() string {
 	return result
 }
 
+// GetLocalizationResourceName returns the module's localization resource class name,
+// e.g. "CatalogResource" for the "Catalog" module
+func (s *Solution) GetLocalizationResourceName() string {
+	if s.LocalizationResource != "" {
+		return s.LocalizationResource
+	}
+	return s.ModuleName + "Resource"
+}
+
 // GetModuleNameWithSuffix returns the module name with suffix for use in templates
 // Format: ModuleName[ModuleSuffix]
 // Example: "ProductModule" or "ProductService" or "Product"
@@ -295,10 +705,11 @@ func (s *Solution) GetModuleNameWithSuffix() string {
 // MultiTenancy represents multi-tenancy configuration
 type MultiTenancy struct {
 	Enabled             bool   `json:"enabled"`
-	Strategy            string `json:"strategy"`            // "none", "host", "tenant-per-db", "tenant-per-schema"
-	EnableCrossTenant   bool   `json:"enableCrossTenant"`   // Allow cross-tenant queries
-	TenantIdProperty    string `json:"tenantIdProperty"`    // Property name for tenant ID (default: "TenantId")
-	EnableDataIsolation bool   `json:"enableDataIsolation"` // Enable automatic data isolation
+	Strategy            string `json:"strategy"`               // "none", "host", "tenant-per-db", "tenant-per-schema"
+	EnableCrossTenant   bool   `json:"enableCrossTenant"`      // Allow cross-tenant queries
+	TenantIdProperty    string `json:"tenantIdProperty"`       // Property name for tenant ID (default: "TenantId")
+	TenantIdType        string `json:"tenantIdType,omitempty"` // Tenant ID type: "Guid" (default), "long", or "string"
+	EnableDataIsolation bool   `json:"enableDataIsolation"`    // Enable automatic data isolation
 }
 
 // CustomRepository represents custom repository configuration
@@ -316,6 +727,45 @@ type RepositoryMethod struct {
 	Description string            `json:"description,omitempty"`
 }
 
+// Endpoint represents a custom controller action and the app service method behind it
+type Endpoint struct {
+	Name             string            `json:"name"`                       // App service method and action name, e.g. "GetSalesReportAsync"
+	HTTPMethod       string            `json:"httpMethod"`                 // "GET", "POST", "PUT", "PATCH" or "DELETE"
+	Route            string            `json:"route,omitempty"`            // Route template relative to the controller, e.g. "reports/sales/{year}"
+	ReturnType       string            `json:"returnType,omitempty"`       // Result type without Task, e.g. "List<ProductDto>"; empty for none
+	Parameters       []MethodParameter `json:"parameters,omitempty"`       // Route parameters are bound by name, others from the query or body
+	RepositoryMethod string            `json:"repositoryMethod,omitempty"` // Custom repository method the app service calls; a stub is generated otherwise
+	Description      string            `json:"description,omitempty"`
+}
+
+// routeParameterPattern matches the parameter names of a route template, e.g. year in {year:int}
+var routeParameterPattern = regexp.MustCompile(`\{\*{0,2}([A-Za-z_][A-Za-z0-9_]*)[^}]*\}`)
+
+// RouteParameters returns the names of the parameters in the endpoint's route template
+func (e *Endpoint) RouteParameters() []string {
+	var names []string
+	for _, match := range routeParameterPattern.FindAllStringSubmatch(e.Route, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// Result returns the type a repository method produces, unwrapping Task<T>, and whether the
+// method is awaited. Methods returning Task or void have no result.
+func (m *RepositoryMethod) Result() (resultType string, awaitable bool) {
+	returnType := strings.TrimSpace(m.ReturnType)
+	switch {
+	case returnType == "Task":
+		return "", true
+	case strings.HasPrefix(returnType, "Task<") && strings.HasSuffix(returnType, ">"):
+		return strings.TrimSpace(returnType[len("Task<") : len(returnType)-1]), true
+	case returnType == "void":
+		return "", false
+	default:
+		return returnType, false
+	}
+}
+
 // MethodParameter represents a method parameter
 type MethodParameter struct {
 	Name string `json:"name"`
@@ -349,11 +799,48 @@ type EnumDefinition struct {
 	Name            string      `json:"name"`
 	UnderlyingType  string      `json:"underlyingType"` // "int", "string", etc.
 	Values          []EnumValue `json:"values"`
-	UseLocalization bool        `json:"useLocalization"` // Generate localization entries
-	GenerateLookup  bool        `json:"generateLookup"`  // Generate lookup/extension methods
+	UseLocalization bool        `json:"useLocalization"`   // Generate localization entries
+	GenerateLookup  bool        `json:"generateLookup"`    // Generate lookup/extension methods
+	IsFlags         bool        `json:"isFlags,omitempty"` // Bitwise flags enum; values must be zero or powers of two
 	Description     string      `json:"description,omitempty"`
 }
 
+// IsStringBacked reports whether the enum is stored by member name rather than by number. C#
+// enums cannot derive from string, so such an enum is declared with the default int type.
+func (e EnumDefinition) IsStringBacked() bool {
+	return e.UnderlyingType == "string"
+}
+
+// ResolvedValues returns the enum values to generate. For a flags enum, values without an
+// explicit value are assigned the next power of two not used by another value.
+func (e EnumDefinition) ResolvedValues() []EnumValue {
+	values := make([]EnumValue, len(e.Values))
+	copy(values, e.Values)
+	if !e.IsFlags {
+		return values
+	}
+
+	used := make(map[uint64]bool)
+	for _, val := range values {
+		if n, err := strconv.ParseUint(val.Value, 10, 64); err == nil {
+			used[n] = true
+		}
+	}
+
+	next := uint64(1)
+	for i := range values {
+		if values[i].Value != "" {
+			continue
+		}
+		for used[next] {
+			next <<= 1
+		}
+		values[i].Value = strconv.FormatUint(next, 10)
+		used[next] = true
+	}
+	return values
+}
+
 // EnumValue represents a single enum value
 type EnumValue struct {
 	Name            string `json:"name"`
@@ -373,7 +860,11 @@ type ValueObjectConfig struct {
 
 // ValidationRule represents a custom validation rule
 type ValidationRule struct {
-	Type         string `json:"type"`  // "Range", "RegularExpression", "Custom", etc.
-	Value        string `json:"value"` // The validation value/pattern
+	Type         string `json:"type"`               // "Range", "RegularExpression", "Custom", "Compare", etc.
+	Value        string `json:"value"`              // The validation value/pattern; for Compare, the other property's name
+	Operator     string `json:"operator,omitempty"` // Compare operator between the property and the other one (default ">")
 	ErrorMessage string `json:"errorMessage,omitempty"`
 }
+
+// CompareOperators lists the operators a Compare validation rule accepts
+var CompareOperators = []string{">", ">=", "<", "<=", "==", "!="}
@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchema_AddEntity(t *testing.T) {
+	sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+
+	if err := sch.AddEntity(Entity{Name: "Category", Properties: []Property{{Name: "Title", Type: "string"}}}); err != nil {
+		t.Fatalf("AddEntity() error = %v", err)
+	}
+	if len(sch.Entities) != 2 || sch.Entities[1].Name != "Category" {
+		t.Fatalf("Entities = %+v, want Category appended", sch.Entities)
+	}
+
+	err := sch.AddEntity(Entity{Name: "Product"})
+	if err == nil || !strings.Contains(err.Error(), "entity 'Product' already exists") {
+		t.Fatalf("AddEntity() duplicate error = %v", err)
+	}
+	if len(sch.Entities) != 2 {
+		t.Errorf("duplicate entity was appended: %d entities", len(sch.Entities))
+	}
+}
+
+func TestEntity_IsAggregateRoot(t *testing.T) {
+	tests := []struct {
+		entityType        string
+		isAggregateRoot   bool
+		isCreationAudited bool
+	}{
+		{"Entity", false, false},
+		{"ValueObject", false, false},
+		{"AggregateRoot", true, false},
+		{"CreationAuditedAggregateRoot", true, true},
+		{"AuditedAggregateRoot", true, true},
+		{"FullAuditedAggregateRoot", true, true},
+	}
+
+	for _, tt := range tests {
+		entity := Entity{Name: "Product", EntityType: tt.entityType}
+		if got := entity.IsAggregateRoot(); got != tt.isAggregateRoot {
+			t.Errorf("%s: IsAggregateRoot() = %t, want %t", tt.entityType, got, tt.isAggregateRoot)
+		}
+		if got := entity.IsCreationAudited(); got != tt.isCreationAudited {
+			t.Errorf("%s: IsCreationAudited() = %t, want %t", tt.entityType, got, tt.isCreationAudited)
+		}
+	}
+}
+
+func TestEntity_ShouldGenerateManager(t *testing.T) {
+	yes, no := true, false
+	guarded := &Relations{OneToMany: []OneToManyRelation{{TargetEntity: "Product"}}}
+
+	tests := []struct {
+		name         string
+		entity       Entity
+		deleteGuards bool
+		want         bool
+	}{
+		{"aggregate root", Entity{EntityType: "FullAuditedAggregateRoot"}, false, true},
+		{"plain entity", Entity{EntityType: "Entity"}, false, false},
+		{"plain entity with delete guards", Entity{EntityType: "Entity", Relations: guarded}, true, true},
+		{"plain entity with guards disabled", Entity{EntityType: "Entity", Relations: guarded}, false, false},
+		{"opted in", Entity{EntityType: "Entity", GenerateManager: &yes}, false, true},
+		{"opted out", Entity{EntityType: "AggregateRoot", GenerateManager: &no}, false, false},
+		{"value object", Entity{EntityType: "ValueObject", GenerateManager: &yes}, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entity.ShouldGenerateManager(tt.deleteGuards); got != tt.want {
+			t.Errorf("%s: ShouldGenerateManager(%t) = %t, want %t", tt.name, tt.deleteGuards, got, tt.want)
+		}
+	}
+}
+
+func TestSchema_CloneKeepsValidationDefaultsOut(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name:       "Document",
+		Properties: []Property{{Name: "Content", Type: "file"}},
+		Relations:  &Relations{ManyToOne: []ManyToOneRelation{{TargetEntity: "Document"}}},
+	})
+
+	clone, err := sch.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if err := clone.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	original := sch.Entities[0]
+	if original.Properties[0].Type != "file" || original.Properties[0].IsFile {
+		t.Errorf("validating the clone rewrote the original property: %+v", original.Properties[0])
+	}
+	if original.Relations.ManyToOne[0].NavigationProperty != "" {
+		t.Errorf("validating the clone filled in the original relation: %+v", original.Relations.ManyToOne[0])
+	}
+}
@@ -1,46 +1,73 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/csharp"
 )
 
-// Validate validates the schema and returns any errors
+// tablePrefixPattern matches a prefix that keeps table names legal unquoted SQL identifiers
+var tablePrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// culturePattern matches a BCP-47 culture name: a language, then an optional script and region,
+// e.g. "en", "en-US", "zh-Hans" or "es-419"
+var culturePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z]{4})?(-([A-Za-z]{2}|[0-9]{3}))?$`)
+
+// Validate validates the schema and returns every problem found, joined with errors.Join
 func (s *Schema) Validate() error {
-	if err := s.validateSolution(); err != nil {
-		return err
-	}
+	errs := s.validateSolution()
 
 	if len(s.Entities) == 0 {
-		return fmt.Errorf("schema must contain at least one entity")
+		errs = append(errs, fmt.Errorf("schema must contain at least one entity"))
 	}
 
-	entityNames := make(map[string]bool)
-	for i, entity := range s.Entities {
-		if err := s.validateEntity(&entity, entityNames); err != nil {
-			return fmt.Errorf("entity[%d] '%s': %w", i, entity.Name, err)
+	// Derived entities take their entity type and key from the root of their hierarchy
+	for i := range s.Entities {
+		entity := &s.Entities[i]
+		if entity.BaseEntity != "" {
+			errs = append(errs, prefixErrors(fmt.Sprintf("entity[%d] '%s'", i, entity.Name), s.validateBaseEntity(entity))...)
 		}
+	}
+
+	entityNames := make(map[string]bool)
+	for i := range s.Entities {
+		entity := &s.Entities[i]
+		errs = append(errs, prefixErrors(fmt.Sprintf("entity[%d] '%s'", i, entity.Name), s.validateEntity(entity, entityNames))...)
 		entityNames[entity.Name] = true
 	}
 
 	// Validate relations reference existing entities
-	for _, entity := range s.Entities {
-		if err := s.validateRelations(&entity, entityNames); err != nil {
-			return fmt.Errorf("entity '%s' relations: %w", entity.Name, err)
-		}
+	for i := range s.Entities {
+		entity := &s.Entities[i]
+		errs = append(errs, prefixErrors(fmt.Sprintf("entity '%s' relations", entity.Name), s.validateRelations(entity, entityNames))...)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func (s *Schema) validateSolution() error {
+// prefixErrors wraps each error with the path of the schema element it belongs to
+func prefixErrors(prefix string, errs []error) []error {
+	wrapped := make([]error, len(errs))
+	for i, err := range errs {
+		wrapped[i] = fmt.Errorf("%s: %w", prefix, err)
+	}
+	return wrapped
+}
+
+func (s *Schema) validateSolution() []error {
+	var errs []error
+
 	if s.Solution.Name == "" {
-		return fmt.Errorf("solution.name is required")
+		errs = append(errs, fmt.Errorf("solution.name is required"))
 	}
 
 	if s.Solution.ModuleName == "" {
-		return fmt.Errorf("solution.moduleName is required")
+		errs = append(errs, fmt.Errorf("solution.moduleName is required"))
 	}
 
 	// Set default ModuleSuffix to "Module" for backward compatibility
@@ -79,7 +106,7 @@ func (s *Schema) validateSolution() error {
 		TargetAuto:              true,
 	}
 	if !validTargets[s.Solution.TargetFramework] {
-		return fmt.Errorf("solution.targetFramework must be one of: aspnetcore9, aspnetcore10, abp8-monolith, abp8-microservice, abp9-monolith, abp9-microservice, abp10-monolith, abp10-microservice, or auto, got '%s'", s.Solution.TargetFramework)
+		errs = append(errs, fmt.Errorf("solution.targetFramework must be one of: aspnetcore9, aspnetcore10, abp8-monolith, abp8-microservice, abp9-monolith, abp9-microservice, abp10-monolith, abp10-microservice, or auto, got '%s'", s.Solution.TargetFramework))
 	}
 
 	if s.Solution.PrimaryKeyType == "" {
@@ -88,7 +115,7 @@ func (s *Schema) validateSolution() error {
 
 	validPKTypes := map[string]bool{"Guid": true, "long": true, "configurable": true}
 	if !validPKTypes[s.Solution.PrimaryKeyType] {
-		return fmt.Errorf("solution.primaryKeyType must be 'Guid', 'long', or 'configurable', got '%s'", s.Solution.PrimaryKeyType)
+		errs = append(errs, fmt.Errorf("solution.primaryKeyType must be 'Guid', 'long', or 'configurable', got '%s'", s.Solution.PrimaryKeyType))
 	}
 
 	if s.Solution.DBProvider == "" {
@@ -97,7 +124,23 @@ func (s *Schema) validateSolution() error {
 
 	validProviders := map[string]bool{"efcore": true, "mongodb": true, "both": true}
 	if !validProviders[s.Solution.DBProvider] {
-		return fmt.Errorf("solution.dbProvider must be 'efcore', 'mongodb', or 'both', got '%s'", s.Solution.DBProvider)
+		errs = append(errs, fmt.Errorf("solution.dbProvider must be 'efcore', 'mongodb', or 'both', got '%s'", s.Solution.DBProvider))
+	}
+
+	// Set default table prefix, matching ABP's startup templates
+	if s.Solution.TablePrefix == "" {
+		s.Solution.TablePrefix = "App"
+	}
+	if !tablePrefixPattern.MatchString(s.Solution.TablePrefix) {
+		errs = append(errs, fmt.Errorf("solution.tablePrefix must start with a letter or underscore and contain only letters, digits, and underscores, got '%s'", s.Solution.TablePrefix))
+	}
+
+	if s.Solution.SeedCount < 0 {
+		errs = append(errs, fmt.Errorf("solution.seedCount must not be negative, got %d", s.Solution.SeedCount))
+	}
+
+	if s.Solution.LocalizationResource != "" && !isValidIdentifier(s.Solution.LocalizationResource) {
+		errs = append(errs, fmt.Errorf("solution.localizationResource must be a valid C# identifier, got '%s'", s.Solution.LocalizationResource))
 	}
 
 	// Set default generation mode to "existing" for backward compatibility
@@ -108,16 +151,23 @@ func (s *Schema) validateSolution() error {
 	// Validate generation mode
 	validModes := map[GenerationMode]bool{GenerationModeExisting: true, GenerationModeNew: true}
 	if !validModes[s.Solution.GenerationMode] {
-		return fmt.Errorf("solution.generationMode must be 'existing' or 'new', got '%s'", s.Solution.GenerationMode)
+		errs = append(errs, fmt.Errorf("solution.generationMode must be 'existing' or 'new', got '%s'", s.Solution.GenerationMode))
 	}
 
 	// Validate multi-tenancy configuration
 	if s.Solution.MultiTenancy != nil {
 		if err := s.validateMultiTenancy(s.Solution.MultiTenancy); err != nil {
-			return fmt.Errorf("solution.multiTenancy: %w", err)
+			errs = append(errs, fmt.Errorf("solution.multiTenancy: %w", err))
 		}
 	}
 
+	// Validate background workers
+	workerNames := make(map[string]bool)
+	for i, worker := range s.Solution.Workers {
+		errs = append(errs, prefixErrors(fmt.Sprintf("solution.workers[%d] '%s'", i, worker.Name), s.validateWorker(&worker, workerNames))...)
+		workerNames[worker.Name] = true
+	}
+
 	// Set default validation type
 	if s.Options.ValidationType == "" {
 		s.Options.ValidationType = "fluentvalidation"
@@ -125,7 +175,7 @@ func (s *Schema) validateSolution() error {
 
 	validValidationTypes := map[string]bool{"fluentvalidation": true, "native": true}
 	if !validValidationTypes[s.Options.ValidationType] {
-		return fmt.Errorf("options.validationType must be 'fluentvalidation' or 'native', got '%s'", s.Options.ValidationType)
+		errs = append(errs, fmt.Errorf("options.validationType must be 'fluentvalidation' or 'native', got '%s'", s.Options.ValidationType))
 	}
 
 	// Auto-detect mapping library based on ABP version if not set
@@ -142,18 +192,39 @@ func (s *Schema) validateSolution() error {
 	// Validate mapping library
 	validMappingLibraries := map[string]bool{"automapper": true, "mapperly": true}
 	if !validMappingLibraries[s.Options.MappingLibrary] {
-		return fmt.Errorf("options.mappingLibrary must be 'automapper' or 'mapperly', got '%s'", s.Options.MappingLibrary)
+		errs = append(errs, fmt.Errorf("options.mappingLibrary must be 'automapper' or 'mapperly', got '%s'", s.Options.MappingLibrary))
+	}
+
+	// gRPC services are only generated for microservices; an auto target is checked once detected
+	if s.Options.GenerateGrpc && s.Solution.TargetFramework != TargetAuto && !s.Solution.TargetFramework.IsMicroservice() {
+		errs = append(errs, fmt.Errorf("options.generateGrpc requires an abp*-microservice targetFramework, got '%s'", s.Solution.TargetFramework))
+	}
+
+	// Set default test framework
+	if s.Options.TestFramework == "" {
+		s.Options.TestFramework = "xunit"
+	}
+
+	validTestFrameworks := map[string]bool{"xunit": true, "nunit": true}
+	if !validTestFrameworks[s.Options.TestFramework] {
+		errs = append(errs, fmt.Errorf("options.testFramework must be 'xunit' or 'nunit', got '%s'", s.Options.TestFramework))
 	}
 
+	// Cultures name the localization files, so localization needs at least one
+	if s.Options.UseLocalization && len(s.Options.LocalizationCultures) == 0 {
+		s.Options.LocalizationCultures = []string{"en"}
+	}
+	errs = append(errs, validateCultures(s.Options.LocalizationCultures)...)
+
 	// Validate localization merge configuration
 	if s.Options.LocalizationMerge != nil {
 		validStrategies := map[string]bool{"overwrite": true, "append": true, "skip": true}
 		if s.Options.LocalizationMerge.ConflictStrategy != "" && !validStrategies[s.Options.LocalizationMerge.ConflictStrategy] {
-			return fmt.Errorf("options.localizationMerge.conflictStrategy must be 'overwrite', 'append', or 'skip', got '%s'", s.Options.LocalizationMerge.ConflictStrategy)
+			errs = append(errs, fmt.Errorf("options.localizationMerge.conflictStrategy must be 'overwrite', 'append', or 'skip', got '%s'", s.Options.LocalizationMerge.ConflictStrategy))
 		}
 	}
 
-	return nil
+	return errs
 }
 
 func (s *Schema) validateMultiTenancy(mt *MultiTenancy) error {
@@ -164,16 +235,102 @@ func (s *Schema) validateMultiTenancy(mt *MultiTenancy) error {
 	if mt.TenantIdProperty == "" {
 		mt.TenantIdProperty = "TenantId"
 	}
+	if mt.TenantIdType == "" {
+		mt.TenantIdType = "Guid"
+	}
+	validTenantIdTypes := map[string]bool{"Guid": true, "long": true, "string": true}
+	if !validTenantIdTypes[mt.TenantIdType] {
+		return fmt.Errorf("tenantIdType must be 'Guid', 'long', or 'string', got '%s'", mt.TenantIdType)
+	}
 	return nil
 }
 
-func (s *Schema) validateEntity(entity *Entity, existingNames map[string]bool) error {
-	if entity.Name == "" {
-		return fmt.Errorf("entity name is required")
+// maxWorkerIntervalSeconds is the longest worker interval ABP's timer can hold, as its Period is an
+// int of milliseconds
+const maxWorkerIntervalSeconds = math.MaxInt32 / 1000
+
+func (s *Schema) validateWorker(worker *BackgroundWorker, existingNames map[string]bool) []error {
+	var errs []error
+
+	if worker.Name == "" {
+		errs = append(errs, fmt.Errorf("name is required"))
+	} else if !isValidIdentifier(worker.Name) {
+		errs = append(errs, fmt.Errorf("name must be a valid C# identifier"))
+	} else if existingNames[worker.Name] {
+		errs = append(errs, fmt.Errorf("duplicate worker name"))
+	}
+
+	if worker.IntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("intervalSeconds must be positive, got %d", worker.IntervalSeconds))
+	} else if worker.IntervalSeconds > maxWorkerIntervalSeconds {
+		errs = append(errs, fmt.Errorf("intervalSeconds must be at most %d (about 24 days), got %d", maxWorkerIntervalSeconds, worker.IntervalSeconds))
+	}
+
+	return errs
+}
+
+// validateBaseEntity checks the entity's baseEntity hierarchy and copies the root entity's
+// entityType and primary key to the entity when it does not set them
+func (s *Schema) validateBaseEntity(entity *Entity) []error {
+	if entity.BaseEntity == entity.Name {
+		return []error{fmt.Errorf("baseEntity cannot reference the entity itself")}
+	}
+	if s.FindEntity(entity.BaseEntity) == nil {
+		return []error{fmt.Errorf("baseEntity '%s' does not exist", entity.BaseEntity)}
+	}
+
+	bases := s.GetBaseEntities(entity)
+	if last := bases[0]; last.BaseEntity != "" {
+		if s.FindEntity(last.BaseEntity) == nil {
+			// Reported on the base entity itself
+			return nil
+		}
+		return []error{fmt.Errorf("baseEntity '%s' forms an inheritance cycle", entity.BaseEntity)}
+	}
+
+	var errs []error
+	root := bases[0]
+	if entity.BaseClass != "" {
+		errs = append(errs, fmt.Errorf("baseEntity and baseClass cannot both be set"))
+	}
+
+	rootType := root.EntityType
+	if rootType == "" {
+		rootType = "FullAuditedAggregateRoot"
+	}
+	switch {
+	case rootType == "ValueObject" || entity.EntityType == "ValueObject":
+		errs = append(errs, fmt.Errorf("value objects cannot be part of an entity hierarchy"))
+	case entity.EntityType == "":
+		entity.EntityType = rootType
+	case entity.EntityType != rootType:
+		errs = append(errs, fmt.Errorf("entityType '%s' must match '%s' of base entity '%s'", entity.EntityType, rootType, root.Name))
+	}
+
+	if entity.PrimaryKeyType == "" {
+		entity.PrimaryKeyType = root.PrimaryKeyType
+		entity.PrimaryKeyUnderlyingType = root.PrimaryKeyUnderlyingType
+	} else if entity.PrimaryKeyType != root.GetEffectivePrimaryKeyType(s.Solution.PrimaryKeyType) {
+		errs = append(errs, fmt.Errorf("primaryKeyType '%s' must match base entity '%s'", entity.PrimaryKeyType, root.Name))
+	}
+
+	for _, base := range bases {
+		for _, prop := range entity.Properties {
+			if base.FindProperty(prop.Name) != nil {
+				errs = append(errs, fmt.Errorf("property '%s' is already declared by base entity '%s'", prop.Name, base.Name))
+			}
+		}
 	}
+	return errs
+}
+
+func (s *Schema) validateEntity(entity *Entity, existingNames map[string]bool) []error {
+	var errs []error
 
-	if existingNames[entity.Name] {
-		return fmt.Errorf("duplicate entity name '%s'", entity.Name)
+	if entity.Name == "" {
+		errs = append(errs, fmt.Errorf("entity name is required"))
+	} else if existingNames[entity.Name] {
+		errs = append(errs, fmt.Errorf("duplicate entity name '%s'", entity.Name))
 	}
 
 	if entity.TableName == "" {
@@ -185,33 +342,55 @@ func (s *Schema) validateEntity(entity *Entity, existingNames map[string]bool) e
 	}
 
 	validTypes := map[string]bool{
-		"Entity":                   true,
-		"AggregateRoot":            true,
-		"FullAuditedAggregateRoot": true,
-		"AuditedAggregateRoot":     true,
-		"ValueObject":              true,
+		"Entity":                       true,
+		"AggregateRoot":                true,
+		"CreationAuditedAggregateRoot": true,
+		"AuditedAggregateRoot":         true,
+		"FullAuditedAggregateRoot":     true,
+		"ValueObject":                  true,
 	}
 	if !validTypes[entity.EntityType] {
-		return fmt.Errorf("invalid entityType '%s'", entity.EntityType)
+		errs = append(errs, fmt.Errorf("invalid entityType '%s'", entity.EntityType))
+	}
+
+	// A custom base class replaces the ABP one in the entity declaration; EntityType still drives generation
+	if entity.BaseClass != "" && !isValidQualifiedName(entity.BaseClass) {
+		errs = append(errs, fmt.Errorf("baseClass '%s' is not a valid C# class name", entity.BaseClass))
+	}
+
+	if err := s.validatePrimaryKey(entity); err != nil {
+		errs = append(errs, err)
 	}
 
-	if len(entity.Properties) == 0 && entity.EntityType != "ValueObject" {
-		return fmt.Errorf("entity must have at least one property")
+	if len(entity.Properties) == 0 && entity.EntityType != "ValueObject" && entity.BaseEntity == "" {
+		errs = append(errs, fmt.Errorf("entity must have at least one property"))
 	}
 
+	enums := s.allEnums()
 	propertyNames := make(map[string]bool)
-	for i, prop := range entity.Properties {
-		if err := s.validateProperty(&prop, propertyNames); err != nil {
-			return fmt.Errorf("property[%d] '%s': %w", i, prop.Name, err)
+	var concurrencyTokens []string
+	for i := range entity.Properties {
+		prop := &entity.Properties[i]
+		if prop.IsConcurrencyToken {
+			concurrencyTokens = append(concurrencyTokens, prop.Name)
+		}
+		propErrs := s.validateProperty(prop, propertyNames)
+		if err := validateDefaultValue(prop, enums); err != nil {
+			propErrs = append(propErrs, err)
 		}
+		errs = append(errs, prefixErrors(fmt.Sprintf("property[%d] '%s'", i, prop.Name), propErrs)...)
 		propertyNames[prop.Name] = true
 	}
+	errs = append(errs, validateColumnNames(entity.Properties)...)
+	if len(concurrencyTokens) > 1 {
+		errs = append(errs, fmt.Errorf("at most one concurrency token is allowed, got %s", strings.Join(concurrencyTokens, ", ")))
+	}
 
 	// Validate custom repository
 	if entity.CustomRepository != nil {
 		for i, method := range entity.CustomRepository.Methods {
 			if err := s.validateRepositoryMethod(&method); err != nil {
-				return fmt.Errorf("customRepository.methods[%d] '%s': %w", i, method.Name, err)
+				errs = append(errs, fmt.Errorf("customRepository.methods[%d] '%s': %w", i, method.Name, err))
 			}
 		}
 	}
@@ -219,26 +398,95 @@ func (s *Schema) validateEntity(entity *Entity, existingNames map[string]bool) e
 	// Validate domain events
 	for i, event := range entity.DomainEvents {
 		if err := s.validateDomainEvent(&event); err != nil {
-			return fmt.Errorf("domainEvents[%d] '%s': %w", i, event.Name, err)
+			errs = append(errs, fmt.Errorf("domainEvents[%d] '%s': %w", i, event.Name, err))
 		}
 	}
 
 	// Validate enums
 	enumNames := make(map[string]bool)
 	for i, enum := range entity.Enums {
-		if err := s.validateEnum(&enum, enumNames); err != nil {
-			return fmt.Errorf("enums[%d] '%s': %w", i, enum.Name, err)
-		}
+		errs = append(errs, prefixErrors(fmt.Sprintf("enums[%d] '%s'", i, enum.Name), s.validateEnum(&enum, enumNames))...)
 		enumNames[enum.Name] = true
 	}
 
+	// Validate indexes
+	for i, index := range entity.Indexes {
+		errs = append(errs, prefixErrors(fmt.Sprintf("indexes[%d]", i), validateIndex(entity, index))...)
+	}
+
+	// Validate app service operations
+	errs = append(errs, validateOperations(entity.Operations)...)
+
+	// Delete guards live in the domain manager
+	if entity.GenerateManager != nil {
+		switch {
+		case *entity.GenerateManager && entity.EntityType == "ValueObject":
+			errs = append(errs, fmt.Errorf("generateManager cannot be true for a ValueObject"))
+		case !*entity.GenerateManager && s.Options.GenerateDeleteGuards && len(entity.GetRestrictedOneToManyRelations()) > 0:
+			errs = append(errs, fmt.Errorf("generateManager cannot be false while options.generateDeleteGuards guards its one-to-many relations"))
+		}
+	}
+
+	// Validate sortable properties
+	errs = append(errs, validateSortableProperties(entity)...)
+
+	// Validate cross-property comparisons
+	errs = append(errs, validateCompareRules(entity)...)
+
+	// Validate seed data
+	errs = append(errs, validateSeedData(entity, enums)...)
+	if entity.SeedCount < 0 {
+		errs = append(errs, fmt.Errorf("seedCount must not be negative, got %d", entity.SeedCount))
+	}
+	if (len(entity.SeedData) > 0 || entity.SeedCount > 0) && entity.EntityType != "ValueObject" && !s.CanGenerateKeys(entity) {
+		errs = append(errs, fmt.Errorf("seedData and seedCount need a primary key the seeder can generate: a Guid, a Guid-backed strongly-typed id, or a long with EF Core"))
+	}
+
+	// Validate custom endpoints
+	endpointNames := make(map[string]bool)
+	for i := range entity.CustomEndpoints {
+		endpoint := &entity.CustomEndpoints[i]
+		errs = append(errs, prefixErrors(fmt.Sprintf("customEndpoints[%d] '%s'", i, endpoint.Name), validateEndpoint(entity, endpoint, endpointNames))...)
+		endpointNames[endpoint.Name] = true
+	}
+
 	// Validate value object configuration
 	if entity.ValueObjectConfig != nil && entity.EntityType == "ValueObject" {
-		if err := s.validateValueObjectConfig(entity.ValueObjectConfig, entity.Properties); err != nil {
-			return fmt.Errorf("valueObjectConfig: %w", err)
+		for _, err := range s.validateValueObjectConfig(entity.ValueObjectConfig, entity.Properties) {
+			errs = append(errs, fmt.Errorf("valueObjectConfig: %w", err))
 		}
 	}
 
+	return errs
+}
+
+// allEnums returns the enums defined across all entities
+func (s *Schema) allEnums() []EnumDefinition {
+	var enums []EnumDefinition
+	for _, entity := range s.Entities {
+		enums = append(enums, entity.Enums...)
+	}
+	return enums
+}
+
+func (s *Schema) validatePrimaryKey(entity *Entity) error {
+	if !entity.HasStronglyTypedId() {
+		if entity.PrimaryKeyUnderlyingType != "" {
+			return fmt.Errorf("primaryKeyUnderlyingType is only allowed with a strongly-typed primaryKeyType, got primaryKeyType '%s'", entity.PrimaryKeyType)
+		}
+		return nil
+	}
+
+	if !isValidIdentifier(entity.PrimaryKeyType) {
+		return fmt.Errorf("primaryKeyType '%s' is not a valid C# type name", entity.PrimaryKeyType)
+	}
+
+	if entity.PrimaryKeyUnderlyingType == "" {
+		entity.PrimaryKeyUnderlyingType = "Guid"
+	}
+	if !IsBuiltInPrimaryKeyType(entity.PrimaryKeyUnderlyingType) {
+		return fmt.Errorf("primaryKeyUnderlyingType must be 'Guid' or 'long', got '%s'", entity.PrimaryKeyUnderlyingType)
+	}
 	return nil
 }
 
@@ -266,77 +514,416 @@ func (s *Schema) validateDomainEvent(event *DomainEvent) error {
 	return nil
 }
 
-func (s *Schema) validateEnum(enum *EnumDefinition, existingNames map[string]bool) error {
+func (s *Schema) validateEnum(enum *EnumDefinition, existingNames map[string]bool) []error {
+	var errs []error
+
 	if enum.Name == "" {
-		return fmt.Errorf("enum name is required")
-	}
-	if existingNames[enum.Name] {
-		return fmt.Errorf("duplicate enum name '%s'", enum.Name)
+		errs = append(errs, fmt.Errorf("enum name is required"))
+	} else if existingNames[enum.Name] {
+		errs = append(errs, fmt.Errorf("duplicate enum name '%s'", enum.Name))
 	}
 	if enum.UnderlyingType == "" {
 		enum.UnderlyingType = "int"
 	}
 	if len(enum.Values) == 0 {
-		return fmt.Errorf("enum must have at least one value")
+		errs = append(errs, fmt.Errorf("enum must have at least one value"))
 	}
 	valueNames := make(map[string]bool)
 	for i, val := range enum.Values {
 		if val.Name == "" {
-			return fmt.Errorf("enum value[%d] name is required", i)
-		}
-		if valueNames[val.Name] {
-			return fmt.Errorf("duplicate enum value name '%s'", val.Name)
+			errs = append(errs, fmt.Errorf("enum value[%d] name is required", i))
+		} else if valueNames[val.Name] {
+			errs = append(errs, fmt.Errorf("duplicate enum value name '%s'", val.Name))
 		}
 		valueNames[val.Name] = true
 	}
-	return nil
+	if enum.IsFlags {
+		errs = append(errs, validateFlagsEnum(enum)...)
+	}
+	return errs
+}
+
+// validateFlagsEnum checks that a flags enum has an integral underlying type and that every
+// explicit value is zero or a distinct power of two
+func validateFlagsEnum(enum *EnumDefinition) []error {
+	var errs []error
+
+	integralTypes := map[string]bool{"byte": true, "sbyte": true, "short": true, "ushort": true, "int": true, "uint": true, "long": true, "ulong": true}
+	if !integralTypes[enum.UnderlyingType] {
+		errs = append(errs, fmt.Errorf("flags enum underlying type must be an integral type, got '%s'", enum.UnderlyingType))
+	}
+
+	seen := make(map[uint64]string)
+	for _, val := range enum.Values {
+		if val.Value == "" {
+			continue // Assigned the next free power of two when generating
+		}
+		n, err := strconv.ParseUint(val.Value, 10, 64)
+		if err != nil || n&(n-1) != 0 {
+			errs = append(errs, fmt.Errorf("flags enum value '%s' must be 0 or a power of two, got '%s'", val.Name, val.Value))
+			continue
+		}
+		if other, ok := seen[n]; ok {
+			errs = append(errs, fmt.Errorf("flags enum values '%s' and '%s' share the value %d", other, val.Name, n))
+		}
+		seen[n] = val.Name
+	}
+	return errs
+}
+
+// validateOperations checks that operations only names known app service operations, each once
+func validateOperations(operations []string) []error {
+	var errs []error
+
+	valid := make(map[string]bool)
+	for _, op := range AllOperations {
+		valid[op] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, op := range operations {
+		if !valid[op] {
+			errs = append(errs, fmt.Errorf("operations must only contain %s, got '%s'", strings.Join(AllOperations, ", "), op))
+		} else if seen[op] {
+			errs = append(errs, fmt.Errorf("duplicate operation '%s'", op))
+		}
+		seen[op] = true
+	}
+	return errs
+}
+
+// crudMethodNames are the app service methods generated for the standard operations
+var crudMethodNames = map[string]bool{
+	"GetAsync": true, "GetListAsync": true, "CreateAsync": true, "UpdateAsync": true, "DeleteAsync": true, "QueryAsync": true,
+}
+
+// validateEndpoint checks a custom endpoint's name, HTTP method and parameters, normalizing
+// the HTTP method to upper case
+func validateEndpoint(entity *Entity, endpoint *Endpoint, existingNames map[string]bool) []error {
+	var errs []error
+
+	switch {
+	case !isValidIdentifier(endpoint.Name):
+		errs = append(errs, fmt.Errorf("name must be a valid C# identifier"))
+	case existingNames[endpoint.Name]:
+		errs = append(errs, fmt.Errorf("duplicate endpoint name '%s'", endpoint.Name))
+	case crudMethodNames[endpoint.Name]:
+		errs = append(errs, fmt.Errorf("name '%s' is reserved for the generated CRUD methods", endpoint.Name))
+	}
+
+	endpoint.HTTPMethod = strings.ToUpper(endpoint.HTTPMethod)
+	validMethods := map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+	if !validMethods[endpoint.HTTPMethod] {
+		errs = append(errs, fmt.Errorf("httpMethod must be GET, POST, PUT, PATCH or DELETE, got '%s'", endpoint.HTTPMethod))
+	}
+
+	params := make(map[string]bool)
+	for _, param := range endpoint.Parameters {
+		if !isValidIdentifier(param.Name) || param.Type == "" {
+			errs = append(errs, fmt.Errorf("parameter '%s' needs a valid name and a type", param.Name))
+		} else if params[param.Name] {
+			errs = append(errs, fmt.Errorf("duplicate parameter '%s'", param.Name))
+		}
+		params[param.Name] = true
+	}
+
+	routeParams := make(map[string]bool)
+	for _, name := range endpoint.RouteParameters() {
+		routeParams[name] = true
+		if !params[name] {
+			errs = append(errs, fmt.Errorf("route parameter '%s' is not a parameter of the endpoint", name))
+		}
+	}
+
+	if endpoint.HTTPMethod != "GET" && endpoint.HTTPMethod != "DELETE" {
+		bodyParams := 0
+		for _, param := range endpoint.Parameters {
+			if !routeParams[param.Name] {
+				bodyParams++
+			}
+		}
+		if bodyParams > 1 {
+			errs = append(errs, fmt.Errorf("%s endpoints can take at most one parameter outside the route, sent as the request body", endpoint.HTTPMethod))
+		}
+	}
+
+	if endpoint.RepositoryMethod != "" {
+		method := entity.FindRepositoryMethod(endpoint.RepositoryMethod)
+		if method == nil {
+			errs = append(errs, fmt.Errorf("repositoryMethod '%s' is not a customRepository method", endpoint.RepositoryMethod))
+		} else {
+			for _, param := range method.Parameters {
+				if !params[param.Name] {
+					errs = append(errs, fmt.Errorf("repositoryMethod '%s' parameter '%s' is not a parameter of the endpoint", method.Name, param.Name))
+				}
+			}
+			if result, _ := method.Result(); result == "" && endpoint.ReturnType != "" {
+				errs = append(errs, fmt.Errorf("repositoryMethod '%s' returns no result for returnType '%s'", method.Name, endpoint.ReturnType))
+			}
+		}
+	}
+
+	return errs
 }
 
-func (s *Schema) validateValueObjectConfig(config *ValueObjectConfig, properties []Property) error {
+// validateSortableProperties checks that sortableProperties only names the primary key or
+// existing properties, each once
+func validateSortableProperties(entity *Entity) []error {
+	var errs []error
+
+	propNames := map[string]bool{"Id": true}
+	for _, prop := range entity.Properties {
+		propNames[prop.Name] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range entity.SortableProperties {
+		if !propNames[name] {
+			errs = append(errs, fmt.Errorf("sortable property '%s' does not exist in properties", name))
+		} else if seen[name] {
+			errs = append(errs, fmt.Errorf("duplicate sortable property '%s'", name))
+		}
+		seen[name] = true
+	}
+	return errs
+}
+
+// validateCultures checks that localization cultures are distinct BCP-47 culture names
+func validateCultures(cultures []string) []error {
+	var errs []error
+
+	seen := make(map[string]bool)
+	for i, culture := range cultures {
+		if !culturePattern.MatchString(culture) {
+			errs = append(errs, fmt.Errorf("options.localizationCultures[%d] '%s' is not a valid culture name, expected a BCP-47 tag such as 'en' or 'en-US'", i, culture))
+		} else if seen[strings.ToLower(culture)] {
+			errs = append(errs, fmt.Errorf("duplicate localization culture '%s'", culture))
+		}
+		seen[strings.ToLower(culture)] = true
+	}
+	return errs
+}
+
+// validateCompareRules checks that Compare validation rules reference another property of the
+// entity with a supported operator, defaulting the operator to ">". Both properties must be
+// accepted by the Create and Update DTOs, the only place the comparison is generated.
+func validateCompareRules(entity *Entity) []error {
+	var errs []error
+
+	propNames := make(map[string]bool)
+	for _, prop := range entity.Properties {
+		propNames[prop.Name] = true
+	}
+	inputNames := make(map[string]bool)
+	for _, prop := range entity.GetInputProperties() {
+		inputNames[prop.Name] = true
+	}
+	operators := make(map[string]bool)
+	for _, op := range CompareOperators {
+		operators[op] = true
+	}
+
+	for i := range entity.Properties {
+		prop := &entity.Properties[i]
+		for j := range prop.ValidationRules {
+			rule := &prop.ValidationRules[j]
+			if rule.Type != "Compare" {
+				continue
+			}
+
+			prefix := fmt.Sprintf("property '%s' validationRules[%d]", prop.Name, j)
+			switch {
+			case rule.Value == prop.Name:
+				errs = append(errs, fmt.Errorf("%s: a property cannot be compared with itself", prefix))
+			case !propNames[rule.Value]:
+				errs = append(errs, fmt.Errorf("%s: compared property '%s' does not exist in properties", prefix, rule.Value))
+			case !inputNames[prop.Name]:
+				errs = append(errs, fmt.Errorf("%s: property '%s' is not on the Create and Update DTOs and cannot be compared", prefix, prop.Name))
+			case !inputNames[rule.Value]:
+				errs = append(errs, fmt.Errorf("%s: compared property '%s' is not on the Create and Update DTOs", prefix, rule.Value))
+			}
+
+			if rule.Operator == "" {
+				rule.Operator = ">"
+			}
+			if !operators[rule.Operator] {
+				errs = append(errs, fmt.Errorf("%s: invalid compare operator '%s', expected one of %s", prefix, rule.Operator, strings.Join(CompareOperators, ", ")))
+			}
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateValueObjectConfig(config *ValueObjectConfig, properties []Property) []error {
 	// Validate equality members exist
 	propNames := make(map[string]bool)
 	for _, prop := range properties {
 		propNames[prop.Name] = true
 	}
+	var errs []error
 	for _, member := range config.EqualityMembers {
 		if !propNames[member] {
-			return fmt.Errorf("equality member '%s' does not exist in properties", member)
+			errs = append(errs, fmt.Errorf("equality member '%s' does not exist in properties", member))
 		}
 	}
-	return nil
+	return errs
 }
 
-func (s *Schema) validateProperty(prop *Property, existingNames map[string]bool) error {
-	if prop.Name == "" {
-		return fmt.Errorf("property name is required")
-	}
+func (s *Schema) validateProperty(prop *Property, existingNames map[string]bool) []error {
+	var errs []error
 
-	if existingNames[prop.Name] {
-		return fmt.Errorf("duplicate property name '%s'", prop.Name)
+	if prop.Name == "" {
+		errs = append(errs, fmt.Errorf("property name is required"))
+	} else if existingNames[prop.Name] {
+		errs = append(errs, fmt.Errorf("duplicate property name '%s'", prop.Name))
 	}
 
+	// The remaining checks depend on the type
 	if prop.Type == "" {
-		return fmt.Errorf("property type is required")
+		return append(errs, fmt.Errorf("property type is required"))
 	}
 
 	// Allow custom types (might be enums or other entities)
 	// Types are validated at compile time, so we accept any type string here
 
+	// File properties are stored as byte[] and exchanged as streams rather than through DTOs
+	if prop.Type == "file" || prop.Type == "binary" {
+		prop.Type = "byte[]"
+		prop.IsFile = true
+	}
+	if prop.IsFile {
+		switch {
+		case prop.Type != "byte[]":
+			errs = append(errs, fmt.Errorf("file property must be of type file, binary or byte[], got '%s'", prop.Type))
+		case prop.MaxLength > 0:
+			errs = append(errs, fmt.Errorf("maxLength cannot be used with file properties"))
+		case prop.IsConcurrencyToken:
+			errs = append(errs, fmt.Errorf("a file property cannot be a concurrency token"))
+		case prop.DefaultValue != "":
+			errs = append(errs, fmt.Errorf("file properties cannot have a default value"))
+		}
+	}
+
 	if prop.IsForeignKey && prop.TargetEntity == "" {
-		return fmt.Errorf("foreign key property must specify targetEntity")
+		errs = append(errs, fmt.Errorf("foreign key property must specify targetEntity"))
+	}
+
+	if err := validatePrecision(prop); err != nil {
+		errs = append(errs, err)
+	}
+
+	if prop.IsFilterable && !prop.IsEnum && !IsFilterableType(prop.Type) {
+		errs = append(errs, fmt.Errorf("type '%s' cannot be filtered from a query string", prop.Type))
+	}
+
+	if prop.IsConcurrencyToken && prop.Type != "byte[]" {
+		errs = append(errs, fmt.Errorf("concurrency token must be of type byte[], got '%s'", prop.Type))
+	}
+
+	if prop.ColumnName != "" && !isValidColumnName(prop.ColumnName) {
+		errs = append(errs, fmt.Errorf("columnName '%s' must not be blank or contain quotes, backslashes or control characters", prop.ColumnName))
+	}
+
+	return errs
+}
+
+// validateColumnNames checks that a columnName override does not map two properties to
+// the same database column. Duplicate property names are reported by validateProperty.
+func validateColumnNames(properties []Property) []error {
+	var errs []error
+	columns := make(map[string]Property)
+	for _, prop := range properties {
+		column := prop.Name
+		if prop.ColumnName != "" {
+			column = prop.ColumnName
+		}
+		key := strings.ToLower(column)
+		if other, ok := columns[key]; ok {
+			if other.ColumnName != "" || prop.ColumnName != "" {
+				errs = append(errs, fmt.Errorf("properties '%s' and '%s' map to the same column '%s'", other.Name, prop.Name, column))
+			}
+			continue
+		}
+		columns[key] = prop
 	}
+	return errs
+}
 
+// isValidColumnName checks that a column name can be emitted inside a C# string literal as-is
+func isValidColumnName(name string) bool {
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+	for _, r := range name {
+		if r == '"' || r == '\\' || r < ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateIndex checks that an index covers declared properties or relation foreign keys
+func validateIndex(entity *Entity, index IndexDefinition) []error {
+	if len(index.Properties) == 0 {
+		return []error{fmt.Errorf("index must list at least one property")}
+	}
+
+	known := make(map[string]bool, len(entity.Properties))
+	for _, prop := range entity.Properties {
+		known[prop.Name] = true
+	}
+	for _, member := range entity.generatedMembers() {
+		if member.IsForeignKey {
+			known[member.Name] = true
+		}
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(index.Properties))
+	for _, name := range index.Properties {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("property '%s' does not exist", name))
+		} else if seen[name] {
+			errs = append(errs, fmt.Errorf("property '%s' is listed more than once", name))
+		}
+		seen[name] = true
+	}
+	return errs
+}
+
+// validatePrecision checks the decimal precision and scale of a property
+func validatePrecision(prop *Property) error {
+	if prop.Precision == 0 && prop.Scale == 0 {
+		return nil
+	}
+
+	if prop.Type != "decimal" {
+		return fmt.Errorf("precision and scale are only supported for decimal properties, got type '%s'", prop.Type)
+	}
+	if prop.Precision < 0 || prop.Scale < 0 {
+		return fmt.Errorf("precision and scale must not be negative")
+	}
+	if prop.Precision == 0 {
+		return fmt.Errorf("scale requires precision to be set")
+	}
+	if prop.Scale > prop.Precision {
+		return fmt.Errorf("scale (%d) must not exceed precision (%d)", prop.Scale, prop.Precision)
+	}
 	return nil
 }
 
-func (s *Schema) validateRelations(entity *Entity, entityNames map[string]bool) error {
+func (s *Schema) validateRelations(entity *Entity, entityNames map[string]bool) []error {
 	if entity.Relations == nil {
 		return nil
 	}
 
-	for i, rel := range entity.Relations.OneToOne {
+	var errs []error
+
+	for i := range entity.Relations.OneToOne {
+		rel := &entity.Relations.OneToOne[i]
 		if rel.TargetEntity == "" {
-			return fmt.Errorf("oneToOne[%d]: targetEntity is required", i)
+			errs = append(errs, fmt.Errorf("oneToOne[%d]: targetEntity is required", i))
+			continue
 		}
 		if rel.NavigationProperty == "" {
 			rel.NavigationProperty = rel.TargetEntity
@@ -345,25 +932,38 @@ func (s *Schema) validateRelations(entity *Entity, entityNames map[string]bool)
 
 	for i, rel := range entity.Relations.OneToMany {
 		if rel.TargetEntity == "" {
-			return fmt.Errorf("oneToMany[%d]: targetEntity is required", i)
+			errs = append(errs, fmt.Errorf("oneToMany[%d]: targetEntity is required", i))
 		}
 		// Note: Target entity might not exist yet (forward reference) - this is OK for generation
 	}
 
-	for i, rel := range entity.Relations.ManyToOne {
+	for i := range entity.Relations.ManyToOne {
+		rel := &entity.Relations.ManyToOne[i]
 		if rel.TargetEntity == "" {
-			return fmt.Errorf("manyToOne[%d]: targetEntity is required", i)
+			errs = append(errs, fmt.Errorf("manyToOne[%d]: targetEntity is required", i))
+			continue
 		}
 		if rel.NavigationProperty == "" {
 			rel.NavigationProperty = rel.TargetEntity
 		}
 	}
 
-	for i, rel := range entity.Relations.ManyToMany {
+	for i := range entity.Relations.ManyToMany {
+		rel := &entity.Relations.ManyToMany[i]
 		if rel.TargetEntity == "" {
-			return fmt.Errorf("manyToMany[%d]: targetEntity is required", i)
+			errs = append(errs, fmt.Errorf("manyToMany[%d]: targetEntity is required", i))
+			continue
 		}
-		if rel.JoinEntity == "" {
+		if rel.IsSelfReference || rel.TargetEntity == entity.Name {
+			if rel.NavigationProperty != "" && rel.NavigationProperty == rel.InverseProperty {
+				errs = append(errs, fmt.Errorf("manyToMany[%d]: self-referencing navigationProperty and inverseProperty must differ", i))
+			}
+			if rel.SourceForeignKeyName != "" && rel.SourceForeignKeyName == rel.TargetForeignKeyName {
+				errs = append(errs, fmt.Errorf("manyToMany[%d]: self-referencing sourceForeignKeyName and targetForeignKeyName must differ", i))
+			}
+		}
+		// Self-referencing join entities are named after the navigation, which the relationship handler defaults
+		if rel.JoinEntity == "" && !rel.IsSelfReference && rel.TargetEntity != entity.Name {
 			// Auto-generate join entity name
 			entities := []string{entity.Name, rel.TargetEntity}
 			// Sort to ensure consistent naming
@@ -374,20 +974,55 @@ func (s *Schema) validateRelations(entity *Entity, entityNames map[string]bool)
 		}
 	}
 
-	return nil
+	return errs
 }
 
-// Pluralize converts a singular word to plural (simple implementation)
+// Pluralize converts a singular word to plural. It shares the go-pluralize rules used by
+// the templates so table, DbSet and navigation names agree across packages.
 func Pluralize(word string) string {
-	// This is a simple implementation - the actual pluralization will be handled by go-pluralize library
-	if strings.HasSuffix(word, "y") && len(word) > 1 {
-		return word[:len(word)-1] + "ies"
+	return csharp.Pluralize(word)
+}
+
+// csharpKeywords are the reserved C# keywords, which cannot name a type, member or parameter
+var csharpKeywords = map[string]bool{
+	"abstract": true, "as": true, "base": true, "bool": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "checked": true, "class": true, "const": true,
+	"continue": true, "decimal": true, "default": true, "delegate": true, "do": true, "double": true,
+	"else": true, "enum": true, "event": true, "explicit": true, "extern": true, "false": true,
+	"finally": true, "fixed": true, "float": true, "for": true, "foreach": true, "goto": true,
+	"if": true, "implicit": true, "in": true, "int": true, "interface": true, "internal": true,
+	"is": true, "lock": true, "long": true, "namespace": true, "new": true, "null": true,
+	"object": true, "operator": true, "out": true, "override": true, "params": true, "private": true,
+	"protected": true, "public": true, "readonly": true, "ref": true, "return": true, "sbyte": true,
+	"sealed": true, "short": true, "sizeof": true, "stackalloc": true, "static": true, "string": true,
+	"struct": true, "switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"typeof": true, "uint": true, "ulong": true, "unchecked": true, "unsafe": true, "ushort": true,
+	"using": true, "virtual": true, "void": true, "volatile": true, "while": true,
+}
+
+// isValidIdentifier checks if name is a valid C# identifier that is not a reserved keyword
+func isValidIdentifier(name string) bool {
+	if name == "" || csharpKeywords[name] {
+		return false
+	}
+	for i, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(i > 0 && isDigit) {
+			return false
+		}
 	}
-	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") ||
-		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh") {
-		return word + "es"
+	return true
+}
+
+// isValidQualifiedName checks if name is a C# identifier, optionally namespace-qualified
+func isValidQualifiedName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !isValidIdentifier(part) {
+			return false
+		}
 	}
-	return word + "s"
+	return true
 }
 
 // isABPVersion10OrHigher checks if the ABP version is 10.0 or higher
@@ -0,0 +1,976 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/csharp"
+)
+
+func newValidSchema(entities ...Entity) *Schema {
+	return &Schema{
+		Solution: Solution{Name: "Shop", ModuleName: "Catalog"},
+		Entities: entities,
+	}
+}
+
+func TestValidate_StronglyTypedIdUnderlyingType(t *testing.T) {
+	tests := []struct {
+		name           string
+		primaryKeyType string
+		underlyingType string
+		wantErr        string
+	}{
+		{"default underlying type", "ProductId", "", ""},
+		{"long underlying type", "ProductId", "long", ""},
+		{"unsupported underlying type", "ProductId", "string", "primaryKeyUnderlyingType must be 'Guid' or 'long'"},
+		{"invalid struct name", "Product-Id", "", "is not a valid C# type name"},
+		{"keyword struct name", "int", "", "is not a valid C# type name"},
+		{"underlying type without custom key", "Guid", "long", "only allowed with a strongly-typed primaryKeyType"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:                     "Product",
+				PrimaryKeyType:           tt.primaryKeyType,
+				PrimaryKeyUnderlyingType: tt.underlyingType,
+				Properties:               []Property{{Name: "Name", Type: "string"}},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DecimalPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		propType  string
+		precision int
+		scale     int
+		wantErr   string
+	}{
+		{"precision and scale", "decimal", 18, 2, ""},
+		{"precision only", "decimal", 10, 0, ""},
+		{"scale exceeds precision", "decimal", 4, 6, "scale (6) must not exceed precision (4)"},
+		{"scale without precision", "decimal", 0, 2, "scale requires precision"},
+		{"negative precision", "decimal", -1, 0, "must not be negative"},
+		{"non-decimal type", "int", 10, 0, "only supported for decimal properties"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Product",
+				Properties: []Property{{Name: "Price", Type: tt.propType, Precision: tt.precision, Scale: tt.scale}},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPluralize_IrregularAndCompoundWords(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Person", "People"},
+		{"Child", "Children"},
+		{"Mouse", "Mice"},
+		{"Category", "Categories"},
+		{"SalesPerson", "SalesPeople"},
+		{"Address", "Addresses"},
+		{"Product", "Products"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			got := Pluralize(tt.word)
+			if got != tt.want {
+				t.Errorf("Pluralize(%q) = %q; want %q", tt.word, got, tt.want)
+			}
+			if shared := csharp.Pluralize(tt.word); shared != got {
+				t.Errorf("csharp.Pluralize(%q) = %q; schema.Pluralize = %q", tt.word, shared, got)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	sch := newValidSchema(
+		Entity{
+			Name: "Product",
+			Properties: []Property{
+				{Name: "Name"},
+				{Name: "Price", Type: "decimal", Scale: 2},
+				{Name: "Name", Type: "string"},
+			},
+		},
+		Entity{Name: "Order", EntityType: "Aggregate"},
+	)
+	sch.Solution.DBProvider = "sqlite"
+
+	err := sch.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil; want errors")
+	}
+
+	for _, want := range []string{
+		"solution.dbProvider must be 'efcore', 'mongodb', or 'both', got 'sqlite'",
+		"entity[0] 'Product': property[0] 'Name': property type is required",
+		"entity[0] 'Product': property[1] 'Price': scale requires precision to be set",
+		"entity[0] 'Product': property[2] 'Name': duplicate property name 'Name'",
+		"entity[1] 'Order': invalid entityType 'Aggregate'",
+		"entity[1] 'Order': entity must have at least one property",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%s", want, err)
+		}
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Validate() error %T does not wrap multiple errors", err)
+	}
+	if got := len(joined.Unwrap()); got != 6 {
+		t.Errorf("Validate() returned %d errors; want 6:\n%s", got, err)
+	}
+}
+
+func TestValidate_Indexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   IndexDefinition
+		wantErr string
+	}{
+		{"declared property", IndexDefinition{Properties: []string{"Sku"}, Unique: true}, ""},
+		{"relation foreign key", IndexDefinition{Properties: []string{"CategoryId", "Sku"}}, ""},
+		{"no properties", IndexDefinition{}, "index must list at least one property"},
+		{"unknown property", IndexDefinition{Properties: []string{"Barcode"}}, "indexes[0]: property 'Barcode' does not exist"},
+		{"repeated property", IndexDefinition{Properties: []string{"Sku", "Sku"}}, "property 'Sku' is listed more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Product",
+				Properties: []Property{{Name: "Sku", Type: "string"}},
+				Relations: &Relations{
+					ManyToOne: []ManyToOneRelation{{TargetEntity: "Category"}},
+				},
+				Indexes: []IndexDefinition{tt.index},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SelfReferencingManyToManyNames(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name:       "User",
+		Properties: []Property{{Name: "UserName", Type: "string"}},
+		Relations: &Relations{
+			ManyToMany: []ManyToManyRelation{{TargetEntity: "User", NavigationProperty: "Friends", InverseProperty: "Friends"}},
+		},
+	})
+
+	err := sch.Validate()
+	if err == nil || !strings.Contains(err.Error(), "navigationProperty and inverseProperty must differ") {
+		t.Errorf("Validate() error = %v; want clashing self-reference navigations rejected", err)
+	}
+}
+
+func TestValidate_TablePrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{"", "App", false},
+		{"Saas", "Saas", false},
+		{"Cms_", "Cms_", false},
+		{"1App", "1App", true},
+		{"My-App", "My-App", true},
+	}
+
+	for _, tt := range tests {
+		sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+		sch.Solution.TablePrefix = tt.prefix
+
+		err := sch.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with tablePrefix %q error = %v; wantErr %v", tt.prefix, err, tt.wantErr)
+		}
+		if sch.Solution.TablePrefix != tt.want {
+			t.Errorf("tablePrefix %q became %q; want %q", tt.prefix, sch.Solution.TablePrefix, tt.want)
+		}
+	}
+}
+
+func TestValidate_TestFramework(t *testing.T) {
+	tests := []struct {
+		framework string
+		want      string
+		wantErr   bool
+	}{
+		{"", "xunit", false},
+		{"xunit", "xunit", false},
+		{"nunit", "nunit", false},
+		{"mstest", "mstest", true},
+	}
+
+	for _, tt := range tests {
+		sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+		sch.Options.TestFramework = tt.framework
+
+		err := sch.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with testFramework %q error = %v; wantErr %v", tt.framework, err, tt.wantErr)
+		}
+		if sch.Options.TestFramework != tt.want {
+			t.Errorf("testFramework %q became %q; want %q", tt.framework, sch.Options.TestFramework, tt.want)
+		}
+	}
+}
+
+func TestValidate_TenantIdType(t *testing.T) {
+	tests := []struct {
+		tenantIdType string
+		want         string
+		wantErr      bool
+	}{
+		{"", "Guid", false},
+		{"long", "long", false},
+		{"string", "string", false},
+		{"int", "int", true},
+	}
+
+	for _, tt := range tests {
+		sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+		sch.Solution.MultiTenancy = &MultiTenancy{Enabled: true, TenantIdType: tt.tenantIdType}
+
+		err := sch.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with tenantIdType %q error = %v; wantErr %v", tt.tenantIdType, err, tt.wantErr)
+		}
+		if sch.Solution.MultiTenancy.TenantIdType != tt.want {
+			t.Errorf("tenantIdType %q became %q; want %q", tt.tenantIdType, sch.Solution.MultiTenancy.TenantIdType, tt.want)
+		}
+	}
+}
+
+func TestValidate_FlagsEnum(t *testing.T) {
+	tests := []struct {
+		name           string
+		underlyingType string
+		values         []EnumValue
+		wantErr        string
+	}{
+		{"powers of two", "int", []EnumValue{{Name: "None", Value: "0"}, {Name: "Read", Value: "1"}, {Name: "Write", Value: "2"}}, ""},
+		{"auto-assigned values", "long", []EnumValue{{Name: "Read"}, {Name: "Write"}}, ""},
+		{"combined value", "int", []EnumValue{{Name: "Read", Value: "1"}, {Name: "ReadWrite", Value: "3"}}, "'ReadWrite' must be 0 or a power of two"},
+		{"duplicate value", "int", []EnumValue{{Name: "Read", Value: "1"}, {Name: "View", Value: "1"}}, "share the value 1"},
+		{"string underlying type", "string", []EnumValue{{Name: "Read", Value: "1"}}, "must be an integral type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Document",
+				Properties: []Property{{Name: "Title", Type: "string"}},
+				Enums:      []EnumDefinition{{Name: "AccessRights", UnderlyingType: tt.underlyingType, IsFlags: true, Values: tt.values}},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Operations(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []string
+		wantErr    string
+	}{
+		{"all by default", nil, ""},
+		{"read only", []string{"read", "list"}, ""},
+		{"unknown operation", []string{"read", "patch"}, "got 'patch'"},
+		{"duplicate operation", []string{"read", "read"}, "duplicate operation 'read'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Product",
+				Properties: []Property{{Name: "Name", Type: "string"}},
+				Operations: tt.operations,
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ColumnName(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties []Property
+		wantErr    string
+	}{
+		{"override", []Property{{Name: "Name", Type: "string", ColumnName: "product_name"}}, ""},
+		{"quote", []Property{{Name: "Name", Type: "string", ColumnName: `product"name`}}, "columnName 'product\"name' must not be blank"},
+		{"blank", []Property{{Name: "Name", Type: "string", ColumnName: " "}}, "must not be blank"},
+		{"same column", []Property{
+			{Name: "Name", Type: "string", ColumnName: "title"},
+			{Name: "Title", Type: "string"},
+		}, "properties 'Name' and 'Title' map to the same column 'Title'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: tt.properties})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_BaseClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseClass string
+		wantErr   bool
+	}{
+		{"unset", "", false},
+		{"identifier", "MyAuditedEntity", false},
+		{"qualified", "Acme.Shared.MyAuditedEntity", false},
+		{"generic arguments", "MyAuditedEntity<Guid>", true},
+		{"blank", " ", true},
+		{"trailing dot", "Acme.", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Product",
+				BaseClass:  tt.baseClass,
+				Properties: []Property{{Name: "Name", Type: "string"}},
+			})
+
+			err := sch.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "is not a valid C# class name") {
+				t.Errorf("Validate() error = %v, want baseClass error", err)
+			}
+		})
+	}
+}
+
+func TestValidate_SortableProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		sortable []string
+		wantErr  string
+	}{
+		{"any by default", nil, ""},
+		{"property and primary key", []string{"Name", "Id"}, ""},
+		{"unknown property", []string{"Name", "Price"}, "sortable property 'Price' does not exist in properties"},
+		{"duplicate property", []string{"Name", "Name"}, "duplicate sortable property 'Name'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:               "Product",
+				Properties:         []Property{{Name: "Name", Type: "string"}},
+				SortableProperties: tt.sortable,
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CustomEndpoints(t *testing.T) {
+	year := MethodParameter{Name: "year", Type: "int"}
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		wantErr  string
+	}{
+		{"route parameter", Endpoint{Name: "GetReportAsync", HTTPMethod: "get", Route: "reports/{year:int}", Parameters: []MethodParameter{year}}, ""},
+		{"repository method", Endpoint{Name: "GetTopAsync", HTTPMethod: "GET", ReturnType: "List<ProductDto>", Parameters: []MethodParameter{year}, RepositoryMethod: "GetTopSellingAsync"}, ""},
+		{"invalid name", Endpoint{Name: "Get Report", HTTPMethod: "GET"}, "name must be a valid C# identifier"},
+		{"reserved name", Endpoint{Name: "GetListAsync", HTTPMethod: "GET"}, "reserved for the generated CRUD methods"},
+		{"invalid method", Endpoint{Name: "ReportAsync", HTTPMethod: "HEAD"}, "httpMethod must be GET, POST, PUT, PATCH or DELETE, got 'HEAD'"},
+		{"unbound route parameter", Endpoint{Name: "ReportAsync", HTTPMethod: "GET", Route: "reports/{year}"}, "route parameter 'year' is not a parameter of the endpoint"},
+		{"two body parameters", Endpoint{Name: "ImportAsync", HTTPMethod: "POST", Parameters: []MethodParameter{year, {Name: "input", Type: "ImportDto"}}}, "at most one parameter outside the route"},
+		{"unknown repository method", Endpoint{Name: "ReportAsync", HTTPMethod: "GET", RepositoryMethod: "GetReportAsync"}, "repositoryMethod 'GetReportAsync' is not a customRepository method"},
+		{"missing repository argument", Endpoint{Name: "GetTopAsync", HTTPMethod: "GET", RepositoryMethod: "GetTopSellingAsync"}, "parameter 'year' is not a parameter of the endpoint"},
+		{"repository without result", Endpoint{Name: "RebuildAsync", HTTPMethod: "POST", ReturnType: "int", RepositoryMethod: "RebuildIndexAsync"}, "returns no result for returnType 'int'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:       "Product",
+				Properties: []Property{{Name: "Name", Type: "string"}},
+				CustomRepository: &CustomRepository{Methods: []RepositoryMethod{
+					{Name: "GetTopSellingAsync", ReturnType: "Task<List<Product>>", Parameters: []MethodParameter{year}},
+					{Name: "RebuildIndexAsync", ReturnType: "Task"},
+				}},
+				CustomEndpoints: []Endpoint{tt.endpoint},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_WorkerInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		wantErr  string
+	}{
+		{"one minute", 60, ""},
+		{"longest timer period", 2147483, ""},
+		{"zero", 0, "intervalSeconds must be positive, got 0"},
+		{"overflows the timer", 2147484, "intervalSeconds must be at most 2147483 (about 24 days), got 2147484"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.Workers = []BackgroundWorker{{Name: "CleanupWorker", IntervalSeconds: tt.interval}}
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ConcurrencyToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties []Property
+		wantErr    string
+	}{
+		{"single token", []Property{{Name: "Version", Type: "byte[]", IsConcurrencyToken: true}}, ""},
+		{"wrong type", []Property{{Name: "Version", Type: "long", IsConcurrencyToken: true}}, "concurrency token must be of type byte[], got 'long'"},
+		{"two tokens", []Property{
+			{Name: "Version", Type: "byte[]", IsConcurrencyToken: true},
+			{Name: "RowVersion", Type: "byte[]", IsConcurrencyToken: true},
+		}, "at most one concurrency token is allowed, got Version, RowVersion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := append([]Property{{Name: "Name", Type: "string"}}, tt.properties...)
+			err := newValidSchema(Entity{Name: "Product", Properties: properties}).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnumDefinition_ResolvedValues(t *testing.T) {
+	enum := EnumDefinition{
+		Name:    "AccessRights",
+		IsFlags: true,
+		Values:  []EnumValue{{Name: "None", Value: "0"}, {Name: "Read"}, {Name: "Write", Value: "2"}, {Name: "Delete"}},
+	}
+
+	var got []string
+	for _, val := range enum.ResolvedValues() {
+		got = append(got, val.Name+"="+val.Value)
+	}
+	if want := "None=0 Read=1 Write=2 Delete=4"; strings.Join(got, " ") != want {
+		t.Errorf("ResolvedValues() = %v, want %s", got, want)
+	}
+	if enum.Values[1].Value != "" {
+		t.Errorf("ResolvedValues() modified the definition: %+v", enum.Values)
+	}
+}
+
+func TestValidate_FileProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+		wantErr  string
+	}{
+		{"file type", Property{Name: "Manual", Type: "file"}, ""},
+		{"binary type", Property{Name: "Manual", Type: "binary"}, ""},
+		{"explicit flag", Property{Name: "Manual", Type: "byte[]", IsFile: true}, ""},
+		{"wrong type", Property{Name: "Manual", Type: "string", IsFile: true}, "file property must be of type file, binary or byte[], got 'string'"},
+		{"max length", Property{Name: "Manual", Type: "file", MaxLength: 100}, "maxLength cannot be used with file properties"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}, tt.property}})
+			err := s.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				if prop := s.Entities[0].Properties[1]; prop.Type != "byte[]" || !prop.IsFile {
+					t.Errorf("property = %+v, want a byte[] file property", prop)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SeedCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		solution int
+		entity   int
+		wantErr  string
+	}{
+		{"unset", 0, 0, ""},
+		{"solution and entity counts", 10, 50, ""},
+		{"negative solution count", -1, 0, "solution.seedCount must not be negative, got -1"},
+		{"negative entity count", 0, -5, "seedCount must not be negative, got -5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}, SeedCount: tt.entity})
+			sch.Solution.SeedCount = tt.solution
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_LocalizationResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		wantErr  string
+	}{
+		{"default", "", ""},
+		{"custom class", "ShopResource", ""},
+		{"qualified name", "Acme.ShopResource", "solution.localizationResource must be a valid C# identifier, got 'Acme.ShopResource'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.LocalizationResource = tt.resource
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_BaseEntity(t *testing.T) {
+	customer := func() Entity {
+		return Entity{Name: "Customer", PrimaryKeyType: "long", Properties: []Property{{Name: "Name", Type: "string"}}}
+	}
+	tests := []struct {
+		name    string
+		derived Entity
+		extra   []Entity
+		wantErr string
+	}{
+		{"derived without own properties", Entity{Name: "IndividualCustomer", BaseEntity: "Customer"}, nil, ""},
+		{"unknown base entity", Entity{Name: "IndividualCustomer", BaseEntity: "Person"}, nil, "baseEntity 'Person' does not exist"},
+		{"self reference", Entity{Name: "IndividualCustomer", BaseEntity: "IndividualCustomer"}, nil, "baseEntity cannot reference the entity itself"},
+		{"cycle", Entity{Name: "A", BaseEntity: "B"}, []Entity{{Name: "B", BaseEntity: "A"}}, "forms an inheritance cycle"},
+		{"with base class", Entity{Name: "IndividualCustomer", BaseEntity: "Customer", BaseClass: "MyEntity"}, nil, "baseEntity and baseClass cannot both be set"},
+		{"different entity type", Entity{Name: "IndividualCustomer", BaseEntity: "Customer", EntityType: "AggregateRoot"}, nil, "entityType 'AggregateRoot' must match 'FullAuditedAggregateRoot'"},
+		{"different key", Entity{Name: "IndividualCustomer", BaseEntity: "Customer", PrimaryKeyType: "Guid"}, nil, "primaryKeyType 'Guid' must match base entity 'Customer'"},
+		{"redeclared property", Entity{Name: "IndividualCustomer", BaseEntity: "Customer", Properties: []Property{{Name: "Name", Type: "string"}}}, nil, "property 'Name' is already declared by base entity 'Customer'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(append([]Entity{customer(), tt.derived}, tt.extra...)...)
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				derived := sch.FindEntity(tt.derived.Name)
+				if derived.EntityType != "FullAuditedAggregateRoot" || derived.PrimaryKeyType != "long" {
+					t.Errorf("derived entity type and key = %s, %s; want them copied from the base entity", derived.EntityType, derived.PrimaryKeyType)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CompareRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ValidationRule
+		wantErr string
+	}{
+		{"other property", ValidationRule{Type: "Compare", Value: "StartDate"}, ""},
+		{"explicit operator", ValidationRule{Type: "Compare", Value: "StartDate", Operator: ">="}, ""},
+		{"unknown property", ValidationRule{Type: "Compare", Value: "BeginDate"}, "compared property 'BeginDate' does not exist in properties"},
+		{"itself", ValidationRule{Type: "Compare", Value: "EndDate"}, "a property cannot be compared with itself"},
+		{"invalid operator", ValidationRule{Type: "Compare", Value: "StartDate", Operator: "=>"}, "invalid compare operator '=>'"},
+		{"read-only property", ValidationRule{Type: "Compare", Value: "PublishedAt"}, "compared property 'PublishedAt' is not on the Create and Update DTOs"},
+		{"foreign key", ValidationRule{Type: "Compare", Value: "CampaignId"}, "compared property 'CampaignId' is not on the Create and Update DTOs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name: "Promotion",
+				Properties: []Property{
+					{Name: "StartDate", Type: "DateTime"},
+					{Name: "EndDate", Type: "DateTime", ValidationRules: []ValidationRule{tt.rule}},
+					{Name: "PublishedAt", Type: "DateTime", ReadOnly: true},
+					{Name: "CampaignId", Type: "Guid", IsForeignKey: true, TargetEntity: "Campaign"},
+				},
+			})
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				if got := sch.Entities[0].Properties[1].ValidationRules[0].Operator; got == "" {
+					t.Error("Validate() did not default the compare operator")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CompareRuleOnNonInputProperty(t *testing.T) {
+	sch := newValidSchema(Entity{
+		Name: "Promotion",
+		Properties: []Property{
+			{Name: "StartDate", Type: "DateTime"},
+			{Name: "PublishedAt", Type: "DateTime", ReadOnly: true, ValidationRules: []ValidationRule{{Type: "Compare", Value: "StartDate"}}},
+		},
+	})
+
+	err := sch.Validate()
+	want := "property 'PublishedAt' is not on the Create and Update DTOs and cannot be compared"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Validate() error = %v, want %q", err, want)
+	}
+}
+
+func TestValidate_GenerateGrpc(t *testing.T) {
+	tests := []struct {
+		target  TargetFramework
+		wantErr bool
+	}{
+		{TargetABP9Microservice, false},
+		{TargetAuto, false},
+		{TargetABP9Monolith, true},
+		{TargetASPNETCore9, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.target), func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+			sch.Solution.TargetFramework = tt.target
+			sch.Options.GenerateGrpc = true
+
+			err := sch.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "options.generateGrpc requires an abp*-microservice targetFramework") {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_LocalizationCultures(t *testing.T) {
+	tests := []struct {
+		name     string
+		cultures []string
+		wantErr  string
+	}{
+		{"language and region", []string{"en", "en-US", "zh-Hans", "zh-Hans-CN", "es-419"}, ""},
+		{"trailing space", []string{"en-US "}, "options.localizationCultures[0] 'en-US ' is not a valid culture name"},
+		{"language name", []string{"en", "english"}, "options.localizationCultures[1] 'english' is not a valid culture name"},
+		{"duplicate", []string{"en-US", "en-us"}, "duplicate localization culture 'en-us'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+			sch.Options.UseLocalization = true
+			sch.Options.LocalizationCultures = tt.cultures
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DefaultLocalizationCulture(t *testing.T) {
+	sch := newValidSchema(Entity{Name: "Product", Properties: []Property{{Name: "Name", Type: "string"}}})
+	sch.Options.UseLocalization = true
+
+	if err := sch.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := strings.Join(sch.Options.LocalizationCultures, ","); got != "en" {
+		t.Errorf("LocalizationCultures = %q, want \"en\"", got)
+	}
+}
+
+func TestValidate_GenerateManager(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name         string
+		entityType   string
+		manager      *bool
+		deleteGuards bool
+		wantErr      string
+	}{
+		{"opted out", "Entity", &no, false, ""},
+		{"opted out with delete guards", "Entity", &no, true, "generateManager cannot be false"},
+		{"value object opted in", "ValueObject", &yes, false, "generateManager cannot be true for a ValueObject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(
+				Entity{
+					Name:            "Category",
+					EntityType:      tt.entityType,
+					GenerateManager: tt.manager,
+					Properties:      []Property{{Name: "Name", Type: "string"}},
+					Relations: &Relations{
+						OneToMany: []OneToManyRelation{{TargetEntity: "Product", ForeignKeyName: "CategoryId"}},
+					},
+				},
+				Entity{
+					Name: "Product",
+					Properties: []Property{
+						{Name: "Name", Type: "string"},
+						{Name: "CategoryId", Type: "Guid", IsForeignKey: true, TargetEntity: "Category"},
+					},
+				},
+			)
+			sch.Options.GenerateDeleteGuards = tt.deleteGuards
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SeedDataNeedsGeneratedKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		primaryKeyType string
+		underlyingType string
+		dbProvider     string
+		wantErr        string
+	}{
+		{"guid", "Guid", "", "mongodb", ""},
+		{"guid-backed typed id", "ProductId", "", "efcore", ""},
+		{"long identity", "long", "", "efcore", ""},
+		{"long without identity", "long", "", "mongodb", "need a primary key the seeder can generate"},
+		{"long-backed typed id", "ProductId", "long", "efcore", "need a primary key the seeder can generate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := newValidSchema(Entity{
+				Name:                     "Product",
+				PrimaryKeyType:           tt.primaryKeyType,
+				PrimaryKeyUnderlyingType: tt.underlyingType,
+				Properties:               []Property{{Name: "Name", Type: "string"}},
+				SeedData:                 []SeedRow{{"Name": "Widget"}},
+			})
+			sch.Solution.DBProvider = tt.dbProvider
+
+			err := sch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_RelationDefaults(t *testing.T) {
+	sch := newValidSchema(
+		Entity{
+			Name:       "Order",
+			Properties: []Property{{Name: "Number", Type: "string"}},
+			Relations: &Relations{
+				OneToOne:   []OneToOneRelation{{TargetEntity: "Address", IsOwned: true}},
+				ManyToOne:  []ManyToOneRelation{{TargetEntity: "Customer"}},
+				ManyToMany: []ManyToManyRelation{{TargetEntity: "Tag"}, {TargetEntity: "Order"}},
+			},
+		},
+		Entity{Name: "Address", EntityType: "ValueObject", Properties: []Property{{Name: "Street", Type: "string"}}},
+		Entity{Name: "Customer", Properties: []Property{{Name: "Name", Type: "string"}}},
+		Entity{Name: "Tag", Properties: []Property{{Name: "Label", Type: "string"}}},
+	)
+	if err := sch.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	relations := sch.Entities[0].Relations
+	if got := relations.OneToOne[0].NavigationProperty; got != "Address" {
+		t.Errorf("oneToOne navigationProperty = %q, want Address", got)
+	}
+	if got := relations.ManyToOne[0].NavigationProperty; got != "Customer" {
+		t.Errorf("manyToOne navigationProperty = %q, want Customer", got)
+	}
+	if got := relations.ManyToMany[0].JoinEntity; got != "OrderTag" {
+		t.Errorf("manyToMany joinEntity = %q, want OrderTag", got)
+	}
+	// Self-referencing join entities are named by the relationship handler after the navigation
+	if got := relations.ManyToMany[1].JoinEntity; got != "" {
+		t.Errorf("self-referencing joinEntity = %q, want it left to the relationship handler", got)
+	}
+}
@@ -4,41 +4,45 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/gertd/go-pluralize"
+	"github.com/mohamedhabibwork/abp-gen/internal/csharp"
 )
 
-var pluralizeClient *pluralize.Client
-
-func init() {
-	pluralizeClient = pluralize.NewClient()
-}
-
 // GetTemplateFuncs returns all custom template functions
 func GetTemplateFuncs() template.FuncMap {
 	return template.FuncMap{
-		"pluralize":  Pluralize,
-		"camelCase":  CamelCase,
-		"pascalCase": PascalCase,
-		"lowerFirst": LowerFirst,
-		"upperFirst": UpperFirst,
-		"csType":     CSType,
-		"nullable":   Nullable,
-		"attribute":  Attribute,
-		"contains":   strings.Contains,
-		"hasPrefix":  strings.HasPrefix,
-		"hasSuffix":  strings.HasSuffix,
-		"trimSuffix": strings.TrimSuffix,
-		"trimPrefix": strings.TrimPrefix,
-		"toLower":    strings.ToLower,
-		"toUpper":    strings.ToUpper,
-		"join":       strings.Join,
-		"sub":        Sub,
+		"pluralize":   Pluralize,
+		"singularize": Singularize,
+		"camelCase":   CamelCase,
+		"pascalCase":  PascalCase,
+		"lowerFirst":  LowerFirst,
+		"upperFirst":  UpperFirst,
+		"csType":      CSType,
+		"nullable":    Nullable,
+		"csharpType":  CSharpType,
+		"attribute":   Attribute,
+		"contains":    strings.Contains,
+		"hasPrefix":   strings.HasPrefix,
+		"hasSuffix":   strings.HasSuffix,
+		"trimSuffix":  strings.TrimSuffix,
+		"trimPrefix":  strings.TrimPrefix,
+		"toLower":     strings.ToLower,
+		"toUpper":     strings.ToUpper,
+		"join":        strings.Join,
+		"sub":         Sub,
+		"xmlDoc":      XMLDoc,
+		"langVersion": LangVersion,
+		"isAbpTarget": IsAbpTarget,
 	}
 }
 
 // Pluralize converts singular to plural
 func Pluralize(word string) string {
-	return pluralizeClient.Plural(word)
+	return csharp.Pluralize(word)
+}
+
+// Singularize converts plural to singular
+func Singularize(word string) string {
+	return csharp.Singularize(word)
 }
 
 // CamelCase converts string to camelCase
@@ -83,6 +87,36 @@ func UpperFirst(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
+// XMLDoc escapes text for a C# XML doc comment, joining its lines so it fits on one
+func XMLDoc(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	return strings.ReplaceAll(s, ">", "&gt;")
+}
+
+// LangVersion returns the default C# language version of a target framework: 12 for
+// ABP 8 (.NET 8), 13 for .NET 9 targets and 14 for .NET 10 targets. Unknown targets
+// get 8, the oldest version generated code must compile with.
+func LangVersion(target string) int {
+	switch {
+	case target == "aspnetcore10" || strings.HasPrefix(target, "abp10-"):
+		return 14
+	case target == "aspnetcore9" || strings.HasPrefix(target, "abp9-"):
+		return 13
+	case strings.HasPrefix(target, "abp8-"):
+		return 12
+	default:
+		return 8
+	}
+}
+
+// IsAbpTarget reports whether a target framework is an ABP solution rather than a plain ASP.NET
+// Core application. Unset and auto-detected targets count as ABP, the default target.
+func IsAbpTarget(target string) bool {
+	return !strings.HasPrefix(target, "aspnetcore")
+}
+
 // CSType maps common type names to C# types
 func CSType(typeName string) string {
 	typeMap := map[string]string{
@@ -109,24 +143,12 @@ func CSType(typeName string) string {
 
 // Nullable adds nullable marker for nullable types
 func Nullable(typeName string, isNullable bool) string {
-	if !isNullable {
-		return typeName
-	}
-
-	// Value types that need nullable marker
-	valueTypes := map[string]bool{
-		"int": true, "long": true, "decimal": true,
-		"DateTime": true, "bool": true, "Guid": true,
-		"byte": true, "short": true, "float": true,
-		"double": true,
-	}
-
-	if valueTypes[typeName] {
-		return typeName + "?"
-	}
+	return csharp.Nullable(typeName, isNullable)
+}
 
-	// Reference types (string, custom types) don't need ?
-	return typeName
+// CSharpType renders a property's C# type, adding the nullable marker for nullable value types
+func CSharpType(prop csharp.TypedProperty) string {
+	return csharp.Type(prop)
 }
 
 // Attribute generates C# data annotation attributes
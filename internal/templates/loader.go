@@ -2,21 +2,24 @@ package templates
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 	"text/template"
 )
 
 //go:embed *.tmpl
 var embeddedTemplates embed.FS
 
-// Loader manages template loading from various sources
+// Loader manages template loading from various sources. It is safe for concurrent use.
 type Loader struct {
 	customPath      string
 	targetFramework string // Target framework: "aspnetcore9", "abp8-microservice", "abp8-monolith"
 	templates       map[string]*template.Template
+	mu              sync.Mutex // Guards targetFramework and templates
 }
 
 // NewLoader creates a new template loader
@@ -42,84 +45,125 @@ func NewLoaderWithTarget(customPath string, targetFramework string) *Loader {
 
 // SetTargetFramework sets the target framework for template loading
 func (l *Loader) SetTargetFramework(target string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.targetFramework = target
 	// Clear cached templates when target changes
 	l.templates = make(map[string]*template.Template)
 }
 
+// Template source layers, in resolution order
+const (
+	SourceCustom    = "custom"    // The --templates directory
+	SourceExtracted = "extracted" // ./abp-gen-templates, written by init
+	SourceEmbedded  = "embedded"  // Built into the binary
+)
+
+// TemplateSource is a location a template can be loaded from
+type TemplateSource struct {
+	Layer string // SourceCustom, SourceExtracted or SourceEmbedded
+	Path  string // File path, or the path within the embedded templates
+}
+
+// SkippedSource is a template that exists at a higher-priority source but failed to load
+type SkippedSource struct {
+	Source TemplateSource
+	Err    error
+}
+
+// TemplateError is a template that could not be loaded. Path is the source that failed to parse,
+// or empty when no source holds the template.
+type TemplateError struct {
+	Name string
+	Path string
+	Err  error
+}
+
+func (e *TemplateError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// sources returns the locations of a template in resolution order
+func (l *Loader) sources(name string) []TemplateSource {
+	var sources []TemplateSource
+	if l.customPath != "" {
+		sources = append(sources,
+			TemplateSource{SourceCustom, filepath.Join(l.customPath, l.targetFramework, name)},
+			TemplateSource{SourceCustom, filepath.Join(l.customPath, "common", name)},
+		)
+	}
+	return append(sources,
+		TemplateSource{SourceExtracted, filepath.Join("./abp-gen-templates/", l.targetFramework, name)},
+		TemplateSource{SourceExtracted, filepath.Join("./abp-gen-templates/common", name)},
+		TemplateSource{SourceEmbedded, filepath.Join(l.targetFramework, name)},
+		TemplateSource{SourceEmbedded, filepath.Join("common", name)},
+		// Last resort: the embedded root (backward compatibility)
+		TemplateSource{SourceEmbedded, name},
+	)
+}
+
 // Load loads a template by name with the following priority:
 // 1. Target-specific custom path (if provided)
 // 2. Target-specific extracted templates directory
 // 3. Target-specific embedded templates
 // 4. Common/shared templates (fallback)
 func (l *Loader) Load(name string) (*template.Template, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	// Check if already loaded
 	cacheKey := l.targetFramework + ":" + name
 	if tmpl, ok := l.templates[cacheKey]; ok {
 		return tmpl, nil
 	}
 
-	var tmpl *template.Template
-	var err error
-
-	// Try target-specific custom path first
-	if l.customPath != "" {
-		targetPath := filepath.Join(l.customPath, l.targetFramework, name)
-		tmpl, err = l.loadFromPath(targetPath)
-		if err == nil {
-			l.templates[cacheKey] = tmpl
-			return tmpl, nil
-		}
-
-		// Try common custom path
-		commonPath := filepath.Join(l.customPath, "common", name)
-		tmpl, err = l.loadFromPath(commonPath)
-		if err == nil {
-			l.templates[cacheKey] = tmpl
-			return tmpl, nil
+	tmpl, _, skipped, err := l.resolve(name)
+	if err != nil {
+		loadErr := &TemplateError{Name: name, Err: err}
+		if len(skipped) > 0 {
+			loadErr.Path = skipped[0].Source.Path
 		}
+		return nil, loadErr
 	}
 
-	// Try target-specific extracted templates directory
-	extractedPath := filepath.Join("./abp-gen-templates/", l.targetFramework, name)
-	tmpl, err = l.loadFromPath(extractedPath)
-	if err == nil {
-		l.templates[cacheKey] = tmpl
-		return tmpl, nil
-	}
-
-	// Try common extracted templates
-	commonExtractedPath := filepath.Join("./abp-gen-templates/common", name)
-	tmpl, err = l.loadFromPath(commonExtractedPath)
-	if err == nil {
-		l.templates[cacheKey] = tmpl
-		return tmpl, nil
-	}
+	l.templates[cacheKey] = tmpl
+	return tmpl, nil
+}
 
-	// Try target-specific embedded templates
-	targetEmbedPath := filepath.Join(l.targetFramework, name)
-	tmpl, err = l.loadFromEmbedded(targetEmbedPath)
-	if err == nil {
-		l.templates[cacheKey] = tmpl
-		return tmpl, nil
-	}
+// Resolve returns the source Load takes a template from, along with the higher-priority
+// sources holding the template that Load skipped because they failed to parse
+func (l *Loader) Resolve(name string) (TemplateSource, []SkippedSource, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// Fall back to common embedded templates
-	commonEmbedPath := filepath.Join("common", name)
-	tmpl, err = l.loadFromEmbedded(commonEmbedPath)
-	if err == nil {
-		l.templates[cacheKey] = tmpl
-		return tmpl, nil
-	}
+	_, source, skipped, err := l.resolve(name)
+	return source, skipped, err
+}
 
-	// Last resort: try loading from root (backward compatibility)
-	tmpl, err = l.loadFromEmbedded(name)
-	if err != nil {
-		return nil, fmt.Errorf("template '%s' not found for target '%s' in any location: %w", name, l.targetFramework, err)
+// resolve loads a template from the first of its sources that holds a valid template
+func (l *Loader) resolve(name string) (*template.Template, TemplateSource, []SkippedSource, error) {
+	var skipped []SkippedSource
+	var err error
+	for _, source := range l.sources(name) {
+		var tmpl *template.Template
+		if source.Layer == SourceEmbedded {
+			tmpl, err = l.loadFromEmbedded(source.Path)
+		} else {
+			tmpl, err = l.loadFromPath(source.Path)
+		}
+		if err == nil {
+			return tmpl, source, skipped, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			skipped = append(skipped, SkippedSource{Source: source, Err: err})
+		}
 	}
-
-	l.templates[cacheKey] = tmpl
-	return tmpl, nil
+	return nil, TemplateSource{}, skipped, fmt.Errorf("template '%s' not found for target '%s' in any location: %w", name, l.targetFramework, err)
 }
 
 // loadFromPath loads template from filesystem
@@ -0,0 +1,146 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunLogPath is where the operation log of the last generation run is kept, relative to
+// the working directory
+var RunLogPath = filepath.Join(".abp-gen", "last-run.json")
+
+// RunLog records the files a generation run created or updated so the run can be undone
+type RunLog struct {
+	Time       time.Time     `json:"time"`
+	Operations []RunLogEntry `json:"operations"`
+}
+
+// RunLogEntry is a created or updated file in a run log
+type RunLogEntry struct {
+	Path      string        `json:"path"`
+	Operation OperationType `json:"operation"`
+	Backup    string        `json:"backup,omitempty"` // Copy of the content from before the run
+}
+
+// UndoAction is what undoing a run did with a file
+type UndoAction string
+
+const (
+	UndoDeleted     UndoAction = "DELETED"      // Created by the run and removed
+	UndoRestored    UndoAction = "RESTORED"     // Updated by the run and restored from its backup
+	UndoNotRestored UndoAction = "NOT_RESTORED" // Updated by the run without a backup to restore
+)
+
+// UndoResult is the outcome of undoing a run for a single file
+type UndoResult struct {
+	Path   string
+	Action UndoAction
+}
+
+// SaveRunLog writes the files created and updated so far to path, replacing an earlier log.
+// Paths are stored as absolute paths so the log can be undone from another directory.
+func (w *Writer) SaveRunLog(path string) error {
+	log := RunLog{Time: time.Now(), Operations: []RunLogEntry{}}
+	for _, op := range w.Manifest() {
+		if op.Type != OperationCreate && op.Type != OperationUpdate {
+			continue
+		}
+
+		entry := RunLogEntry{Path: op.Path, Operation: op.Type, Backup: op.Backup}
+		if abs, err := filepath.Abs(op.Path); err == nil {
+			entry.Path = abs
+		}
+		if entry.Backup != "" {
+			if abs, err := filepath.Abs(entry.Backup); err == nil {
+				entry.Backup = abs
+			}
+		}
+		log.Operations = append(log.Operations, entry)
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run log: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for run log: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run log %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadRunLog reads a run log written by SaveRunLog
+func LoadRunLog(path string) (*RunLog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var log RunLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse run log %s: %w", path, err)
+	}
+	return &log, nil
+}
+
+// Undo reverts the run: files it created are deleted, and files it updated are restored
+// from their backups. A file written several times is reverted once, according to its
+// first operation in the run. Results are returned in reverse run order.
+func (l *RunLog) Undo() ([]UndoResult, error) {
+	first := make(map[string]RunLogEntry)
+	for _, entry := range l.Operations {
+		if _, seen := first[entry.Path]; !seen {
+			first[entry.Path] = entry
+		}
+	}
+
+	var results []UndoResult
+	for i := len(l.Operations) - 1; i >= 0; i-- {
+		entry, pending := first[l.Operations[i].Path]
+		if !pending {
+			continue
+		}
+		delete(first, entry.Path)
+
+		result, err := undoEntry(entry, l.Operations)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// undoEntry reverts the first operation of a file in a run
+func undoEntry(entry RunLogEntry, operations []RunLogEntry) (UndoResult, error) {
+	if entry.Operation == OperationCreate {
+		if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+			return UndoResult{}, fmt.Errorf("failed to delete %s: %w", entry.Path, err)
+		}
+
+		// A later update in the same run backed up content the run itself wrote
+		for _, op := range operations {
+			if op.Path == entry.Path && op.Backup != "" {
+				if err := os.Remove(op.Backup); err != nil && !os.IsNotExist(err) {
+					return UndoResult{}, fmt.Errorf("failed to delete %s: %w", op.Backup, err)
+				}
+				break
+			}
+		}
+		return UndoResult{Path: entry.Path, Action: UndoDeleted}, nil
+	}
+
+	if entry.Backup == "" || !fileExists(entry.Backup) {
+		return UndoResult{Path: entry.Path, Action: UndoNotRestored}, nil
+	}
+	if err := os.Rename(entry.Backup, entry.Path); err != nil {
+		return UndoResult{}, fmt.Errorf("failed to restore %s from %s: %w", entry.Path, entry.Backup, err)
+	}
+	return UndoResult{Path: entry.Path, Action: UndoRestored}, nil
+}
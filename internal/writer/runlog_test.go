@@ -0,0 +1,82 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunLog_Undo(t *testing.T) {
+	dir := t.TempDir()
+	created := filepath.Join(dir, "Product.cs")
+	updated := filepath.Join(dir, "ShopDbContext.cs")
+	noBackup := filepath.Join(dir, "en.json")
+	for path, content := range map[string]string{updated: "old context", noBackup: "{}"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := NewWriter(false, true, false)
+	w.SetBackup(true)
+	for _, write := range []struct{ path, content string }{
+		{created, "first"},
+		{created, "second"},
+		{updated, "new context"},
+		{updated, "newer context"},
+	} {
+		if err := w.WriteFile(write.path, write.content); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	w.SetBackup(false)
+	if err := w.WriteFile(noBackup, `{"New": "Added"}`); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	logPath := filepath.Join(dir, ".abp-gen", "last-run.json")
+	if err := w.SaveRunLog(logPath); err != nil {
+		t.Fatalf("SaveRunLog() error = %v", err)
+	}
+	log, err := LoadRunLog(logPath)
+	if err != nil {
+		t.Fatalf("LoadRunLog() error = %v", err)
+	}
+	if len(log.Operations) != 5 {
+		t.Fatalf("run log has %d operations, want 5: %+v", len(log.Operations), log.Operations)
+	}
+
+	results, err := log.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	want := []UndoResult{
+		{noBackup, UndoNotRestored},
+		{updated, UndoRestored},
+		{created, UndoDeleted},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Undo() = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("created file still exists")
+	}
+	if _, err := os.Stat(created + BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("backup of created file still exists")
+	}
+	if content, _ := os.ReadFile(updated); string(content) != "old context" {
+		t.Errorf("updated file = %q, want the content from before the run", content)
+	}
+	if _, err := os.Stat(updated + BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("backup of restored file still exists")
+	}
+	if content, _ := os.ReadFile(noBackup); string(content) != `{"New": "Added"}` {
+		t.Errorf("file without backup = %q, want it left as generated", content)
+	}
+}
@@ -1,10 +1,14 @@
 package writer
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/merger"
 )
@@ -14,7 +18,19 @@ type FileOperation struct {
 	Type     OperationType
 	Path     string
 	Content  string
-	Existing bool // Whether file already exists
+	Existing bool   // Whether file already exists
+	Backup   string // Copy of the content from before the run, if one was written
+}
+
+// MarshalJSON encodes the operation as a manifest entry: its path, operation and content size.
+// The content itself is left out.
+func (op FileOperation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path      string        `json:"path"`
+		Operation OperationType `json:"operation"`
+		Bytes     int           `json:"bytes"`
+		Existing  bool          `json:"existing"`
+	}{op.Path, op.Type, len(op.Content), op.Existing})
 }
 
 // OperationType represents the type of file operation
@@ -26,7 +42,8 @@ const (
 	OperationSkip   OperationType = "SKIP"
 )
 
-// Writer handles file writing with support for dry-run and force modes
+// Writer handles file writing with support for dry-run and force modes.
+// Its methods may be called from several goroutines; writes are serialized.
 type Writer struct {
 	DryRun      bool
 	Force       bool
@@ -34,6 +51,32 @@ type Writer struct {
 	MergeMode   bool
 	Operations  []FileOperation
 	mergeEngine *merger.Engine
+	header      string
+	backup      bool
+	batchMerge  bool
+	pending     []*merger.PendingMerge // Merges queued until FlushMerges, in queue order
+	backedUp    map[string]bool        // Paths already backed up in this run
+	out         io.Writer              // Receives the operation log and summary
+	mu          sync.Mutex             // Serializes writes, including read-modify-write updates
+}
+
+// BackupSuffix is appended to the path of an existing file to name its backup copy
+const BackupSuffix = ".bak"
+
+// SetHeader sets the generated header comment prepended to C# files (empty disables it)
+func (w *Writer) SetHeader(header string) {
+	w.header = header
+}
+
+// SetBackup enables copying existing files to {path}.bak before they are overwritten or merged
+func (w *Writer) SetBackup(enabled bool) {
+	w.backup = enabled
+}
+
+// SetBatchMerge queues merges of existing files instead of prompting per file;
+// FlushMerges then summarizes them and asks for one decision
+func (w *Writer) SetBatchMerge(enabled bool) {
+	w.batchMerge = enabled
 }
 
 // SetMergeAll configures the merge engine to merge all files without prompting
@@ -48,6 +91,26 @@ func (w *Writer) SetMergeAll(enabled bool) {
 	}
 }
 
+// SetOutput sets where the operation log, summary and merge progress are printed
+func (w *Writer) SetOutput(out io.Writer) {
+	w.out = out
+	if w.mergeEngine != nil {
+		w.mergeEngine.SetOutput(out)
+	}
+}
+
+// Output returns where the writer prints, for generators reporting alongside its log
+func (w *Writer) Output() io.Writer {
+	return w.out
+}
+
+// SetNonInteractive configures the merge engine to fail instead of prompting
+func (w *Writer) SetNonInteractive(enabled bool) {
+	if w.mergeEngine != nil {
+		w.mergeEngine.NonInteractive = enabled
+	}
+}
+
 // NewWriter creates a new file writer
 func NewWriter(dryRun, force, verbose bool) *Writer {
 	return &Writer{
@@ -57,6 +120,7 @@ func NewWriter(dryRun, force, verbose bool) *Writer {
 		MergeMode:   false,
 		Operations:  []FileOperation{},
 		mergeEngine: merger.NewEngine(force, verbose),
+		out:         os.Stdout,
 	}
 }
 
@@ -69,11 +133,50 @@ func NewWriterWithMerge(dryRun, force, verbose, mergeMode bool) *Writer {
 		MergeMode:   mergeMode,
 		Operations:  []FileOperation{},
 		mergeEngine: merger.NewEngine(force, verbose),
+		out:         os.Stdout,
+	}
+}
+
+// FileError is a failure to write or update a file, including failures to compute its content
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// fileError attributes err to path
+func fileError(path string, err error) error {
+	if err == nil {
+		return nil
 	}
+	return &FileError{Path: filepath.Clean(path), Err: err}
 }
 
 // WriteFile writes content to a file
 func (w *Writer) WriteFile(path string, content string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return fileError(path, w.writeFile(path, content))
+}
+
+// writeFile writes content rendered by a generator to a file, prepending (or refreshing) the
+// generated header; the caller holds w.mu
+func (w *Writer) writeFile(path string, content string) error {
+	if w.header != "" && merger.SupportsGeneratedHeader(path) {
+		content = merger.ApplyGeneratedHeader(content, w.header)
+	}
+	return w.writeContent(path, content)
+}
+
+// writeContent writes content to a file as is; the caller holds w.mu
+func (w *Writer) writeContent(path string, content string) error {
 	// Normalize path
 	path = filepath.Clean(path)
 
@@ -82,6 +185,10 @@ func (w *Writer) WriteFile(path string, content string) error {
 
 	// If merge mode is enabled and file exists, try to merge
 	if w.MergeMode && exists && !w.Force {
+		if w.batchMerge {
+			return w.queueMerge(path, content)
+		}
+
 		mergedContent, shouldWrite, err := w.mergeEngine.MergeFile(path, content)
 		if err != nil {
 			return fmt.Errorf("merge failed for %s: %w", path, err)
@@ -103,16 +210,20 @@ func (w *Writer) WriteFile(path string, content string) error {
 		content = mergedContent
 	}
 
-	// Determine operation type
+	// Existing files are only replaced when forced or merged
+	if exists && !w.Force && !w.MergeMode {
+		w.logOperation(OperationSkip, path)
+		return nil
+	}
+
+	return w.commit(path, content, exists)
+}
+
+// commit records the create or update of path and writes content unless this is a dry run
+func (w *Writer) commit(path string, content string, exists bool) error {
 	opType := OperationCreate
 	if exists {
-		if w.Force || w.MergeMode {
-			opType = OperationUpdate
-		} else {
-			opType = OperationSkip
-			w.logOperation(opType, path)
-			return nil
-		}
+		opType = OperationUpdate
 	}
 
 	// Record operation
@@ -131,6 +242,21 @@ func (w *Writer) WriteFile(path string, content string) error {
 		return nil
 	}
 
+	// Keep the previous version before replacing it; a file updated more than once in a
+	// run keeps the backup taken before its first update
+	if exists && w.backup {
+		if !w.backedUp[path] {
+			if err := backupFile(path); err != nil {
+				return err
+			}
+			if w.backedUp == nil {
+				w.backedUp = make(map[string]bool)
+			}
+			w.backedUp[path] = true
+		}
+		w.Operations[len(w.Operations)-1].Backup = path + BackupSuffix
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -145,10 +271,98 @@ func (w *Writer) WriteFile(path string, content string) error {
 	return nil
 }
 
+// writeUpdate writes back an in-place update of an existing file, which may be hand-written, so
+// it is never labeled as generated. Updates are targeted edits that check for their own content,
+// so they apply in every mode rather than being skipped like regenerated files or merged as a
+// whole; a file queued for a batch merge gets the update in its queued content instead. The
+// caller holds w.mu
+func (w *Writer) writeUpdate(path string, content string) error {
+	path = filepath.Clean(path)
+	for _, queued := range w.pending {
+		if queued.Path == path {
+			return w.queueMerge(path, content)
+		}
+	}
+	return w.commit(path, content, true)
+}
+
+// queueMerge computes the merge of content into the existing file at path and keeps it
+// for FlushMerges. A later write to the same path replaces the queued one.
+func (w *Writer) queueMerge(path string, content string) error {
+	pending, err := w.mergeEngine.PreviewMerge(path, content)
+	if err != nil {
+		return err
+	}
+
+	w.logOperation("QUEUED", path)
+	for i, queued := range w.pending {
+		if queued.Path == path {
+			w.pending[i] = pending
+			return nil
+		}
+	}
+	w.pending = append(w.pending, pending)
+	return nil
+}
+
+// FlushMerges prints one summary of the queued merges, asks for a single decision and
+// writes the results. It does nothing when no merge was queued.
+func (w *Writer) FlushMerges() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	pending := w.pending
+	w.pending = nil
+
+	results, err := w.mergeEngine.ResolveBatch(pending)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		if !result.Write {
+			w.Operations = append(w.Operations, FileOperation{
+				Type:     OperationSkip,
+				Path:     result.Path,
+				Content:  pending[i].NewContent,
+				Existing: true,
+			})
+			w.logOperation(OperationSkip, result.Path)
+			continue
+		}
+		if err := w.commit(result.Path, result.Content, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentContent returns the content of path as later writes should see it: the content
+// queued for a batch merge if there is one, otherwise the file on disk
+func (w *Writer) currentContent(path string) ([]byte, error) {
+	path = filepath.Clean(path)
+	for _, queued := range w.pending {
+		if queued.Path == path {
+			return []byte(queued.NewContent), nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
 // UpdateFile updates an existing file by applying a modification function
 func (w *Writer) UpdateFile(path string, modifyFunc func(string) (string, error)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return fileError(path, w.updateFile(path, modifyFunc))
+}
+
+// updateFile applies modifyFunc to the file at path; the caller holds w.mu
+func (w *Writer) updateFile(path string, modifyFunc func(string) (string, error)) error {
 	// Read existing content
-	content, err := os.ReadFile(path)
+	content, err := w.currentContent(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("file does not exist: %s", path)
@@ -156,21 +370,38 @@ func (w *Writer) UpdateFile(path string, modifyFunc func(string) (string, error)
 		return err
 	}
 
-	// Apply modification
-	newContent, err := modifyFunc(string(content))
+	// Apply modification with LF line endings, then restore the file's own
+	format := merger.DetectLineFormat(string(content))
+	newContent, err := modifyFunc(merger.NormalizeLineEndings(string(content)))
 	if err != nil {
 		return err
 	}
 
 	// Write back
-	return w.WriteFile(path, newContent)
+	return w.writeUpdate(path, format.Apply(newContent))
 }
 
 // UpdateFileIdempotent updates a file only if the specified content doesn't already exist
 // If the file doesn't exist, it will call createFunc to generate initial content
 func (w *Writer) UpdateFileIdempotent(path string, searchPattern string, insertFunc func(string) (string, error), createFunc func() (string, error)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	contains := func(content string) bool { return strings.Contains(content, searchPattern) }
+	return fileError(path, w.updateFileIdempotent(path, contains, insertFunc, createFunc))
+}
+
+// UpdateFileIdempotentMatch is UpdateFileIdempotent with a regular expression recognizing the
+// existing content, for patterns that must not match inside longer names
+func (w *Writer) UpdateFileIdempotentMatch(path string, pattern *regexp.Regexp, insertFunc func(string) (string, error), createFunc func() (string, error)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return fileError(path, w.updateFileIdempotent(path, pattern.MatchString, insertFunc, createFunc))
+}
+
+// updateFileIdempotent implements UpdateFileIdempotent; the caller holds w.mu
+func (w *Writer) updateFileIdempotent(path string, contains func(string) bool, insertFunc func(string) (string, error), createFunc func() (string, error)) error {
 	// Read existing content
-	content, err := os.ReadFile(path)
+	content, err := w.currentContent(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// File doesn't exist, create it with initial content
@@ -184,15 +415,17 @@ func (w *Writer) UpdateFileIdempotent(path string, searchPattern string, insertF
 			}
 
 			// Write the initial file
-			return w.WriteFile(path, initialContent)
+			return w.writeFile(path, initialContent)
 		}
 		return err
 	}
 
-	contentStr := string(content)
+	// Apply modification with LF line endings, then restore the file's own
+	format := merger.DetectLineFormat(string(content))
+	contentStr := merger.NormalizeLineEndings(string(content))
 
 	// Check if pattern already exists
-	if strings.Contains(contentStr, searchPattern) {
+	if contains(contentStr) {
 		w.logOperation(OperationSkip, path+" (already contains pattern)")
 		return nil
 	}
@@ -204,7 +437,7 @@ func (w *Writer) UpdateFileIdempotent(path string, searchPattern string, insertF
 	}
 
 	// Write back
-	return w.WriteFile(path, newContent)
+	return w.writeUpdate(path, format.Apply(newContent))
 }
 
 // EnsureDirectory ensures a directory exists
@@ -217,10 +450,23 @@ func (w *Writer) EnsureDirectory(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
+// Manifest returns the file operations planned or performed so far, in order
+func (w *Writer) Manifest() []FileOperation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	manifest := make([]FileOperation, len(w.Operations))
+	copy(manifest, w.Operations)
+	return manifest
+}
+
 // PrintSummary prints a summary of operations
 func (w *Writer) PrintSummary() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	if len(w.Operations) == 0 {
-		fmt.Println("No operations performed.")
+		fmt.Fprintln(w.out, "No operations performed.")
 		return
 	}
 
@@ -239,14 +485,14 @@ func (w *Writer) PrintSummary() {
 		}
 	}
 
-	fmt.Println("\n=== Summary ===")
-	fmt.Printf("Created: %d\n", created)
-	fmt.Printf("Updated: %d\n", updated)
-	fmt.Printf("Skipped: %d\n", skipped)
-	fmt.Printf("Total:   %d\n", len(w.Operations))
+	fmt.Fprintln(w.out, "\n=== Summary ===")
+	fmt.Fprintf(w.out, "Created: %d\n", created)
+	fmt.Fprintf(w.out, "Updated: %d\n", updated)
+	fmt.Fprintf(w.out, "Skipped: %d\n", skipped)
+	fmt.Fprintf(w.out, "Total:   %d\n", len(w.Operations))
 
 	if w.DryRun {
-		fmt.Println("\nDRY RUN: No files were actually modified.")
+		fmt.Fprintln(w.out, "\nDRY RUN: No files were actually modified.")
 	}
 }
 
@@ -266,11 +512,13 @@ func (w *Writer) logOperation(opType interface{}, path string) {
 		prefix = "[SKIP]  "
 	case "CREATE_DIR":
 		prefix = "[MKDIR] "
+	case "QUEUED":
+		prefix = "[QUEUED]"
 	default:
 		prefix = "[INFO]  "
 	}
 
-	fmt.Printf("%s %s\n", prefix, path)
+	fmt.Fprintf(w.out, "%s %s\n", prefix, path)
 }
 
 // fileExists checks if a file exists
@@ -279,6 +527,24 @@ func fileExists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
+// backupFile copies the file at path to path+BackupSuffix, replacing an older backup
+func backupFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s for backup: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s for backup: %w", path, err)
+	}
+
+	if err := os.WriteFile(path+BackupSuffix, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return nil
+}
+
 // ReadFile reads a file and returns its content
 func ReadFile(path string) (string, error) {
 	content, err := os.ReadFile(path)
@@ -0,0 +1,256 @@
+package writer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriter_Backup(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		backup     bool
+		wantBackup bool
+	}{
+		{"backup enabled", false, true, true},
+		{"backup disabled", false, false, false},
+		{"dry run", true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Product.cs")
+			if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			w := NewWriter(tt.dryRun, true, false)
+			w.SetBackup(tt.backup)
+			if err := w.WriteFile(path, "new"); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			backup, err := os.ReadFile(path + BackupSuffix)
+			if !tt.wantBackup {
+				if err == nil {
+					t.Errorf("unexpected backup file with %q", backup)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backup not written: %v", err)
+			}
+			if string(backup) != "old" {
+				t.Errorf("backup = %q, want previous content", backup)
+			}
+		})
+	}
+}
+
+func TestWriter_BatchMerge(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "en.json")
+	textPath := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(jsonPath, []byte(`{"Existing": "Kept"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(textPath, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWriterWithMerge(false, false, false, true)
+	w.SetBatchMerge(true)
+	w.SetNonInteractive(true)
+	w.SetMergeAll(true)
+
+	if err := w.WriteFile(jsonPath, `{"First": "One"}`); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	// Later updates build on the queued content rather than the file on disk
+	addSecond := func(content string) (string, error) {
+		return strings.Replace(content, `"First": "One"`, `"First": "One", "Second": "Two"`, 1), nil
+	}
+	if err := w.UpdateFileIdempotent(jsonPath, `"Second"`, addSecond, nil); err != nil {
+		t.Fatalf("UpdateFileIdempotent() error = %v", err)
+	}
+	if err := w.WriteFile(textPath, "new"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if content, _ := os.ReadFile(jsonPath); !strings.Contains(string(content), `"Existing"`) || strings.Contains(string(content), `"First"`) {
+		t.Errorf("file written before FlushMerges: %s", content)
+	}
+	if len(w.Manifest()) != 0 {
+		t.Errorf("operations recorded before FlushMerges: %+v", w.Manifest())
+	}
+
+	if err := w.FlushMerges(); err != nil {
+		t.Fatalf("FlushMerges() error = %v", err)
+	}
+
+	merged, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Existing"`, `"First"`, `"Second"`} {
+		if !strings.Contains(string(merged), want) {
+			t.Errorf("merged file missing %s: %s", want, merged)
+		}
+	}
+	if text, _ := os.ReadFile(textPath); string(text) != "old" {
+		t.Errorf("unmergeable file = %q, want it skipped", text)
+	}
+
+	ops := w.Manifest()
+	if len(ops) != 2 || ops[0].Type != OperationUpdate || ops[1].Type != OperationSkip {
+		t.Errorf("operations = %+v, want an update and a skip", ops)
+	}
+}
+
+func TestWriter_BatchMergeNonInteractive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "en.json")
+	if err := os.WriteFile(path, []byte(`{"Existing": "Kept"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWriterWithMerge(false, false, false, true)
+	w.SetBatchMerge(true)
+	w.SetNonInteractive(true)
+
+	if err := w.WriteFile(path, `{"Name": "Name"}`); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	err := w.FlushMerges()
+	if err == nil || !strings.Contains(err.Error(), "merge decision required for 1 file(s)") {
+		t.Errorf("FlushMerges() error = %v, want a non-interactive decision error", err)
+	}
+}
+
+func TestWriter_UpdateFileKeepsLineEndings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ShopModule.cs")
+	original := "public class ShopModule\r\n{\r\n}"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWriter(false, true, false)
+	addMethod := func(content string) (string, error) {
+		if strings.Contains(content, "\r") {
+			t.Errorf("update function received CRLF content: %q", content)
+		}
+		return strings.Replace(content, "{\n}", "{\n    void Configure() { }\n}\n", 1), nil
+	}
+	if err := w.UpdateFileIdempotent(path, "Configure", addMethod, nil); err != nil {
+		t.Fatalf("UpdateFileIdempotent() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "public class ShopModule\r\n{\r\n    void Configure() { }\r\n}"
+	if string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+func TestWriter_SetOutput(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(true, false, false)
+	w.SetOutput(&out)
+
+	path := filepath.Join(t.TempDir(), "Product.cs")
+	if err := w.WriteFile(path, "class Product {}"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	w.PrintSummary()
+
+	for _, want := range []string{"[CREATE] " + path, "=== Summary ===", "DRY RUN"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWriter_HeaderOnlyOnRenderedContent(t *testing.T) {
+	dir := t.TempDir()
+	header := "// <auto-generated> by abp-gen v1.0.0 from schema.json"
+	w := NewWriter(false, true, false)
+	w.SetOutput(io.Discard)
+	w.SetHeader(header)
+
+	module := filepath.Join(dir, "ShopEntityFrameworkCoreModule.cs")
+	if err := os.WriteFile(module, []byte("public class ShopEntityFrameworkCoreModule\n{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	addMethod := func(content string) (string, error) {
+		return strings.Replace(content, "{\n}", "{\n    void Configure() { }\n}", 1), nil
+	}
+	if err := w.UpdateFileIdempotent(module, "Configure", addMethod, nil); err != nil {
+		t.Fatalf("UpdateFileIdempotent() error = %v", err)
+	}
+	if err := w.UpdateFile(module, func(content string) (string, error) { return content + "// updated\n", nil }); err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+	content, err := os.ReadFile(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "<auto-generated>") {
+		t.Errorf("in-place update labeled the hand-written file as generated:\n%s", content)
+	}
+
+	entity := filepath.Join(dir, "Product.cs")
+	if err := w.WriteFile(entity, "public class Product\n{\n}\n"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	content, err = os.ReadFile(entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), header+"\n") {
+		t.Errorf("rendered file does not start with the header:\n%s", content)
+	}
+}
+
+func TestWriter_UpdatesApplyInEveryMode(t *testing.T) {
+	writers := []struct {
+		name string
+		w    *Writer
+	}{
+		{"default", NewWriter(false, false, false)},
+		{"merge", NewWriterWithMerge(false, false, false, true)},
+		{"force", NewWriter(false, true, false)},
+	}
+
+	for _, tt := range writers {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ShopDomainModule.cs")
+			if err := os.WriteFile(path, []byte("public class ShopDomainModule\n{\n}\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			tt.w.SetOutput(io.Discard)
+
+			addMethod := func(content string) (string, error) {
+				return strings.Replace(content, "{\n}", "{\n    void Configure() { }\n}", 1), nil
+			}
+			for i := 0; i < 2; i++ {
+				if err := tt.w.UpdateFileIdempotent(path, "Configure", addMethod, nil); err != nil {
+					t.Fatalf("UpdateFileIdempotent() run %d error = %v", i+1, err)
+				}
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Count(string(content), "void Configure()"); got != 1 {
+				t.Errorf("update applied %d times; want 1:\n%s", got, content)
+			}
+		})
+	}
+}
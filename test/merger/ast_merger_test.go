@@ -0,0 +1,147 @@
+package merger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/merger"
+)
+
+func TestASTMerger_MergeEntityProperty(t *testing.T) {
+	astMerger := merger.NewASTMerger()
+
+	// Hand-edited entity: custom constructor body, navigation collection with a protected setter
+	existing := `using System;
+using System.Collections.Generic;
+using Volo.Abp.Domain.Entities.Auditing;
+
+namespace Acme.Shop.Domain.Entities.CatalogModule
+{
+    public class Product : FullAuditedAggregateRoot<Guid>
+    {
+        public string Name { get; set; }
+        public bool IsActive { get; private set; } = true;
+        public virtual ICollection<Tag> Tags { get; protected set; }
+
+        protected Product()
+        {
+            Tags = new List<Tag>();
+        }
+
+        public Product(Guid id, string name) : base(id)
+        {
+            Tags = new List<Tag>();
+            SetName(name);
+            IsActive = true; // hand-edited
+        }
+
+        public void SetName(string name) => Name = name;
+    }
+}
+`
+
+	// Regenerated entity that adds Price
+	newContent := `using System;
+using System.Collections.Generic;
+using System.ComponentModel.DataAnnotations;
+using Volo.Abp.Domain.Entities.Auditing;
+
+namespace Acme.Shop.Domain.Entities.CatalogModule
+{
+    public class Product : FullAuditedAggregateRoot<Guid>
+    {
+        public string Name { get; set; }
+        [Required]
+        public decimal Price { get; set; }
+        public virtual ICollection<Tag> Tags { get; protected set; }
+
+        protected Product()
+        {
+            Tags = new List<Tag>();
+        }
+
+        public Product(Guid id, string name, decimal price) : base(id)
+        {
+            Tags = new List<Tag>();
+            SetName(name);
+            SetPrice(price);
+        }
+
+        public void SetName(string name) => Name = name;
+        public void SetPrice(decimal price) => Price = price;
+    }
+}
+`
+
+	merged, conflicts, err := astMerger.Merge(existing, newContent, merger.FileTypeEntity)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(conflicts) > 0 {
+		t.Fatalf("Expected no conflicts, got %d: %v", len(conflicts), conflicts)
+	}
+
+	// Price goes after the last scalar property, before the navigation collection
+	wantProperties := `        public bool IsActive { get; private set; } = true;
+        [Required]
+        public decimal Price { get; set; }
+        public virtual ICollection<Tag> Tags { get; protected set; }`
+	if !strings.Contains(merged, wantProperties) {
+		t.Errorf("Price not placed after the last scalar property:\n%s", merged)
+	}
+
+	checks := map[string]int{
+		"public decimal Price { get; set; }":                           1,
+		"public virtual ICollection<Tag> Tags { get; protected set; }": 1,
+		"IsActive = true; // hand-edited":                              1,
+		"public void SetPrice(decimal price) => Price = price;":        1,
+		"using System.ComponentModel.DataAnnotations;":                 1,
+	}
+	for snippet, want := range checks {
+		if got := strings.Count(merged, snippet); got != want {
+			t.Errorf("%q appears %d times; want %d:\n%s", snippet, got, want, merged)
+		}
+	}
+}
+
+func TestASTMerger_MergePropertiesMatchesClassByName(t *testing.T) {
+	astMerger := merger.NewASTMerger()
+
+	existing := `namespace Test
+{
+    public class Product
+    {
+        public string Name { get; set; }
+    }
+
+    public class ProductDetails
+    {
+        public string Notes { get; set; }
+    }
+}
+`
+
+	newContent := `namespace Test
+{
+    public class Product
+    {
+        public string Name { get; set; }
+        public int Stock { get; set; }
+    }
+}
+`
+
+	merged, _, err := astMerger.Merge(existing, newContent, merger.FileTypeEntity)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	want := `        public string Name { get; set; }
+        public int Stock { get; set; }
+    }
+
+    public class ProductDetails`
+	if !strings.Contains(merged, want) {
+		t.Errorf("Stock not added to Product:\n%s", merged)
+	}
+}
@@ -64,6 +64,11 @@ func TestClassifier_ClassifyFile(t *testing.T) {
 			path:         "/path/to/CreateProductDtoValidator.cs",
 			expectedType: merger.FileTypeValidator,
 		},
+		{
+			name:         "Enum extensions",
+			path:         "/path/to/Enums/CatalogModule/OrderStatusExtensions.cs",
+			expectedType: merger.FileTypeEnumExtensions,
+		},
 		{
 			name:         "Localization JSON",
 			path:         "/path/to/en.json",
@@ -94,6 +99,16 @@ func TestClassifier_GetMergeStrategy(t *testing.T) {
 			fileType:         merger.FileTypePermissions,
 			expectedStrategy: merger.MergeStrategyPattern,
 		},
+		{
+			name:             "AutoMapper profile uses pattern strategy",
+			fileType:         merger.FileTypeAutoMapperProfile,
+			expectedStrategy: merger.MergeStrategyPattern,
+		},
+		{
+			name:             "Enum extensions use pattern strategy",
+			fileType:         merger.FileTypeEnumExtensions,
+			expectedStrategy: merger.MergeStrategyPattern,
+		},
 		{
 			name:             "Entity uses AST strategy",
 			fileType:         merger.FileTypeEntity,
@@ -0,0 +1,67 @@
+package merger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/merger"
+)
+
+// TestUnifiedDiff_Identical tests that identical content produces no diff
+func TestUnifiedDiff_Identical(t *testing.T) {
+	content := "line 1\nline 2\n"
+	if diff := merger.UnifiedDiff("a", "b", content, content); diff != "" {
+		t.Errorf("expected no diff, got:\n%s", diff)
+	}
+}
+
+// TestUnifiedDiff_ChangedLine tests that a changed line is shown with context and +/- markers
+func TestUnifiedDiff_ChangedLine(t *testing.T) {
+	existing := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	newContent := "a\nb\nc\nd\nE\nf\ng\nh\ni\n"
+
+	diff := merger.UnifiedDiff("Product.cs (existing)", "Product.cs (generated)", existing, newContent)
+
+	expected := `--- Product.cs (existing)
++++ Product.cs (generated)
+@@ -2,7 +2,8 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
++i
+`
+	if diff != expected {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", diff, expected)
+	}
+}
+
+// TestUnifiedDiff_SeparateHunks tests that distant changes are split into separate hunks
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var existing, newContent []string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a' + i))
+		existing = append(existing, line)
+		newContent = append(newContent, line)
+	}
+	newContent[1] = "changed first"
+	newContent = append(newContent[:18], newContent[19:]...)
+
+	diff := merger.UnifiedDiff("old", "new", strings.Join(existing, "\n"), strings.Join(newContent, "\n"))
+
+	for _, want := range []string{
+		"@@ -1,5 +1,5 @@\n a\n-b\n+changed first\n c\n",
+		"@@ -16,5 +16,4 @@\n p\n q\n r\n-s\n t\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q\n%s", want, diff)
+		}
+	}
+	if strings.Count(diff, "@@ -") != 2 {
+		t.Errorf("expected 2 hunks, got:\n%s", diff)
+	}
+}
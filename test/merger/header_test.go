@@ -0,0 +1,59 @@
+package merger_test
+
+import (
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/merger"
+)
+
+func TestApplyGeneratedHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		header   string
+		expected string
+	}{
+		{
+			name:     "adds header",
+			content:  "namespace Test;\n",
+			header:   "// <auto-generated> by abp-gen v1.0.0 from schema.json",
+			expected: "// <auto-generated> by abp-gen v1.0.0 from schema.json\nnamespace Test;\n",
+		},
+		{
+			name:     "replaces existing header",
+			content:  "// <auto-generated> by abp-gen v1.0.0 from schema.json\nnamespace Test;\n",
+			header:   "// <auto-generated> by abp-gen v1.1.0 from schema.json",
+			expected: "// <auto-generated> by abp-gen v1.1.0 from schema.json\nnamespace Test;\n",
+		},
+		{
+			name:     "removes header when empty",
+			content:  "// <auto-generated> by abp-gen v1.0.0 from schema.json\r\nnamespace Test;\n",
+			header:   "",
+			expected: "namespace Test;\n",
+		},
+		{
+			name:     "keeps hand-written comments",
+			content:  "// Copyright Acme\nnamespace Test;\n",
+			header:   "// <auto-generated> by abp-gen v1.0.0 from schema.json",
+			expected: "// <auto-generated> by abp-gen v1.0.0 from schema.json\n// Copyright Acme\nnamespace Test;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := merger.ApplyGeneratedHeader(tt.content, tt.header)
+			if result != tt.expected {
+				t.Errorf("ApplyGeneratedHeader() = %q; want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSupportsGeneratedHeader(t *testing.T) {
+	if !merger.SupportsGeneratedHeader("Entities/Product.cs") {
+		t.Error("expected C# files to support the generated header")
+	}
+	if merger.SupportsGeneratedHeader("Localization/en.json") {
+		t.Error("expected JSON files not to support the generated header")
+	}
+}
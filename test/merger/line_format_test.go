@@ -0,0 +1,84 @@
+package merger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mohamedhabibwork/abp-gen/internal/merger"
+)
+
+func TestDetectLineFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected merger.LineFormat
+	}{
+		{"empty", "", merger.LineFormat{LineEnding: "\n", TrailingNewline: true}},
+		{"lf", "a\nb\n", merger.LineFormat{LineEnding: "\n", TrailingNewline: true}},
+		{"crlf", "a\r\nb\r\n", merger.LineFormat{LineEnding: "\r\n", TrailingNewline: true}},
+		{"mostly crlf", "a\r\nb\r\nc\nd", merger.LineFormat{LineEnding: "\r\n", TrailingNewline: false}},
+		{"no trailing newline", "a\nb", merger.LineFormat{LineEnding: "\n", TrailingNewline: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := merger.DetectLineFormat(tt.content); result != tt.expected {
+				t.Errorf("DetectLineFormat() = %+v; want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineFormat_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   merger.LineFormat
+		content  string
+		expected string
+	}{
+		{"restores crlf", merger.LineFormat{LineEnding: "\r\n", TrailingNewline: true}, "a\nb\n", "a\r\nb\r\n"},
+		{"adds trailing newline", merger.LineFormat{LineEnding: "\n", TrailingNewline: true}, "a\nb", "a\nb\n"},
+		{"removes trailing newline", merger.LineFormat{LineEnding: "\r\n", TrailingNewline: false}, "a\nb\n\n", "a\r\nb"},
+		{"mixed input", merger.LineFormat{LineEnding: "\n", TrailingNewline: true}, "a\r\nb\n", "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.format.Apply(tt.content); result != tt.expected {
+				t.Errorf("Apply() = %q; want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEngine_MergeKeepsLineEndings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "en.json")
+	if err := os.WriteFile(path, []byte("{\r\n  \"Existing\": \"Kept\"\r\n}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := merger.NewEngine(false, false)
+	engine.NonInteractive = true
+	engine.SetMergeAll(merger.MergeDecisionMerge)
+
+	merged, write, err := engine.MergeFile(path, "{\n  \"New\": \"Added\"\n}\n")
+	if err != nil {
+		t.Fatalf("MergeFile() error = %v", err)
+	}
+	if !write {
+		t.Fatal("MergeFile() did not request a write")
+	}
+	if !strings.Contains(merged, `"New"`) || !strings.Contains(merged, `"Existing"`) {
+		t.Errorf("merged content missing keys: %q", merged)
+	}
+	for i, c := range merged {
+		if c == '\n' && (i == 0 || merged[i-1] != '\r') {
+			t.Fatalf("merged content has LF line ending at %d: %q", i, merged)
+		}
+	}
+	if merged[len(merged)-1] == '\n' {
+		t.Errorf("merged content gained a trailing newline: %q", merged)
+	}
+}
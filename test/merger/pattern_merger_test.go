@@ -1,6 +1,7 @@
 package merger_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/mohamedhabibwork/abp-gen/internal/merger"
@@ -40,3 +41,175 @@ func TestPatternMerger_MergePermissions(t *testing.T) {
 		t.Error("Merged content is empty")
 	}
 }
+
+func TestPatternMerger_MergeAutoMapperProfile(t *testing.T) {
+	patternMerger := merger.NewPatternMerger()
+
+	existing := `namespace Test
+{
+    public class ProductProfile : Profile
+    {
+        public ProductProfile()
+        {
+            CreateMap<Product, ProductDto>()
+                .ForMember(dest => dest.CategoryName, opt => opt.Ignore());
+
+            // Hand-written map
+            CreateMap<Product, ProductLookupDto>();
+        }
+    }
+}`
+
+	newContent := `namespace Test
+{
+    public class ProductProfile : Profile
+    {
+        public ProductProfile()
+        {
+            CreateMap<Product,ProductDto>();
+
+            CreateMap<CreateProductDto, Product>();
+
+            CreateMap<Product, ProductEto>()
+                .ForMember(dest => dest.Id, opt => opt.MapFrom(src => src.Id));
+        }
+    }
+}`
+
+	merged, conflicts, err := patternMerger.Merge(existing, newContent, merger.FileTypeAutoMapperProfile)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(conflicts) > 0 {
+		t.Errorf("Expected no conflicts, got %d", len(conflicts))
+	}
+
+	want := `            CreateMap<Product, ProductLookupDto>();
+
+            CreateMap<CreateProductDto, Product>();
+
+            CreateMap<Product, ProductEto>()
+                .ForMember(dest => dest.Id, opt => opt.MapFrom(src => src.Id));
+        }
+    }
+}`
+	if !strings.HasSuffix(merged, want) {
+		t.Errorf("new maps not appended to the constructor:\n%s", merged)
+	}
+	if count := strings.Count(merged, "CreateMap<Product, ProductDto>"); count != 1 {
+		t.Errorf("existing map duplicated %d times:\n%s", count, merged)
+	}
+	if !strings.Contains(merged, "opt => opt.Ignore());") {
+		t.Errorf("existing map was changed:\n%s", merged)
+	}
+
+	// Merging again is a no-op
+	again, _, err := patternMerger.Merge(merged, newContent, merger.FileTypeAutoMapperProfile)
+	if err != nil {
+		t.Fatalf("second Merge failed: %v", err)
+	}
+	if again != merged {
+		t.Errorf("second merge changed the file:\n%s", again)
+	}
+}
+
+func TestPatternMerger_MergeAutoMapperProfileEmptyConstructor(t *testing.T) {
+	patternMerger := merger.NewPatternMerger()
+
+	existing := `public class CatalogApplicationAutoMapperProfile : Profile
+{
+    public CatalogApplicationAutoMapperProfile() { }
+}`
+	newContent := `public class CatalogApplicationAutoMapperProfile : Profile
+{
+    public CatalogApplicationAutoMapperProfile()
+    {
+        CreateMap<Product, ProductDto>();
+    }
+}`
+
+	merged, _, err := patternMerger.Merge(existing, newContent, merger.FileTypeAutoMapperProfile)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	want := `    public CatalogApplicationAutoMapperProfile() {
+        CreateMap<Product, ProductDto>();
+    }
+}`
+	if !strings.HasSuffix(merged, want) {
+		t.Errorf("unexpected merge result:\n%s", merged)
+	}
+}
+
+func TestPatternMerger_MergeEnumExtensions(t *testing.T) {
+	patternMerger := merger.NewPatternMerger()
+
+	existing := `using System;
+using System.Linq;
+
+namespace Acme.Shop.Domain.Shared.CatalogModule
+{
+    public static class OrderStatusExtensions
+    {
+        public static string GetDisplayName(this OrderStatus value)
+        {
+            // Hand-written lookup
+            return L[value.ToString()];
+        }
+    }
+}
+`
+	newContent := `using System;
+using System.Collections.Generic;
+using System.Linq;
+
+namespace Acme.Shop.Domain.Shared.CatalogModule
+{
+    public static class OrderStatusExtensions
+    {
+        public static List<NameValueDto<int>> GetLookupList()
+        {
+            return Enum.GetValues(typeof(OrderStatus))
+                .Cast<OrderStatus>()
+                .Select(e => new NameValueDto<int> { Name = e.ToString(), Value = (int)e })
+                .ToList();
+        }
+
+        public static string GetDisplayName(this OrderStatus value)
+        {
+            return value.ToString();
+        }
+    }
+}
+`
+
+	merged, conflicts, err := patternMerger.Merge(existing, newContent, merger.FileTypeEnumExtensions)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(conflicts) > 0 {
+		t.Errorf("Expected no conflicts, got %d", len(conflicts))
+	}
+
+	for _, want := range []string{
+		"using System.Linq;\nusing System.Collections.Generic;",
+		"            // Hand-written lookup\n            return L[value.ToString()];\n        }\n\n        public static List<NameValueDto<int>> GetLookupList()",
+		"                .ToList();\n        }\n    }\n}\n",
+	} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("merged content missing %q:\n%s", want, merged)
+		}
+	}
+	if got := strings.Count(merged, "GetDisplayName"); got != 1 {
+		t.Errorf("GetDisplayName declared %d times; want 1:\n%s", got, merged)
+	}
+
+	again, _, err := patternMerger.Merge(merged, newContent, merger.FileTypeEnumExtensions)
+	if err != nil {
+		t.Fatalf("second Merge failed: %v", err)
+	}
+	if again != merged {
+		t.Errorf("merging twice changed the file:\n%s", again)
+	}
+}